@@ -10,9 +10,6 @@ require (
 	github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue v1.20.53
 	github.com/aws/aws-sdk-go-v2/service/dynamodb v1.61.0
 	github.com/aws/smithy-go v1.27.4
-	github.com/coocood/freecache v1.2.7
-	github.com/eko/gocache/lib/v4 v4.2.3
-	github.com/eko/gocache/store/freecache/v4 v4.2.4
 	github.com/redis/go-redis/extra/redisotel/v9 v9.21.0
 	github.com/redis/go-redis/v9 v9.21.0
 	github.com/stretchr/testify v1.11.1
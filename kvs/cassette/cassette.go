@@ -0,0 +1,295 @@
+// Package cassette records the calls to a kvs.LowLevelClient and replays them offline.
+package cassette
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// ErrNoInteraction is returned by a Replayer when its cassette holds no interaction
+// matching a call.
+const ErrNoInteraction = kvs.KeyValueError("[kvs]: cassette has no matching interaction")
+
+// Operations recorded in cassettes, as Interaction.Operation. The variants with and
+// without context are the same operation.
+const (
+	// OperationGet is Get and GetWithContext.
+	OperationGet = "Get"
+	// OperationSave is Save and SaveWithContext.
+	OperationSave = "Save"
+	// OperationBulkGet is BulkGet and BulkGetWithContext.
+	OperationBulkGet = "BulkGet"
+	// OperationBulkSave is BulkSave and BulkSaveWithContext.
+	OperationBulkSave = "BulkSave"
+	// OperationDelete is Delete and DeleteWithContext.
+	OperationDelete = "Delete"
+	// OperationSaveIfAbsent is SaveIfAbsent.
+	OperationSaveIfAbsent = "SaveIfAbsent"
+	// OperationCompareAndSwap is CompareAndSwap.
+	OperationCompareAndSwap = "CompareAndSwap"
+	// OperationCompareAndDelete is CompareAndDelete.
+	OperationCompareAndDelete = "CompareAndDelete"
+	// OperationIncrement is Increment.
+	OperationIncrement = "Increment"
+	// OperationIncrementFloat is IncrementFloat.
+	OperationIncrementFloat = "IncrementFloat"
+	// OperationPatch is Patch.
+	OperationPatch = "Patch"
+	// OperationGetFields is GetFields.
+	OperationGetFields = "GetFields"
+	// OperationScan is Scan.
+	OperationScan = "Scan"
+	// OperationQuery is Query.
+	OperationQuery = "Query"
+	// OperationQueryPage is QueryPage.
+	OperationQueryPage = "QueryPage"
+	// OperationQueryIndex is QueryIndex.
+	OperationQueryIndex = "QueryIndex"
+	// OperationCommit is the Commit of a transaction started by NewTx.
+	OperationCommit = "Commit"
+)
+
+// Interaction is a call to a kvs.LowLevelClient and its result: one line of a cassette.
+type Interaction struct {
+	Request   Request  `json:"request"`
+	Response  Response `json:"response"`
+	Operation string   `json:"op"`
+}
+
+// Request holds the arguments of a call. Only the arguments of the operation are set.
+type Request struct {
+	Item     *Item            `json:"item,omitempty"`
+	Expected *Item            `json:"expected,omitempty"`
+	Scan     *kvs.ScanOptions `json:"scan,omitempty"`
+	Query    *Query           `json:"query,omitempty"`
+	Updates  map[string]any   `json:"updates,omitempty"`
+	Key      string           `json:"key,omitempty"`
+	Delta    json.Number      `json:"delta,omitempty"`
+	Keys     []string         `json:"keys,omitempty"`
+	Items    []*Item          `json:"items,omitempty"`
+	Fields   []string         `json:"fields,omitempty"`
+	Tx       []TxOperation    `json:"tx,omitempty"`
+	TTL      time.Duration    `json:"ttl,omitempty"`
+}
+
+// Query holds the arguments of Query and QueryPage, or of QueryIndex.
+type Query struct {
+	Partition string            `json:"partition,omitempty"`
+	Index     string            `json:"index,omitempty"`
+	Value     string            `json:"value,omitempty"`
+	Condition kvs.SortCondition `json:"condition"`
+	Options   kvs.QueryOptions  `json:"options"`
+}
+
+// TxOperation is a kvs.TxOperation in a cassette, whose Type is held by name.
+type TxOperation struct {
+	Item     *Item  `json:"item,omitempty"`
+	Expected *Item  `json:"expected,omitempty"`
+	Type     string `json:"type"`
+	Key      string `json:"key"`
+}
+
+// newTxOperations returns the cassette TxOperations of operations.
+func newTxOperations(operations []kvs.TxOperation) []TxOperation {
+	recorded := make([]TxOperation, len(operations))
+	for i, operation := range operations {
+		recorded[i] = TxOperation{
+			Item:     newItem(operation.Item),
+			Expected: newItem(operation.Expected),
+			Type:     operation.Type.String(),
+			Key:      operation.Key,
+		}
+	}
+	return recorded
+}
+
+// Response holds the result of a call.
+type Response struct {
+	Item *Item `json:"item,omitempty"`
+	// Canceled is the error of a canceled transaction, replayed as is.
+	Canceled *kvs.TxCanceledError `json:"canceled,omitempty"`
+	Count    json.Number          `json:"count,omitempty"`
+	Error    string               `json:"error,omitempty"`
+	// Is is the message of the kvs or context error wrapped by Error, so that the
+	// replayed error matches it with errors.Is.
+	Is    string  `json:"is,omitempty"`
+	Token string  `json:"token,omitempty"`
+	Items []*Item `json:"items,omitempty"`
+}
+
+// Item is a kvs.Item in a cassette, whose Value is held as JSON.
+type Item struct {
+	Key       string          `json:"key"`
+	Codec     string          `json:"codec,omitempty"`
+	Value     json.RawMessage `json:"value,omitempty"`
+	TTL       int64           `json:"ttl,omitempty"`
+	Version   int64           `json:"version,omitempty"`
+	CreatedAt int64           `json:"created,omitempty"`
+}
+
+// newItem returns the cassette Item of item, or nil for a nil item. A value that does
+// not marshal to JSON is left out.
+func newItem(item *kvs.Item) *Item {
+	if item == nil {
+		return nil
+	}
+
+	return &Item{
+		Key:       item.Key,
+		Codec:     item.Codec,
+		Value:     encodeValue(item.Value),
+		TTL:       item.TTL,
+		Version:   item.Version,
+		CreatedAt: item.CreatedAt,
+	}
+}
+
+// encodeValue returns the JSON of value. A kvs.ValueDecoder, such as a native DynamoDB
+// value, is decoded first and recorded as the JSON string the other backends return, so
+// that the replayed item decodes with TryGetValueAsObjectType like the recorded one.
+func encodeValue(value any) json.RawMessage {
+	if decoder, ok := value.(kvs.ValueDecoder); ok {
+		var decoded any
+		if err := decoder.DecodeValue(&decoded); err != nil {
+			return nil
+		}
+		bytes, err := json.Marshal(decoded)
+		if err != nil {
+			return nil
+		}
+		value = string(bytes)
+	}
+
+	encoded, _ := json.Marshal(value)
+	return encoded
+}
+
+// newItems returns the cassette Items of items.
+func newItems(items *kvs.Items) []*Item {
+	if items == nil {
+		return nil
+	}
+
+	recorded := make([]*Item, 0, items.Len())
+	for item := range items.All() {
+		recorded = append(recorded, newItem(item))
+	}
+	return recorded
+}
+
+// item returns the kvs.Item of r, whose Value is the JSON value decoded as any.
+func (r *Item) item() *kvs.Item {
+	if r == nil {
+		return nil
+	}
+
+	var value any
+	if len(r.Value) > 0 {
+		_ = json.Unmarshal(r.Value, &value)
+	}
+	return &kvs.Item{
+		Key:       r.Key,
+		Codec:     r.Codec,
+		Value:     value,
+		TTL:       r.TTL,
+		Version:   r.Version,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+// withoutTTL returns a copy of r without its TTL, or nil for a nil item.
+func (r *Item) withoutTTL() *Item {
+	if r == nil {
+		return nil
+	}
+
+	item := *r
+	item.TTL = 0
+	return &item
+}
+
+// items returns the kvs.Items of recorded items.
+func items(recorded []*Item) *kvs.Items {
+	items := new(kvs.Items)
+	for _, item := range recorded {
+		items.Add(item.item())
+	}
+	return items
+}
+
+// sentinels are the errors that replayed errors match with errors.Is.
+var sentinels = []error{
+	kvs.ErrKeyNotFound,
+	kvs.ErrEmptyKey,
+	kvs.ErrNilItem,
+	kvs.ErrConvert,
+	kvs.ErrMarshal,
+	kvs.ErrTooManyKeys,
+	kvs.ErrInternal,
+	kvs.ErrConditionFailed,
+	kvs.ErrFieldPath,
+	kvs.ErrUnsupported,
+	kvs.ErrInvalidToken,
+	kvs.ErrInvalidTx,
+	context.Canceled,
+	context.DeadlineExceeded,
+}
+
+// replayedError is an error read from a cassette.
+type replayedError struct {
+	sentinel error
+	message  string
+}
+
+func (r *replayedError) Error() string {
+	return r.message
+}
+
+func (r *replayedError) Unwrap() error {
+	return r.sentinel
+}
+
+// withError records err in the response.
+func (r Response) withError(err error) Response {
+	if err == nil {
+		return r
+	}
+
+	r.Error = err.Error()
+	if canceled := (*kvs.TxCanceledError)(nil); errors.As(err, &canceled) {
+		r.Canceled = canceled
+	}
+	for _, sentinel := range sentinels {
+		if errors.Is(err, sentinel) {
+			r.Is = sentinel.Error()
+			break
+		}
+	}
+	return r
+}
+
+// err returns the error recorded in the response, if any.
+func (r Response) err() error {
+	if r.Error == "" {
+		return nil
+	}
+	if r.Canceled != nil {
+		return r.Canceled
+	}
+
+	replayed := &replayedError{message: r.Error}
+	for _, sentinel := range sentinels {
+		if sentinel.Error() == r.Is {
+			replayed.sentinel = sentinel
+			if r.Error == r.Is {
+				return sentinel
+			}
+			break
+		}
+	}
+	return replayed
+}
@@ -0,0 +1,292 @@
+package cassette_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	"github.com/arielsrv/go-kvs-client/kvs/cassette"
+	"github.com/arielsrv/go-kvs-client/kvs/dynamodb"
+	kvsredis "github.com/arielsrv/go-kvs-client/kvs/redis"
+)
+
+type user struct {
+	Name string `json:"name"`
+	ID   int    `json:"id"`
+}
+
+// session makes calls covering every operation and returns what they observed.
+func session(t *testing.T, client kvs.LowLevelClient) []any {
+	t.Helper()
+	ctx := t.Context()
+	var observed []any
+	observe := func(values ...any) {
+		for _, value := range values {
+			if err, ok := value.(error); ok {
+				value = errors.Is(err, kvs.ErrKeyNotFound) || errors.Is(err, kvs.ErrConditionFailed)
+			}
+			observed = append(observed, value)
+		}
+	}
+	decode := func(item *kvs.Item) user {
+		var value user
+		require.NoError(t, item.TryGetValueAsObjectType(&value))
+		return value
+	}
+
+	require.NoError(t, client.Save("1", kvs.NewItem("1", user{ID: 1, Name: "John"})))
+	item, err := client.Get("1")
+	require.NoError(t, err)
+	observe(item.Key, decode(item))
+	_, err = client.Get("missing")
+	observe(err)
+
+	items := new(kvs.Items)
+	items.Add(kvs.NewItem("2", user{ID: 2, Name: "Jane"}))
+	items.Add(kvs.NewItem("3", user{ID: 3, Name: "Joe"}))
+	require.NoError(t, client.BulkSave(items))
+	found, err := client.BulkGet([]string{"3", "missing", "2"})
+	require.NoError(t, err)
+	for item := range found.All() {
+		observe(item.Key, decode(item))
+	}
+
+	observe(client.SaveIfAbsent(ctx, "1", kvs.NewItem("1", user{ID: 1})))
+	require.NoError(t, client.CompareAndSwap(ctx, "1", item, kvs.NewItem("1", user{ID: 1, Name: "Jim"})))
+	observe(client.CompareAndDelete(ctx, "1", item))
+	require.NoError(t, client.Patch(ctx, "2", map[string]any{"name": "Janet"}))
+	fields, err := client.GetFields(ctx, "2", "name")
+	require.NoError(t, err)
+	observe(decode(fields))
+
+	count, err := client.Increment(ctx, "hits", 2, time.Minute)
+	require.NoError(t, err)
+	ratio, err := client.IncrementFloat(ctx, "ratio", 0.5, time.Minute)
+	require.NoError(t, err)
+	observe(count, ratio)
+
+	for item, err := range client.Scan(ctx, kvs.ScanOptions{Prefix: "3"}) {
+		require.NoError(t, err)
+		observe(item.Key)
+	}
+
+	require.NoError(t, client.Delete("3"))
+	_, err = client.Get("3")
+	observe(err)
+	return observed
+}
+
+func record(t *testing.T) (*bytes.Buffer, []any) {
+	t.Helper()
+	var buffer bytes.Buffer
+	recorder := cassette.NewRecorder(kvsredis.NewBuilder(kvsredis.WithKeyPrefix("__kvs:users")).FakeBuild(), &buffer)
+	observed := session(t, recorder)
+	require.NoError(t, recorder.Err())
+	require.Equal(t, "__kvs:users", recorder.ContainerName())
+	return &buffer, observed
+}
+
+func TestReplayer_Strict_ReplaysSession(t *testing.T) {
+	cassetteData, recorded := record(t)
+	require.Equal(t, 15, strings.Count(cassetteData.String(), "\n"))
+
+	replayer, err := cassette.NewReplayer(cassetteData)
+	require.NoError(t, err)
+	require.Equal(t, recorded, session(t, replayer))
+	require.Zero(t, replayer.Remaining())
+	require.Equal(t, "cassette", replayer.ContainerName())
+}
+
+func TestReplayer_Strict_RejectsOtherCalls(t *testing.T) {
+	cassetteData, _ := record(t)
+	replayer, err := cassette.NewReplayer(cassetteData)
+	require.NoError(t, err)
+
+	_, err = replayer.Get("1")
+	require.ErrorIs(t, err, cassette.ErrNoInteraction, "out of order")
+	require.ErrorIs(t, replayer.Save("1", kvs.NewItem("1", user{ID: 1, Name: "Johnny"})), cassette.ErrNoInteraction)
+	require.NoError(t, replayer.Save("1", kvs.NewItem("1", user{Name: "John", ID: 1})))
+	require.Equal(t, 14, replayer.Remaining())
+}
+
+func TestReplayer_Lenient_MatchesByKeys(t *testing.T) {
+	cassetteData, _ := record(t)
+	replayer, err := cassette.NewReplayer(cassetteData, cassette.WithMatching(cassette.MatchLenient))
+	require.NoError(t, err)
+
+	_, err = replayer.Get("3")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+	require.NoError(t, replayer.Save("1", kvs.NewItem("1", "another value", time.Hour)))
+
+	item, err := replayer.Get("1")
+	require.NoError(t, err)
+	require.Equal(t, "1", item.Key)
+	item, err = replayer.Get("1")
+	require.NoError(t, err, "the last interaction is replayed again")
+	require.Equal(t, "1", item.Key)
+
+	count, err := replayer.Increment(t.Context(), "hits", 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	_, err = replayer.Get("4")
+	require.ErrorIs(t, err, cassette.ErrNoInteraction)
+}
+
+func TestReplayer_Errors(t *testing.T) {
+	var buffer bytes.Buffer
+	recorder := cassette.NewRecorder(kvsredis.NewBuilder().FakeBuild(), &buffer)
+	require.ErrorIs(t, recorder.SaveIfAbsent(t.Context(), "", kvs.NewItem("", 1)), kvs.ErrEmptyKey)
+	_, err := recorder.BulkGet(make([]string, kvsredis.MaxBulkKeys+1))
+	require.ErrorIs(t, err, kvs.ErrTooManyKeys)
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	_, recordedErr := recorder.GetWithContext(ctx, "1")
+	require.ErrorIs(t, recordedErr, context.Canceled)
+
+	replayer, err := cassette.NewReplayer(&buffer)
+	require.NoError(t, err)
+	require.Equal(t, kvs.ErrEmptyKey, replayer.SaveIfAbsent(t.Context(), "", kvs.NewItem("", 1)))
+	_, err = replayer.BulkGet(make([]string, kvsredis.MaxBulkKeys+1))
+	require.ErrorIs(t, err, kvs.ErrTooManyKeys)
+	_, err = replayer.Get("1")
+	require.ErrorIs(t, err, context.Canceled)
+	require.EqualError(t, err, recordedErr.Error())
+}
+
+func TestRecordFile_LoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.jsonl")
+	recorder, err := cassette.RecordFile(kvsredis.NewBuilder().FakeBuild(), path)
+	require.NoError(t, err)
+	recorded := session(t, recorder)
+	require.NoError(t, recorder.Close())
+
+	replayer, err := cassette.LoadFile(path, cassette.WithContainerName("users"))
+	require.NoError(t, err)
+	require.Equal(t, recorded, session(t, replayer))
+	require.Equal(t, "users", replayer.ContainerName())
+
+	_, err = cassette.LoadFile(filepath.Join(t.TempDir(), "missing.jsonl"))
+	require.Error(t, err)
+	_, err = cassette.NewReplayer(strings.NewReader("{\"op\":\"Get\"}\n\nnot json\n"))
+	require.ErrorContains(t, err, "cassette line 3")
+}
+
+func TestReplayer_Strict_IgnoresItemTTLs(t *testing.T) {
+	clock := kvs.NewFakeClock(time.Unix(1_700_000_000, 0))
+	calls := func(client kvs.LowLevelClient) {
+		ctx := t.Context()
+		item := kvs.NewItemWithClock(clock, "1", user{ID: 1}, time.Hour)
+		require.NoError(t, client.Save("1", item))
+		require.ErrorIs(t, client.SaveIfAbsent(ctx, "1", item), kvs.ErrConditionFailed)
+		stored, err := client.Get("1")
+		require.NoError(t, err)
+		require.NoError(t, client.CompareAndSwap(ctx, "1", stored, kvs.NewItemWithClock(clock, "1", user{ID: 2}, time.Hour)))
+		items := new(kvs.Items)
+		items.Add(kvs.NewItemWithClock(clock, "2", user{ID: 2}, time.Minute))
+		require.NoError(t, client.BulkSave(items))
+	}
+
+	var buffer bytes.Buffer
+	recorder := cassette.NewRecorder(kvsredis.NewBuilder(kvsredis.WithClock(clock)).FakeBuild(), &buffer)
+	calls(recorder)
+	require.NoError(t, recorder.Err())
+
+	clock.Advance(time.Second)
+	replayer, err := cassette.NewReplayer(&buffer)
+	require.NoError(t, err)
+	calls(replayer)
+	require.Zero(t, replayer.Remaining())
+
+	require.ErrorIs(t, replayer.Save("1", kvs.NewItemWithClock(clock, "1", user{ID: 3}, time.Hour)), cassette.ErrNoInteraction)
+}
+
+func TestRecorder_DynamoDBAttributeStorage_RoundTrips(t *testing.T) {
+	var buffer bytes.Buffer
+	recorder := cassette.NewRecorder(dynamodb.NewBuilder(
+		dynamodb.WithContainerName("__kvs-users"),
+		dynamodb.WithStorageMode(dynamodb.StorageAttributes),
+	).FakeBuild(), &buffer)
+	recorded := kvs.NewKVSClient[user](recorder)
+	require.NoError(t, recorded.Save("1", &user{ID: 1, Name: "John"}))
+	want, err := recorded.Get("1")
+	require.NoError(t, err)
+	require.Equal(t, &user{ID: 1, Name: "John"}, want)
+
+	replayer, err := cassette.NewReplayer(&buffer)
+	require.NoError(t, err)
+	replayed := kvs.NewKVSClient[user](replayer)
+	require.NoError(t, replayed.Save("1", &user{ID: 1, Name: "John"}))
+	got, err := replayed.Get("1")
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
+
+func TestReplayer_QueriesAndTransactions(t *testing.T) {
+	byName := dynamodb.NewSecondaryIndex("by-name", "gsi_name", func(value user) string { return value.Name })
+	calls := func(client kvs.LowLevelClient) []any {
+		ctx := t.Context()
+		var observed []any
+		querier, transactor := client.(kvs.Querier), client.(kvs.Transactor)
+
+		require.NoError(t, client.Save("1", kvs.NewItem("1", user{ID: 1, Name: "John"})))
+		for item, err := range querier.Query(ctx, "1", kvs.SortCondition{}, kvs.QueryOptions{}) {
+			require.NoError(t, err)
+			observed = append(observed, item.Key)
+		}
+		page, token, err := querier.QueryPage(ctx, "1", kvs.SortCondition{}, kvs.QueryOptions{PageSize: 1})
+		require.NoError(t, err)
+		observed = append(observed, page.Len(), token)
+		for item, err := range client.(kvs.IndexQuerier).QueryIndex(ctx, "by-name", "John") {
+			require.NoError(t, err)
+			observed = append(observed, item.Key)
+		}
+
+		require.NoError(t, transactor.NewTx().Put("2", kvs.NewItem("2", user{ID: 2})).Delete("1").Commit(ctx))
+		err = transactor.NewTx().ConditionCheck("2", nil).Put("3", kvs.NewItem("3", user{ID: 3})).Commit(ctx)
+		var canceled *kvs.TxCanceledError
+		require.ErrorAs(t, err, &canceled)
+		observed = append(observed, *canceled)
+		return observed
+	}
+
+	var buffer bytes.Buffer
+	recorder := cassette.NewRecorder(dynamodb.NewBuilder(
+		dynamodb.WithContainerName("__kvs-users"),
+		dynamodb.WithSecondaryIndexes(byName),
+	).FakeBuild(), &buffer)
+	recorded := calls(recorder)
+	require.NoError(t, recorder.Err())
+	require.Equal(t, []any{"1", 1, "", "1", kvs.TxCanceledError{Key: "2", Index: 0}}, recorded)
+
+	replayer, err := cassette.NewReplayer(&buffer)
+	require.NoError(t, err)
+	require.Equal(t, recorded, calls(replayer))
+	require.Zero(t, replayer.Remaining())
+}
+
+func TestRecorder_UnsupportedQueries(t *testing.T) {
+	ctx := t.Context()
+	var buffer bytes.Buffer
+	recorder := cassette.NewRecorder(kvsredis.NewBuilder().FakeBuild(), &buffer)
+	for _, err := range recorder.Query(ctx, "1", kvs.SortCondition{}, kvs.QueryOptions{}) {
+		require.ErrorIs(t, err, kvs.ErrUnsupported)
+	}
+	_, _, err := recorder.QueryPage(ctx, "1", kvs.SortCondition{}, kvs.QueryOptions{})
+	require.ErrorIs(t, err, kvs.ErrUnsupported)
+
+	replayer, err := cassette.NewReplayer(&buffer)
+	require.NoError(t, err)
+	for _, err = range replayer.Query(ctx, "1", kvs.SortCondition{}, kvs.QueryOptions{}) {
+		require.ErrorIs(t, err, kvs.ErrUnsupported)
+	}
+	_, _, err = replayer.QueryPage(ctx, "1", kvs.SortCondition{}, kvs.QueryOptions{})
+	require.ErrorIs(t, err, kvs.ErrUnsupported)
+}
@@ -0,0 +1,42 @@
+// Package cassette records the calls to a kvs.LowLevelClient and replays them offline.
+//
+// A Recorder wraps a real backend, typically in an integration test running against
+// Docker, and writes every call and its result to a cassette: a JSONL file holding one
+// Interaction per line. A Replayer then serves the recorded results without any
+// backend, so that downstream teams can run realistic tests offline and in CI.
+//
+// Key Components:
+//   - Recorder: the recording decorator (NewRecorder, RecordFile).
+//   - Replayer: the replay client (NewReplayer, LoadFile). With MatchStrict, the
+//     calls must be made in the recorded order with the recorded arguments (item
+//     TTLs, which are absolute times, excepted); with
+//     MatchLenient, they are matched by operation and keys only.
+//   - Interaction: the format of a cassette line, readable and editable by hand.
+//   - Queries and transactions: Recorder and Replayer are kvs.Querier,
+//     kvs.IndexQuerier and kvs.Transactor. The Recorder forwards them to the
+//     wrapped client, and records kvs.ErrUnsupported when it does not implement
+//     them; a transaction is recorded on Commit, with its operations.
+//
+// Recorded errors are replayed with their message, and match the kvs and context
+// errors they wrapped with errors.Is. Item values are held as JSON, so replayed items
+// carry the decoded JSON value (a string, for the values read from the backends of
+// this module, which TryGetValueAsObjectType decodes as usual). Values implementing
+// kvs.ValueDecoder, such as DynamoDB native attributes, are recorded as that string.
+//
+// Usage:
+//
+//	// Record once, against the real backend.
+//	recorder, err := cassette.RecordFile(redisClient, "testdata/users.jsonl")
+//	if err != nil {
+//	    // Handle error
+//	}
+//	defer recorder.Close()
+//	users := kvs.NewKVSClient[UserDTO](recorder)
+//
+//	// Replay offline.
+//	replayer, err := cassette.LoadFile("testdata/users.jsonl", cassette.WithMatching(cassette.MatchLenient))
+//	if err != nil {
+//	    // Handle error
+//	}
+//	users := kvs.NewKVSClient[UserDTO](replayer)
+package cassette
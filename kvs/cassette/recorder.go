@@ -0,0 +1,336 @@
+// Package cassette records the calls to a kvs.LowLevelClient and replays them offline.
+package cassette
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"iter"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// Recorder is a kvs.LowLevelClient that forwards the calls to the client it wraps and
+// writes each call and its result to a cassette, as one JSON Interaction per line.
+// It is also a kvs.Querier, a kvs.IndexQuerier and a kvs.Transactor, whose calls fail
+// with kvs.ErrUnsupported, and are recorded so, when the wrapped client does not
+// implement the interface.
+// Recorder is safe for concurrent use; concurrent calls are written in the order in
+// which they complete.
+type Recorder struct {
+	client  kvs.LowLevelClient
+	encoder *json.Encoder
+	closer  io.Closer
+	err     error
+	mu      sync.Mutex
+}
+
+// NewRecorder creates a new Recorder of the calls to client, writing the cassette to w.
+func NewRecorder(client kvs.LowLevelClient, w io.Writer) *Recorder {
+	return &Recorder{
+		client:  client,
+		encoder: json.NewEncoder(w),
+	}
+}
+
+// RecordFile creates a new Recorder of the calls to client, writing the cassette to
+// the file at path, which is created or truncated. Close the Recorder to close the file.
+func RecordFile(client kvs.LowLevelClient, path string) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	recorder := NewRecorder(client, file)
+	recorder.closer = file
+	return recorder, nil
+}
+
+// Err returns the first error met while writing the cassette. Write errors do not fail
+// the calls, which return the results of the wrapped client.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.err
+}
+
+// Close closes the cassette file opened by RecordFile, and returns the first error met
+// while writing the cassette. The wrapped client is not closed.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closer != nil {
+		if err := r.closer.Close(); err != nil && r.err == nil {
+			r.err = err
+		}
+		r.closer = nil
+	}
+	return r.err
+}
+
+// Get retrieves an item by its key.
+func (r *Recorder) Get(key string) (*kvs.Item, error) {
+	return r.GetWithContext(context.Background(), key)
+}
+
+// GetWithContext retrieves an item by its key, and records the call.
+func (r *Recorder) GetWithContext(ctx context.Context, key string) (*kvs.Item, error) {
+	item, err := r.client.GetWithContext(ctx, key)
+	r.record(OperationGet, Request{Key: key}, Response{Item: newItem(item)}, err)
+	return item, err
+}
+
+// Save stores an item with the specified key.
+func (r *Recorder) Save(key string, item *kvs.Item) error {
+	return r.SaveWithContext(context.Background(), key, item)
+}
+
+// SaveWithContext stores an item with the specified key, and records the call.
+func (r *Recorder) SaveWithContext(ctx context.Context, key string, item *kvs.Item) error {
+	request := Request{Key: key, Item: newItem(item)}
+	err := r.client.SaveWithContext(ctx, key, item)
+	r.record(OperationSave, request, Response{}, err)
+	return err
+}
+
+// BulkGet retrieves multiple items by their keys.
+func (r *Recorder) BulkGet(keys []string) (*kvs.Items, error) {
+	return r.BulkGetWithContext(context.Background(), keys)
+}
+
+// BulkGetWithContext retrieves multiple items by their keys, and records the call.
+func (r *Recorder) BulkGetWithContext(ctx context.Context, keys []string) (*kvs.Items, error) {
+	items, err := r.client.BulkGetWithContext(ctx, keys)
+	r.record(OperationBulkGet, Request{Keys: keys}, Response{Items: newItems(items)}, err)
+	return items, err
+}
+
+// BulkSave stores multiple items.
+func (r *Recorder) BulkSave(items *kvs.Items) error {
+	return r.BulkSaveWithContext(context.Background(), items)
+}
+
+// BulkSaveWithContext stores multiple items, and records the call.
+func (r *Recorder) BulkSaveWithContext(ctx context.Context, items *kvs.Items) error {
+	request := Request{Items: newItems(items)}
+	err := r.client.BulkSaveWithContext(ctx, items)
+	r.record(OperationBulkSave, request, Response{}, err)
+	return err
+}
+
+// Delete removes the item stored under key.
+func (r *Recorder) Delete(key string) error {
+	return r.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext removes the item stored under key, and records the call.
+func (r *Recorder) DeleteWithContext(ctx context.Context, key string) error {
+	err := r.client.DeleteWithContext(ctx, key)
+	r.record(OperationDelete, Request{Key: key}, Response{}, err)
+	return err
+}
+
+// SaveIfAbsent stores an item only if the key is not taken, and records the call.
+func (r *Recorder) SaveIfAbsent(ctx context.Context, key string, item *kvs.Item) error {
+	request := Request{Key: key, Item: newItem(item)}
+	err := r.client.SaveIfAbsent(ctx, key, item)
+	r.record(OperationSaveIfAbsent, request, Response{}, err)
+	return err
+}
+
+// CompareAndSwap replaces the item stored under key only if it still matches expected,
+// and records the call.
+func (r *Recorder) CompareAndSwap(ctx context.Context, key string, expected, item *kvs.Item) error {
+	request := Request{Key: key, Expected: newItem(expected), Item: newItem(item)}
+	err := r.client.CompareAndSwap(ctx, key, expected, item)
+	r.record(OperationCompareAndSwap, request, Response{}, err)
+	return err
+}
+
+// CompareAndDelete removes the item stored under key only if it still matches expected,
+// and records the call.
+func (r *Recorder) CompareAndDelete(ctx context.Context, key string, expected *kvs.Item) error {
+	request := Request{Key: key, Expected: newItem(expected)}
+	err := r.client.CompareAndDelete(ctx, key, expected)
+	r.record(OperationCompareAndDelete, request, Response{}, err)
+	return err
+}
+
+// Increment adds delta to the integer counter stored under key, and records the call.
+func (r *Recorder) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	count, err := r.client.Increment(ctx, key, delta, ttl)
+	r.record(
+		OperationIncrement,
+		Request{Key: key, Delta: json.Number(strconv.FormatInt(delta, 10)), TTL: ttl},
+		Response{Count: json.Number(strconv.FormatInt(count, 10))},
+		err,
+	)
+	return count, err
+}
+
+// IncrementFloat adds delta to the floating-point counter stored under key, and records
+// the call.
+func (r *Recorder) IncrementFloat(ctx context.Context, key string, delta float64, ttl time.Duration) (float64, error) {
+	count, err := r.client.IncrementFloat(ctx, key, delta, ttl)
+	r.record(
+		OperationIncrementFloat,
+		Request{Key: key, Delta: json.Number(strconv.FormatFloat(delta, 'g', -1, 64)), TTL: ttl},
+		Response{Count: json.Number(strconv.FormatFloat(count, 'g', -1, 64))},
+		err,
+	)
+	return count, err
+}
+
+// Patch updates fields of the JSON object stored under key, and records the call.
+func (r *Recorder) Patch(ctx context.Context, key string, updates map[string]any) error {
+	err := r.client.Patch(ctx, key, updates)
+	r.record(OperationPatch, Request{Key: key, Updates: updates}, Response{}, err)
+	return err
+}
+
+// GetFields retrieves fields of the JSON object stored under key, and records the call.
+func (r *Recorder) GetFields(ctx context.Context, key string, fields ...string) (*kvs.Item, error) {
+	item, err := r.client.GetFields(ctx, key, fields...)
+	r.record(OperationGetFields, Request{Key: key, Fields: fields}, Response{Item: newItem(item)}, err)
+	return item, err
+}
+
+// Scan enumerates the items stored in the container, and records the call once the
+// sequence ends: the items yielded until then, and the error, if any.
+func (r *Recorder) Scan(ctx context.Context, opts kvs.ScanOptions) iter.Seq2[*kvs.Item, error] {
+	return r.sequence(OperationScan, Request{Scan: &opts}, r.client.Scan(ctx, opts))
+}
+
+// Query enumerates the items of a partition, and records the call once the sequence
+// ends, like Scan.
+func (r *Recorder) Query(
+	ctx context.Context,
+	partition string,
+	condition kvs.SortCondition,
+	opts kvs.QueryOptions,
+) iter.Seq2[*kvs.Item, error] {
+	request := Request{Query: &Query{Partition: partition, Condition: condition, Options: opts}}
+	querier, ok := r.client.(kvs.Querier)
+	if !ok {
+		return r.sequence(OperationQuery, request, unsupported)
+	}
+	return r.sequence(OperationQuery, request, querier.Query(ctx, partition, condition, opts))
+}
+
+// QueryPage returns a page of the items of a partition, and records the call.
+func (r *Recorder) QueryPage(
+	ctx context.Context,
+	partition string,
+	condition kvs.SortCondition,
+	opts kvs.QueryOptions,
+) (*kvs.Items, string, error) {
+	var (
+		items *kvs.Items
+		token string
+		err   error = kvs.ErrUnsupported
+	)
+	if querier, ok := r.client.(kvs.Querier); ok {
+		items, token, err = querier.QueryPage(ctx, partition, condition, opts)
+	}
+	r.record(
+		OperationQueryPage,
+		Request{Query: &Query{Partition: partition, Condition: condition, Options: opts}},
+		Response{Items: newItems(items), Token: token},
+		err,
+	)
+	return items, token, err
+}
+
+// QueryIndex enumerates the items of a secondary index value, and records the call
+// once the sequence ends, like Scan.
+func (r *Recorder) QueryIndex(ctx context.Context, index, value string) iter.Seq2[*kvs.Item, error] {
+	request := Request{Query: &Query{Index: index, Value: value}}
+	querier, ok := r.client.(kvs.IndexQuerier)
+	if !ok {
+		return r.sequence(OperationQueryIndex, request, unsupported)
+	}
+	return r.sequence(OperationQueryIndex, request, querier.QueryIndex(ctx, index, value))
+}
+
+// NewTx starts a transaction committed by the wrapped client. Its Commit is recorded
+// with the operations of the transaction.
+func (r *Recorder) NewTx() *kvs.Tx {
+	return kvs.NewTx(r.commitTx)
+}
+
+// commitTx commits operations in a transaction of the wrapped client, and records the call.
+func (r *Recorder) commitTx(ctx context.Context, operations []kvs.TxOperation) error {
+	var err error = kvs.ErrUnsupported
+	if transactor, ok := r.client.(kvs.Transactor); ok {
+		tx := transactor.NewTx()
+		for _, operation := range operations {
+			switch operation.Type {
+			case kvs.TxPut:
+				tx.Put(operation.Key, operation.Item)
+			case kvs.TxDelete:
+				tx.Delete(operation.Key)
+			case kvs.TxConditionCheck:
+				tx.ConditionCheck(operation.Key, operation.Expected)
+			}
+		}
+		err = tx.Commit(ctx)
+	}
+	r.record(OperationCommit, Request{Tx: newTxOperations(operations)}, Response{}, err)
+	return err
+}
+
+// ContainerName returns the container name of the wrapped client.
+func (r *Recorder) ContainerName() string {
+	return r.client.ContainerName()
+}
+
+// sequence yields the items of items, and records the call with request once the
+// sequence ends: the items yielded until then, and the error, if any.
+func (r *Recorder) sequence(
+	operation string,
+	request Request,
+	items iter.Seq2[*kvs.Item, error],
+) iter.Seq2[*kvs.Item, error] {
+	return func(yield func(*kvs.Item, error) bool) {
+		var (
+			yielded []*Item
+			failure error
+		)
+		defer func() {
+			r.record(operation, request, Response{Items: yielded}, failure)
+		}()
+
+		for item, err := range items {
+			if err != nil {
+				failure = err
+			} else {
+				yielded = append(yielded, newItem(item))
+			}
+			if !yield(item, err) {
+				return
+			}
+		}
+	}
+}
+
+// unsupported is the sequence of the queries the wrapped client does not implement.
+func unsupported(yield func(*kvs.Item, error) bool) {
+	yield(nil, kvs.ErrUnsupported)
+}
+
+// record writes an interaction to the cassette.
+func (r *Recorder) record(operation string, request Request, response Response, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	interaction := Interaction{Operation: operation, Request: request, Response: response.withError(err)}
+	if encodeErr := r.encoder.Encode(interaction); encodeErr != nil && r.err == nil {
+		r.err = encodeErr
+	}
+}
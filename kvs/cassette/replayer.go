@@ -0,0 +1,415 @@
+// Package cassette records the calls to a kvs.LowLevelClient and replays them offline.
+package cassette
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// Matching selects how a Replayer matches the calls with the interactions of its cassette.
+type Matching int
+
+// Matching modes.
+const (
+	// MatchStrict replays the interactions in the recorded order: each call must have
+	// the operation and the arguments of the next interaction. The TTLs of items are
+	// left out, since they are absolute times that depend on when the call is made.
+	MatchStrict Matching = iota
+	// MatchLenient replays, for each call, the first interaction not replayed yet with
+	// the operation and the keys of the call, regardless of the order of the calls and
+	// of the other arguments (values, TTLs, deltas, ...). Once all of them have been
+	// replayed, the last one is replayed again, as for a retried or polled call.
+	MatchLenient
+)
+
+// Replayer is a kvs.LowLevelClient that serves the results recorded in a cassette,
+// without any backend. A call that matches no interaction fails with ErrNoInteraction.
+// It is also a kvs.Querier, a kvs.IndexQuerier and a kvs.Transactor.
+// Replayer is safe for concurrent use.
+type Replayer struct {
+	interactions  []Interaction
+	replayed      []bool
+	containerName string
+	last          map[string]int
+	next          int
+	matching      Matching
+	mu            sync.Mutex
+}
+
+// ReplayerOptions configures a Replayer. Used with the functional-options pattern.
+type ReplayerOptions func(*Replayer)
+
+// WithMatching returns a ReplayerOptions that sets the matching mode, MatchStrict by default.
+func WithMatching(matching Matching) ReplayerOptions {
+	return func(r *Replayer) { r.matching = matching }
+}
+
+// WithContainerName returns a ReplayerOptions that sets the name returned by
+// ContainerName, "cassette" by default.
+func WithContainerName(name string) ReplayerOptions {
+	return func(r *Replayer) { r.containerName = name }
+}
+
+// NewReplayer creates a new Replayer of the cassette read from reader.
+// Returns an error if a line of the cassette is not a valid Interaction.
+func NewReplayer(reader io.Reader, opts ...ReplayerOptions) (*Replayer, error) {
+	replayer := &Replayer{
+		containerName: "cassette",
+		last:          map[string]int{},
+	}
+	for _, opt := range opts {
+		opt(replayer)
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var interaction Interaction
+		if err := json.Unmarshal(scanner.Bytes(), &interaction); err != nil {
+			return nil, fmt.Errorf("cassette line %d: %w", line, err)
+		}
+		replayer.interactions = append(replayer.interactions, interaction)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	replayer.replayed = make([]bool, len(replayer.interactions))
+	return replayer, nil
+}
+
+// LoadFile creates a new Replayer of the cassette file at path.
+func LoadFile(path string, opts ...ReplayerOptions) (*Replayer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return NewReplayer(file, opts...)
+}
+
+// Remaining returns the number of interactions not replayed yet, so that tests can
+// check that every recorded call has been made.
+func (r *Replayer) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	remaining := 0
+	for _, replayed := range r.replayed {
+		if !replayed {
+			remaining++
+		}
+	}
+	return remaining
+}
+
+// Get retrieves an item by its key.
+func (r *Replayer) Get(key string) (*kvs.Item, error) {
+	return r.GetWithContext(context.Background(), key)
+}
+
+// GetWithContext replays a Get.
+func (r *Replayer) GetWithContext(_ context.Context, key string) (*kvs.Item, error) {
+	response, err := r.replay(OperationGet, Request{Key: key})
+	if err != nil {
+		return nil, err
+	}
+	return response.Item.item(), nil
+}
+
+// Save stores an item with the specified key.
+func (r *Replayer) Save(key string, item *kvs.Item) error {
+	return r.SaveWithContext(context.Background(), key, item)
+}
+
+// SaveWithContext replays a Save.
+func (r *Replayer) SaveWithContext(_ context.Context, key string, item *kvs.Item) error {
+	_, err := r.replay(OperationSave, Request{Key: key, Item: newItem(item)})
+	return err
+}
+
+// BulkGet retrieves multiple items by their keys.
+func (r *Replayer) BulkGet(keys []string) (*kvs.Items, error) {
+	return r.BulkGetWithContext(context.Background(), keys)
+}
+
+// BulkGetWithContext replays a BulkGet.
+func (r *Replayer) BulkGetWithContext(_ context.Context, keys []string) (*kvs.Items, error) {
+	response, err := r.replay(OperationBulkGet, Request{Keys: keys})
+	if err != nil {
+		return nil, err
+	}
+	return items(response.Items), nil
+}
+
+// BulkSave stores multiple items.
+func (r *Replayer) BulkSave(items *kvs.Items) error {
+	return r.BulkSaveWithContext(context.Background(), items)
+}
+
+// BulkSaveWithContext replays a BulkSave.
+func (r *Replayer) BulkSaveWithContext(_ context.Context, items *kvs.Items) error {
+	_, err := r.replay(OperationBulkSave, Request{Items: newItems(items)})
+	return err
+}
+
+// Delete removes the item stored under key.
+func (r *Replayer) Delete(key string) error {
+	return r.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext replays a Delete.
+func (r *Replayer) DeleteWithContext(_ context.Context, key string) error {
+	_, err := r.replay(OperationDelete, Request{Key: key})
+	return err
+}
+
+// SaveIfAbsent replays a SaveIfAbsent.
+func (r *Replayer) SaveIfAbsent(_ context.Context, key string, item *kvs.Item) error {
+	_, err := r.replay(OperationSaveIfAbsent, Request{Key: key, Item: newItem(item)})
+	return err
+}
+
+// CompareAndSwap replays a CompareAndSwap.
+func (r *Replayer) CompareAndSwap(_ context.Context, key string, expected, item *kvs.Item) error {
+	_, err := r.replay(OperationCompareAndSwap, Request{Key: key, Expected: newItem(expected), Item: newItem(item)})
+	return err
+}
+
+// CompareAndDelete replays a CompareAndDelete.
+func (r *Replayer) CompareAndDelete(_ context.Context, key string, expected *kvs.Item) error {
+	_, err := r.replay(OperationCompareAndDelete, Request{Key: key, Expected: newItem(expected)})
+	return err
+}
+
+// Increment replays an Increment.
+func (r *Replayer) Increment(_ context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	response, err := r.replay(
+		OperationIncrement,
+		Request{Key: key, Delta: json.Number(strconv.FormatInt(delta, 10)), TTL: ttl},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return response.Count.Int64()
+}
+
+// IncrementFloat replays an IncrementFloat.
+func (r *Replayer) IncrementFloat(_ context.Context, key string, delta float64, ttl time.Duration) (float64, error) {
+	response, err := r.replay(
+		OperationIncrementFloat,
+		Request{Key: key, Delta: json.Number(strconv.FormatFloat(delta, 'g', -1, 64)), TTL: ttl},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return response.Count.Float64()
+}
+
+// Patch replays a Patch.
+func (r *Replayer) Patch(_ context.Context, key string, updates map[string]any) error {
+	_, err := r.replay(OperationPatch, Request{Key: key, Updates: updates})
+	return err
+}
+
+// GetFields replays a GetFields.
+func (r *Replayer) GetFields(_ context.Context, key string, fields ...string) (*kvs.Item, error) {
+	response, err := r.replay(OperationGetFields, Request{Key: key, Fields: fields})
+	if err != nil {
+		return nil, err
+	}
+	return response.Item.item(), nil
+}
+
+// Scan replays a Scan: the recorded items, then the recorded error, if any.
+func (r *Replayer) Scan(_ context.Context, opts kvs.ScanOptions) iter.Seq2[*kvs.Item, error] {
+	return r.sequence(OperationScan, Request{Scan: &opts})
+}
+
+// Query replays a Query, like Scan.
+func (r *Replayer) Query(
+	_ context.Context,
+	partition string,
+	condition kvs.SortCondition,
+	opts kvs.QueryOptions,
+) iter.Seq2[*kvs.Item, error] {
+	return r.sequence(OperationQuery, Request{Query: &Query{Partition: partition, Condition: condition, Options: opts}})
+}
+
+// QueryPage replays a QueryPage.
+func (r *Replayer) QueryPage(
+	_ context.Context,
+	partition string,
+	condition kvs.SortCondition,
+	opts kvs.QueryOptions,
+) (*kvs.Items, string, error) {
+	response, err := r.replay(
+		OperationQueryPage,
+		Request{Query: &Query{Partition: partition, Condition: condition, Options: opts}},
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	return items(response.Items), response.Token, nil
+}
+
+// QueryIndex replays a QueryIndex, like Scan.
+func (r *Replayer) QueryIndex(_ context.Context, index, value string) iter.Seq2[*kvs.Item, error] {
+	return r.sequence(OperationQueryIndex, Request{Query: &Query{Index: index, Value: value}})
+}
+
+// NewTx starts a transaction whose Commit is replayed.
+func (r *Replayer) NewTx() *kvs.Tx {
+	return kvs.NewTx(func(_ context.Context, operations []kvs.TxOperation) error {
+		_, err := r.replay(OperationCommit, Request{Tx: newTxOperations(operations)})
+		return err
+	})
+}
+
+// sequence replays a call yielding items: the recorded items, then the recorded
+// error, if any.
+func (r *Replayer) sequence(operation string, request Request) iter.Seq2[*kvs.Item, error] {
+	return func(yield func(*kvs.Item, error) bool) {
+		response, err := r.replay(operation, request)
+		if err != nil && response.Error == "" {
+			yield(nil, err)
+			return
+		}
+
+		for _, item := range response.Items {
+			if !yield(item.item(), nil) {
+				return
+			}
+		}
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// ContainerName returns the name set by WithContainerName.
+func (r *Replayer) ContainerName() string {
+	return r.containerName
+}
+
+// replay finds the interaction matching a call, marks it replayed and returns its
+// response, along with the recorded error or ErrNoInteraction.
+func (r *Replayer) replay(operation string, request Request) (Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	index := -1
+	switch r.matching {
+	case MatchLenient:
+		index = r.lenientMatch(operation, request)
+	default:
+		if r.next < len(r.interactions) && r.strictMatch(r.interactions[r.next], operation, request) {
+			index = r.next
+			r.next++
+		}
+	}
+	if index < 0 {
+		return Response{}, fmt.Errorf("%w: %s %s", ErrNoInteraction, operation, describe(request))
+	}
+
+	r.replayed[index] = true
+	response := r.interactions[index].Response
+	return response, response.err()
+}
+
+// strictMatch reports whether interaction has operation and request.
+func (r *Replayer) strictMatch(interaction Interaction, operation string, request Request) bool {
+	return interaction.Operation == operation && normalize(interaction.Request) == normalize(request)
+}
+
+// lenientMatch returns the index of the first interaction not replayed yet with the
+// operation and keys of request, or else of the last one replayed, or -1.
+func (r *Replayer) lenientMatch(operation string, request Request) int {
+	signature := operation + " " + describe(request)
+	for i, interaction := range r.interactions {
+		if r.replayed[i] || interaction.Operation != operation || describe(interaction.Request) != describe(request) {
+			continue
+		}
+		r.last[signature] = i
+		return i
+	}
+	if i, found := r.last[signature]; found {
+		return i
+	}
+	return -1
+}
+
+// describe returns the keys of request, which identify it in lenient matching and errors.
+func describe(request Request) string {
+	keys := slices.Clone(request.Keys)
+	if request.Key != "" {
+		keys = append(keys, request.Key)
+	}
+	for _, item := range request.Items {
+		if item != nil {
+			keys = append(keys, item.Key)
+		}
+	}
+	if request.Scan != nil {
+		keys = append(keys, request.Scan.Prefix+"*")
+	}
+	if request.Query != nil && request.Query.Index != "" {
+		keys = append(keys, request.Query.Index+"="+request.Query.Value)
+	} else if request.Query != nil {
+		keys = append(keys, request.Query.Partition)
+	}
+	for _, operation := range request.Tx {
+		keys = append(keys, operation.Key)
+	}
+	return fmt.Sprintf("%q", keys)
+}
+
+// normalize returns the canonical JSON of request without the TTLs of its items, and
+// of its transaction items: objects are re-encoded with sorted keys, so that a request
+// read from a cassette equals the request of the same call, whenever it is made.
+func normalize(request Request) string {
+	request.Item, request.Expected = request.Item.withoutTTL(), request.Expected.withoutTTL()
+	if request.Items != nil {
+		items := make([]*Item, len(request.Items))
+		for i, item := range request.Items {
+			items[i] = item.withoutTTL()
+		}
+		request.Items = items
+	}
+	if request.Tx != nil {
+		operations := slices.Clone(request.Tx)
+		for i := range operations {
+			operations[i].Item, operations[i].Expected = operations[i].Item.withoutTTL(), operations[i].Expected.withoutTTL()
+		}
+		request.Tx = operations
+	}
+
+	encoded, err := json.Marshal(request)
+	if err != nil {
+		return ""
+	}
+
+	var decoded any
+	if err = json.Unmarshal(encoded, &decoded); err != nil {
+		return ""
+	}
+	encoded, _ = json.Marshal(decoded)
+	return string(encoded)
+}
@@ -0,0 +1,620 @@
+// Package chaos provides a kvs.LowLevelClient decorator that injects faults.
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// Error constants for injected faults.
+const (
+	// ErrInjected is the error injected by SetErrorRate when no error is given.
+	ErrInjected = kvs.KeyValueError("[kvs]: chaos failure injected")
+	// ErrThrottled mimics a backend rejecting a request over its throughput.
+	ErrThrottled = kvs.KeyValueError("[kvs]: chaos request throttled")
+	// ErrPartialWrite is returned by BulkSave when partial results left items unsaved,
+	// as when a backend leaves items of a batch unprocessed.
+	ErrPartialWrite = kvs.KeyValueError("[kvs]: chaos bulk save partially applied")
+)
+
+// ErrTimeout mimics a request timing out: it wraps context.DeadlineExceeded.
+var ErrTimeout = fmt.Errorf("[kvs]: chaos request timed out: %w", context.DeadlineExceeded)
+
+// Operation identifies the operations of kvs.LowLevelClient that faults apply to.
+// The variants with and without context are the same operation.
+type Operation string
+
+// Operations.
+const (
+	// AnyOperation applies a fault to every operation without a fault of its own.
+	AnyOperation Operation = ""
+	// OperationGet is Get and GetWithContext.
+	OperationGet Operation = "Get"
+	// OperationSave is Save and SaveWithContext.
+	OperationSave Operation = "Save"
+	// OperationBulkGet is BulkGet and BulkGetWithContext.
+	OperationBulkGet Operation = "BulkGet"
+	// OperationBulkSave is BulkSave and BulkSaveWithContext.
+	OperationBulkSave Operation = "BulkSave"
+	// OperationDelete is Delete and DeleteWithContext.
+	OperationDelete Operation = "Delete"
+	// OperationSaveIfAbsent is SaveIfAbsent.
+	OperationSaveIfAbsent Operation = "SaveIfAbsent"
+	// OperationCompareAndSwap is CompareAndSwap.
+	OperationCompareAndSwap Operation = "CompareAndSwap"
+	// OperationCompareAndDelete is CompareAndDelete.
+	OperationCompareAndDelete Operation = "CompareAndDelete"
+	// OperationIncrement is Increment and IncrementFloat.
+	OperationIncrement Operation = "Increment"
+	// OperationPatch is Patch.
+	OperationPatch Operation = "Patch"
+	// OperationGetFields is GetFields.
+	OperationGetFields Operation = "GetFields"
+	// OperationScan is Scan.
+	OperationScan Operation = "Scan"
+	// OperationQuery is Query and QueryPage.
+	OperationQuery Operation = "Query"
+	// OperationQueryIndex is QueryIndex.
+	OperationQueryIndex Operation = "QueryIndex"
+	// OperationCommit is the Commit of the transactions started by NewTx.
+	OperationCommit Operation = "Commit"
+)
+
+// errorFault is an error injected at a rate.
+type errorFault struct {
+	err  error
+	rate float64
+}
+
+// Client is a kvs.LowLevelClient that injects faults in the calls to the client it
+// wraps: latencies drawn from a Distribution, errors at a rate, partial bulk results
+// and slow-drip responses. It is also a kvs.Querier, a kvs.IndexQuerier and a
+// kvs.Transactor, whose calls fail with kvs.ErrUnsupported when the wrapped client
+// does not implement the interface. Faults are configured per Operation, or for AnyOperation,
+// and can be changed at runtime; a fault of an operation takes precedence over the
+// fault of the same kind of AnyOperation.
+//
+// Every random decision is drawn from a source seeded by WithSeed, so that a sequence
+// of calls made one at a time meets the same faults on every run. Client is safe for
+// concurrent use, but concurrent calls draw in an unspecified order.
+type Client struct {
+	client    kvs.LowLevelClient
+	rng       *rand.Rand
+	latencies map[Operation]Distribution
+	errors    map[Operation]errorFault
+	partials  map[Operation]float64
+	drips     map[Operation]time.Duration
+	mu        sync.Mutex
+	disabled  bool
+}
+
+// ClientOptions configures a Client. Used with the functional-options pattern.
+type ClientOptions func(*Client)
+
+// WithSeed returns a ClientOptions that seeds the random source of the Client.
+// Without it, the source is seeded randomly.
+func WithSeed(seed uint64) ClientOptions {
+	return func(c *Client) { c.rng = rand.New(rand.NewPCG(seed, seed)) }
+}
+
+// WithLatency returns a ClientOptions that adds the latencies drawn from latency to
+// the calls of operations. See Client.SetLatency.
+func WithLatency(latency Distribution, operations ...Operation) ClientOptions {
+	return func(c *Client) { c.SetLatency(latency, operations...) }
+}
+
+// WithErrorRate returns a ClientOptions that fails a fraction rate of the calls of
+// operations with err. See Client.SetErrorRate.
+func WithErrorRate(rate float64, err error, operations ...Operation) ClientOptions {
+	return func(c *Client) { c.SetErrorRate(rate, err, operations...) }
+}
+
+// WithPartialResults returns a ClientOptions that drops a fraction rate of the items of
+// the bulk operations and scans. See Client.SetPartialResults.
+func WithPartialResults(rate float64, operations ...Operation) ClientOptions {
+	return func(c *Client) { c.SetPartialResults(rate, operations...) }
+}
+
+// WithSlowDrip returns a ClientOptions that delays each item read by interval.
+// See Client.SetSlowDrip.
+func WithSlowDrip(interval time.Duration, operations ...Operation) ClientOptions {
+	return func(c *Client) { c.SetSlowDrip(interval, operations...) }
+}
+
+// NewClient creates a new Client injecting faults in the calls to client.
+func NewClient(client kvs.LowLevelClient, opts ...ClientOptions) *Client {
+	seed := rand.Uint64()
+	chaos := &Client{
+		client:    client,
+		rng:       rand.New(rand.NewPCG(seed, seed)),
+		latencies: map[Operation]Distribution{},
+		errors:    map[Operation]errorFault{},
+		partials:  map[Operation]float64{},
+		drips:     map[Operation]time.Duration{},
+	}
+	for _, opt := range opts {
+		opt(chaos)
+	}
+	return chaos
+}
+
+// SetLatency adds the latencies drawn from latency to the calls of operations, or of
+// AnyOperation when none is given, before they reach the wrapped client. A nil latency
+// removes the delay. A call whose context ends while it is delayed fails with the
+// context error.
+func (r *Client) SetLatency(latency Distribution, operations ...Operation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, operation := range orAny(operations) {
+		r.latencies[operation] = latency
+	}
+}
+
+// SetErrorRate fails a fraction rate, between 0 and 1, of the calls of operations, or
+// of AnyOperation when none is given, with err (ErrInjected when nil) without reaching
+// the wrapped client. Realistic errors are ErrThrottled, ErrTimeout, kvs.ErrKeyNotFound
+// for reads and kvs.ErrConditionFailed for conditional writes. A zero rate exempts
+// operations from the errors of AnyOperation.
+func (r *Client) SetErrorRate(rate float64, err error, operations ...Operation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err == nil {
+		err = ErrInjected
+	}
+	for _, operation := range orAny(operations) {
+		r.errors[operation] = errorFault{err: err, rate: rate}
+	}
+}
+
+// SetPartialResults drops each item of the calls of operations, or of AnyOperation
+// when none is given, with probability rate. It applies to BulkGet, Scan, Query,
+// QueryPage and QueryIndex, which leave the dropped items out, and to BulkSave, which
+// does not save them and returns ErrPartialWrite.
+func (r *Client) SetPartialResults(rate float64, operations ...Operation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, operation := range orAny(operations) {
+		r.partials[operation] = rate
+	}
+}
+
+// SetSlowDrip delays each item read by the calls of operations, or of AnyOperation
+// when none is given, by interval: BulkGet and QueryPage return after one interval per
+// item, and Scan, Query and QueryIndex yield one item per interval.
+func (r *Client) SetSlowDrip(interval time.Duration, operations ...Operation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, operation := range orAny(operations) {
+		r.drips[operation] = interval
+	}
+}
+
+// Disable stops injecting faults, until Enable. The faults are kept.
+func (r *Client) Disable() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.disabled = true
+}
+
+// Enable resumes injecting faults after Disable.
+func (r *Client) Enable() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.disabled = false
+}
+
+// Reset removes every fault.
+func (r *Client) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.latencies)
+	clear(r.errors)
+	clear(r.partials)
+	clear(r.drips)
+}
+
+// Get retrieves an item by its key.
+func (r *Client) Get(key string) (*kvs.Item, error) {
+	return r.GetWithContext(context.Background(), key)
+}
+
+// GetWithContext retrieves an item by its key, after the faults of OperationGet.
+func (r *Client) GetWithContext(ctx context.Context, key string) (*kvs.Item, error) {
+	if err := r.inject(ctx, OperationGet); err != nil {
+		return nil, err
+	}
+	return r.client.GetWithContext(ctx, key)
+}
+
+// Save stores an item with the specified key.
+func (r *Client) Save(key string, item *kvs.Item) error {
+	return r.SaveWithContext(context.Background(), key, item)
+}
+
+// SaveWithContext stores an item with the specified key, after the faults of OperationSave.
+func (r *Client) SaveWithContext(ctx context.Context, key string, item *kvs.Item) error {
+	if err := r.inject(ctx, OperationSave); err != nil {
+		return err
+	}
+	return r.client.SaveWithContext(ctx, key, item)
+}
+
+// BulkGet retrieves multiple items by their keys.
+func (r *Client) BulkGet(keys []string) (*kvs.Items, error) {
+	return r.BulkGetWithContext(context.Background(), keys)
+}
+
+// BulkGetWithContext retrieves multiple items by their keys, after the faults of
+// OperationBulkGet. Partial results leave items out, and slow drip delays each item.
+func (r *Client) BulkGetWithContext(ctx context.Context, keys []string) (*kvs.Items, error) {
+	if err := r.inject(ctx, OperationBulkGet); err != nil {
+		return nil, err
+	}
+
+	items, err := r.client.BulkGetWithContext(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	drip := r.drip(OperationBulkGet)
+	kept := new(kvs.Items)
+	for item := range items.All() {
+		if r.dropped(OperationBulkGet) {
+			continue
+		}
+		if err = sleep(ctx, drip); err != nil {
+			return nil, err
+		}
+		kept.Add(item)
+	}
+	return kept, nil
+}
+
+// BulkSave stores multiple items.
+func (r *Client) BulkSave(items *kvs.Items) error {
+	return r.BulkSaveWithContext(context.Background(), items)
+}
+
+// BulkSaveWithContext stores multiple items, after the faults of OperationBulkSave.
+// Returns ErrPartialWrite when partial results left items unsaved.
+func (r *Client) BulkSaveWithContext(ctx context.Context, items *kvs.Items) error {
+	if err := r.inject(ctx, OperationBulkSave); err != nil {
+		return err
+	}
+	if items == nil {
+		return r.client.BulkSaveWithContext(ctx, items)
+	}
+
+	kept := new(kvs.Items)
+	for item := range items.All() {
+		if !r.dropped(OperationBulkSave) {
+			kept.Add(item)
+		}
+	}
+	if err := r.client.BulkSaveWithContext(ctx, kept); err != nil {
+		return err
+	}
+	if kept.Len() < items.Len() {
+		return ErrPartialWrite
+	}
+	return nil
+}
+
+// Delete removes the item stored under key.
+func (r *Client) Delete(key string) error {
+	return r.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext removes the item stored under key, after the faults of OperationDelete.
+func (r *Client) DeleteWithContext(ctx context.Context, key string) error {
+	if err := r.inject(ctx, OperationDelete); err != nil {
+		return err
+	}
+	return r.client.DeleteWithContext(ctx, key)
+}
+
+// SaveIfAbsent stores an item only if the key is not taken, after the faults of
+// OperationSaveIfAbsent.
+func (r *Client) SaveIfAbsent(ctx context.Context, key string, item *kvs.Item) error {
+	if err := r.inject(ctx, OperationSaveIfAbsent); err != nil {
+		return err
+	}
+	return r.client.SaveIfAbsent(ctx, key, item)
+}
+
+// CompareAndSwap replaces the item stored under key only if it still matches expected,
+// after the faults of OperationCompareAndSwap.
+func (r *Client) CompareAndSwap(ctx context.Context, key string, expected, item *kvs.Item) error {
+	if err := r.inject(ctx, OperationCompareAndSwap); err != nil {
+		return err
+	}
+	return r.client.CompareAndSwap(ctx, key, expected, item)
+}
+
+// CompareAndDelete removes the item stored under key only if it still matches expected,
+// after the faults of OperationCompareAndDelete.
+func (r *Client) CompareAndDelete(ctx context.Context, key string, expected *kvs.Item) error {
+	if err := r.inject(ctx, OperationCompareAndDelete); err != nil {
+		return err
+	}
+	return r.client.CompareAndDelete(ctx, key, expected)
+}
+
+// Increment adds delta to the integer counter stored under key, after the faults of
+// OperationIncrement.
+func (r *Client) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	if err := r.inject(ctx, OperationIncrement); err != nil {
+		return 0, err
+	}
+	return r.client.Increment(ctx, key, delta, ttl)
+}
+
+// IncrementFloat adds delta to the floating-point counter stored under key, after the
+// faults of OperationIncrement.
+func (r *Client) IncrementFloat(ctx context.Context, key string, delta float64, ttl time.Duration) (float64, error) {
+	if err := r.inject(ctx, OperationIncrement); err != nil {
+		return 0, err
+	}
+	return r.client.IncrementFloat(ctx, key, delta, ttl)
+}
+
+// Patch updates fields of the JSON object stored under key, after the faults of
+// OperationPatch.
+func (r *Client) Patch(ctx context.Context, key string, updates map[string]any) error {
+	if err := r.inject(ctx, OperationPatch); err != nil {
+		return err
+	}
+	return r.client.Patch(ctx, key, updates)
+}
+
+// GetFields retrieves fields of the JSON object stored under key, after the faults of
+// OperationGetFields.
+func (r *Client) GetFields(ctx context.Context, key string, fields ...string) (*kvs.Item, error) {
+	if err := r.inject(ctx, OperationGetFields); err != nil {
+		return nil, err
+	}
+	return r.client.GetFields(ctx, key, fields...)
+}
+
+// Scan enumerates the items stored in the container, after the faults of OperationScan.
+// Partial results leave items out, and slow drip delays each item.
+func (r *Client) Scan(ctx context.Context, opts kvs.ScanOptions) iter.Seq2[*kvs.Item, error] {
+	return r.sequence(ctx, OperationScan, func() iter.Seq2[*kvs.Item, error] {
+		return r.client.Scan(ctx, opts)
+	})
+}
+
+// Query enumerates the items of a partition, after the faults of OperationQuery.
+// Partial results leave items out, and slow drip delays each item. Yields
+// kvs.ErrUnsupported if the wrapped client is not a kvs.Querier.
+func (r *Client) Query(
+	ctx context.Context,
+	partition string,
+	condition kvs.SortCondition,
+	opts kvs.QueryOptions,
+) iter.Seq2[*kvs.Item, error] {
+	return r.sequence(ctx, OperationQuery, func() iter.Seq2[*kvs.Item, error] {
+		querier, ok := r.client.(kvs.Querier)
+		if !ok {
+			return func(yield func(*kvs.Item, error) bool) {
+				yield(nil, kvs.ErrUnsupported)
+			}
+		}
+		return querier.Query(ctx, partition, condition, opts)
+	})
+}
+
+// QueryPage returns a page of the items of a partition, after the faults of
+// OperationQuery. Partial results leave items out, and slow drip delays each item.
+// Returns kvs.ErrUnsupported if the wrapped client is not a kvs.Querier.
+func (r *Client) QueryPage(
+	ctx context.Context,
+	partition string,
+	condition kvs.SortCondition,
+	opts kvs.QueryOptions,
+) (*kvs.Items, string, error) {
+	if err := r.inject(ctx, OperationQuery); err != nil {
+		return nil, "", err
+	}
+	querier, ok := r.client.(kvs.Querier)
+	if !ok {
+		return nil, "", kvs.ErrUnsupported
+	}
+
+	items, token, err := querier.QueryPage(ctx, partition, condition, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	drip := r.drip(OperationQuery)
+	kept := new(kvs.Items)
+	for item := range items.All() {
+		if r.dropped(OperationQuery) {
+			continue
+		}
+		if err = sleep(ctx, drip); err != nil {
+			return nil, "", err
+		}
+		kept.Add(item)
+	}
+	return kept, token, nil
+}
+
+// QueryIndex enumerates the items of a secondary index value, after the faults of
+// OperationQueryIndex. Partial results leave items out, and slow drip delays each item.
+// Yields kvs.ErrUnsupported if the wrapped client is not a kvs.IndexQuerier.
+func (r *Client) QueryIndex(ctx context.Context, index, value string) iter.Seq2[*kvs.Item, error] {
+	return r.sequence(ctx, OperationQueryIndex, func() iter.Seq2[*kvs.Item, error] {
+		querier, ok := r.client.(kvs.IndexQuerier)
+		if !ok {
+			return func(yield func(*kvs.Item, error) bool) {
+				yield(nil, kvs.ErrUnsupported)
+			}
+		}
+		return querier.QueryIndex(ctx, index, value)
+	})
+}
+
+// NewTx starts a transaction committed by the wrapped client, after the faults of
+// OperationCommit. Commit returns kvs.ErrUnsupported if the wrapped client is not a
+// kvs.Transactor.
+func (r *Client) NewTx() *kvs.Tx {
+	return kvs.NewTx(r.commitTx)
+}
+
+// commitTx commits operations in a transaction of the wrapped client, after the
+// faults of OperationCommit.
+func (r *Client) commitTx(ctx context.Context, operations []kvs.TxOperation) error {
+	if err := r.inject(ctx, OperationCommit); err != nil {
+		return err
+	}
+	transactor, ok := r.client.(kvs.Transactor)
+	if !ok {
+		return kvs.ErrUnsupported
+	}
+
+	tx := transactor.NewTx()
+	for _, operation := range operations {
+		switch operation.Type {
+		case kvs.TxPut:
+			tx.Put(operation.Key, operation.Item)
+		case kvs.TxDelete:
+			tx.Delete(operation.Key)
+		case kvs.TxConditionCheck:
+			tx.ConditionCheck(operation.Key, operation.Expected)
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// ContainerName returns the container name of the wrapped client.
+func (r *Client) ContainerName() string {
+	return r.client.ContainerName()
+}
+
+// sequence yields the items of the sequence returned by items, after the faults of
+// operation. Partial results leave items out, and slow drip delays each item.
+func (r *Client) sequence(
+	ctx context.Context,
+	operation Operation,
+	items func() iter.Seq2[*kvs.Item, error],
+) iter.Seq2[*kvs.Item, error] {
+	return func(yield func(*kvs.Item, error) bool) {
+		if err := r.inject(ctx, operation); err != nil {
+			yield(nil, err)
+			return
+		}
+
+		drip := r.drip(operation)
+		for item, err := range items() {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if r.dropped(operation) {
+				continue
+			}
+			if err = sleep(ctx, drip); err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
+
+// inject applies the latency of operation, then fails it at its error rate.
+func (r *Client) inject(ctx context.Context, operation Operation) error {
+	r.mu.Lock()
+	if r.disabled {
+		r.mu.Unlock()
+		return nil
+	}
+	var latency time.Duration
+	if distribution := lookup(r.latencies, operation); distribution != nil {
+		latency = distribution(r.rng)
+	}
+	fault := lookup(r.errors, operation)
+	failed := r.chance(fault.rate)
+	r.mu.Unlock()
+
+	if err := sleep(ctx, latency); err != nil {
+		return err
+	}
+	if failed {
+		return fault.err
+	}
+	return nil
+}
+
+// dropped reports whether partial results drop an item of operation.
+func (r *Client) dropped(operation Operation) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return !r.disabled && r.chance(lookup(r.partials, operation))
+}
+
+// drip returns the slow-drip interval of operation.
+func (r *Client) drip(operation Operation) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.disabled {
+		return 0
+	}
+	return lookup(r.drips, operation)
+}
+
+// chance draws whether an event of probability rate happens. Draws nothing when the
+// rate is zero, so that unused faults leave the sequence of draws unchanged.
+func (r *Client) chance(rate float64) bool {
+	return rate > 0 && r.rng.Float64() < rate
+}
+
+// lookup returns the fault of operation, or else the fault of AnyOperation.
+func lookup[T any](faults map[Operation]T, operation Operation) T {
+	if fault, found := faults[operation]; found {
+		return fault
+	}
+	return faults[AnyOperation]
+}
+
+// orAny returns operations, or AnyOperation when none is given.
+func orAny(operations []Operation) []Operation {
+	if len(operations) == 0 {
+		return []Operation{AnyOperation}
+	}
+	return operations
+}
+
+// sleep waits for d, and returns the error of ctx if it ends first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,252 @@
+package chaos_test
+
+import (
+	"context"
+	"math/rand/v2"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	"github.com/arielsrv/go-kvs-client/kvs/chaos"
+	"github.com/arielsrv/go-kvs-client/kvs/dynamodb"
+	"github.com/arielsrv/go-kvs-client/kvs/kvstest"
+	kvsredis "github.com/arielsrv/go-kvs-client/kvs/redis"
+)
+
+func newClient(t *testing.T, opts ...chaos.ClientOptions) *chaos.Client {
+	t.Helper()
+	llc := kvsredis.NewBuilder(kvsredis.WithKeyPrefix("__kvs:users")).FakeBuild()
+	for i := range 10 {
+		key := strconv.Itoa(i)
+		require.NoError(t, llc.Save(key, kvs.NewItem(key, i)))
+	}
+	return chaos.NewClient(llc, opts...)
+}
+
+func keys(count int) []string {
+	keys := make([]string, count)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+	return keys
+}
+
+func TestClient_WithoutFaults_Conforms(t *testing.T) {
+	kvstest.RunConformance(t, func(t *testing.T, ttl time.Duration) kvstest.Backend {
+		clock := kvs.NewFakeClock(time.Unix(1_700_000_000, 0))
+		return kvstest.Backend{
+			Client:  chaos.NewClient(kvsredis.NewBuilder(kvsredis.WithTTL(ttl), kvsredis.WithClock(clock)).FakeBuild()),
+			Now:     clock.Now,
+			Advance: clock.Advance,
+		}
+	})
+}
+
+func TestClient_ErrorRate_PerOperation(t *testing.T) {
+	client := newClient(t, chaos.WithErrorRate(1, chaos.ErrThrottled, chaos.OperationGet))
+
+	_, err := client.Get("1")
+	require.ErrorIs(t, err, chaos.ErrThrottled)
+	require.NoError(t, client.Save("1", kvs.NewItem("1", 1)))
+
+	client.SetErrorRate(1, nil)
+	require.ErrorIs(t, client.Delete("1"), chaos.ErrInjected)
+	_, err = client.Get("1")
+	require.ErrorIs(t, err, chaos.ErrThrottled, "the fault of the operation takes precedence")
+
+	client.SetErrorRate(0, nil, chaos.OperationDelete)
+	require.NoError(t, client.Delete("1"))
+
+	client.SetErrorRate(1, chaos.ErrTimeout, chaos.OperationIncrement)
+	_, err = client.Increment(t.Context(), "counter", 1, 0)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	client.SetErrorRate(1, kvs.ErrKeyNotFound, chaos.OperationScan)
+	for _, err = range client.Scan(t.Context(), kvs.ScanOptions{}) {
+		require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+	}
+}
+
+func TestClient_ErrorRate_DeterministicUnderSeed(t *testing.T) {
+	failures := func() []bool {
+		client := newClient(t, chaos.WithSeed(42), chaos.WithErrorRate(0.5, nil, chaos.OperationGet))
+		var failed []bool
+		for range 50 {
+			_, err := client.Get("1")
+			failed = append(failed, err != nil)
+		}
+		return failed
+	}
+
+	first := failures()
+	require.Equal(t, first, failures())
+	require.Contains(t, first, true)
+	require.Contains(t, first, false)
+}
+
+func TestClient_Latency(t *testing.T) {
+	client := newClient(t, chaos.WithLatency(chaos.Fixed(20*time.Millisecond), chaos.OperationGet))
+
+	start := time.Now()
+	_, err := client.Get("1")
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(t.Context(), time.Millisecond)
+	defer cancel()
+	_, err = client.GetWithContext(ctx, "1")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClient_PartialResults(t *testing.T) {
+	client := newClient(t, chaos.WithSeed(7), chaos.WithPartialResults(0.5, chaos.OperationBulkGet))
+
+	items, err := client.BulkGet(keys(10))
+	require.NoError(t, err)
+	require.Positive(t, items.Len())
+	require.Less(t, items.Len(), 10)
+
+	client.SetPartialResults(1, chaos.OperationBulkSave)
+	saved := new(kvs.Items)
+	saved.Add(kvs.NewItem("new", 1))
+	require.ErrorIs(t, client.BulkSave(saved), chaos.ErrPartialWrite)
+	_, err = client.Get("new")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+
+	client.SetPartialResults(1, chaos.OperationScan)
+	count := 0
+	for _, err = range client.Scan(t.Context(), kvs.ScanOptions{}) {
+		require.NoError(t, err)
+		count++
+	}
+	require.Zero(t, count)
+}
+
+func TestClient_SlowDrip(t *testing.T) {
+	client := newClient(t, chaos.WithSlowDrip(10*time.Millisecond))
+
+	start := time.Now()
+	items, err := client.BulkGet(keys(3))
+	require.NoError(t, err)
+	require.Equal(t, 3, items.Len())
+	require.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 25*time.Millisecond)
+	defer cancel()
+	count := 0
+	for _, err = range client.Scan(ctx, kvs.ScanOptions{}) {
+		if err != nil {
+			break
+		}
+		count++
+	}
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, count, 10)
+}
+
+func TestClient_RuntimeControl(t *testing.T) {
+	client := newClient(t, chaos.WithErrorRate(1, nil))
+
+	_, err := client.Get("1")
+	require.ErrorIs(t, err, chaos.ErrInjected)
+
+	client.Disable()
+	_, err = client.Get("1")
+	require.NoError(t, err)
+
+	client.Enable()
+	_, err = client.Get("1")
+	require.ErrorIs(t, err, chaos.ErrInjected)
+
+	client.Reset()
+	_, err = client.Get("1")
+	require.NoError(t, err)
+	require.Equal(t, "__kvs:users", client.ContainerName())
+}
+
+func TestClient_Queries_Forwarded(t *testing.T) {
+	ctx := t.Context()
+	byName := dynamodb.NewSecondaryIndex("by-name", "gsi_name", func(value string) string { return value })
+	client := chaos.NewClient(dynamodb.NewBuilder(
+		dynamodb.WithContainerName("__kvs-test"),
+		dynamodb.WithSecondaryIndexes(byName),
+	).FakeBuild())
+	require.NoError(t, client.Save("1", kvs.NewItem("1", "John")))
+
+	var queried []string
+	for item, err := range client.Query(ctx, "1", kvs.SortCondition{}, kvs.QueryOptions{}) {
+		require.NoError(t, err)
+		queried = append(queried, item.Key)
+	}
+	require.Equal(t, []string{"1"}, queried)
+	page, _, err := client.QueryPage(ctx, "1", kvs.SortCondition{}, kvs.QueryOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 1, page.Len())
+	for item, err := range client.QueryIndex(ctx, "by-name", "John") {
+		require.NoError(t, err)
+		require.Equal(t, "1", item.Key)
+	}
+
+	client.SetErrorRate(1, chaos.ErrThrottled, chaos.OperationQuery, chaos.OperationQueryIndex)
+	_, _, err = client.QueryPage(ctx, "1", kvs.SortCondition{}, kvs.QueryOptions{})
+	require.ErrorIs(t, err, chaos.ErrThrottled)
+	for _, err = range client.QueryIndex(ctx, "by-name", "John") {
+		require.ErrorIs(t, err, chaos.ErrThrottled)
+	}
+
+	unsupported := newClient(t)
+	for _, err = range unsupported.Query(ctx, "1", kvs.SortCondition{}, kvs.QueryOptions{}) {
+		require.ErrorIs(t, err, kvs.ErrUnsupported)
+	}
+	_, _, err = unsupported.QueryPage(ctx, "1", kvs.SortCondition{}, kvs.QueryOptions{})
+	require.ErrorIs(t, err, kvs.ErrUnsupported)
+	for _, err = range unsupported.QueryIndex(ctx, "by-name", "John") {
+		require.ErrorIs(t, err, kvs.ErrUnsupported)
+	}
+}
+
+func TestClient_NewTx_Forwarded(t *testing.T) {
+	ctx := t.Context()
+	client := newClient(t)
+
+	current, err := client.Get("1")
+	require.NoError(t, err)
+	require.NoError(t, client.NewTx().
+		ConditionCheck("1", current).
+		Put("11", kvs.NewItem("11", 11)).
+		Delete("2").
+		Commit(ctx))
+	_, err = client.Get("11")
+	require.NoError(t, err)
+	_, err = client.Get("2")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+
+	err = client.NewTx().ConditionCheck("11", nil).Put("12", kvs.NewItem("12", 12)).Commit(ctx)
+	require.ErrorIs(t, err, kvs.ErrConditionFailed)
+
+	client.SetErrorRate(1, chaos.ErrTimeout, chaos.OperationCommit)
+	err = client.NewTx().Delete("3").Commit(ctx)
+	require.ErrorIs(t, err, chaos.ErrTimeout)
+	_, err = client.Get("3")
+	require.NoError(t, err)
+}
+
+func TestDistributions(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 1))
+
+	require.Equal(t, time.Second, chaos.Fixed(time.Second)(rng))
+	require.Equal(t, time.Second, chaos.Uniform(time.Second, time.Second)(rng))
+	spikes := chaos.Spikes(chaos.Fixed(time.Millisecond), 1, chaos.Fixed(time.Second))
+	require.Equal(t, time.Second, spikes(rng))
+
+	for range 1000 {
+		uniform := chaos.Uniform(time.Millisecond, 2*time.Millisecond)(rng)
+		require.GreaterOrEqual(t, uniform, time.Millisecond)
+		require.Less(t, uniform, 2*time.Millisecond)
+		require.GreaterOrEqual(t, chaos.Normal(time.Millisecond, 10*time.Millisecond)(rng), time.Duration(0))
+		require.GreaterOrEqual(t, chaos.Exponential(time.Millisecond)(rng), time.Duration(0))
+	}
+}
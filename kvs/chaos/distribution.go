@@ -0,0 +1,57 @@
+// Package chaos provides a kvs.LowLevelClient decorator that injects faults.
+package chaos
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Distribution draws latencies from rng. Distributions are called with the
+// random source of the Client, so that the latencies are reproducible under a
+// seed. A non-positive latency adds no delay.
+type Distribution func(rng *rand.Rand) time.Duration
+
+// Fixed returns a Distribution that always returns latency.
+func Fixed(latency time.Duration) Distribution {
+	return func(*rand.Rand) time.Duration {
+		return latency
+	}
+}
+
+// Uniform returns a Distribution of latencies spread evenly in [minimum, maximum).
+func Uniform(minimum, maximum time.Duration) Distribution {
+	return func(rng *rand.Rand) time.Duration {
+		if maximum <= minimum {
+			return minimum
+		}
+		return minimum + time.Duration(rng.Int64N(int64(maximum-minimum)))
+	}
+}
+
+// Normal returns a Distribution of latencies normally distributed around mean,
+// with the given standard deviation. Negative samples are clamped to zero.
+func Normal(mean, stddev time.Duration) Distribution {
+	return func(rng *rand.Rand) time.Duration {
+		return max(0, mean+time.Duration(rng.NormFloat64()*float64(stddev)))
+	}
+}
+
+// Exponential returns a Distribution of exponentially distributed latencies with
+// the given mean: most calls are fast and a few are very slow, as the tail
+// latencies of a loaded backend.
+func Exponential(mean time.Duration) Distribution {
+	return func(rng *rand.Rand) time.Duration {
+		return time.Duration(rng.ExpFloat64() * float64(mean))
+	}
+}
+
+// Spikes returns a Distribution drawing from base, except for a fraction rate of
+// the calls drawn from spike, such as a garbage collection pause or a failover.
+func Spikes(base Distribution, rate float64, spike Distribution) Distribution {
+	return func(rng *rand.Rand) time.Duration {
+		if rng.Float64() < rate {
+			return spike(rng)
+		}
+		return base(rng)
+	}
+}
@@ -0,0 +1,39 @@
+// Package chaos provides a kvs.LowLevelClient decorator that injects faults.
+//
+// A Client wraps any kvs.LowLevelClient, such as a FakeBuild client in tests or a
+// real backend in a staging environment, so that the degradation paths of a
+// service (retries, fallbacks, timeouts, circuit breakers) can be exercised
+// without breaking real infrastructure.
+//
+// Key Components:
+//   - Client: the decorator. Faults are set per Operation or for AnyOperation,
+//     with options at construction or with setters at runtime, and are paused
+//     with Disable or removed with Reset.
+//   - Latency: delays drawn from a Distribution (Fixed, Uniform, Normal,
+//     Exponential, Spikes). Delays honour the context of the call.
+//   - Errors: a fraction of the calls fail with a given error, such as
+//     ErrThrottled, ErrTimeout or kvs.ErrKeyNotFound.
+//   - Partial results: BulkGet, Scan and the queries drop items, BulkSave
+//     leaves items unsaved and returns ErrPartialWrite.
+//   - Slow drip: BulkGet, Scan and the queries deliver their items one interval
+//     at a time.
+//   - Queries and transactions: Client forwards kvs.Querier, kvs.IndexQuerier
+//     and kvs.Transactor to the wrapped client, and returns kvs.ErrUnsupported
+//     when it does not implement them.
+//
+// Every random decision is drawn from a source seeded by WithSeed, so that a
+// test meets the same faults on every run.
+//
+// Usage:
+//
+//	client := chaos.NewClient(
+//	    redis.NewBuilder(redis.WithKeyPrefix("__kvs:users")).FakeBuild(),
+//	    chaos.WithSeed(42),
+//	    chaos.WithLatency(chaos.Exponential(20*time.Millisecond)),
+//	    chaos.WithErrorRate(0.1, chaos.ErrThrottled, chaos.OperationGet),
+//	)
+//	users := kvs.NewKVSClient[UserDTO](client)
+//
+//	client.SetErrorRate(1, chaos.ErrTimeout) // the backend goes down
+//	client.Reset()                           // and recovers
+package chaos
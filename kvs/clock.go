@@ -0,0 +1,66 @@
+// Package kvs provides a generic key-value store client interface and implementation.
+package kvs
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the current time. The clients and backends read the time through a Clock
+// to compute and check TTLs, so that tests can control it with a FakeClock.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// ClockFunc adapts a function such as time.Now to the Clock interface.
+type ClockFunc func() time.Time
+
+// Now returns the time returned by the function.
+func (r ClockFunc) Now() time.Time {
+	return r()
+}
+
+// SystemClock is the Clock of the system, used when none is configured.
+var SystemClock Clock = ClockFunc(time.Now)
+
+// FakeClock is a Clock that only moves when told to, for deterministic tests of TTL
+// expiry, leases and cache ages. It is safe for concurrent use.
+type FakeClock struct {
+	now time.Time
+	mu  sync.Mutex
+}
+
+// NewFakeClock creates a new FakeClock set to now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the time of the clock.
+func (r *FakeClock) Now() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.now
+}
+
+// Advance moves the clock forward by d.
+func (r *FakeClock) Advance(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.now = r.now.Add(d)
+}
+
+// Set sets the time of the clock, which may move it backward.
+func (r *FakeClock) Set(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.now = now
+}
+
+// clockOrSystem returns clock, or SystemClock when clock is nil.
+func clockOrSystem(clock Clock) Clock {
+	if clock == nil {
+		return SystemClock
+	}
+	return clock
+}
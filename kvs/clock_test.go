@@ -0,0 +1,75 @@
+package kvs_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	"github.com/arielsrv/go-kvs-client/kvs/dynamodb"
+	"github.com/arielsrv/go-kvs-client/kvs/model"
+	kvsredis "github.com/arielsrv/go-kvs-client/kvs/redis"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	clock := kvs.NewFakeClock(start)
+	require.Equal(t, start, clock.Now())
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() { clock.Advance(time.Second) })
+	}
+	wg.Wait()
+	require.Equal(t, start.Add(10*time.Second), clock.Now())
+
+	clock.Set(start)
+	require.Equal(t, start, clock.Now())
+	require.Equal(t, start, kvs.ClockFunc(clock.Now).Now())
+	require.WithinDuration(t, time.Now(), kvs.SystemClock.Now(), time.Second)
+}
+
+func TestNewItemWithClock(t *testing.T) {
+	clock := kvs.NewFakeClock(time.Unix(1_700_000_000, 0))
+
+	require.Equal(t, int64(1_700_000_060), kvs.NewItemWithClock(clock, "key", 1, time.Minute).TTL)
+	require.Zero(t, kvs.NewItemWithClock(clock, "key", 1).TTL)
+	require.NotZero(t, kvs.NewItemWithClock(nil, "key", 1, time.Minute).TTL)
+}
+
+func TestKVSClient_WithClock_ExpiresItems(t *testing.T) {
+	for name, build := range map[string]func(clock kvs.Clock) kvs.LowLevelClient{
+		"redis": func(clock kvs.Clock) kvs.LowLevelClient {
+			return kvsredis.NewBuilder(kvsredis.WithStorageMode(kvsredis.StorageHash), kvsredis.WithClock(clock)).FakeBuild()
+		},
+		"dynamodb": func(clock kvs.Clock) kvs.LowLevelClient {
+			return dynamodb.NewBuilder(dynamodb.WithContainerName("__kvs-test"), dynamodb.WithClock(clock)).FakeBuild()
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			clock := kvs.NewFakeClock(time.Unix(1_700_000_000, 0))
+			lowLevelClient := build(clock)
+			client := kvs.NewKVSClient[model.UserDTO](lowLevelClient, kvs.WithClock(clock))
+			ctx := t.Context()
+
+			require.NoError(t, client.Save("1", &model.UserDTO{ID: 1}, time.Minute))
+			require.NoError(t, client.SaveWithOptions(ctx, "2", &model.UserDTO{ID: 2}, kvs.WithTTL(time.Hour)))
+			item, err := lowLevelClient.Get("1")
+			require.NoError(t, err)
+			require.Equal(t, clock.Now().Add(time.Minute).Unix(), item.TTL)
+
+			clock.Advance(time.Minute)
+			_, err = client.Get("1")
+			require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+			user, err := client.Get("2")
+			require.NoError(t, err)
+			require.Equal(t, 2, user.ID)
+
+			clock.Advance(time.Hour)
+			_, err = client.Get("2")
+			require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+		})
+	}
+}
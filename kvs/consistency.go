@@ -0,0 +1,22 @@
+// Package kvs provides a generic key-value store client interface and implementation.
+package kvs
+
+import "context"
+
+// consistentReadKey is the context key of the consistency requested by ContextWithConsistentRead.
+type consistentReadKey struct{}
+
+// ContextWithConsistentRead returns a copy of ctx requesting strongly consistent reads
+// (consistent true) or eventually consistent reads (consistent false) from the backends
+// that offer both, such as DynamoDB, overriding the default of the client for the reads
+// made with it. Backends whose reads are always consistent ignore it.
+func ContextWithConsistentRead(ctx context.Context, consistent bool) context.Context {
+	return context.WithValue(ctx, consistentReadKey{}, consistent)
+}
+
+// ConsistentReadFromContext returns the consistency requested by ContextWithConsistentRead,
+// with ok false when none was requested.
+func ConsistentReadFromContext(ctx context.Context) (consistent, ok bool) {
+	consistent, ok = ctx.Value(consistentReadKey{}).(bool)
+	return consistent, ok
+}
@@ -0,0 +1,22 @@
+package kvs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+func TestContextWithConsistentRead(t *testing.T) {
+	_, ok := kvs.ConsistentReadFromContext(t.Context())
+	require.False(t, ok)
+
+	consistent, ok := kvs.ConsistentReadFromContext(kvs.ContextWithConsistentRead(t.Context(), true))
+	require.True(t, ok)
+	require.True(t, consistent)
+
+	consistent, ok = kvs.ConsistentReadFromContext(kvs.ContextWithConsistentRead(t.Context(), false))
+	require.True(t, ok)
+	require.False(t, consistent)
+}
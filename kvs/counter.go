@@ -0,0 +1,103 @@
+// Package kvs provides a generic key-value store client interface and implementation.
+package kvs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// maxConcurrentIncrements bounds the number of in-flight increments issued by Counter.AddAll.
+const maxConcurrentIncrements = 10
+
+// Counter is a thin helper around LowLevelClient.Increment for integer counters,
+// such as rate-limit buckets or view counters.
+// Counters are created on the first Add; the TTL configured on the Counter is applied
+// at creation time only, so a counter expires ttl after its first increment.
+type Counter struct {
+	lowLevelClient LowLevelClientProxy
+	ttl            time.Duration
+}
+
+// NewCounter creates a new Counter backed by the provided LowLevelClient.
+// Optional TTL can be provided to expire counters after their creation;
+// otherwise the backend's default TTL applies.
+func NewCounter(lowLevelClient LowLevelClient, ttl ...time.Duration) *Counter {
+	counter := &Counter{
+		lowLevelClient: NewLowLevelClientProxy(lowLevelClient),
+	}
+
+	if len(ttl) > 0 {
+		counter.ttl = ttl[0]
+	}
+
+	return counter
+}
+
+// Get returns the current value of the counter stored under key.
+// A missing (or expired) counter reads as zero.
+func (r *Counter) Get(ctx context.Context, key string) (int64, error) {
+	item, err := r.lowLevelClient.GetWithContext(ctx, key)
+	if err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	var value int64
+	err = item.TryGetValueAsObjectType(&value)
+	if err != nil {
+		return 0, err
+	}
+
+	return value, nil
+}
+
+// Add atomically adds delta (which may be negative) to the counter stored under key
+// and returns the new value.
+func (r *Counter) Add(ctx context.Context, key string, delta int64) (int64, error) {
+	return r.lowLevelClient.Increment(ctx, key, delta, r.ttl)
+}
+
+// Reset removes the counter stored under key, so it reads as zero and the next Add
+// recreates it with a fresh TTL.
+func (r *Counter) Reset(ctx context.Context, key string) error {
+	return r.lowLevelClient.DeleteWithContext(ctx, key)
+}
+
+// AddAll adds each delta to its counter and returns the new values keyed by counter key.
+// Every increment is atomic on its own, but the batch as a whole is not: when an error
+// is returned, some counters may already have been updated.
+func (r *Counter) AddAll(ctx context.Context, deltas map[string]int64) (map[string]int64, error) {
+	var mu sync.Mutex
+	values := make(map[string]int64, len(deltas))
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(maxConcurrentIncrements)
+
+	for key, delta := range deltas {
+		group.Go(func() error {
+			value, err := r.lowLevelClient.Increment(groupCtx, key, delta, r.ttl)
+			if err != nil {
+				return err
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			values[key] = value
+
+			return nil
+		})
+	}
+
+	err := group.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
@@ -0,0 +1,101 @@
+package kvs_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	"github.com/arielsrv/go-kvs-client/kvs/dynamodb"
+	kvsredis "github.com/arielsrv/go-kvs-client/kvs/redis"
+	mockkvs "github.com/arielsrv/go-kvs-client/resources/mocks/kvs"
+)
+
+func TestCounter_AddGetReset(t *testing.T) {
+	backends := map[string]kvs.LowLevelClient{
+		"dynamodb": dynamodb.NewLowLevelClient(dynamodb.NewAWSFakeClient(), "__kvs-test"),
+		"redis":    kvsredis.NewBuilder(kvsredis.WithKeyPrefix("__kvs:test")).FakeBuild(),
+	}
+
+	for name, lowLevelClient := range backends {
+		t.Run(name, func(t *testing.T) {
+			counter := kvs.NewCounter(lowLevelClient, time.Minute)
+			ctx := t.Context()
+
+			value, err := counter.Get(ctx, "views")
+			require.NoError(t, err)
+			require.Equal(t, int64(0), value)
+
+			value, err = counter.Add(ctx, "views", 5)
+			require.NoError(t, err)
+			require.Equal(t, int64(5), value)
+
+			value, err = counter.Add(ctx, "views", -2)
+			require.NoError(t, err)
+			require.Equal(t, int64(3), value)
+
+			value, err = counter.Get(ctx, "views")
+			require.NoError(t, err)
+			require.Equal(t, int64(3), value)
+
+			require.NoError(t, counter.Reset(ctx, "views"))
+
+			value, err = counter.Get(ctx, "views")
+			require.NoError(t, err)
+			require.Equal(t, int64(0), value)
+		})
+	}
+}
+
+func TestCounter_AddAll(t *testing.T) {
+	lowLevelClient := kvsredis.NewBuilder(kvsredis.WithKeyPrefix("__kvs:test")).FakeBuild()
+	counter := kvs.NewCounter(lowLevelClient)
+
+	deltas := make(map[string]int64, 20)
+	for i := range 20 {
+		deltas["bucket:"+strconv.Itoa(i)] = int64(i)
+	}
+
+	values, err := counter.AddAll(t.Context(), deltas)
+	require.NoError(t, err)
+	require.Equal(t, deltas, values)
+
+	values, err = counter.AddAll(t.Context(), deltas)
+	require.NoError(t, err)
+	for key, delta := range deltas {
+		require.Equal(t, 2*delta, values[key])
+	}
+}
+
+func TestCounter_AddAll_PropagatesError(t *testing.T) {
+	want := errors.New("boom")
+	lowLevelClient := mockkvs.NewMockLowLevelClient(t)
+	lowLevelClient.EXPECT().
+		Increment(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(0, want)
+
+	counter := kvs.NewCounter(lowLevelClient)
+
+	values, err := counter.AddAll(t.Context(), map[string]int64{"a": 1, "b": 2})
+	require.ErrorIs(t, err, want)
+	require.Nil(t, values)
+}
+
+func TestCounter_Get_PropagatesError(t *testing.T) {
+	want := errors.New("boom")
+	lowLevelClient := mockkvs.NewMockLowLevelClient(t)
+	lowLevelClient.EXPECT().
+		GetWithContext(mock.Anything, "views").
+		Return(nil, want).
+		Once()
+
+	counter := kvs.NewCounter(lowLevelClient)
+
+	value, err := counter.Get(t.Context(), "views")
+	require.ErrorIs(t, err, want)
+	require.Equal(t, int64(0), value)
+}
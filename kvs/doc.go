@@ -9,6 +9,7 @@
 //   - Support for individual and bulk operations
 //   - Context support for cancellation and timeouts
 //   - Optional TTL for automatic expiration of items
+//   - Clock abstraction (Clock, FakeClock) to test expiration deterministically
 //   - AWS DynamoDB implementation
 //
 // Basic Usage:
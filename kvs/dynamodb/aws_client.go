@@ -8,7 +8,8 @@ import (
 )
 
 // AWSClient is an interface for interacting with AWS DynamoDB.
-// It defines methods for basic DynamoDB operations like PutItem, GetItem, BatchGetItem, and BatchWriteItem.
+// It defines methods for basic DynamoDB operations like PutItem, GetItem, UpdateItem, DeleteItem,
+// BatchGetItem, BatchWriteItem, Scan, Query, TransactWriteItems and TransactGetItems.
 // This interface allows for easier testing by mocking the AWS SDK.
 type AWSClient interface {
 	// PutItem puts a single item in a DynamoDB table.
@@ -25,6 +26,20 @@ type AWSClient interface {
 		optFns ...func(*dynamodb.Options),
 	) (*dynamodb.GetItemOutput, error)
 
+	// UpdateItem edits the attributes of a single item, creating it if it does not exist.
+	UpdateItem(
+		ctx context.Context,
+		params *dynamodb.UpdateItemInput,
+		optFns ...func(*dynamodb.Options),
+	) (*dynamodb.UpdateItemOutput, error)
+
+	// DeleteItem deletes a single item from a DynamoDB table.
+	DeleteItem(
+		ctx context.Context,
+		params *dynamodb.DeleteItemInput,
+		optFns ...func(*dynamodb.Options),
+	) (*dynamodb.DeleteItemOutput, error)
+
 	// BatchGetItem retrieves multiple items from one or more DynamoDB tables.
 	BatchGetItem(
 		ctx context.Context,
@@ -38,4 +53,32 @@ type AWSClient interface {
 		params *dynamodb.BatchWriteItemInput,
 		optFns ...func(*dynamodb.Options),
 	) (*dynamodb.BatchWriteItemOutput, error)
+
+	// Scan reads every item in a table (or a segment of it), one page at a time.
+	Scan(
+		ctx context.Context,
+		params *dynamodb.ScanInput,
+		optFns ...func(*dynamodb.Options),
+	) (*dynamodb.ScanOutput, error)
+
+	// Query reads the items of a partition in sort key order, one page at a time.
+	Query(
+		ctx context.Context,
+		params *dynamodb.QueryInput,
+		optFns ...func(*dynamodb.Options),
+	) (*dynamodb.QueryOutput, error)
+
+	// TransactWriteItems applies up to 100 put, delete, update and condition check actions atomically.
+	TransactWriteItems(
+		ctx context.Context,
+		params *dynamodb.TransactWriteItemsInput,
+		optFns ...func(*dynamodb.Options),
+	) (*dynamodb.TransactWriteItemsOutput, error)
+
+	// TransactGetItems reads up to 100 items as a consistent snapshot.
+	TransactGetItems(
+		ctx context.Context,
+		params *dynamodb.TransactGetItemsInput,
+		optFns ...func(*dynamodb.Options),
+	) (*dynamodb.TransactGetItemsOutput, error)
 }
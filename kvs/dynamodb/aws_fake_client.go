@@ -3,194 +3,802 @@ package dynamodb
 
 import (
 	"context"
-	"errors"
-	"math"
+	"hash/fnv"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
-	"github.com/coocood/freecache"
-	"github.com/eko/gocache/lib/v4/cache"
-	"github.com/eko/gocache/lib/v4/store"
-	freecachestore "github.com/eko/gocache/store/freecache/v4"
 
 	"github.com/arielsrv/go-kvs-client/kvs"
 )
 
 // AWSFakeClient is a fake implementation of the AWSClient interface for testing.
-// Instead of interacting with actual DynamoDB, it uses an in-memory cache.
+// Instead of interacting with actual DynamoDB, it keeps the items of every table in memory,
+// by table name, and mimics the behaviour of DynamoDB: whole items are round-tripped,
+// condition, update, filter and projection expressions are evaluated, the limits of the API
+// (100 keys per BatchGetItem, 25 writes per BatchWriteItem, 100 actions per transaction,
+// 400 KB per item) are enforced, and items expire once TTL is enabled on their table with
+// UpdateTimeToLive. Throttling and unprocessed batch requests can be injected
+// (see InjectThrottling and InjectUnprocessed).
 // This allows for testing without requiring a real DynamoDB instance.
 type AWSFakeClient struct {
-	cache cache.CacheInterface[[]byte] // In-memory cache for storing key-value pairs
+	store   *fakeStore       // Items of every table
+	mu      *sync.Mutex      // Serialises write operations, so that conditions and updates are atomic
+	now     func() time.Time // Clock of the TTL expirations
+	names   AttributeNames   // Attribute names of the tables
+	indexes *fakeIndexes     // Global secondary indexes of the tables
+	reads   *fakeReads       // Read requests received, for assertions
+	tables  *fakeTables      // Tables created through CreateTable
+	faults  *fakeFaults      // Injected faults
 }
 
-// NewAWSFakeClient creates a new AWSFakeClient with an in-memory cache.
-// The cache is initialized with the maximum possible size to avoid evictions.
+// FakeRead is a read request received by an AWSFakeClient.
+type FakeRead struct {
+	Operation      string // "GetItem" or "BatchGetItem"
+	ConsistentRead bool   // Whether the request asked for a strongly consistent read
+}
+
+// fakeReads records the read requests received by an AWSFakeClient.
+type fakeReads struct {
+	reads []FakeRead
+	mu    sync.Mutex
+}
+
+// record appends a read request.
+func (r *fakeReads) record(operation string, consistentRead *bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reads = append(r.reads, FakeRead{Operation: operation, ConsistentRead: aws.ToBool(consistentRead)})
+}
+
+// Reads returns the GetItem and BatchGetItem requests received so far, in order, so that
+// tests can assert on their consistency. The fake itself is always consistent.
+func (r AWSFakeClient) Reads() []FakeRead {
+	r.reads.mu.Lock()
+	defer r.reads.mu.Unlock()
+	return slices.Clone(r.reads.reads)
+}
+
+// AWSFakeClientOptions is a function type that configures an AWSFakeClient.
+type AWSFakeClientOptions func(f *AWSFakeClient)
+
+// WithFakeAttributeNames returns an AWSFakeClientOptions that sets the attribute names of
+// the fake tables, and thus their primary key. The LowLevelClient must use the same names.
+func WithFakeAttributeNames(names AttributeNames) AWSFakeClientOptions {
+	return func(f *AWSFakeClient) {
+		f.names = names.withDefaults()
+	}
+}
+
+// WithFakeClock returns an AWSFakeClientOptions that sets the clock against which the TTL
+// of items is checked (kvs.SystemClock by default).
+func WithFakeClock(clock kvs.Clock) AWSFakeClientOptions {
+	return func(f *AWSFakeClient) {
+		f.now = clock.Now
+	}
+}
+
+// fakeKeySeparator joins the partition and sort key values of a composite primary key
+// in the keys of the store.
+const fakeKeySeparator = "\x00"
+
+// NewAWSFakeClient creates a new AWSFakeClient with an empty in-memory store.
+// The tables use the default attribute names unless configured otherwise.
 // Returns a pointer to the new AWSFakeClient.
-func NewAWSFakeClient() *AWSFakeClient {
-	cacheStore := freecachestore.NewFreecache(freecache.NewCache(math.MaxInt8))
+func NewAWSFakeClient(opts ...AWSFakeClientOptions) *AWSFakeClient {
+	fake := &AWSFakeClient{
+		store:   newFakeStore(),
+		mu:      new(sync.Mutex),
+		now:     time.Now,
+		names:   AttributeNames{}.withDefaults(),
+		indexes: newFakeIndexes(),
+		reads:   new(fakeReads),
+		tables:  newFakeTables(),
+		faults:  newFakeFaults(),
+	}
 
-	return &AWSFakeClient{
-		cache: cache.New[[]byte](cacheStore),
+	for _, opt := range opts {
+		opt(fake)
 	}
+
+	return fake
 }
 
 // PutItem implements the AWSClient interface for storing a single item.
-// It extracts the key and value from the input parameters and stores them in the cache.
+// The whole item is kept, so that it is returned as written by GetItem, BatchGetItem and Scan;
+// the value attribute, when present, must be a string as written by LowLevelClient.
+// A ConditionExpression is evaluated against the stored item, and the ALL_OLD return values
+// are honoured.
 // Returns an error if the key or value cannot be converted to the expected type,
-// or if the cache operation fails.
+// or if the item exceeds MaxItemSize.
 func (r AWSFakeClient) PutItem(
 	ctx context.Context,
 	params *dynamodb.PutItemInput,
 	_ ...func(*dynamodb.Options),
 ) (*dynamodb.PutItemOutput, error) {
-	keyMember, convert := params.Item[KeyName].(*types.AttributeValueMemberS)
-	if !convert {
-		return nil, kvs.ErrConvert
+	if err := r.start(ctx, "PutItem"); err != nil {
+		return nil, err
 	}
 
-	valueMember, convert := params.Item[ValueName].(*types.AttributeValueMemberS)
-	if !convert {
-		return nil, kvs.ErrConvert
+	table := aws.ToString(params.TableName)
+	key, err := r.itemKey(params.Item, kvs.ErrConvert)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkItemSize(params.Item); err != nil {
+		return nil, err
 	}
 
-	if err := r.cache.Set(ctx, keyMember.Value, []byte(valueMember.Value)); err != nil {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := r.load(table, key)
+	err = checkCondition(stored, params.ConditionExpression,
+		params.ExpressionAttributeNames, params.ExpressionAttributeValues)
+	if err != nil {
 		return nil, err
 	}
 
-	return &dynamodb.PutItemOutput{}, nil
+	r.store.put(table, key, params.Item)
+
+	output := &dynamodb.PutItemOutput{}
+	if params.ReturnValues == types.ReturnValueAllOld {
+		output.Attributes = stored
+	}
+	return output, nil
 }
 
 // GetItem implements the AWSClient interface for retrieving a single item.
-// It extracts the key from the input parameters and retrieves the corresponding value from the cache.
-// Returns the item if found, or an error if the key cannot be converted to the expected type,
-// the key is not found, or the cache operation fails.
+// It extracts the key from the input parameters and retrieves the corresponding item from the store,
+// restricted to the attributes of the ProjectionExpression, if any. As in DynamoDB, the output
+// has no Item when the key is not found.
+// Returns kvs.ErrConvert if the key cannot be converted to the expected type.
 func (r AWSFakeClient) GetItem(
 	ctx context.Context,
 	params *dynamodb.GetItemInput,
 	_ ...func(*dynamodb.Options),
 ) (*dynamodb.GetItemOutput, error) {
-	keyMember, convert := params.Key[KeyName].(*types.AttributeValueMemberS)
-	if !convert {
-		return nil, kvs.ErrConvert
+	if err := r.start(ctx, "GetItem"); err != nil {
+		return nil, err
 	}
+	r.reads.record("GetItem", params.ConsistentRead)
 
-	value, err := r.cache.Get(ctx, keyMember.Value)
+	table := aws.ToString(params.TableName)
+	key, err := r.storageKey(params.Key, kvs.ErrConvert)
 	if err != nil {
-		if errors.Is(err, &store.NotFound{}) {
-			return nil, kvs.ErrKeyNotFound
+		return nil, err
+	}
+
+	item := r.load(table, key)
+	if item == nil {
+		return &dynamodb.GetItemOutput{}, nil
+	}
+
+	if params.ProjectionExpression != nil {
+		item, err = projectItem(item, *params.ProjectionExpression, params.ExpressionAttributeNames)
+		if err != nil {
+			return nil, err
 		}
+	}
+
+	return &dynamodb.GetItemOutput{Item: item}, nil
+}
+
+// UpdateItem implements the AWSClient interface for editing a single item.
+// The UpdateExpression supports SET (with a value, a path or if_not_exists), REMOVE and numeric
+// ADD actions on top-level and nested map attributes; a missing item is created. Without
+// UpdateExpression, the value bound to ":delta" is added to the value attribute, as issued
+// by LowLevelClient.Increment. A ConditionExpression is evaluated as in PutItem, and the
+// UPDATED_NEW and ALL_NEW return values are honoured.
+// Returns kvs.ErrConvert if the key, the delta or the stored value are not of the expected type.
+func (r AWSFakeClient) UpdateItem(
+	ctx context.Context,
+	params *dynamodb.UpdateItemInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.UpdateItemOutput, error) {
+	if err := r.start(ctx, "UpdateItem"); err != nil {
+		return nil, err
+	}
+
+	table := aws.ToString(params.TableName)
+	key, err := r.storageKey(params.Key, kvs.ErrConvert)
+	if err != nil {
+		return nil, err
+	}
+
+	expression := "ADD #value :delta"
+	names := map[string]string{"#value": r.names.Value}
+	if params.UpdateExpression != nil {
+		expression, names = *params.UpdateExpression, params.ExpressionAttributeNames
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := r.load(table, key)
+	err = checkCondition(stored, params.ConditionExpression,
+		params.ExpressionAttributeNames, params.ExpressionAttributeValues)
+	if err != nil {
+		return nil, err
+	}
+
+	item := stored
+	if item == nil {
+		item = cloneItem(params.Key)
+	}
+
+	updated, err := applyUpdate(item, slices.Collect(maps.Keys(params.Key)), expression, names,
+		params.ExpressionAttributeValues)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkItemSize(item); err != nil {
 		return nil, err
 	}
 
-	return &dynamodb.GetItemOutput{
-		Item: map[string]types.AttributeValue{
-			KeyName: &types.AttributeValueMemberS{
-				Value: keyMember.Value,
-			},
-			ValueName: &types.AttributeValueMemberS{
-				Value: string(value),
-			},
-		},
-	}, nil
+	r.store.put(table, key, item)
+
+	output := &dynamodb.UpdateItemOutput{}
+	switch params.ReturnValues {
+	case types.ReturnValueAllNew:
+		output.Attributes = item
+	case types.ReturnValueUpdatedNew:
+		output.Attributes = map[string]types.AttributeValue{}
+		for _, name := range updated {
+			if value, found := item[name]; found {
+				output.Attributes[name] = value
+			}
+		}
+	default:
+	}
+
+	return output, nil
+}
+
+// load returns the item stored under key in table, or nil if there is none or it has expired.
+func (r AWSFakeClient) load(table, key string) map[string]types.AttributeValue {
+	item := r.store.get(table, key)
+	if item == nil || r.expired(table, item) {
+		return nil
+	}
+	return item
+}
+
+// items returns the live items of table, by store key.
+func (r AWSFakeClient) items(table string) map[string]map[string]types.AttributeValue {
+	items := r.store.snapshot(table)
+	for key, item := range items {
+		if r.expired(table, item) {
+			delete(items, key)
+		}
+	}
+	return items
+}
+
+// expired reports whether item has expired: TTL is enabled on table, and the TTL attribute of
+// the item is a number of seconds since the epoch that has elapsed. Unlike DynamoDB, which
+// may take days to delete them, expired items disappear as soon as their TTL elapses.
+func (r AWSFakeClient) expired(table string, item map[string]types.AttributeValue) bool {
+	attribute, enabled := r.ttlAttributeName(table)
+	if !enabled {
+		return false
+	}
+
+	ttl, ok := item[attribute].(*types.AttributeValueMemberN)
+	if !ok {
+		return false
+	}
+	expiresAt, err := strconv.ParseInt(ttl.Value, 10, 64)
+	return err == nil && expiresAt <= r.now().Unix()
+}
+
+// checkItemSize returns a ValidationException if item exceeds MaxItemSize.
+func checkItemSize(item map[string]types.AttributeValue) error {
+	if itemSize(item) > MaxItemSize {
+		return errFakeValidation("Item size has exceeded the maximum allowed size")
+	}
+	return nil
+}
+
+// itemKey validates the key and value attributes of an item to be written and returns its store key.
+// A key or value of the wrong type yields err.
+func (r AWSFakeClient) itemKey(item map[string]types.AttributeValue, err error) (string, error) {
+	if value, found := item[r.names.Value]; found {
+		if _, convert := value.(*types.AttributeValueMemberS); !convert {
+			return "", err
+		}
+	}
+
+	return r.storageKey(item, err)
+}
+
+// storageKey returns the store key of the item with the primary key attributes of item,
+// which must be strings; err is returned otherwise.
+func (r AWSFakeClient) storageKey(item map[string]types.AttributeValue, err error) (string, error) {
+	partition, convert := item[r.names.Key].(*types.AttributeValueMemberS)
+	if !convert {
+		return "", err
+	}
+	if r.names.SortKey == "" {
+		return partition.Value, nil
+	}
+
+	sort, convert := item[r.names.SortKey].(*types.AttributeValueMemberS)
+	if !convert {
+		return "", err
+	}
+	return partition.Value + fakeKeySeparator + sort.Value, nil
+}
+
+// primaryKey returns the primary key attributes of item.
+func (r AWSFakeClient) primaryKey(item map[string]types.AttributeValue) map[string]types.AttributeValue {
+	key := map[string]types.AttributeValue{r.names.Key: item[r.names.Key]}
+	if r.names.SortKey != "" {
+		key[r.names.SortKey] = item[r.names.SortKey]
+	}
+	return key
+}
+
+// addNumbers adds two DynamoDB number strings, using integer arithmetic when both are integers.
+func addNumbers(a, b string) (string, error) {
+	intA, errA := strconv.ParseInt(a, 10, 64)
+	intB, errB := strconv.ParseInt(b, 10, 64)
+	if errA == nil && errB == nil {
+		return strconv.FormatInt(intA+intB, 10), nil
+	}
+
+	floatA, errA := strconv.ParseFloat(a, 64)
+	floatB, errB := strconv.ParseFloat(b, 64)
+	if errA != nil || errB != nil {
+		return "", kvs.ErrConvert
+	}
+
+	return strconv.FormatFloat(floatA+floatB, 'f', -1, 64), nil
+}
+
+// DeleteItem implements the AWSClient interface for deleting a single item.
+// Deleting a missing key is not an error. A ConditionExpression is evaluated as in PutItem,
+// and the ALL_OLD return values are honoured.
+// Returns kvs.ErrConvert if the key cannot be converted to the expected type.
+func (r AWSFakeClient) DeleteItem(
+	ctx context.Context,
+	params *dynamodb.DeleteItemInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.DeleteItemOutput, error) {
+	if err := r.start(ctx, "DeleteItem"); err != nil {
+		return nil, err
+	}
+
+	table := aws.ToString(params.TableName)
+	key, err := r.storageKey(params.Key, kvs.ErrConvert)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := r.load(table, key)
+	err = checkCondition(stored, params.ConditionExpression,
+		params.ExpressionAttributeNames, params.ExpressionAttributeValues)
+	if err != nil {
+		return nil, err
+	}
+
+	r.store.delete(table, key)
+
+	output := &dynamodb.DeleteItemOutput{}
+	if params.ReturnValues == types.ReturnValueAllOld {
+		output.Attributes = stored
+	}
+	return output, nil
+}
+
+// checkCondition evaluates an optional condition expression against the stored item, which is nil
+// when no item is stored under the key.
+// Returns a *types.ConditionalCheckFailedException when the condition does not hold.
+func checkCondition(
+	item map[string]types.AttributeValue,
+	expression *string,
+	names map[string]string,
+	values map[string]types.AttributeValue,
+) error {
+	if expression == nil {
+		return nil
+	}
+
+	holds, err := evaluateCondition(*expression, names, values, item)
+	if err != nil {
+		return err
+	}
+	if !holds {
+		return &types.ConditionalCheckFailedException{Message: aws.String("The conditional request failed")}
+	}
+
+	return nil
 }
 
-// BatchGetItem implements the AWSClient interface for retrieving multiple items.
-// It extracts the keys from the input parameters and retrieves the corresponding values from the cache.
-// Returns a collection of items that were found, or an error if the keys cannot be found in the request,
-// a key cannot be converted to the expected type, or a cache operation fails.
-// If a key is not found in the cache, it is skipped without returning an error.
+// BatchGetItem implements the AWSClient interface for retrieving multiple items from one or more tables.
+// Missing keys are skipped without error, and a ProjectionExpression is honoured as in GetItem.
+// Like DynamoDB, a request must hold between 1 and 100 keys, without duplicates, or a
+// ValidationException is returned. Keys left unprocessed by InjectUnprocessed are returned in
+// UnprocessedKeys. Returns kvs.ErrInternal if a key cannot be converted to the expected type.
 func (r AWSFakeClient) BatchGetItem(
 	ctx context.Context,
 	params *dynamodb.BatchGetItemInput,
 	_ ...func(*dynamodb.Options),
 ) (*dynamodb.BatchGetItemOutput, error) {
-	records, found := params.RequestItems[r.getContainerName()]
-	if !found {
-		return nil, kvs.ErrNilItem
+	if err := r.start(ctx, "BatchGetItem"); err != nil {
+		return nil, err
 	}
 
-	batchGetItemOutput := new(dynamodb.BatchGetItemOutput)
-	batchGetItemOutput.Responses = make(map[string][]map[string]types.AttributeValue, len(records.Keys))
-	batchGetItemOutput.Responses[r.getContainerName()] = []map[string]types.AttributeValue{}
-
-	for i := range records.Keys {
-		key, exist := records.Keys[i][KeyName]
-		if !exist {
-			return nil, kvs.ErrInternal
-		}
-		keyValueMember, ok := key.(*types.AttributeValueMemberS)
-		if !ok {
-			return nil, kvs.ErrInternal
+	total := 0
+	keys := map[string][]string{}
+	for table, request := range params.RequestItems {
+		total += len(request.Keys)
+		seen := map[string]bool{}
+		for _, primaryKey := range request.Keys {
+			key, err := r.storageKey(primaryKey, kvs.ErrInternal)
+			if err != nil {
+				return nil, err
+			}
+			if seen[key] {
+				return nil, errFakeValidation("Provided list of item keys contains duplicates")
+			}
+			seen[key] = true
+			keys[table] = append(keys[table], key)
 		}
+	}
+	if err := checkBatchSize(total, MaxBatchGetKeys, "BatchGetItem"); err != nil {
+		return nil, err
+	}
 
-		value, err := r.cache.Get(ctx, keyValueMember.Value)
-		if err != nil {
-			if errors.Is(err, &store.NotFound{}) {
+	output := &dynamodb.BatchGetItemOutput{
+		Responses:       make(map[string][]map[string]types.AttributeValue, len(params.RequestItems)),
+		UnprocessedKeys: map[string]types.KeysAndAttributes{},
+	}
+	for table, request := range params.RequestItems {
+		r.reads.record("BatchGetItem", request.ConsistentRead)
+		output.Responses[table] = []map[string]types.AttributeValue{}
+
+		for i, key := range keys[table] {
+			if r.faults.unprocess() {
+				unprocessed, found := output.UnprocessedKeys[table]
+				if !found {
+					unprocessed = request
+					unprocessed.Keys = nil
+				}
+				unprocessed.Keys = append(unprocessed.Keys, request.Keys[i])
+				output.UnprocessedKeys[table] = unprocessed
 				continue
 			}
 
-			return nil, err
+			item := r.load(table, key)
+			if item == nil {
+				continue
+			}
+			if request.ProjectionExpression != nil {
+				projected, err := projectItem(item, *request.ProjectionExpression, request.ExpressionAttributeNames)
+				if err != nil {
+					return nil, err
+				}
+				item = projected
+			}
+			output.Responses[table] = append(output.Responses[table], item)
 		}
+	}
 
-		batchGetItemOutput.Responses[r.getContainerName()] = append(
-			batchGetItemOutput.Responses[r.getContainerName()],
-			[]map[string]types.AttributeValue{
-				{
-					KeyName: &types.AttributeValueMemberS{
-						Value: keyValueMember.Value,
-					},
-					ValueName: &types.AttributeValueMemberS{
-						Value: string(value),
-					},
-				},
-			}...,
-		)
+	return output, nil
+}
+
+// checkBatchSize returns a ValidationException if a batch operation has no request, or more than limit.
+func checkBatchSize(size, limit int, operation string) error {
+	switch {
+	case size == 0:
+		return errFakeValidation("The " + operation + " request must have at least one item")
+	case size > limit:
+		return errFakeValidation("Too many items requested for the " + operation + " call")
+	default:
+		return nil
 	}
-	return batchGetItemOutput, nil
 }
 
-// getContainerName returns the name of the container or service that this client interacts with.
-// For the fake client, this is always "__kvs-test".
-// Used for metrics and logging.
-func (r AWSFakeClient) getContainerName() string {
-	return "__kvs-test"
+// fakeWrite is a WriteRequest resolved to its table and store key.
+type fakeWrite struct {
+	request types.WriteRequest
+	table   string
+	key     string
 }
 
-// BatchWriteItem implements the AWSClient interface for storing multiple items.
-// It extracts the keys and values from the input parameters and stores them in the cache.
-// Returns an error if the items cannot be found in the request, a key or value cannot be
-// converted to the expected type, or a cache operation fails.
-// Only PutRequest operations are supported; DeleteRequest operations will return an error.
+// BatchWriteItem implements the AWSClient interface for storing and deleting multiple items in one
+// or more tables. Each WriteRequest holds either a PutRequest or a DeleteRequest.
+// Like DynamoDB, a request must hold between 1 and 25 write requests, without two on the same item,
+// and every item must fit in MaxItemSize, or a ValidationException is returned. Write requests left
+// unprocessed by InjectUnprocessed are returned in UnprocessedItems.
+// Returns kvs.ErrInternal if a write request is empty, or a key or value cannot be converted to
+// the expected type.
 func (r AWSFakeClient) BatchWriteItem(
 	ctx context.Context,
 	params *dynamodb.BatchWriteItemInput,
 	_ ...func(*dynamodb.Options),
 ) (*dynamodb.BatchWriteItemOutput, error) {
-	records, found := params.RequestItems[r.getContainerName()]
-	if !found {
+	if err := r.start(ctx, "BatchWriteItem"); err != nil {
+		return nil, err
+	}
+
+	var writes []fakeWrite
+	for _, table := range slices.Sorted(maps.Keys(params.RequestItems)) {
+		seen := map[string]bool{}
+		for _, request := range params.RequestItems[table] {
+			key, err := r.writeKey(request)
+			if err != nil {
+				return nil, err
+			}
+			if seen[key] {
+				return nil, errFakeValidation("Provided list of item keys contains duplicates")
+			}
+			seen[key] = true
+			writes = append(writes, fakeWrite{request: request, table: table, key: key})
+		}
+	}
+	if err := checkBatchSize(len(writes), MaxBatchWriteItems, "BatchWriteItem"); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	output := &dynamodb.BatchWriteItemOutput{UnprocessedItems: map[string][]types.WriteRequest{}}
+	for _, write := range writes {
+		switch {
+		case r.faults.unprocess():
+			output.UnprocessedItems[write.table] = append(output.UnprocessedItems[write.table], write.request)
+		case write.request.PutRequest != nil:
+			r.store.put(write.table, write.key, write.request.PutRequest.Item)
+		default:
+			r.store.delete(write.table, write.key)
+		}
+	}
+
+	return output, nil
+}
+
+// writeKey validates a WriteRequest and returns the store key of its item.
+func (r AWSFakeClient) writeKey(request types.WriteRequest) (string, error) {
+	switch {
+	case request.PutRequest != nil && request.DeleteRequest == nil:
+		key, err := r.itemKey(request.PutRequest.Item, kvs.ErrInternal)
+		if err != nil {
+			return "", err
+		}
+		return key, checkItemSize(request.PutRequest.Item)
+	case request.DeleteRequest != nil && request.PutRequest == nil:
+		return r.storageKey(request.DeleteRequest.Key, kvs.ErrInternal)
+	default:
+		return "", kvs.ErrInternal
+	}
+}
+
+// Scan implements the AWSClient interface for reading every stored item of a table.
+// Items are returned in primary key order. Limit, ExclusiveStartKey/LastEvaluatedKey and
+// Segment/TotalSegments are honoured; Limit caps the number of items evaluated before
+// filtering, as DynamoDB does. A FilterExpression is evaluated on each item with the
+// condition grammar of PutItem, and a ProjectionExpression restricts the attributes
+// returned, as in GetItem.
+func (r AWSFakeClient) Scan(
+	ctx context.Context,
+	params *dynamodb.ScanInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.ScanOutput, error) {
+	if err := r.start(ctx, "Scan"); err != nil {
+		return nil, err
+	}
+
+	if params.FilterExpression != nil {
+		// Rejects invalid filters even when there is nothing to scan.
+		_, err := evaluateCondition(*params.FilterExpression,
+			params.ExpressionAttributeNames, params.ExpressionAttributeValues, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var startKey string
+	if params.ExclusiveStartKey != nil {
+		key, err := r.storageKey(params.ExclusiveStartKey, kvs.ErrInternal)
+		if err != nil {
+			return nil, err
+		}
+		startKey = key
+	}
+
+	table := aws.ToString(params.TableName)
+
+	items := r.items(table)
+	for key := range items {
+		if (startKey != "" && key <= startKey) ||
+			(params.Segment != nil && params.TotalSegments != nil &&
+				segmentOf(key, *params.TotalSegments) != *params.Segment) {
+			delete(items, key)
+		}
+	}
+
+	keys := slices.Sorted(maps.Keys(items))
+	output := &dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{}}
+	truncated := params.Limit != nil && int(*params.Limit) < len(keys)
+	if truncated {
+		keys = keys[:*params.Limit]
+	}
+
+	for i, key := range keys {
+		item := items[key]
+		if truncated && i == len(keys)-1 {
+			output.LastEvaluatedKey = r.primaryKey(item)
+		}
+
+		item, matches, err := filterItem(item, params.FilterExpression, params.ProjectionExpression,
+			params.ExpressionAttributeNames, params.ExpressionAttributeValues)
+		if err != nil {
+			return nil, err
+		}
+		if matches {
+			output.Items = append(output.Items, item)
+		}
+	}
+	output.Count = int32(len(output.Items))
+
+	return output, nil
+}
+
+// Query implements the AWSClient interface for reading the items of a partition of a table
+// or, with IndexName, of a global secondary index declared with WithFakeSecondaryIndexes or
+// CreateTable. The KeyConditionExpression is evaluated on each item with the condition grammar
+// of PutItem, and the matching items are returned in sort key order (index key order for
+// indexes), reversed when ScanIndexForward is false. Limit, ExclusiveStartKey/LastEvaluatedKey,
+// FilterExpression and ProjectionExpression are honoured as in Scan.
+func (r AWSFakeClient) Query(
+	ctx context.Context,
+	params *dynamodb.QueryInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.QueryOutput, error) {
+	if err := r.start(ctx, "Query"); err != nil {
+		return nil, err
+	}
+
+	if params.KeyConditionExpression == nil {
 		return nil, kvs.ErrInternal
 	}
+	// Rejects invalid expressions even when there is nothing to query.
+	for _, expression := range []*string{params.KeyConditionExpression, params.FilterExpression} {
+		if expression == nil {
+			continue
+		}
+		if _, err := evaluateCondition(*expression,
+			params.ExpressionAttributeNames, params.ExpressionAttributeValues, nil); err != nil {
+			return nil, err
+		}
+	}
 
-	for i := range records {
-		record := records[i]
-		if record.PutRequest == nil {
+	table := aws.ToString(params.TableName)
+
+	var indexAttribute string
+	if params.IndexName != nil {
+		attribute, found := r.indexAttribute(table, *params.IndexName)
+		if !found {
 			return nil, kvs.ErrInternal
 		}
+		indexAttribute = attribute
+	}
+	// orderKey orders the items by index key, then by store key, which orders the items
+	// of a partition by sort key.
+	orderKey := func(key string, item map[string]types.AttributeValue) string {
+		if indexAttribute == "" {
+			return key
+		}
+		return stringAttribute(item[indexAttribute]) + fakeKeySeparator + key
+	}
 
-		keyMember, convert := record.PutRequest.Item[KeyName].(*types.AttributeValueMemberS)
-		if !convert {
-			return nil, kvs.ErrInternal
+	descending := params.ScanIndexForward != nil && !*params.ScanIndexForward
+	var startKey string
+	if params.ExclusiveStartKey != nil {
+		key, err := r.storageKey(params.ExclusiveStartKey, kvs.ErrInternal)
+		if err != nil {
+			return nil, err
 		}
+		startKey = orderKey(key, params.ExclusiveStartKey)
+	}
 
-		keyValue, convert := record.PutRequest.Item[ValueName].(*types.AttributeValueMemberS)
-		if !convert {
-			return nil, kvs.ErrInternal
+	items := map[string]map[string]types.AttributeValue{}
+	for key, item := range r.items(table) {
+		if indexAttribute != "" {
+			// Items without a string index key are not in the index.
+			if _, indexed := item[indexAttribute].(*types.AttributeValueMemberS); !indexed {
+				continue
+			}
 		}
 
-		if err := r.cache.Set(ctx, keyMember.Value, []byte(keyValue.Value)); err != nil {
+		order := orderKey(key, item)
+		if startKey != "" && ((!descending && order <= startKey) || (descending && order >= startKey)) {
+			continue
+		}
+
+		matches, err := evaluateCondition(*params.KeyConditionExpression,
+			params.ExpressionAttributeNames, params.ExpressionAttributeValues, item)
+		if err != nil {
 			return nil, err
 		}
+		if matches {
+			items[order] = item
+		}
 	}
 
-	return &dynamodb.BatchWriteItemOutput{}, nil
+	keys := slices.Sorted(maps.Keys(items))
+	if descending {
+		slices.Reverse(keys)
+	}
+	output := &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{}}
+	truncated := params.Limit != nil && int(*params.Limit) < len(keys)
+	if truncated {
+		keys = keys[:*params.Limit]
+	}
+
+	for i, key := range keys {
+		item := items[key]
+		if truncated && i == len(keys)-1 {
+			output.LastEvaluatedKey = r.primaryKey(item)
+			if indexAttribute != "" {
+				output.LastEvaluatedKey[indexAttribute] = item[indexAttribute]
+			}
+		}
+
+		item, matches, err := filterItem(item, params.FilterExpression, params.ProjectionExpression,
+			params.ExpressionAttributeNames, params.ExpressionAttributeValues)
+		if err != nil {
+			return nil, err
+		}
+		if matches {
+			output.Items = append(output.Items, item)
+		}
+	}
+	output.Count = int32(len(output.Items))
+
+	return output, nil
+}
+
+// filterItem evaluates the FilterExpression of a Scan or Query on item and, when it
+// matches, applies the ProjectionExpression. Both expressions are optional.
+func filterItem(
+	item map[string]types.AttributeValue,
+	filter, projection *string,
+	names map[string]string,
+	values map[string]types.AttributeValue,
+) (map[string]types.AttributeValue, bool, error) {
+	if filter != nil {
+		matches, err := evaluateCondition(*filter, names, values, item)
+		if err != nil || !matches {
+			return nil, false, err
+		}
+	}
+
+	if projection != nil {
+		projected, err := projectItem(item, *projection, names)
+		if err != nil {
+			return nil, false, err
+		}
+		return projected, true, nil
+	}
+
+	return item, true, nil
+}
+
+// segmentOf assigns a key to one of total parallel scan segments.
+func segmentOf(key string, total int32) int32 {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(key))
+	return int32(hash.Sum32() % uint32(total))
 }
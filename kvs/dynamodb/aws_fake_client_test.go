@@ -2,11 +2,14 @@ package dynamodb_test
 
 import (
 	"context"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsdynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
 	"github.com/stretchr/testify/require"
 
 	"github.com/arielsrv/go-kvs-client/kvs"
@@ -22,6 +25,14 @@ const fakeTableName = "__kvs-test"
 
 func newFake() *dynamodb.AWSFakeClient { return dynamodb.NewAWSFakeClient() }
 
+func requireValidationException(t *testing.T, err error) {
+	t.Helper()
+
+	var apiErr smithy.APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, "ValidationException", apiErr.ErrorCode())
+}
+
 func TestAWSFakeClient_PutItem_NonStringKey_ReturnsErrConvert(t *testing.T) {
 	fake := newFake()
 
@@ -60,7 +71,7 @@ func TestAWSFakeClient_GetItem_NonStringKey_ReturnsErrConvert(t *testing.T) {
 	require.ErrorIs(t, err, kvs.ErrConvert)
 }
 
-func TestAWSFakeClient_BatchGetItem_UnknownTable_ReturnsErrNilItem(t *testing.T) {
+func TestAWSFakeClient_BatchGetItem_EmptyRequest_ReturnsValidationException(t *testing.T) {
 	fake := newFake()
 
 	_, err := fake.BatchGetItem(context.Background(), &awsdynamodb.BatchGetItemInput{
@@ -68,7 +79,7 @@ func TestAWSFakeClient_BatchGetItem_UnknownTable_ReturnsErrNilItem(t *testing.T)
 			"some-other-table": {Keys: nil},
 		},
 	})
-	require.ErrorIs(t, err, kvs.ErrNilItem)
+	requireValidationException(t, err)
 }
 
 func TestAWSFakeClient_BatchGetItem_MissingKeyAttribute_ReturnsErrInternal(t *testing.T) {
@@ -118,7 +129,7 @@ func TestAWSFakeClient_BatchGetItem_MissingKeys_AreSkippedNotErrored(t *testing.
 	require.Empty(t, out.Responses[fakeTableName])
 }
 
-func TestAWSFakeClient_BatchWriteItem_UnknownTable_ReturnsErrInternal(t *testing.T) {
+func TestAWSFakeClient_BatchWriteItem_EmptyRequest_ReturnsValidationException(t *testing.T) {
 	fake := newFake()
 
 	_, err := fake.BatchWriteItem(context.Background(), &awsdynamodb.BatchWriteItemInput{
@@ -126,7 +137,7 @@ func TestAWSFakeClient_BatchWriteItem_UnknownTable_ReturnsErrInternal(t *testing
 			"some-other-table": {},
 		},
 	})
-	require.ErrorIs(t, err, kvs.ErrInternal)
+	requireValidationException(t, err)
 }
 
 func TestAWSFakeClient_BatchWriteItem_NilPutRequest_ReturnsErrInternal(t *testing.T) {
@@ -176,11 +187,9 @@ func TestAWSFakeClient_BatchWriteItem_NonStringValue_ReturnsErrInternal(t *testi
 	require.ErrorIs(t, err, kvs.ErrInternal)
 }
 
-// hugeValue returns a string large enough to be rejected by the freecache
-// backend that powers AWSFakeClient (per-entry limit ~= 64KB). 256KB is well
-// above any plausible threshold.
+// hugeValue returns a string larger than the 400 KB limit of DynamoDB items.
 func hugeValue() string {
-	const size = 256 * 1024
+	const size = dynamodb.MaxItemSize + 1
 	b := make([]byte, size)
 	for i := range b {
 		b[i] = 'x'
@@ -188,7 +197,7 @@ func hugeValue() string {
 	return string(b)
 }
 
-func TestAWSFakeClient_PutItem_CacheSetError_Propagates(t *testing.T) {
+func TestAWSFakeClient_PutItem_ItemTooLarge_ReturnsValidationException(t *testing.T) {
 	fake := newFake()
 
 	_, err := fake.PutItem(context.Background(), &awsdynamodb.PutItemInput{
@@ -198,10 +207,10 @@ func TestAWSFakeClient_PutItem_CacheSetError_Propagates(t *testing.T) {
 			"value": &types.AttributeValueMemberS{Value: hugeValue()},
 		},
 	})
-	require.Error(t, err)
+	requireValidationException(t, err)
 }
 
-func TestAWSFakeClient_BatchWriteItem_CacheSetError_Propagates(t *testing.T) {
+func TestAWSFakeClient_BatchWriteItem_ItemTooLarge_ReturnsValidationException(t *testing.T) {
 	fake := newFake()
 
 	_, err := fake.BatchWriteItem(context.Background(), &awsdynamodb.BatchWriteItemInput{
@@ -216,5 +225,492 @@ func TestAWSFakeClient_BatchWriteItem_CacheSetError_Propagates(t *testing.T) {
 			}},
 		},
 	})
-	require.Error(t, err)
+	requireValidationException(t, err)
+}
+
+func TestAWSFakeClient_Scan_UnsupportedFilter_ReturnsErrInternal(t *testing.T) {
+	fake := newFake()
+
+	_, err := fake.Scan(context.Background(), &awsdynamodb.ScanInput{
+		TableName:        aws.String(fakeTableName),
+		FilterExpression: aws.String("#v = :v"),
+	})
+	require.ErrorIs(t, err, kvs.ErrInternal)
+}
+
+func TestAWSFakeClient_Scan_BadStartKey_ReturnsErrInternal(t *testing.T) {
+	fake := newFake()
+
+	_, err := fake.Scan(context.Background(), &awsdynamodb.ScanInput{
+		TableName: aws.String(fakeTableName),
+		ExclusiveStartKey: map[string]types.AttributeValue{
+			"key": &types.AttributeValueMemberN{Value: "1"},
+		},
+	})
+	require.ErrorIs(t, err, kvs.ErrInternal)
+}
+
+func TestAWSFakeClient_UpdateItem_NonNumericStoredValue_ReturnsErrConvert(t *testing.T) {
+	fake := newFake()
+	ctx := context.Background()
+
+	_, err := fake.PutItem(ctx, &awsdynamodb.PutItemInput{
+		TableName: aws.String(fakeTableName),
+		Item: map[string]types.AttributeValue{
+			"key":   &types.AttributeValueMemberS{Value: "k"},
+			"value": &types.AttributeValueMemberS{Value: `"text"`},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = fake.UpdateItem(ctx, &awsdynamodb.UpdateItemInput{
+		TableName: aws.String(fakeTableName),
+		Key: map[string]types.AttributeValue{
+			"key": &types.AttributeValueMemberS{Value: "k"},
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":delta": &types.AttributeValueMemberN{Value: "1"},
+		},
+	})
+	require.ErrorIs(t, err, kvs.ErrConvert)
+}
+
+func TestAWSFakeClient_UpdateItem_DeleteItem_NonStringKey_ReturnsErrConvert(t *testing.T) {
+	fake := newFake()
+	key := map[string]types.AttributeValue{
+		"key": &types.AttributeValueMemberN{Value: "1"},
+	}
+
+	_, err := fake.UpdateItem(context.Background(), &awsdynamodb.UpdateItemInput{Key: key})
+	require.ErrorIs(t, err, kvs.ErrConvert)
+
+	_, err = fake.DeleteItem(context.Background(), &awsdynamodb.DeleteItemInput{Key: key})
+	require.ErrorIs(t, err, kvs.ErrConvert)
+}
+
+func TestAWSFakeClient_PutItem_ConditionExpression(t *testing.T) {
+	fake := newFake()
+	ctx := context.Background()
+
+	_, err := fake.PutItem(ctx, &awsdynamodb.PutItemInput{
+		TableName: aws.String(fakeTableName),
+		Item: map[string]types.AttributeValue{
+			"key":   &types.AttributeValueMemberS{Value: "k"},
+			"value": &types.AttributeValueMemberS{Value: "abc"},
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		expression string
+		want       bool
+	}{
+		{name: "equal", expression: "#value = :abc", want: true},
+		{name: "not equal", expression: "#value <> :abc", want: false},
+		{name: "less than", expression: "#value < :abd", want: true},
+		{name: "greater or equal", expression: "#value >= :abd", want: false},
+		{name: "number comparison", expression: ":ten > :two", want: true},
+		{name: "missing operand", expression: "#missing = :abc", want: false},
+		{name: "exists", expression: "attribute_exists(#key)", want: true},
+		{name: "not exists", expression: "attribute_not_exists(#key)", want: false},
+		{name: "begins with", expression: "begins_with(#value, :ab)", want: true},
+		{name: "or", expression: "attribute_not_exists(#key) OR #value = :abc", want: true},
+		{name: "and", expression: "attribute_exists(#key) AND #value = :abd", want: false},
+		{name: "not and parentheses", expression: "NOT (#value = :abd OR #value = :ab)", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := fake.DeleteItem(ctx, &awsdynamodb.DeleteItemInput{
+				TableName: aws.String(fakeTableName),
+				Key: map[string]types.AttributeValue{
+					"key": &types.AttributeValueMemberS{Value: "k"},
+				},
+				ConditionExpression: aws.String(tt.expression + " AND #value = :nothing"),
+				ExpressionAttributeNames: map[string]string{
+					"#key": "key", "#value": "value", "#missing": "missing",
+				},
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":abc":     &types.AttributeValueMemberS{Value: "abc"},
+					":abd":     &types.AttributeValueMemberS{Value: "abd"},
+					":ab":      &types.AttributeValueMemberS{Value: "ab"},
+					":ten":     &types.AttributeValueMemberN{Value: "10"},
+					":two":     &types.AttributeValueMemberN{Value: "2"},
+					":nothing": &types.AttributeValueMemberS{Value: ""},
+				},
+			})
+			var conditionErr *types.ConditionalCheckFailedException
+			require.ErrorAs(t, err, &conditionErr)
+
+			_, err = fake.PutItem(ctx, &awsdynamodb.PutItemInput{
+				TableName: aws.String(fakeTableName),
+				Item: map[string]types.AttributeValue{
+					"key":   &types.AttributeValueMemberS{Value: "k"},
+					"value": &types.AttributeValueMemberS{Value: "abc"},
+				},
+				ConditionExpression: aws.String(tt.expression),
+				ExpressionAttributeNames: map[string]string{
+					"#key": "key", "#value": "value", "#missing": "missing",
+				},
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":abc": &types.AttributeValueMemberS{Value: "abc"},
+					":abd": &types.AttributeValueMemberS{Value: "abd"},
+					":ab":  &types.AttributeValueMemberS{Value: "ab"},
+					":ten": &types.AttributeValueMemberN{Value: "10"},
+					":two": &types.AttributeValueMemberN{Value: "2"},
+				},
+			})
+			if tt.want {
+				require.NoError(t, err)
+			} else {
+				require.ErrorAs(t, err, &conditionErr)
+			}
+		})
+	}
+}
+
+func TestAWSFakeClient_PutItem_MalformedCondition_ReturnsErrInternal(t *testing.T) {
+	fake := newFake()
+
+	for _, expression := range []string{"(#value = :v", "begins_with #value", "#value ~ :v", "#value = :v )"} {
+		_, err := fake.PutItem(context.Background(), &awsdynamodb.PutItemInput{
+			TableName: aws.String(fakeTableName),
+			Item: map[string]types.AttributeValue{
+				"key":   &types.AttributeValueMemberS{Value: "k"},
+				"value": &types.AttributeValueMemberS{Value: "v"},
+			},
+			ConditionExpression:      aws.String(expression),
+			ExpressionAttributeNames: map[string]string{"#value": "value"},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":v": &types.AttributeValueMemberS{Value: "v"},
+			},
+		})
+		require.ErrorIs(t, err, kvs.ErrInternal, expression)
+	}
+}
+
+func TestAWSFakeClient_UpdateItem_Expressions(t *testing.T) {
+	fake := newFake()
+	ctx := context.Background()
+	key := map[string]types.AttributeValue{"key": &types.AttributeValueMemberS{Value: "k"}}
+	update := func(expression string, values map[string]types.AttributeValue) (*awsdynamodb.UpdateItemOutput, error) {
+		return fake.UpdateItem(ctx, &awsdynamodb.UpdateItemInput{
+			TableName:                 aws.String(fakeTableName),
+			Key:                       key,
+			UpdateExpression:          aws.String(expression),
+			ExpressionAttributeNames:  map[string]string{"#m": "map", "#c": "count"},
+			ExpressionAttributeValues: values,
+			ReturnValues:              types.ReturnValueAllNew,
+		})
+	}
+
+	output, err := update("SET #m = :m, name = :n ADD #c :one", map[string]types.AttributeValue{
+		":m":   &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{}},
+		":n":   &types.AttributeValueMemberS{Value: "a"},
+		":one": &types.AttributeValueMemberN{Value: "1"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &types.AttributeValueMemberN{Value: "1"}, output.Attributes["count"])
+
+	output, err = update("SET #m.inner = if_not_exists(#m.inner, :v) REMOVE name", map[string]types.AttributeValue{
+		":v": &types.AttributeValueMemberS{Value: "b"},
+	})
+	require.NoError(t, err)
+	require.NotContains(t, output.Attributes, "name")
+	require.Equal(t, &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+		"inner": &types.AttributeValueMemberS{Value: "b"},
+	}}, output.Attributes["map"])
+
+	get, err := fake.GetItem(ctx, &awsdynamodb.GetItemInput{
+		TableName:                aws.String(fakeTableName),
+		Key:                      key,
+		ProjectionExpression:     aws.String("#m.inner, missing"),
+		ExpressionAttributeNames: map[string]string{"#m": "map"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]types.AttributeValue{
+		"map": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"inner": &types.AttributeValueMemberS{Value: "b"},
+		}},
+	}, get.Item)
+
+	_, err = update("SET absent.inner = :v", map[string]types.AttributeValue{
+		":v": &types.AttributeValueMemberS{Value: "c"},
+	})
+	var apiError smithy.APIError
+	require.ErrorAs(t, err, &apiError)
+	require.Equal(t, "ValidationException", apiError.ErrorCode())
+
+	for _, expression := range []string{"DELETE #m :v", "SET #m[0] = :v", "SET #m :v", "SET #m = :missing"} {
+		_, err = update(expression, map[string]types.AttributeValue{
+			":v": &types.AttributeValueMemberS{Value: "c"},
+		})
+		require.ErrorIs(t, err, kvs.ErrInternal, expression)
+	}
+}
+
+func stringKey(key string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{"key": &types.AttributeValueMemberS{Value: key}}
+}
+
+func TestAWSFakeClient_TableNames(t *testing.T) {
+	fake := newFake()
+	ctx := context.Background()
+
+	_, err := fake.PutItem(ctx, &awsdynamodb.PutItemInput{TableName: aws.String("users"), Item: stringKey("1")})
+	require.NoError(t, err)
+	_, err = fake.BatchWriteItem(ctx, &awsdynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]types.WriteRequest{
+			"orders": {{PutRequest: &types.PutRequest{Item: stringKey("2")}}},
+		},
+	})
+	require.NoError(t, err)
+
+	output, err := fake.GetItem(ctx, &awsdynamodb.GetItemInput{TableName: aws.String("orders"), Key: stringKey("1")})
+	require.NoError(t, err)
+	require.Nil(t, output.Item)
+
+	batch, err := fake.BatchGetItem(ctx, &awsdynamodb.BatchGetItemInput{
+		RequestItems: map[string]types.KeysAndAttributes{
+			"users":  {Keys: []map[string]types.AttributeValue{stringKey("1"), stringKey("2")}},
+			"orders": {Keys: []map[string]types.AttributeValue{stringKey("1"), stringKey("2")}},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []map[string]types.AttributeValue{stringKey("1")}, batch.Responses["users"])
+	require.Equal(t, []map[string]types.AttributeValue{stringKey("2")}, batch.Responses["orders"])
+
+	scan, err := fake.Scan(ctx, &awsdynamodb.ScanInput{TableName: aws.String("orders")})
+	require.NoError(t, err)
+	require.Equal(t, []map[string]types.AttributeValue{stringKey("2")}, scan.Items)
+}
+
+func TestAWSFakeClient_RoundTrip(t *testing.T) {
+	fake := newFake()
+	ctx := context.Background()
+	item := map[string]types.AttributeValue{
+		"key":    &types.AttributeValueMemberS{Value: "k"},
+		"ttl":    &types.AttributeValueMemberN{Value: "1700000000"},
+		"bytes":  &types.AttributeValueMemberB{Value: []byte("b")},
+		"bool":   &types.AttributeValueMemberBOOL{Value: true},
+		"null":   &types.AttributeValueMemberNULL{Value: true},
+		"list":   &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberN{Value: "1"}}},
+		"map":    &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"a": &types.AttributeValueMemberS{Value: "b"}}},
+		"ss":     &types.AttributeValueMemberSS{Value: []string{"a", "b"}},
+		"ns":     &types.AttributeValueMemberNS{Value: []string{"1", "2"}},
+		"bs":     &types.AttributeValueMemberBS{Value: [][]byte{[]byte("a")}},
+		"string": &types.AttributeValueMemberS{Value: "s"},
+	}
+
+	put, err := fake.PutItem(ctx, &awsdynamodb.PutItemInput{TableName: aws.String(fakeTableName), Item: item})
+	require.NoError(t, err)
+	require.Nil(t, put.Attributes)
+
+	// The stored item does not share the attribute values of the request.
+	item["map"].(*types.AttributeValueMemberM).Value["a"] = &types.AttributeValueMemberS{Value: "changed"}
+
+	output, err := fake.GetItem(ctx, &awsdynamodb.GetItemInput{TableName: aws.String(fakeTableName), Key: stringKey("k")})
+	require.NoError(t, err)
+	require.Equal(t, &types.AttributeValueMemberS{Value: "b"}, output.Item["map"].(*types.AttributeValueMemberM).Value["a"])
+	output.Item["map"] = item["map"]
+	require.Equal(t, item, output.Item)
+
+	put, err = fake.PutItem(ctx, &awsdynamodb.PutItemInput{
+		TableName:    aws.String(fakeTableName),
+		Item:         stringKey("k"),
+		ReturnValues: types.ReturnValueAllOld,
+	})
+	require.NoError(t, err)
+	require.Len(t, put.Attributes, len(item))
+
+	deleted, err := fake.DeleteItem(ctx, &awsdynamodb.DeleteItemInput{
+		TableName:    aws.String(fakeTableName),
+		Key:          stringKey("k"),
+		ReturnValues: types.ReturnValueAllOld,
+	})
+	require.NoError(t, err)
+	require.Equal(t, stringKey("k"), deleted.Attributes)
+}
+
+func TestAWSFakeClient_BatchWriteItem_DeleteRequests(t *testing.T) {
+	fake := newFake()
+	ctx := context.Background()
+
+	_, err := fake.BatchWriteItem(ctx, &awsdynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]types.WriteRequest{
+			fakeTableName: {
+				{PutRequest: &types.PutRequest{Item: stringKey("1")}},
+				{PutRequest: &types.PutRequest{Item: stringKey("2")}},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = fake.BatchWriteItem(ctx, &awsdynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]types.WriteRequest{
+			fakeTableName: {
+				{DeleteRequest: &types.DeleteRequest{Key: stringKey("1")}},
+				{DeleteRequest: &types.DeleteRequest{Key: stringKey("missing")}},
+				{PutRequest: &types.PutRequest{Item: stringKey("3")}},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	scan, err := fake.Scan(ctx, &awsdynamodb.ScanInput{TableName: aws.String(fakeTableName)})
+	require.NoError(t, err)
+	require.Equal(t, []map[string]types.AttributeValue{stringKey("2"), stringKey("3")}, scan.Items)
+
+	_, err = fake.BatchWriteItem(ctx, &awsdynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]types.WriteRequest{
+			fakeTableName: {{
+				PutRequest:    &types.PutRequest{Item: stringKey("1")},
+				DeleteRequest: &types.DeleteRequest{Key: stringKey("1")},
+			}},
+		},
+	})
+	require.ErrorIs(t, err, kvs.ErrInternal)
+}
+
+func TestAWSFakeClient_Limits(t *testing.T) {
+	fake := newFake()
+	ctx := context.Background()
+
+	keys := make([]map[string]types.AttributeValue, dynamodb.MaxBatchGetKeys+1)
+	writes := make([]types.WriteRequest, dynamodb.MaxBatchWriteItems+1)
+	for i := range keys {
+		keys[i] = stringKey(strconv.Itoa(i))
+	}
+	for i := range writes {
+		writes[i] = types.WriteRequest{PutRequest: &types.PutRequest{Item: stringKey(strconv.Itoa(i))}}
+	}
+
+	_, err := fake.BatchGetItem(ctx, &awsdynamodb.BatchGetItemInput{
+		RequestItems: map[string]types.KeysAndAttributes{fakeTableName: {Keys: keys}},
+	})
+	requireValidationException(t, err)
+	_, err = fake.BatchGetItem(ctx, &awsdynamodb.BatchGetItemInput{
+		RequestItems: map[string]types.KeysAndAttributes{fakeTableName: {Keys: []map[string]types.AttributeValue{
+			stringKey("1"), stringKey("1"),
+		}}},
+	})
+	requireValidationException(t, err)
+
+	_, err = fake.BatchWriteItem(ctx, &awsdynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]types.WriteRequest{fakeTableName: writes},
+	})
+	requireValidationException(t, err)
+	_, err = fake.BatchWriteItem(ctx, &awsdynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]types.WriteRequest{fakeTableName: {writes[0], writes[0]}},
+	})
+	requireValidationException(t, err)
+
+	large := stringKey("large")
+	large["value"] = &types.AttributeValueMemberS{Value: hugeValue()}
+	_, err = fake.TransactWriteItems(ctx, &awsdynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{{Put: &types.Put{TableName: aws.String(fakeTableName), Item: large}}},
+	})
+	requireValidationException(t, err)
+
+	_, err = fake.UpdateItem(ctx, &awsdynamodb.UpdateItemInput{
+		TableName:                 aws.String(fakeTableName),
+		Key:                       stringKey("large"),
+		UpdateExpression:          aws.String("SET #value = :value"),
+		ExpressionAttributeNames:  map[string]string{"#value": "value"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{":value": large["value"]},
+	})
+	requireValidationException(t, err)
+}
+
+func TestAWSFakeClient_TTL(t *testing.T) {
+	ctx := context.Background()
+	clock := kvs.NewFakeClock(time.Unix(1_700_000_000, 0))
+	now := clock.Now()
+	fake := dynamodb.NewAWSFakeClient(dynamodb.WithFakeClock(clock))
+
+	item := stringKey("k")
+	item["ttl"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Add(time.Minute).Unix(), 10)}
+	_, err := fake.PutItem(ctx, &awsdynamodb.PutItemInput{TableName: aws.String(fakeTableName), Item: item})
+	require.NoError(t, err)
+
+	get := func() map[string]types.AttributeValue {
+		output, err := fake.GetItem(ctx, &awsdynamodb.GetItemInput{TableName: aws.String(fakeTableName), Key: stringKey("k")})
+		require.NoError(t, err)
+		return output.Item
+	}
+
+	// Items do not expire until TTL is enabled on the table.
+	clock.Advance(time.Hour)
+	require.Equal(t, item, get())
+
+	require.NoError(t, dynamodb.NewBuilder().WithContainerName(fakeTableName).EnsureTableWithClient(ctx, fake))
+	require.Nil(t, get())
+	scan, err := fake.Scan(ctx, &awsdynamodb.ScanInput{TableName: aws.String(fakeTableName)})
+	require.NoError(t, err)
+	require.Empty(t, scan.Items)
+
+	// An expired item counts as absent for conditions.
+	_, err = fake.PutItem(ctx, &awsdynamodb.PutItemInput{
+		TableName:                aws.String(fakeTableName),
+		Item:                     stringKey("k"),
+		ConditionExpression:      aws.String("attribute_not_exists(#key)"),
+		ExpressionAttributeNames: map[string]string{"#key": "key"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, stringKey("k"), get())
+}
+
+func TestAWSFakeClient_InjectThrottling(t *testing.T) {
+	fake := newFake()
+	ctx := context.Background()
+	fake.InjectThrottling(1, "GetItem")
+	fake.InjectThrottling(2)
+
+	var throttled *types.ProvisionedThroughputExceededException
+	_, err := fake.GetItem(ctx, &awsdynamodb.GetItemInput{TableName: aws.String(fakeTableName), Key: stringKey("k")})
+	require.ErrorAs(t, err, &throttled)
+	_, err = fake.PutItem(ctx, &awsdynamodb.PutItemInput{TableName: aws.String(fakeTableName), Item: stringKey("k")})
+	require.ErrorAs(t, err, &throttled)
+	_, err = fake.Scan(ctx, &awsdynamodb.ScanInput{TableName: aws.String(fakeTableName)})
+	require.ErrorAs(t, err, &throttled)
+
+	_, err = fake.GetItem(ctx, &awsdynamodb.GetItemInput{TableName: aws.String(fakeTableName), Key: stringKey("k")})
+	require.NoError(t, err)
+}
+
+func TestAWSFakeClient_InjectUnprocessed(t *testing.T) {
+	fake := newFake()
+	ctx := context.Background()
+	fake.InjectUnprocessed(2)
+
+	write, err := fake.BatchWriteItem(ctx, &awsdynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]types.WriteRequest{
+			fakeTableName: {
+				{PutRequest: &types.PutRequest{Item: stringKey("1")}},
+				{PutRequest: &types.PutRequest{Item: stringKey("2")}},
+				{PutRequest: &types.PutRequest{Item: stringKey("3")}},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string][]types.WriteRequest{
+		fakeTableName: {
+			{PutRequest: &types.PutRequest{Item: stringKey("1")}},
+			{PutRequest: &types.PutRequest{Item: stringKey("2")}},
+		},
+	}, write.UnprocessedItems)
+
+	fake.InjectUnprocessed(1)
+	keys := []map[string]types.AttributeValue{stringKey("2"), stringKey("3")}
+	read, err := fake.BatchGetItem(ctx, &awsdynamodb.BatchGetItemInput{
+		RequestItems: map[string]types.KeysAndAttributes{
+			fakeTableName: {Keys: keys, ConsistentRead: aws.Bool(true)},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []map[string]types.AttributeValue{stringKey("3")}, read.Responses[fakeTableName])
+	require.Equal(t, map[string]types.KeysAndAttributes{
+		fakeTableName: {Keys: keys[:1], ConsistentRead: aws.Bool(true)},
+	}, read.UnprocessedKeys)
 }
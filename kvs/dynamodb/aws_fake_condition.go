@@ -0,0 +1,333 @@
+// Package dynamodb provides AWS DynamoDB specific implementation of the KVS client.
+package dynamodb
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// evaluateCondition evaluates a DynamoDB condition expression against item, which is nil
+// when no item is stored under the key. It supports the subset of the expression grammar
+// used by this module: OR, AND, NOT, parentheses, the comparators =, <>, <, <=, > and >=
+// on string and number attributes (= and <> on any attribute), BETWEEN, nested document
+// paths, and the attribute_exists, attribute_not_exists and begins_with functions.
+// Returns kvs.ErrInternal for anything else.
+func evaluateCondition(
+	expression string,
+	names map[string]string,
+	values map[string]types.AttributeValue,
+	item map[string]types.AttributeValue,
+) (bool, error) {
+	parser := &conditionParser{
+		tokens: tokenizeCondition(expression),
+		names:  names,
+		values: values,
+		item:   item,
+	}
+	if err := parser.checkPlaceholders(); err != nil {
+		return false, err
+	}
+
+	result, err := parser.parseOr()
+	if err != nil {
+		return false, err
+	}
+	if parser.position != len(parser.tokens) {
+		return false, kvs.ErrInternal
+	}
+
+	return result, nil
+}
+
+// conditionParser is a recursive-descent evaluator over the tokens of a condition expression.
+type conditionParser struct {
+	names    map[string]string
+	values   map[string]types.AttributeValue
+	item     map[string]types.AttributeValue
+	tokens   []string
+	position int
+}
+
+func (r *conditionParser) parseOr() (bool, error) {
+	result, err := r.parseAnd()
+	if err != nil {
+		return false, err
+	}
+
+	for r.acceptKeyword("OR") {
+		right, err := r.parseAnd()
+		if err != nil {
+			return false, err
+		}
+		result = result || right
+	}
+
+	return result, nil
+}
+
+func (r *conditionParser) parseAnd() (bool, error) {
+	result, err := r.parseUnary()
+	if err != nil {
+		return false, err
+	}
+
+	for r.acceptKeyword("AND") {
+		right, err := r.parseUnary()
+		if err != nil {
+			return false, err
+		}
+		result = result && right
+	}
+
+	return result, nil
+}
+
+func (r *conditionParser) parseUnary() (bool, error) {
+	if r.acceptKeyword("NOT") {
+		result, err := r.parseUnary()
+		return !result, err
+	}
+
+	if r.accept("(") {
+		result, err := r.parseOr()
+		if err != nil {
+			return false, err
+		}
+		if !r.accept(")") {
+			return false, kvs.ErrInternal
+		}
+		return result, nil
+	}
+
+	token := r.next()
+	switch strings.ToLower(token) {
+	case "attribute_exists", "attribute_not_exists":
+		arguments, err := r.parseArguments(1)
+		if err != nil {
+			return false, err
+		}
+		_, exists := r.operand(arguments[0])
+		return exists == (strings.ToLower(token) == "attribute_exists"), nil
+	case "begins_with":
+		arguments, err := r.parseArguments(2)
+		if err != nil {
+			return false, err
+		}
+		value, found := r.operand(arguments[0])
+		prefix, prefixFound := r.operand(arguments[1])
+		valueString, isString := value.(*types.AttributeValueMemberS)
+		prefixString, prefixIsString := prefix.(*types.AttributeValueMemberS)
+		return found && prefixFound && isString && prefixIsString &&
+			strings.HasPrefix(valueString.Value, prefixString.Value), nil
+	}
+
+	comparator := r.next()
+	if strings.EqualFold(comparator, "BETWEEN") {
+		return r.parseBetween(token)
+	}
+	if !slices.Contains([]string{"=", "<>", "<", "<=", ">", ">="}, comparator) {
+		return false, kvs.ErrInternal
+	}
+	right := r.next()
+	left, leftFound := r.operand(token)
+	value, rightFound := r.operand(right)
+	if !leftFound || !rightFound {
+		return false, nil
+	}
+
+	return compareAttributes(left, comparator, value)
+}
+
+// parseBetween evaluates "token BETWEEN low AND high", both bounds included.
+func (r *conditionParser) parseBetween(token string) (bool, error) {
+	lowToken := r.next()
+	if !r.acceptKeyword("AND") {
+		return false, kvs.ErrInternal
+	}
+	highToken := r.next()
+
+	value, found := r.operand(token)
+	low, lowFound := r.operand(lowToken)
+	high, highFound := r.operand(highToken)
+	if !found || !lowFound || !highFound {
+		return false, nil
+	}
+
+	above, err := compareAttributes(value, ">=", low)
+	if err != nil || !above {
+		return false, err
+	}
+	return compareAttributes(value, "<=", high)
+}
+
+// checkPlaceholders returns kvs.ErrInternal if a name or value placeholder is not defined,
+// as DynamoDB rejects such expressions.
+func (r *conditionParser) checkPlaceholders() error {
+	for _, token := range r.tokens {
+		if strings.HasPrefix(token, ":") {
+			if _, found := r.values[token]; !found {
+				return kvs.ErrInternal
+			}
+			continue
+		}
+		for segment := range strings.SplitSeq(token, ".") {
+			if _, found := r.names[segment]; strings.HasPrefix(segment, "#") && !found {
+				return kvs.ErrInternal
+			}
+		}
+	}
+	return nil
+}
+
+// parseArguments reads a parenthesized list of count operands.
+func (r *conditionParser) parseArguments(count int) ([]string, error) {
+	if !r.accept("(") {
+		return nil, kvs.ErrInternal
+	}
+
+	arguments := make([]string, 0, count)
+	for i := range count {
+		if i > 0 && !r.accept(",") {
+			return nil, kvs.ErrInternal
+		}
+		arguments = append(arguments, r.next())
+	}
+
+	if !r.accept(")") {
+		return nil, kvs.ErrInternal
+	}
+
+	return arguments, nil
+}
+
+// operand resolves a value placeholder or a document path of attribute names and name placeholders.
+func (r *conditionParser) operand(token string) (types.AttributeValue, bool) {
+	if strings.HasPrefix(token, ":") {
+		value, found := r.values[token]
+		return value, found
+	}
+
+	path, err := parsePath(token, r.names)
+	if err != nil {
+		return nil, false
+	}
+
+	return resolvePath(r.item, path)
+}
+
+func (r *conditionParser) next() string {
+	if r.position >= len(r.tokens) {
+		return ""
+	}
+	token := r.tokens[r.position]
+	r.position++
+	return token
+}
+
+func (r *conditionParser) accept(token string) bool {
+	if r.position < len(r.tokens) && r.tokens[r.position] == token {
+		r.position++
+		return true
+	}
+	return false
+}
+
+func (r *conditionParser) acceptKeyword(keyword string) bool {
+	if r.position < len(r.tokens) && strings.EqualFold(r.tokens[r.position], keyword) {
+		r.position++
+		return true
+	}
+	return false
+}
+
+// compareAttributes applies comparator to two string or two number attributes, or tests
+// two attributes of any other type for equality.
+func compareAttributes(left types.AttributeValue, comparator string, right types.AttributeValue) (bool, error) {
+	var order int
+	switch leftValue := left.(type) {
+	case *types.AttributeValueMemberS:
+		rightValue, ok := right.(*types.AttributeValueMemberS)
+		if !ok {
+			return false, nil
+		}
+		order = strings.Compare(leftValue.Value, rightValue.Value)
+	case *types.AttributeValueMemberN:
+		rightValue, ok := right.(*types.AttributeValueMemberN)
+		if !ok {
+			return false, nil
+		}
+		leftNumber, err := strconv.ParseFloat(leftValue.Value, 64)
+		if err != nil {
+			return false, kvs.ErrConvert
+		}
+		rightNumber, err := strconv.ParseFloat(rightValue.Value, 64)
+		if err != nil {
+			return false, kvs.ErrConvert
+		}
+		switch {
+		case leftNumber < rightNumber:
+			order = -1
+		case leftNumber > rightNumber:
+			order = 1
+		}
+	default:
+		if comparator == "=" || comparator == "<>" {
+			return attributesEqual(left, right) == (comparator == "="), nil
+		}
+		return false, kvs.ErrInternal
+	}
+
+	switch comparator {
+	case "=":
+		return order == 0, nil
+	case "<>":
+		return order != 0, nil
+	case "<":
+		return order < 0, nil
+	case "<=":
+		return order <= 0, nil
+	case ">":
+		return order > 0, nil
+	case ">=":
+		return order >= 0, nil
+	default:
+		return false, kvs.ErrInternal
+	}
+}
+
+// tokenizeCondition splits a condition expression into names, placeholders, comparators and punctuation.
+func tokenizeCondition(expression string) []string {
+	var tokens []string
+	for i := 0; i < len(expression); {
+		char := rune(expression[i])
+		switch {
+		case unicode.IsSpace(char):
+			i++
+		case strings.ContainsRune("(),", char):
+			tokens = append(tokens, string(char))
+			i++
+		case strings.ContainsRune("=<>", char):
+			end := i + 1
+			for end < len(expression) && strings.ContainsRune("=<>", rune(expression[end])) {
+				end++
+			}
+			tokens = append(tokens, expression[i:end])
+			i = end
+		default:
+			end := i + 1
+			for end < len(expression) && !unicode.IsSpace(rune(expression[end])) &&
+				!strings.ContainsRune("(),=<>", rune(expression[end])) {
+				end++
+			}
+			tokens = append(tokens, expression[i:end])
+			i = end
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,91 @@
+// Package dynamodb provides AWS DynamoDB specific implementation of the KVS client.
+package dynamodb
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+)
+
+// fakeFaults holds the faults injected in an AWSFakeClient.
+type fakeFaults struct {
+	throttled   map[string]int // Remaining throttled calls by operation, "" for any operation
+	unprocessed int            // Remaining batch requests to leave unprocessed
+	mu          sync.Mutex
+}
+
+// newFakeFaults returns a fakeFaults injecting nothing.
+func newFakeFaults() *fakeFaults {
+	return &fakeFaults{throttled: map[string]int{}}
+}
+
+// InjectThrottling makes the next times calls of each of operations ("GetItem", "BatchWriteItem", ...),
+// or of any operation when none is given, fail with a *types.ProvisionedThroughputExceededException,
+// as DynamoDB does when a table exceeds its throughput. The AWS SDK retries these errors; the fake
+// does not, so that retries can be tested. Injections add up.
+func (r AWSFakeClient) InjectThrottling(times int, operations ...string) {
+	r.faults.mu.Lock()
+	defer r.faults.mu.Unlock()
+
+	if len(operations) == 0 {
+		operations = []string{""}
+	}
+	for _, operation := range operations {
+		r.faults.throttled[operation] += times
+	}
+}
+
+// InjectUnprocessed makes the next count keys of BatchGetItem requests and write requests of
+// BatchWriteItem requests come back in UnprocessedKeys and UnprocessedItems, without being read
+// or applied, as DynamoDB does when a batch exceeds the throughput of the table. Injections add up.
+func (r AWSFakeClient) InjectUnprocessed(count int) {
+	r.faults.mu.Lock()
+	defer r.faults.mu.Unlock()
+
+	r.faults.unprocessed += count
+}
+
+// start begins operation: it fails with the error of ctx once ctx is done, as the AWS SDK
+// does, or else with an injected throttling of operation.
+func (r AWSFakeClient) start(ctx context.Context, operation string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return r.faults.throttle(operation)
+}
+
+// throttle consumes an injected throttling of operation, if any, and returns its error.
+func (r *fakeFaults) throttle(operation string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, name := range []string{operation, ""} {
+		if r.throttled[name] > 0 {
+			r.throttled[name]--
+			return &types.ProvisionedThroughputExceededException{
+				Message: aws.String("The level of configured provisioned throughput for the table was exceeded"),
+			}
+		}
+	}
+	return nil
+}
+
+// unprocess consumes an injected unprocessed request, and reports whether there was one.
+func (r *fakeFaults) unprocess() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.unprocessed > 0 {
+		r.unprocessed--
+		return true
+	}
+	return false
+}
+
+// errFakeValidation returns the ValidationException of DynamoDB with message.
+func errFakeValidation(message string) error {
+	return &smithy.GenericAPIError{Code: "ValidationException", Message: message}
+}
@@ -0,0 +1,65 @@
+// Package dynamodb provides AWS DynamoDB specific implementation of the KVS client.
+package dynamodb
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// fakeIndexes holds the partition key attributes of the global secondary indexes declared on
+// an AWSFakeClient. Index entries are derived from the items when a Query reads an index:
+// items without a string index key attribute are left out of the index, as DynamoDB does.
+type fakeIndexes struct {
+	attributes map[string]string // Partition key attribute of each index
+	mu         sync.Mutex
+}
+
+// WithFakeSecondaryIndexes returns an AWSFakeClientOptions that declares global secondary
+// indexes of the fake tables, which can then be read with Query and IndexName. The indexes
+// of the tables created by CreateTable are declared implicitly.
+func WithFakeSecondaryIndexes(indexes ...SecondaryIndex) AWSFakeClientOptions {
+	return func(f *AWSFakeClient) {
+		f.indexes.mu.Lock()
+		defer f.indexes.mu.Unlock()
+
+		for _, index := range indexes {
+			f.indexes.attributes[index.Name] = index.Attribute
+		}
+	}
+}
+
+func newFakeIndexes() *fakeIndexes {
+	return &fakeIndexes{attributes: map[string]string{}}
+}
+
+// indexAttribute returns the partition key attribute of the index name of table: an index
+// declared with WithFakeSecondaryIndexes, or an index of the table created by CreateTable.
+func (r AWSFakeClient) indexAttribute(table, name string) (string, bool) {
+	r.indexes.mu.Lock()
+	attribute, found := r.indexes.attributes[name]
+	r.indexes.mu.Unlock()
+	if found {
+		return attribute, true
+	}
+
+	r.tables.mu.Lock()
+	defer r.tables.mu.Unlock()
+
+	description, found := r.tables.tables[table]
+	if !found {
+		return "", false
+	}
+	for _, index := range description.GlobalSecondaryIndexes {
+		if aws.ToString(index.IndexName) != name {
+			continue
+		}
+		for _, element := range index.KeySchema {
+			if element.KeyType == types.KeyTypeHash {
+				return aws.ToString(element.AttributeName), true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,409 @@
+// Package dynamodb provides AWS DynamoDB specific implementation of the KVS client.
+package dynamodb
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// fakeAttribute is the DynamoDB JSON representation of an attribute value, used by the
+// Query pagination tokens.
+type fakeAttribute struct {
+	S    *string                   `json:"S,omitempty"`
+	N    *string                   `json:"N,omitempty"`
+	B    []byte                    `json:"B,omitempty"`
+	BOOL *bool                     `json:"BOOL,omitempty"`
+	NULL bool                      `json:"NULL,omitempty"`
+	M    *map[string]fakeAttribute `json:"M,omitempty"`
+	L    *[]fakeAttribute          `json:"L,omitempty"`
+	SS   []string                  `json:"SS,omitempty"`
+	NS   []string                  `json:"NS,omitempty"`
+	BS   [][]byte                  `json:"BS,omitempty"`
+}
+
+// encodeItem serialises an item to DynamoDB JSON.
+func encodeItem(item map[string]types.AttributeValue) ([]byte, error) {
+	attributes := make(map[string]fakeAttribute, len(item))
+	for name, value := range item {
+		attribute, err := toFakeAttribute(value)
+		if err != nil {
+			return nil, err
+		}
+		attributes[name] = attribute
+	}
+	return json.Marshal(attributes)
+}
+
+// decodeItem parses an item serialised by encodeItem.
+func decodeItem(bytes []byte) (map[string]types.AttributeValue, error) {
+	var attributes map[string]fakeAttribute
+	if err := json.Unmarshal(bytes, &attributes); err != nil {
+		return nil, err
+	}
+
+	item := make(map[string]types.AttributeValue, len(attributes))
+	for name, attribute := range attributes {
+		item[name] = fromFakeAttribute(attribute)
+	}
+	return item, nil
+}
+
+func toFakeAttribute(value types.AttributeValue) (fakeAttribute, error) {
+	switch member := value.(type) {
+	case *types.AttributeValueMemberS:
+		return fakeAttribute{S: aws.String(member.Value)}, nil
+	case *types.AttributeValueMemberN:
+		return fakeAttribute{N: aws.String(member.Value)}, nil
+	case *types.AttributeValueMemberB:
+		return fakeAttribute{B: member.Value}, nil
+	case *types.AttributeValueMemberBOOL:
+		return fakeAttribute{BOOL: aws.Bool(member.Value)}, nil
+	case *types.AttributeValueMemberNULL:
+		return fakeAttribute{NULL: true}, nil
+	case *types.AttributeValueMemberM:
+		attributes := make(map[string]fakeAttribute, len(member.Value))
+		for name, nested := range member.Value {
+			attribute, err := toFakeAttribute(nested)
+			if err != nil {
+				return fakeAttribute{}, err
+			}
+			attributes[name] = attribute
+		}
+		return fakeAttribute{M: &attributes}, nil
+	case *types.AttributeValueMemberL:
+		attributes := make([]fakeAttribute, len(member.Value))
+		for i, nested := range member.Value {
+			attribute, err := toFakeAttribute(nested)
+			if err != nil {
+				return fakeAttribute{}, err
+			}
+			attributes[i] = attribute
+		}
+		return fakeAttribute{L: &attributes}, nil
+	case *types.AttributeValueMemberSS:
+		return fakeAttribute{SS: member.Value}, nil
+	case *types.AttributeValueMemberNS:
+		return fakeAttribute{NS: member.Value}, nil
+	case *types.AttributeValueMemberBS:
+		return fakeAttribute{BS: member.Value}, nil
+	default:
+		return fakeAttribute{}, kvs.ErrConvert
+	}
+}
+
+func fromFakeAttribute(attribute fakeAttribute) types.AttributeValue {
+	switch {
+	case attribute.S != nil:
+		return &types.AttributeValueMemberS{Value: *attribute.S}
+	case attribute.N != nil:
+		return &types.AttributeValueMemberN{Value: *attribute.N}
+	case attribute.B != nil:
+		return &types.AttributeValueMemberB{Value: attribute.B}
+	case attribute.BOOL != nil:
+		return &types.AttributeValueMemberBOOL{Value: *attribute.BOOL}
+	case attribute.M != nil:
+		values := make(map[string]types.AttributeValue, len(*attribute.M))
+		for name, nested := range *attribute.M {
+			values[name] = fromFakeAttribute(nested)
+		}
+		return &types.AttributeValueMemberM{Value: values}
+	case attribute.L != nil:
+		values := make([]types.AttributeValue, len(*attribute.L))
+		for i, nested := range *attribute.L {
+			values[i] = fromFakeAttribute(nested)
+		}
+		return &types.AttributeValueMemberL{Value: values}
+	case attribute.SS != nil:
+		return &types.AttributeValueMemberSS{Value: attribute.SS}
+	case attribute.NS != nil:
+		return &types.AttributeValueMemberNS{Value: attribute.NS}
+	case attribute.BS != nil:
+		return &types.AttributeValueMemberBS{Value: attribute.BS}
+	default:
+		return &types.AttributeValueMemberNULL{Value: true}
+	}
+}
+
+// attributesEqual reports whether two attribute values are deeply equal.
+func attributesEqual(left, right types.AttributeValue) bool {
+	return reflect.DeepEqual(left, right)
+}
+
+// errInvalidDocumentPath is the error returned by DynamoDB when an update
+// expression refers to a nested attribute whose parent does not exist.
+var errInvalidDocumentPath = &smithy.GenericAPIError{
+	Code:    "ValidationException",
+	Message: "The document path provided in the update expression is invalid for update",
+}
+
+// parsePath resolves a document path such as "#a.#b" or "a.b" to its attribute
+// names. List indexes are not supported and return kvs.ErrInternal.
+func parsePath(path string, names map[string]string) ([]string, error) {
+	if path == "" || strings.ContainsAny(path, "[]") {
+		return nil, kvs.ErrInternal
+	}
+
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		if alias, found := names[segment]; found {
+			segments[i] = alias
+			continue
+		}
+		if segment == "" || strings.HasPrefix(segment, "#") || strings.HasPrefix(segment, ":") {
+			return nil, kvs.ErrInternal
+		}
+	}
+	return segments, nil
+}
+
+// resolvePath returns the value found at a document path of item.
+func resolvePath(item map[string]types.AttributeValue, path []string) (types.AttributeValue, bool) {
+	attributes := item
+	for i, name := range path {
+		value, found := attributes[name]
+		if !found {
+			return nil, false
+		}
+		if i == len(path)-1 {
+			return value, true
+		}
+		nested, ok := value.(*types.AttributeValueMemberM)
+		if !ok {
+			return nil, false
+		}
+		attributes = nested.Value
+	}
+	return nil, false
+}
+
+// projectItem applies a ProjectionExpression to item: only the listed document
+// paths are kept, nested maps being copied down to the projected attributes.
+func projectItem(
+	item map[string]types.AttributeValue,
+	expression string,
+	names map[string]string,
+) (map[string]types.AttributeValue, error) {
+	projected := map[string]types.AttributeValue{}
+	for _, rawPath := range strings.Split(expression, ",") {
+		path, err := parsePath(strings.TrimSpace(rawPath), names)
+		if err != nil {
+			return nil, err
+		}
+
+		value, found := resolvePath(item, path)
+		if !found {
+			continue
+		}
+
+		target := projected
+		for _, name := range path[:len(path)-1] {
+			nested, ok := target[name].(*types.AttributeValueMemberM)
+			if !ok {
+				nested = &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{}}
+				target[name] = nested
+			}
+			target = nested.Value
+		}
+		target[path[len(path)-1]] = value
+	}
+	return projected, nil
+}
+
+// applyUpdate evaluates an update expression on item in place and returns the
+// top-level attributes it has changed. The key attributes cannot be updated. It supports SET with a value placeholder,
+// another path or if_not_exists(path, :value); REMOVE; and ADD on numbers.
+// Returns kvs.ErrInternal for anything else, kvs.ErrConvert when ADD meets a
+// non-number, and errInvalidDocumentPath when a nested path has no parent.
+func applyUpdate(
+	item map[string]types.AttributeValue,
+	keys []string,
+	expression string,
+	names map[string]string,
+	values map[string]types.AttributeValue,
+) ([]string, error) {
+	updater := &updateParser{
+		conditionParser: conditionParser{tokens: tokenizeCondition(expression), names: names, values: values},
+		item:            item,
+		keys:            keys,
+	}
+
+	for updater.position < len(updater.tokens) {
+		clause := strings.ToUpper(updater.next())
+		for {
+			var err error
+			switch clause {
+			case "SET":
+				err = updater.set()
+			case "REMOVE":
+				err = updater.remove()
+			case "ADD":
+				err = updater.add()
+			default:
+				return nil, kvs.ErrInternal
+			}
+			if err != nil {
+				return nil, err
+			}
+			if !updater.accept(",") {
+				break
+			}
+		}
+	}
+
+	return updater.updated, nil
+}
+
+// updateParser evaluates the actions of an update expression.
+type updateParser struct {
+	item    map[string]types.AttributeValue
+	keys    []string
+	updated []string
+	conditionParser
+}
+
+func (r *updateParser) set() error {
+	path, err := r.path()
+	if err != nil {
+		return err
+	}
+	if !r.accept("=") {
+		return kvs.ErrInternal
+	}
+
+	token := r.next()
+	var value types.AttributeValue
+	var found bool
+	if strings.EqualFold(token, "if_not_exists") {
+		arguments, err := r.parseArguments(2)
+		if err != nil {
+			return err
+		}
+		existing, err := parsePath(arguments[0], r.names)
+		if err != nil {
+			return err
+		}
+		if value, found = resolvePath(r.item, existing); !found {
+			value, found = r.values[arguments[1]]
+		}
+	} else {
+		value, found, err = r.value(token)
+		if err != nil {
+			return err
+		}
+	}
+	if !found {
+		return kvs.ErrInternal
+	}
+
+	return r.write(path, value)
+}
+
+func (r *updateParser) remove() error {
+	path, err := r.path()
+	if err != nil {
+		return err
+	}
+
+	parent, found := r.item, true
+	if len(path) > 1 {
+		var value types.AttributeValue
+		value, found = resolvePath(r.item, path[:len(path)-1])
+		nested, ok := value.(*types.AttributeValueMemberM)
+		if found && ok {
+			parent = nested.Value
+		}
+		found = found && ok
+	}
+	if found {
+		delete(parent, path[len(path)-1])
+		r.touch(path)
+	}
+	return nil
+}
+
+func (r *updateParser) add() error {
+	path, err := r.path()
+	if err != nil {
+		return err
+	}
+
+	delta, found := r.values[r.next()]
+	deltaNumber, ok := delta.(*types.AttributeValueMemberN)
+	if !found || !ok {
+		return kvs.ErrConvert
+	}
+
+	current := "0"
+	if value, exists := resolvePath(r.item, path); exists {
+		number, isNumber := value.(*types.AttributeValueMemberN)
+		if !isNumber {
+			return kvs.ErrConvert
+		}
+		current = number.Value
+	}
+
+	sum, err := addNumbers(current, deltaNumber.Value)
+	if err != nil {
+		return err
+	}
+	return r.write(path, &types.AttributeValueMemberN{Value: sum})
+}
+
+// path reads the document path of an action, which must not be a key attribute.
+func (r *updateParser) path() ([]string, error) {
+	path, err := parsePath(r.next(), r.names)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(r.keys, path[0]) {
+		return nil, errInvalidDocumentPath
+	}
+	return path, nil
+}
+
+// value resolves the operand of a SET action: a value placeholder or a path.
+func (r *updateParser) value(token string) (types.AttributeValue, bool, error) {
+	if strings.HasPrefix(token, ":") {
+		value, found := r.values[token]
+		return value, found, nil
+	}
+
+	path, err := parsePath(token, r.names)
+	if err != nil {
+		return nil, false, err
+	}
+	value, found := resolvePath(r.item, path)
+	return value, found, nil
+}
+
+// write stores value at path; the parent of a nested path must be a map.
+func (r *updateParser) write(path []string, value types.AttributeValue) error {
+	parent := r.item
+	if len(path) > 1 {
+		existing, found := resolvePath(r.item, path[:len(path)-1])
+		nested, ok := existing.(*types.AttributeValueMemberM)
+		if !found || !ok {
+			return errInvalidDocumentPath
+		}
+		parent = nested.Value
+	}
+
+	parent[path[len(path)-1]] = value
+	r.touch(path)
+	return nil
+}
+
+// touch records the top-level attribute changed by an action.
+func (r *updateParser) touch(path []string) {
+	if !slices.Contains(r.updated, path[0]) {
+		r.updated = append(r.updated, path[0])
+	}
+}
@@ -0,0 +1,165 @@
+// Package dynamodb provides AWS DynamoDB specific implementation of the KVS client.
+package dynamodb
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// fakeStore holds the items of every table of an AWSFakeClient, by table name and cache key.
+// Tables are created implicitly by their first write. Items are cloned on the way in and out,
+// so that callers never share attribute values with the store.
+type fakeStore struct {
+	tables map[string]map[string]map[string]types.AttributeValue
+	mu     sync.RWMutex
+}
+
+// newFakeStore returns an empty fakeStore.
+func newFakeStore() *fakeStore {
+	return &fakeStore{tables: map[string]map[string]map[string]types.AttributeValue{}}
+}
+
+// get returns the item stored under key in table, or nil if there is none.
+func (r *fakeStore) get(table, key string) map[string]types.AttributeValue {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	item, found := r.tables[table][key]
+	if !found {
+		return nil
+	}
+	return cloneItem(item)
+}
+
+// put stores item under key in table.
+func (r *fakeStore) put(table, key string, item map[string]types.AttributeValue) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items, found := r.tables[table]
+	if !found {
+		items = map[string]map[string]types.AttributeValue{}
+		r.tables[table] = items
+	}
+	items[key] = cloneItem(item)
+}
+
+// delete removes the item stored under key in table, if any.
+func (r *fakeStore) delete(table, key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.tables[table], key)
+}
+
+// snapshot returns a copy of the items of table, by cache key.
+func (r *fakeStore) snapshot(table string) map[string]map[string]types.AttributeValue {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := make(map[string]map[string]types.AttributeValue, len(r.tables[table]))
+	for key, item := range r.tables[table] {
+		items[key] = cloneItem(item)
+	}
+	return items
+}
+
+// cloneItem returns a deep copy of item.
+func cloneItem(item map[string]types.AttributeValue) map[string]types.AttributeValue {
+	clone := make(map[string]types.AttributeValue, len(item))
+	for name, value := range item {
+		clone[name] = cloneAttribute(value)
+	}
+	return clone
+}
+
+// cloneAttribute returns a deep copy of an attribute value.
+func cloneAttribute(value types.AttributeValue) types.AttributeValue {
+	switch member := value.(type) {
+	case *types.AttributeValueMemberS:
+		return &types.AttributeValueMemberS{Value: member.Value}
+	case *types.AttributeValueMemberN:
+		return &types.AttributeValueMemberN{Value: member.Value}
+	case *types.AttributeValueMemberB:
+		return &types.AttributeValueMemberB{Value: append([]byte(nil), member.Value...)}
+	case *types.AttributeValueMemberBOOL:
+		return &types.AttributeValueMemberBOOL{Value: member.Value}
+	case *types.AttributeValueMemberNULL:
+		return &types.AttributeValueMemberNULL{Value: member.Value}
+	case *types.AttributeValueMemberM:
+		return &types.AttributeValueMemberM{Value: cloneItem(member.Value)}
+	case *types.AttributeValueMemberL:
+		list := make([]types.AttributeValue, len(member.Value))
+		for i, element := range member.Value {
+			list[i] = cloneAttribute(element)
+		}
+		return &types.AttributeValueMemberL{Value: list}
+	case *types.AttributeValueMemberSS:
+		return &types.AttributeValueMemberSS{Value: append([]string(nil), member.Value...)}
+	case *types.AttributeValueMemberNS:
+		return &types.AttributeValueMemberNS{Value: append([]string(nil), member.Value...)}
+	case *types.AttributeValueMemberBS:
+		set := make([][]byte, len(member.Value))
+		for i, element := range member.Value {
+			set[i] = append([]byte(nil), element...)
+		}
+		return &types.AttributeValueMemberBS{Value: set}
+	default:
+		return value
+	}
+}
+
+// MaxItemSize is the maximum size of a DynamoDB item, 400 KB.
+const MaxItemSize = 400 * 1024
+
+// itemSize returns the size of an item as DynamoDB computes it: the lengths of the
+// attribute names plus the sizes of the attribute values.
+func itemSize(item map[string]types.AttributeValue) int {
+	size := 0
+	for name, value := range item {
+		size += len(name) + attributeSize(value)
+	}
+	return size
+}
+
+// attributeSize returns the size of an attribute value. Numbers take about one byte per
+// two significant digits plus one; documents take 3 bytes plus their elements.
+func attributeSize(value types.AttributeValue) int {
+	switch member := value.(type) {
+	case *types.AttributeValueMemberS:
+		return len(member.Value)
+	case *types.AttributeValueMemberN:
+		return len(member.Value)/2 + 1
+	case *types.AttributeValueMemberB:
+		return len(member.Value)
+	case *types.AttributeValueMemberM:
+		return 3 + itemSize(member.Value)
+	case *types.AttributeValueMemberL:
+		size := 3
+		for _, element := range member.Value {
+			size += attributeSize(element)
+		}
+		return size
+	case *types.AttributeValueMemberSS:
+		size := 0
+		for _, element := range member.Value {
+			size += len(element)
+		}
+		return size
+	case *types.AttributeValueMemberNS:
+		size := 0
+		for _, element := range member.Value {
+			size += len(element)/2 + 1
+		}
+		return size
+	case *types.AttributeValueMemberBS:
+		size := 0
+		for _, element := range member.Value {
+			size += len(element)
+		}
+		return size
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,180 @@
+// Package dynamodb provides AWS DynamoDB specific implementation of the KVS client.
+package dynamodb
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+)
+
+// fakeTables holds the descriptions of the tables created on an AWSFakeClient. Items can be
+// stored in a table whether it has been created or not, but items only expire in the tables
+// on which TTL has been enabled with UpdateTimeToLive.
+type fakeTables struct {
+	tables map[string]*types.TableDescription
+	ttl    map[string]*types.TimeToLiveDescription
+	mu     sync.Mutex
+}
+
+// newFakeTables returns an empty fakeTables.
+func newFakeTables() *fakeTables {
+	return &fakeTables{
+		tables: map[string]*types.TableDescription{},
+		ttl:    map[string]*types.TimeToLiveDescription{},
+	}
+}
+
+// errFakeTableNotFound returns the error of DynamoDB for a missing table.
+func errFakeTableNotFound(tableName *string) error {
+	return &types.ResourceNotFoundException{
+		Message: aws.String("Requested resource not found: Table: " + aws.ToString(tableName) + " not found"),
+	}
+}
+
+// DescribeTable implements the TableClient interface. Tables created by CreateTable are
+// ACTIVE immediately; other tables are reported as missing.
+func (r AWSFakeClient) DescribeTable(
+	_ context.Context,
+	params *dynamodb.DescribeTableInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.DescribeTableOutput, error) {
+	r.tables.mu.Lock()
+	defer r.tables.mu.Unlock()
+
+	table, found := r.tables.tables[aws.ToString(params.TableName)]
+	if !found {
+		return nil, errFakeTableNotFound(params.TableName)
+	}
+	description := *table
+	return &dynamodb.DescribeTableOutput{Table: &description}, nil
+}
+
+// CreateTable implements the TableClient interface. The key attributes of the table and of its
+// global secondary indexes must be declared in AttributeDefinitions.
+// Returns a *types.ResourceInUseException if the table exists.
+func (r AWSFakeClient) CreateTable(
+	_ context.Context,
+	params *dynamodb.CreateTableInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.CreateTableOutput, error) {
+	r.tables.mu.Lock()
+	defer r.tables.mu.Unlock()
+
+	tableName := aws.ToString(params.TableName)
+	if _, found := r.tables.tables[tableName]; found {
+		return nil, &types.ResourceInUseException{Message: aws.String("Table already exists: " + tableName)}
+	}
+
+	defined := map[string]bool{}
+	for _, definition := range params.AttributeDefinitions {
+		defined[aws.ToString(definition.AttributeName)] = true
+	}
+	schemas := [][]types.KeySchemaElement{params.KeySchema}
+	for _, index := range params.GlobalSecondaryIndexes {
+		schemas = append(schemas, index.KeySchema)
+	}
+	for _, schema := range schemas {
+		if len(schema) == 0 {
+			return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "KeySchema is required"}
+		}
+		for _, element := range schema {
+			if !defined[aws.ToString(element.AttributeName)] {
+				return nil, &smithy.GenericAPIError{
+					Code:    "ValidationException",
+					Message: "Key attribute " + aws.ToString(element.AttributeName) + " is not defined",
+				}
+			}
+		}
+	}
+
+	description := &types.TableDescription{
+		TableName:            params.TableName,
+		TableStatus:          types.TableStatusActive,
+		KeySchema:            params.KeySchema,
+		AttributeDefinitions: params.AttributeDefinitions,
+		BillingModeSummary:   &types.BillingModeSummary{BillingMode: params.BillingMode},
+	}
+	for _, index := range params.GlobalSecondaryIndexes {
+		description.GlobalSecondaryIndexes = append(description.GlobalSecondaryIndexes,
+			types.GlobalSecondaryIndexDescription{
+				IndexName:   index.IndexName,
+				IndexStatus: types.IndexStatusActive,
+				KeySchema:   index.KeySchema,
+				Projection:  index.Projection,
+			})
+	}
+	r.tables.tables[tableName] = description
+	r.tables.ttl[tableName] = &types.TimeToLiveDescription{TimeToLiveStatus: types.TimeToLiveStatusDisabled}
+
+	created := *description
+	return &dynamodb.CreateTableOutput{TableDescription: &created}, nil
+}
+
+// DescribeTimeToLive implements the TableClient interface.
+func (r AWSFakeClient) DescribeTimeToLive(
+	_ context.Context,
+	params *dynamodb.DescribeTimeToLiveInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.DescribeTimeToLiveOutput, error) {
+	r.tables.mu.Lock()
+	defer r.tables.mu.Unlock()
+
+	ttl, found := r.tables.ttl[aws.ToString(params.TableName)]
+	if !found {
+		return nil, errFakeTableNotFound(params.TableName)
+	}
+	description := *ttl
+	return &dynamodb.DescribeTimeToLiveOutput{TimeToLiveDescription: &description}, nil
+}
+
+// UpdateTimeToLive implements the TableClient interface. The change is applied immediately.
+// Like DynamoDB, enabling TTL when it is already enabled is a ValidationException.
+func (r AWSFakeClient) UpdateTimeToLive(
+	_ context.Context,
+	params *dynamodb.UpdateTimeToLiveInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.UpdateTimeToLiveOutput, error) {
+	r.tables.mu.Lock()
+	defer r.tables.mu.Unlock()
+
+	tableName := aws.ToString(params.TableName)
+	ttl, found := r.tables.ttl[tableName]
+	if !found {
+		return nil, errFakeTableNotFound(params.TableName)
+	}
+	if params.TimeToLiveSpecification == nil {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "TimeToLiveSpecification is required"}
+	}
+
+	specification := *params.TimeToLiveSpecification
+	enabled := aws.ToBool(specification.Enabled)
+	if enabled == (ttl.TimeToLiveStatus == types.TimeToLiveStatusEnabled) {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "TimeToLive is already in the requested state"}
+	}
+
+	status := types.TimeToLiveStatusDisabled
+	if enabled {
+		status = types.TimeToLiveStatusEnabled
+	}
+	r.tables.ttl[tableName] = &types.TimeToLiveDescription{
+		AttributeName:    specification.AttributeName,
+		TimeToLiveStatus: status,
+	}
+	return &dynamodb.UpdateTimeToLiveOutput{TimeToLiveSpecification: &specification}, nil
+}
+
+// ttlAttributeName returns the TTL attribute of table, if TTL is enabled on it.
+func (r AWSFakeClient) ttlAttributeName(table string) (string, bool) {
+	r.tables.mu.Lock()
+	defer r.tables.mu.Unlock()
+
+	ttl, found := r.tables.ttl[table]
+	if !found || ttl.TimeToLiveStatus != types.TimeToLiveStatusEnabled {
+		return "", false
+	}
+	return aws.ToString(ttl.AttributeName), true
+}
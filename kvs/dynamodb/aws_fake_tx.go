@@ -0,0 +1,184 @@
+// Package dynamodb provides AWS DynamoDB specific implementation of the KVS client.
+package dynamodb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// errTransactionConflict is the error returned by DynamoDB when a transaction has
+// several actions on the same item.
+var errTransactionConflict = &smithy.GenericAPIError{
+	Code:    "ValidationException",
+	Message: "Transaction request cannot include multiple operations on one item",
+}
+
+// fakeTxAction is a TransactWriteItem resolved to its cache key and condition.
+type fakeTxAction struct {
+	item      map[string]types.AttributeValue // Item written by a Put
+	condition *string
+	names     map[string]string
+	values    map[string]types.AttributeValue
+	table     string
+	key       string
+	delete    bool
+	write     bool
+}
+
+// TransactWriteItems implements the AWSClient interface for atomic multi-item writes.
+// Put, Delete and ConditionCheck actions are supported; Update returns kvs.ErrInternal.
+// Like DynamoDB, a transaction holds between 1 and 100 actions, or a ValidationException is returned.
+// Conditions are evaluated as in PutItem, all of them before any write: when one does
+// not hold, nothing is written and a *types.TransactionCanceledException reports a
+// ConditionalCheckFailed reason for each failed action, "None" for the others.
+func (r AWSFakeClient) TransactWriteItems(
+	ctx context.Context,
+	params *dynamodb.TransactWriteItemsInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.TransactWriteItemsOutput, error) {
+	if err := r.start(ctx, "TransactWriteItems"); err != nil {
+		return nil, err
+	}
+	if err := checkBatchSize(len(params.TransactItems), kvs.MaxTxOperations, "TransactWriteItems"); err != nil {
+		return nil, err
+	}
+
+	actions := make([]fakeTxAction, len(params.TransactItems))
+	seen := map[string]bool{}
+	for i, transactItem := range params.TransactItems {
+		action, err := r.newTxAction(transactItem)
+		if err != nil {
+			return nil, err
+		}
+		if seen[action.table+fakeKeySeparator+action.key] {
+			return nil, errTransactionConflict
+		}
+		seen[action.table+fakeKeySeparator+action.key] = true
+		actions[i] = action
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reasons := make([]types.CancellationReason, len(actions))
+	canceled := false
+	for i, action := range actions {
+		reasons[i].Code = aws.String("None")
+
+		stored := r.load(action.table, action.key)
+		if err := checkCondition(stored, action.condition, action.names, action.values); err != nil {
+			var failed *types.ConditionalCheckFailedException
+			if !errors.As(err, &failed) {
+				return nil, err
+			}
+			reasons[i].Code = aws.String(conditionalCheckFailed)
+			canceled = true
+		}
+	}
+	if canceled {
+		return nil, &types.TransactionCanceledException{
+			Message:             aws.String("Transaction cancelled, please refer cancellation reasons for specific reasons"),
+			CancellationReasons: reasons,
+		}
+	}
+
+	for _, action := range actions {
+		switch {
+		case action.delete:
+			r.store.delete(action.table, action.key)
+		case action.write:
+			r.store.put(action.table, action.key, action.item)
+		}
+	}
+
+	return &dynamodb.TransactWriteItemsOutput{}, nil
+}
+
+// newTxAction resolves a TransactWriteItem.
+func (r AWSFakeClient) newTxAction(transactItem types.TransactWriteItem) (fakeTxAction, error) {
+	var action fakeTxAction
+	var err error
+	switch {
+	case transactItem.Put != nil:
+		put := transactItem.Put
+		action.table = aws.ToString(put.TableName)
+		action.key, err = r.itemKey(put.Item, kvs.ErrConvert)
+		if err == nil {
+			err = checkItemSize(put.Item)
+		}
+		action.item, action.write, action.condition = put.Item, true, put.ConditionExpression
+		action.names, action.values = put.ExpressionAttributeNames, put.ExpressionAttributeValues
+	case transactItem.Delete != nil:
+		del := transactItem.Delete
+		action.table = aws.ToString(del.TableName)
+		action.key, err = r.storageKey(del.Key, kvs.ErrConvert)
+		action.delete, action.condition = true, del.ConditionExpression
+		action.names, action.values = del.ExpressionAttributeNames, del.ExpressionAttributeValues
+	case transactItem.ConditionCheck != nil:
+		check := transactItem.ConditionCheck
+		if check.ConditionExpression == nil {
+			return fakeTxAction{}, kvs.ErrInternal
+		}
+		action.table = aws.ToString(check.TableName)
+		action.key, err = r.storageKey(check.Key, kvs.ErrConvert)
+		action.condition = check.ConditionExpression
+		action.names, action.values = check.ExpressionAttributeNames, check.ExpressionAttributeValues
+	default:
+		return fakeTxAction{}, kvs.ErrInternal
+	}
+	if err != nil {
+		return fakeTxAction{}, err
+	}
+
+	return action, nil
+}
+
+// TransactGetItems implements the AWSClient interface for consistent multi-item reads.
+// A response is returned for each Get, in order, with a nil Item when the item is missing.
+// ProjectionExpression is honoured as in GetItem.
+func (r AWSFakeClient) TransactGetItems(
+	ctx context.Context,
+	params *dynamodb.TransactGetItemsInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.TransactGetItemsOutput, error) {
+	if err := r.start(ctx, "TransactGetItems"); err != nil {
+		return nil, err
+	}
+	if err := checkBatchSize(len(params.TransactItems), kvs.MaxTxOperations, "TransactGetItems"); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	output := &dynamodb.TransactGetItemsOutput{Responses: make([]types.ItemResponse, len(params.TransactItems))}
+	for i, transactItem := range params.TransactItems {
+		if transactItem.Get == nil {
+			return nil, kvs.ErrInternal
+		}
+
+		table := aws.ToString(transactItem.Get.TableName)
+		key, err := r.storageKey(transactItem.Get.Key, kvs.ErrConvert)
+		if err != nil {
+			return nil, err
+		}
+		item := r.load(table, key)
+		if item != nil && transactItem.Get.ProjectionExpression != nil {
+			item, err = projectItem(item, *transactItem.Get.ProjectionExpression,
+				transactItem.Get.ExpressionAttributeNames)
+			if err != nil {
+				return nil, err
+			}
+		}
+		output.Responses[i].Item = item
+	}
+
+	return output, nil
+}
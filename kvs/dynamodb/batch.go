@@ -0,0 +1,71 @@
+// Package dynamodb provides AWS DynamoDB specific implementation of the KVS client.
+package dynamodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// Limits of the batch operations of DynamoDB.
+const (
+	MaxBatchGetKeys    = 100 // Keys of a BatchGetItem request
+	MaxBatchWriteItems = 25  // Write requests of a BatchWriteItem request
+)
+
+// ErrUnprocessed is returned by BulkGet and BulkSave when DynamoDB still leaves keys or items
+// unprocessed after batchAttempts requests.
+const ErrUnprocessed = kvs.KeyValueError("[kvs]: batch request left items unprocessed")
+
+const (
+	batchAttempts = 5                     // Requests of a batch, including the retries of unprocessed items
+	batchBackoff  = 10 * time.Millisecond // Wait before the first retry, doubled on each retry
+)
+
+// batchWrite writes a batch of at most MaxBatchWriteItems requests with BatchWriteItem,
+// retrying the unprocessed items.
+func (r *LowLevelClient) batchWrite(ctx context.Context, requests []types.WriteRequest) error {
+	input := &dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]types.WriteRequest{
+			aws.ToString(r.getTableName()): requests,
+		},
+	}
+
+	for attempt := 1; ; attempt++ {
+		output, err := r.AWSClient.BatchWriteItem(ctx, input)
+		if err != nil {
+			return err
+		}
+
+		if len(output.UnprocessedItems) == 0 {
+			return nil
+		}
+		if err = waitBatchRetry(ctx, attempt); err != nil {
+			return err
+		}
+		input.RequestItems = output.UnprocessedItems
+	}
+}
+
+// waitBatchRetry waits before retrying the unprocessed items of the given attempt of a batch.
+// Returns ErrUnprocessed after batchAttempts attempts, or the error of ctx if it is done first.
+func waitBatchRetry(ctx context.Context, attempt int) error {
+	if attempt >= batchAttempts {
+		return ErrUnprocessed
+	}
+
+	timer := time.NewTimer(batchBackoff << (attempt - 1))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -7,14 +7,27 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
 )
 
 // Builder is a struct that helps configure and create a LowLevelClient for DynamoDB.
 // It uses the builder pattern with functional options to allow for flexible configuration.
 type Builder struct {
-	containerName string        // Name of the container or service, used for metrics and logging
-	rawURL        string        // URL for the DynamoDB endpoint, useful for local development
-	ttl           time.Duration // Default Time To Live for items in seconds
+	containerName string            // Name of the container or service, used for metrics and logging
+	rawURL        string            // URL for the DynamoDB endpoint, useful for local development
+	ttl           time.Duration     // Default Time To Live for items in seconds
+	storage       StorageMode       // How item values are stored
+	names         AttributeNames    // Attribute names of the table
+	codec         KeyCodec          // Maps keys to composite primary keys
+	indexes       []SecondaryIndex  // Global secondary indexes of the table
+	clock         kvs.Clock         // Clock of the TTL of items
+	consistent    bool              // Whether reads are strongly consistent by default
+	evictExpired  bool              // Whether expired items are deleted when read
+	billingMode   types.BillingMode // Billing mode of the tables created by EnsureTable
+	readCapacity  int64             // Provisioned read capacity units of the tables created by EnsureTable
+	writeCapacity int64             // Provisioned write capacity units of the tables created by EnsureTable
 }
 
 // BuilderOptions is a function type that configures a Builder.
@@ -59,6 +72,80 @@ func (r *Builder) WithTTL(ttl time.Duration) *Builder {
 	return r
 }
 
+// WithStorageMode sets how item values are stored (StorageString by default).
+// Returns a pointer to the Builder.
+func (r *Builder) WithStorageMode(mode StorageMode) *Builder {
+	r.storage = mode
+	return r
+}
+
+// WithAttributeNames sets the attribute names of the table, for tables whose key, value or
+// TTL attributes are not named "key", "value" and "ttl", or whose primary key is composite.
+// Returns a pointer to the Builder.
+func (r *Builder) WithAttributeNames(names AttributeNames) *Builder {
+	r.names = names
+	return r
+}
+
+// WithKeyCodec sets how keys are split into the partition and sort key values of a composite
+// primary key (SeparatorKeyCodec with DefaultKeySeparator by default).
+// Returns a pointer to the Builder.
+func (r *Builder) WithKeyCodec(codec KeyCodec) *Builder {
+	r.codec = codec
+	return r
+}
+
+// WithSecondaryIndexes declares global secondary indexes of the table (see NewSecondaryIndex):
+// their key attributes are set on every save, and they can be read with QueryIndex.
+// Returns a pointer to the Builder.
+func (r *Builder) WithSecondaryIndexes(indexes ...SecondaryIndex) *Builder {
+	r.indexes = append(r.indexes, indexes...)
+	return r
+}
+
+// WithConsistentRead sets whether Get and BulkGet issue strongly consistent reads by default
+// (eventually consistent reads by default). kvs.ContextWithConsistentRead overrides it per call.
+// Returns a pointer to the Builder.
+func (r *Builder) WithConsistentRead(consistent bool) *Builder {
+	r.consistent = consistent
+	return r
+}
+
+// WithClock sets the clock used to compute the TTL of saved items and to tell whether
+// the items read have expired (kvs.SystemClock by default). FakeBuild gives it to the
+// AWSFakeClient too, so that a kvs.FakeClock expires items without waiting.
+// Returns a pointer to the Builder.
+func (r *Builder) WithClock(clock kvs.Clock) *Builder {
+	r.clock = clock
+	return r
+}
+
+// WithExpiredItemEviction sets whether the expired items found by reads, which are never
+// returned, are also deleted in the background instead of waiting for DynamoDB to purge them.
+// Returns a pointer to the Builder.
+func (r *Builder) WithExpiredItemEviction(enabled bool) *Builder {
+	r.evictExpired = enabled
+	return r
+}
+
+// WithBillingMode sets the billing mode of the table created by EnsureTable
+// (PAY_PER_REQUEST by default). Use WithProvisionedThroughput for PROVISIONED: without
+// capacity units, EnsureTable returns ErrMissingThroughput.
+// Returns a pointer to the Builder.
+func (r *Builder) WithBillingMode(mode types.BillingMode) *Builder {
+	r.billingMode = mode
+	return r
+}
+
+// WithProvisionedThroughput sets the PROVISIONED billing mode, with the given read and write
+// capacity units, for the table (and its indexes) created by EnsureTable.
+// Returns a pointer to the Builder.
+func (r *Builder) WithProvisionedThroughput(read, write int64) *Builder {
+	r.billingMode = types.BillingModeProvisioned
+	r.readCapacity, r.writeCapacity = read, write
+	return r
+}
+
 // WithTTL returns a BuilderOptions that sets the default TTL for items.
 // The TTL is specified in seconds.
 func WithTTL(ttl time.Duration) BuilderOptions {
@@ -83,28 +170,114 @@ func WithEndpointResolver(rawURL string) BuilderOptions {
 	}
 }
 
+// WithStorageMode returns a BuilderOptions that sets how item values are stored.
+func WithStorageMode(mode StorageMode) BuilderOptions {
+	return func(f *Builder) {
+		f.storage = mode
+	}
+}
+
+// WithAttributeNames returns a BuilderOptions that sets the attribute names of the table.
+func WithAttributeNames(names AttributeNames) BuilderOptions {
+	return func(f *Builder) {
+		f.names = names
+	}
+}
+
+// WithKeyCodec returns a BuilderOptions that sets how keys are split into the partition and
+// sort key values of a composite primary key.
+func WithKeyCodec(codec KeyCodec) BuilderOptions {
+	return func(f *Builder) {
+		f.codec = codec
+	}
+}
+
+// WithSecondaryIndexes returns a BuilderOptions that declares global secondary indexes of the table.
+func WithSecondaryIndexes(indexes ...SecondaryIndex) BuilderOptions {
+	return func(f *Builder) {
+		f.indexes = append(f.indexes, indexes...)
+	}
+}
+
+// WithConsistentRead returns a BuilderOptions that sets whether reads are strongly consistent by default.
+func WithConsistentRead(consistent bool) BuilderOptions {
+	return func(f *Builder) {
+		f.consistent = consistent
+	}
+}
+
+// WithClock returns a BuilderOptions that sets the clock of the TTL of items.
+func WithClock(clock kvs.Clock) BuilderOptions {
+	return func(f *Builder) {
+		f.clock = clock
+	}
+}
+
+// WithExpiredItemEviction returns a BuilderOptions that sets whether expired items are deleted when read.
+func WithExpiredItemEviction(enabled bool) BuilderOptions {
+	return func(f *Builder) {
+		f.evictExpired = enabled
+	}
+}
+
+// WithBillingMode returns a BuilderOptions that sets the billing mode of the table created by EnsureTable.
+// Use WithProvisionedThroughput for PROVISIONED: without capacity units, EnsureTable returns
+// ErrMissingThroughput.
+func WithBillingMode(mode types.BillingMode) BuilderOptions {
+	return func(f *Builder) {
+		f.billingMode = mode
+	}
+}
+
+// WithProvisionedThroughput returns a BuilderOptions that sets the PROVISIONED billing mode, with
+// the given read and write capacity units, for the table created by EnsureTable.
+func WithProvisionedThroughput(read, write int64) BuilderOptions {
+	return func(f *Builder) {
+		f.billingMode = types.BillingModeProvisioned
+		f.readCapacity, f.writeCapacity = read, write
+	}
+}
+
 // Build creates a new LowLevelClient using the configured options and the provided AWS config.
 // It sets up the DynamoDB client with the specified endpoint resolver and other options.
 // Returns a pointer to the new LowLevelClient.
 func (r *Builder) Build(awsConfig aws.Config) *LowLevelClient {
-	return NewLowLevelClient(
-		dynamodb.NewFromConfig(awsConfig, func(opts *dynamodb.Options) {
-			if strings.TrimSpace(r.rawURL) != "" {
-				opts.EndpointResolverV2 = NewResolver(r.rawURL)
-			}
-		}),
-		r.containerName,
-		r.ttl,
-	)
+	return r.configure(NewLowLevelClient(r.newClient(awsConfig), r.containerName, r.ttl))
+}
+
+// newClient returns the DynamoDB client of awsConfig, with the configured endpoint resolver.
+func (r *Builder) newClient(awsConfig aws.Config) *dynamodb.Client {
+	return dynamodb.NewFromConfig(awsConfig, func(opts *dynamodb.Options) {
+		if strings.TrimSpace(r.rawURL) != "" {
+			opts.EndpointResolverV2 = NewResolver(r.rawURL)
+		}
+	})
 }
 
 // FakeBuild creates a new LowLevelClient using the configured options and the provided AWS config.
 // It sets up the DynamoDB client with the specified endpoint resolver and other options.
 // Returns a pointer to the new LowLevelClient.
 func (r *Builder) FakeBuild() *LowLevelClient {
-	return NewLowLevelClient(
-		NewAWSFakeClient(),
-		r.containerName,
-		r.ttl,
-	)
+	opts := []AWSFakeClientOptions{WithFakeAttributeNames(r.names), WithFakeSecondaryIndexes(r.indexes...)}
+	if r.clock != nil {
+		opts = append(opts, WithFakeClock(r.clock))
+	}
+	return r.configure(NewLowLevelClient(NewAWSFakeClient(opts...), r.containerName, r.ttl))
+}
+
+// configure applies the options that NewLowLevelClient does not take.
+func (r *Builder) configure(lowLevelClient *LowLevelClient) *LowLevelClient {
+	lowLevelClient.storage = r.storage
+	lowLevelClient.names = r.names.withDefaults()
+	lowLevelClient.codec = r.codec
+	lowLevelClient.indexes = r.indexes
+	lowLevelClient.consistent = r.consistent
+	lowLevelClient.evictExpired = r.evictExpired
+	if r.clock != nil {
+		lowLevelClient.now = r.clock.Now
+	}
+	if lowLevelClient.codec == nil {
+		lowLevelClient.codec = SeparatorKeyCodec{Separator: DefaultKeySeparator}
+	}
+	return lowLevelClient
 }
@@ -0,0 +1,34 @@
+package dynamodb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	"github.com/arielsrv/go-kvs-client/kvs/dynamodb"
+	"github.com/arielsrv/go-kvs-client/kvs/kvstest"
+)
+
+func TestConformance_FakeBuild(t *testing.T) {
+	for name, mode := range map[string]dynamodb.StorageMode{
+		"string":     dynamodb.StorageString,
+		"attributes": dynamodb.StorageAttributes,
+	} {
+		t.Run(name, func(t *testing.T) {
+			kvstest.RunConformance(t, func(t *testing.T, ttl time.Duration) kvstest.Backend {
+				clock := kvs.NewFakeClock(time.Unix(1_700_000_000, 0))
+				return kvstest.Backend{
+					Client: dynamodb.NewBuilder(
+						dynamodb.WithContainerName("__kvs-test"),
+						dynamodb.WithTTL(ttl),
+						dynamodb.WithStorageMode(mode),
+						dynamodb.WithClock(clock),
+					).FakeBuild(),
+					Now:         clock.Now,
+					Advance:     clock.Advance,
+					MaxBulkKeys: dynamodb.MaxBatchGetKeys,
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package dynamodb_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	"github.com/arielsrv/go-kvs-client/kvs/dynamodb"
+)
+
+func TestLowLevelClient_ConsistentRead(t *testing.T) {
+	ctx := t.Context()
+	for _, consistent := range []bool{false, true} {
+		llc := dynamodb.NewBuilder(
+			dynamodb.WithContainerName("__kvs-test"),
+			dynamodb.WithConsistentRead(consistent),
+		).FakeBuild()
+		require.Equal(t, consistent, llc.ConsistentRead())
+		fake := llc.AWSClient.(*dynamodb.AWSFakeClient)
+
+		require.NoError(t, llc.Save("1", kvs.NewItem("1", "John")))
+		_, err := llc.GetWithContext(ctx, "1")
+		require.NoError(t, err)
+		_, err = llc.BulkGetWithContext(ctx, []string{"1"})
+		require.NoError(t, err)
+
+		// The context overrides the default of the client.
+		override := kvs.ContextWithConsistentRead(ctx, !consistent)
+		_, err = llc.GetWithContext(override, "1")
+		require.NoError(t, err)
+		_, err = llc.BulkGetWithContext(override, []string{"1"})
+		require.NoError(t, err)
+
+		require.Equal(t, []dynamodb.FakeRead{
+			{Operation: "GetItem", ConsistentRead: consistent},
+			{Operation: "BatchGetItem", ConsistentRead: consistent},
+			{Operation: "GetItem", ConsistentRead: !consistent},
+			{Operation: "BatchGetItem", ConsistentRead: !consistent},
+		}, fake.Reads())
+	}
+}
+
+// blockingAWSClient holds every GetItem until release is closed.
+type blockingAWSClient struct {
+	*dynamodb.AWSFakeClient
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (c blockingAWSClient) GetItem(
+	ctx context.Context,
+	params *awsdynamodb.GetItemInput,
+	opts ...func(*awsdynamodb.Options),
+) (*awsdynamodb.GetItemOutput, error) {
+	c.entered <- struct{}{}
+	<-c.release
+	return c.AWSFakeClient.GetItem(ctx, params, opts...)
+}
+
+func TestLowLevelClient_ConsistentRead_NotMerged(t *testing.T) {
+	fake := dynamodb.NewAWSFakeClient()
+	client := blockingAWSClient{AWSFakeClient: fake, entered: make(chan struct{}, 2), release: make(chan struct{})}
+	llc := dynamodb.NewLowLevelClient(client, "__kvs-test")
+	require.NoError(t, llc.Save("1", kvs.NewItem("1", "John")))
+
+	var wg sync.WaitGroup
+	for _, consistent := range []bool{false, true} {
+		wg.Go(func() {
+			_, err := llc.GetWithContext(kvs.ContextWithConsistentRead(t.Context(), consistent), "1")
+			require.NoError(t, err)
+		})
+	}
+
+	for range 2 {
+		select {
+		case <-client.entered:
+		case <-time.After(time.Second):
+			t.Fatal("a consistent read was merged with an eventually consistent one")
+		}
+	}
+	close(client.release)
+	wg.Wait()
+
+	require.ElementsMatch(t, []dynamodb.FakeRead{
+		{Operation: "GetItem"},
+		{Operation: "GetItem", ConsistentRead: true},
+	}, fake.Reads())
+}
@@ -14,7 +14,15 @@
 //   - Individual and bulk item retrieval
 //   - Individual and bulk item storage
 //   - Context-aware operations for proper timeout and cancellation handling
-//   - TTL (Time To Live) settings for automatic item expiration
+//   - TTL (Time To Live) settings for automatic item expiration; expired items not yet purged
+//     by DynamoDB are never returned, and can be evicted on read (WithExpiredItemEviction)
+//   - Native attribute-map storage of structured values (StorageAttributes)
+//   - Configurable attribute names and composite (partition + sort) keys (AttributeNames, KeyCodec)
+//   - Partition queries in sort key order, with pagination tokens (Query, QueryPage)
+//   - Global secondary indexes projected on save (SecondaryIndex, QueryIndex)
+//   - Strongly consistent reads, by default or per call (WithConsistentRead, kvs.ContextWithConsistentRead)
+//   - Opt-in table provisioning and TTL enablement, with schema validation (Builder.EnsureTable)
+//   - Multi-item transactions and snapshot reads (NewTx, TransactGet)
 //
 // Usage:
 //
@@ -41,6 +49,7 @@
 //	    kvs.WithTableName("my-table"),
 //	)
 //
-// The package also provides testing utilities like AWS fake clients for unit testing.
+// The package also provides AWSFakeClient, an in-memory DynamoDB for unit testing that enforces
+// the limits of the API and can inject throttling and unprocessed batch items.
 // Cambiar referencia de import en doc.go
 package dynamodb
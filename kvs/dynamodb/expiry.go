@@ -0,0 +1,56 @@
+// Package dynamodb provides AWS DynamoDB specific implementation of the KVS client.
+package dynamodb
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// evictionTimeout bounds the background deletes of expired items.
+const evictionTimeout = 5 * time.Second
+
+// expired reports whether the TTL of item has elapsed. DynamoDB purges expired items
+// up to 48 hours after their TTL, so reads must not trust their presence.
+func (r *LowLevelClient) expired(item *kvs.Item) bool {
+	return item.TTL > 0 && item.TTL <= r.now().Unix()
+}
+
+// evict deletes an expired item in the background when eviction is enabled (see
+// Builder.WithExpiredItemEviction), like the lazy eviction of a Redis expiration. The delete
+// is conditional on the TTL still being elapsed, so that an item saved again in the
+// meantime is kept. It is best effort: errors are ignored, DynamoDB purges the item anyway.
+func (r *LowLevelClient) evict(item *kvs.Item) {
+	if !r.evictExpired {
+		return
+	}
+
+	primaryKey, err := r.primaryKey(item.Key)
+	if err != nil {
+		return
+	}
+
+	input := &dynamodb.DeleteItemInput{
+		TableName:           r.getTableName(),
+		Key:                 primaryKey,
+		ConditionExpression: aws.String("#ttl <= :now"),
+		ExpressionAttributeNames: map[string]string{
+			"#ttl": r.names.TTL,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(r.now().Unix(), 10)},
+		},
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), evictionTimeout)
+		defer cancel()
+		_, _ = r.AWSClient.DeleteItem(ctx, input)
+	}()
+}
@@ -0,0 +1,123 @@
+package dynamodb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	"github.com/arielsrv/go-kvs-client/kvs/dynamodb"
+)
+
+func itemKeys(items *kvs.Items) []string {
+	var keys []string
+	for item := range items.All() {
+		keys = append(keys, item.Key)
+	}
+	return keys
+}
+
+func TestLowLevelClient_ExpiredItems(t *testing.T) {
+	ctx := t.Context()
+	clock := kvs.NewFakeClock(time.Unix(1_700_000_000, 0))
+	now := clock.Now()
+	llc := dynamodb.NewBuilder(
+		dynamodb.WithContainerName("__kvs-test"),
+		dynamodb.WithTTL(time.Minute),
+		dynamodb.WithClock(clock),
+	).FakeBuild()
+	require.False(t, llc.ExpiredItemEviction())
+
+	require.NoError(t, llc.Save("1", kvs.NewItem("1", "John")))
+	require.NoError(t, llc.Save("2", &kvs.Item{Key: "2", Value: "Jane", TTL: now.Add(time.Hour).Unix()}))
+	item, err := llc.Get("1")
+	require.NoError(t, err)
+	require.Equal(t, now.Add(time.Minute).Unix(), item.TTL)
+
+	clock.Advance(time.Minute)
+
+	_, err = llc.GetWithContext(ctx, "1")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+	_, err = llc.GetFields(ctx, "1", "name")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+
+	items, err := llc.BulkGetWithContext(ctx, []string{"1", "2"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"2"}, itemKeys(items))
+
+	items, err = llc.TransactGet(ctx, []string{"1", "2"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"2"}, itemKeys(items))
+
+	// The expired item counts as absent for conditional writes too.
+	require.NoError(t, llc.SaveIfAbsent(ctx, "1", kvs.NewItem("1", "Joe")))
+	item, err = llc.Get("1")
+	require.NoError(t, err)
+	require.Equal(t, `"Joe"`, item.Value)
+}
+
+func TestLowLevelClient_ExpiredItemEviction(t *testing.T) {
+	ctx := t.Context()
+	clock := kvs.NewFakeClock(time.Unix(1_700_000_000, 0))
+	now := clock.Now()
+	llc := dynamodb.NewBuilder().
+		WithContainerName("__kvs-test").
+		WithClock(clock).
+		WithExpiredItemEviction(true).
+		FakeBuild()
+	require.True(t, llc.ExpiredItemEviction())
+
+	for _, key := range []string{"1", "2", "3"} {
+		require.NoError(t, llc.Save(key, &kvs.Item{Key: key, Value: key, TTL: now.Add(time.Minute).Unix()}))
+	}
+	require.NoError(t, llc.Save("4", kvs.NewItem("4", "4")))
+
+	clock.Advance(time.Hour)
+
+	_, err := llc.GetWithContext(ctx, "1")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+	items, err := llc.BulkGetWithContext(ctx, []string{"2", "4"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"4"}, itemKeys(items))
+
+	require.Eventually(t, func() bool {
+		return getRawItem(t, llc, "1") == nil && getRawItem(t, llc, "2") == nil
+	}, time.Second, 10*time.Millisecond)
+	require.NotNil(t, getRawItem(t, llc, "3"))
+	require.NotNil(t, getRawItem(t, llc, "4"))
+}
+
+func TestLowLevelClient_ExpiredItemEviction_ScanAndQuery(t *testing.T) {
+	ctx := t.Context()
+	clock := kvs.NewFakeClock(time.Unix(1_700_000_000, 0))
+	now := clock.Now()
+	llc := dynamodb.NewBuilder().
+		WithContainerName("__kvs-test").
+		WithClock(clock).
+		WithExpiredItemEviction(true).
+		FakeBuild()
+
+	for _, key := range []string{"1", "2"} {
+		require.NoError(t, llc.Save(key, &kvs.Item{Key: key, Value: key, TTL: now.Add(time.Minute).Unix()}))
+	}
+	require.NoError(t, llc.Save("3", kvs.NewItem("3", "3")))
+
+	clock.Advance(time.Hour)
+
+	for item, err := range llc.Query(ctx, "1", kvs.SortCondition{}, kvs.QueryOptions{}) {
+		require.NoError(t, err)
+		require.Fail(t, "expired item queried", item.Key)
+	}
+	var keys []string
+	for item, err := range llc.Scan(ctx, kvs.ScanOptions{}) {
+		require.NoError(t, err)
+		keys = append(keys, item.Key)
+	}
+	require.Equal(t, []string{"3"}, keys)
+
+	require.Eventually(t, func() bool {
+		return getRawItem(t, llc, "1") == nil && getRawItem(t, llc, "2") == nil
+	}, time.Second, 10*time.Millisecond)
+	require.NotNil(t, getRawItem(t, llc, "3"))
+}
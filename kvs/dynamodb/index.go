@@ -0,0 +1,109 @@
+// Package dynamodb provides AWS DynamoDB specific implementation of the KVS client.
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// ErrIndexNotFound is returned by QueryIndex for an index that was not declared on the Builder.
+const ErrIndexNotFound = kvs.KeyValueError("[kvs]: secondary index not declared")
+
+// SecondaryIndex declares a global secondary index of the table, whose partition key
+// attribute is set on save from the saved value. The index must project all attributes.
+type SecondaryIndex struct {
+	Name      string                 // Index name
+	Attribute string                 // Partition key attribute of the index, of type string
+	key       func(value any) string // Index key of a saved value, empty if not indexed
+}
+
+// NewSecondaryIndex declares the index name, whose partition key attribute holds the value
+// returned by mapper for the saved values of type T (or *T, or their JSON representation).
+// Values for which mapper returns an empty string, or that are not a T, are left out of the
+// index, as DynamoDB does for items without the index key attribute.
+func NewSecondaryIndex[T any](name, attribute string, mapper func(value T) string) SecondaryIndex {
+	return SecondaryIndex{
+		Name:      name,
+		Attribute: attribute,
+		key: func(value any) string {
+			switch typed := value.(type) {
+			case T:
+				return mapper(typed)
+			case *T:
+				if typed == nil {
+					return ""
+				}
+				return mapper(*typed)
+			}
+
+			bytes, err := marshalJSON(value)
+			if err != nil {
+				return ""
+			}
+			var decoded T
+			if err = json.Unmarshal(bytes, &decoded); err != nil {
+				return ""
+			}
+			return mapper(decoded)
+		},
+	}
+}
+
+// projectIndexes sets the index key attributes of the item holding value.
+func (r *LowLevelClient) projectIndexes(attributes map[string]types.AttributeValue, value any) {
+	for _, index := range r.indexes {
+		if key := index.key(value); key != "" {
+			attributes[index.Attribute] = &types.AttributeValueMemberS{Value: key}
+		}
+	}
+}
+
+// index returns the declared index name.
+func (r *LowLevelClient) index(name string) (SecondaryIndex, bool) {
+	for _, index := range r.indexes {
+		if index.Name == name {
+			return index, true
+		}
+	}
+	return SecondaryIndex{}, false
+}
+
+// QueryIndex enumerates the items whose partition key in the secondary index is value,
+// using the provided context. Pages of kvs.DefaultScanPageSize items are requested lazily
+// while the sequence is consumed. Returns ErrIndexNotFound if the index was not declared
+// with WithSecondaryIndexes.
+func (r *LowLevelClient) QueryIndex(ctx context.Context, name, value string) iter.Seq2[*kvs.Item, error] {
+	return func(yield func(*kvs.Item, error) bool) {
+		index, found := r.index(name)
+		if !found {
+			yield(nil, ErrIndexNotFound)
+			return
+		}
+		if value == "" {
+			yield(nil, kvs.ErrEmptyKey)
+			return
+		}
+
+		input := &dynamodb.QueryInput{
+			TableName:              r.getTableName(),
+			IndexName:              aws.String(index.Name),
+			KeyConditionExpression: aws.String("#index = :value"),
+			ExpressionAttributeNames: map[string]string{
+				"#index": index.Attribute,
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":value": &types.AttributeValueMemberS{Value: value},
+			},
+			Limit: aws.Int32(kvs.DefaultScanPageSize),
+		}
+
+		r.query(ctx, input, 0, yield)
+	}
+}
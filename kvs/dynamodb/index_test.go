@@ -0,0 +1,90 @@
+package dynamodb_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	"github.com/arielsrv/go-kvs-client/kvs/dynamodb"
+)
+
+type account struct {
+	ID    string `json:"id"    dynamodbav:"id"`
+	Email string `json:"email" dynamodbav:"email"`
+}
+
+var byEmail = dynamodb.NewSecondaryIndex("by-email", "gsi_email", func(value account) string {
+	return value.Email
+})
+
+func queryEmails(t *testing.T, client *kvs.KVSClient[account], email string) []string {
+	t.Helper()
+
+	var ids []string
+	for value, err := range client.QueryIndex(t.Context(), "by-email", email) {
+		require.NoError(t, err)
+		ids = append(ids, value.ID)
+	}
+	return ids
+}
+
+func TestLowLevelClient_QueryIndex(t *testing.T) {
+	for _, mode := range []dynamodb.StorageMode{dynamodb.StorageString, dynamodb.StorageAttributes} {
+		llc := dynamodb.NewBuilder().
+			WithContainerName("__kvs-test").
+			WithStorageMode(mode).
+			WithSecondaryIndexes(byEmail).
+			FakeBuild()
+		client := kvs.NewKVSClient[account](llc)
+
+		require.NoError(t, client.Save("1", &account{ID: "1", Email: "john@example.com"}))
+		require.NoError(t, client.BulkSave([]account{
+			{ID: "2", Email: "jane@example.com"},
+			{ID: "3", Email: "john@example.com"},
+			{ID: "4"},
+		}, func(value account) string { return value.ID }))
+
+		raw := getRawItem(t, llc, "1")
+		require.Equal(t, &types.AttributeValueMemberS{Value: "john@example.com"}, raw["gsi_email"])
+		require.NotContains(t, getRawItem(t, llc, "4"), "gsi_email")
+
+		require.Equal(t, []string{"1", "3"}, queryEmails(t, client, "john@example.com"))
+		require.Equal(t, []string{"2"}, queryEmails(t, client, "jane@example.com"))
+		require.Empty(t, queryEmails(t, client, "joe@example.com"))
+
+		// The index follows updates and deletes.
+		require.NoError(t, client.Save("3", &account{ID: "3", Email: "joe@example.com"}))
+		require.NoError(t, llc.Delete("1"))
+		require.Empty(t, queryEmails(t, client, "john@example.com"))
+		require.Equal(t, []string{"3"}, queryEmails(t, client, "joe@example.com"))
+
+		for _, err := range llc.QueryIndex(t.Context(), "by-name", "John") {
+			require.ErrorIs(t, err, dynamodb.ErrIndexNotFound)
+		}
+	}
+}
+
+func TestLowLevelClient_QueryIndex_Patch(t *testing.T) {
+	ctx := t.Context()
+	for _, mode := range []dynamodb.StorageMode{dynamodb.StorageString, dynamodb.StorageAttributes} {
+		llc := dynamodb.NewBuilder(
+			dynamodb.WithContainerName("__kvs-test"),
+			dynamodb.WithStorageMode(mode),
+			dynamodb.WithSecondaryIndexes(byEmail),
+		).FakeBuild()
+		client := kvs.NewKVSClient[account](llc)
+
+		require.NoError(t, client.Save("1", &account{ID: "1", Email: "john@example.com"}))
+		require.NoError(t, llc.Patch(ctx, "1", map[string]any{"email": "jane@example.com"}))
+
+		require.Empty(t, queryEmails(t, client, "john@example.com"))
+		require.Equal(t, []string{"1"}, queryEmails(t, client, "jane@example.com"))
+
+		// Removing the indexed field removes the item from the index.
+		require.NoError(t, llc.Patch(ctx, "1", map[string]any{"email": nil}))
+		require.Empty(t, queryEmails(t, client, "jane@example.com"))
+		require.NotContains(t, getRawItem(t, llc, "1"), "gsi_email")
+	}
+}
@@ -12,7 +12,6 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	awsdynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/localstack"
@@ -57,16 +56,7 @@ func setupLocalStackDynamoDB(t *testing.T) *kvsdynamo.LowLevelClient {
 		o.BaseEndpoint = aws.String(endpoint)
 	})
 
-	_, err = dynamoClient.CreateTable(ctx, &awsdynamodb.CreateTableInput{
-		TableName: aws.String(integrationTableName),
-		AttributeDefinitions: []types.AttributeDefinition{
-			{AttributeName: aws.String(kvsdynamo.KeyName), AttributeType: types.ScalarAttributeTypeS},
-		},
-		KeySchema: []types.KeySchemaElement{
-			{AttributeName: aws.String(kvsdynamo.KeyName), KeyType: types.KeyTypeHash},
-		},
-		BillingMode: types.BillingModePayPerRequest,
-	})
+	err = kvsdynamo.NewBuilder().WithContainerName(integrationTableName).EnsureTableWithClient(ctx, dynamoClient)
 	require.NoError(t, err)
 
 	return kvsdynamo.NewLowLevelClient(dynamoClient, integrationTableName)
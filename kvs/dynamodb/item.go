@@ -8,6 +8,7 @@ type Item struct {
 	// Key is the unique identifier for the item in DynamoDB.
 	Key string `dynamodbav:"key"`
 	// Value is the data stored in the item, serialized as a JSON string.
+	// It is absent from the values stored as native attributes (see StorageAttributes).
 	Value string `dynamodbav:"value"`
 	// TTL is the Unix timestamp when the item will expire.
 	// If zero, the item does not expire.
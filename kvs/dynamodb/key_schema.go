@@ -0,0 +1,148 @@
+// Package dynamodb provides AWS DynamoDB specific implementation of the KVS client.
+package dynamodb
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// ErrKeySchema is returned when a key cannot be split into the partition and sort
+// key values of a composite primary key.
+const ErrKeySchema = kvs.KeyValueError("[kvs]: key does not match the key schema")
+
+// DefaultKeySeparator separates the partition and sort key values in the keys handled
+// by the default KeyCodec of composite primary keys.
+const DefaultKeySeparator = "|"
+
+// AttributeNames names the attributes of the items of a table. Empty names take the
+// defaults KeyName, ValueName and TTLName. SortKey is only set for tables whose primary
+// key is composite (partition key + sort key); kvs keys are then mapped to both values
+// by a KeyCodec. Key attributes must be of type string.
+type AttributeNames struct {
+	Key     string // Partition key attribute
+	SortKey string // Sort key attribute, empty for simple primary keys
+	Value   string // Value attribute
+	TTL     string // TTL attribute, holding a Unix timestamp
+}
+
+// withDefaults returns the names with the defaults applied.
+func (r AttributeNames) withDefaults() AttributeNames {
+	if r.Key == "" {
+		r.Key = KeyName
+	}
+	if r.Value == "" {
+		r.Value = ValueName
+	}
+	if r.TTL == "" {
+		r.TTL = TTLName
+	}
+	return r
+}
+
+// reserved returns the names that values stored as native attributes cannot use.
+func (r AttributeNames) reserved() []string {
+	names := []string{r.Key, r.Value, r.TTL}
+	if r.SortKey != "" {
+		names = append(names, r.SortKey)
+	}
+	return names
+}
+
+// KeyCodec maps the kvs keys to the partition and sort key values of a composite
+// primary key, and back.
+type KeyCodec interface {
+	// Split returns the partition and sort key values of key, or ErrKeySchema.
+	Split(key string) (partition, sort string, err error)
+	// Join returns the kvs key of a partition and sort key value.
+	Join(partition, sort string) string
+}
+
+// SeparatorKeyCodec is a KeyCodec splitting keys at the first Separator: with "|",
+// "USER#1|PROFILE" is the item of partition key "USER#1" and sort key "PROFILE".
+type SeparatorKeyCodec struct {
+	Separator string
+}
+
+// Split splits key at the first separator. The partition key value must not be empty.
+func (r SeparatorKeyCodec) Split(key string) (string, string, error) {
+	partition, sort, found := strings.Cut(key, r.Separator)
+	if !found || partition == "" {
+		return "", "", ErrKeySchema
+	}
+	return partition, sort, nil
+}
+
+// Join joins the partition and sort key values with the separator.
+func (r SeparatorKeyCodec) Join(partition, sort string) string {
+	return partition + r.Separator + sort
+}
+
+// primaryKey returns the primary key attributes of a kvs key.
+func (r *LowLevelClient) primaryKey(key string) (map[string]types.AttributeValue, error) {
+	if r.names.SortKey == "" {
+		return map[string]types.AttributeValue{
+			r.names.Key: &types.AttributeValueMemberS{Value: key},
+		}, nil
+	}
+
+	partition, sort, err := r.codec.Split(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]types.AttributeValue{
+		r.names.Key:     &types.AttributeValueMemberS{Value: partition},
+		r.names.SortKey: &types.AttributeValueMemberS{Value: sort},
+	}, nil
+}
+
+// splitPrefix returns the partition key value and the sort key prefix of a scan prefix
+// holding a partition key value. Fails with simple primary keys.
+func (r *LowLevelClient) splitPrefix(prefix string) (string, string, error) {
+	if r.names.SortKey == "" {
+		return "", "", ErrKeySchema
+	}
+	return r.codec.Split(prefix)
+}
+
+// itemKey returns the kvs key of an item read from the table.
+func (r *LowLevelClient) itemKey(attributes map[string]types.AttributeValue) string {
+	partition := stringAttribute(attributes[r.names.Key])
+	if r.names.SortKey == "" {
+		return partition
+	}
+	return r.codec.Join(partition, stringAttribute(attributes[r.names.SortKey]))
+}
+
+// isKeyAttribute reports whether name is an attribute of the primary key.
+func (r *LowLevelClient) isKeyAttribute(name string) bool {
+	return name == r.names.Key || (r.names.SortKey != "" && name == r.names.SortKey)
+}
+
+// isReserved reports whether name is a key, value or TTL attribute.
+func (r *LowLevelClient) isReserved(name string) bool {
+	return slices.Contains(r.names.reserved(), name)
+}
+
+// stringAttribute returns the value of a string or number attribute, or the empty string.
+func stringAttribute(value types.AttributeValue) string {
+	switch member := value.(type) {
+	case *types.AttributeValueMemberS:
+		return member.Value
+	case *types.AttributeValueMemberN:
+		return member.Value
+	default:
+		return ""
+	}
+}
+
+// ttlAttribute returns the Unix timestamp held by a TTL attribute, or zero.
+func ttlAttribute(value types.AttributeValue) int64 {
+	ttl, _ := strconv.ParseInt(stringAttribute(value), 10, 64)
+	return ttl
+}
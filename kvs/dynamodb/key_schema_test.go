@@ -0,0 +1,170 @@
+package dynamodb_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsdynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	"github.com/arielsrv/go-kvs-client/kvs/dynamodb"
+)
+
+func scanKeys(t *testing.T, client *dynamodb.LowLevelClient, opts kvs.ScanOptions) []string {
+	t.Helper()
+
+	var keys []string
+	for item, err := range client.Scan(t.Context(), opts) {
+		require.NoError(t, err)
+		keys = append(keys, item.Key)
+	}
+	return keys
+}
+
+func TestSeparatorKeyCodec(t *testing.T) {
+	codec := dynamodb.SeparatorKeyCodec{Separator: "|"}
+
+	partition, sort, err := codec.Split("USER#1|ORDER|2")
+	require.NoError(t, err)
+	require.Equal(t, "USER#1", partition)
+	require.Equal(t, "ORDER|2", sort)
+	require.Equal(t, "USER#1|ORDER|2", codec.Join(partition, sort))
+
+	_, _, err = codec.Split("USER#1")
+	require.ErrorIs(t, err, dynamodb.ErrKeySchema)
+	_, _, err = codec.Split("|PROFILE")
+	require.ErrorIs(t, err, dynamodb.ErrKeySchema)
+}
+
+func TestKeySchema_AttributeNames(t *testing.T) {
+	ctx := t.Context()
+	names := dynamodb.AttributeNames{Key: "pk", Value: "data", TTL: "expires_at"}
+	client := dynamodb.NewBuilder(
+		dynamodb.WithContainerName("__kvs-test"),
+		dynamodb.WithAttributeNames(names),
+	).FakeBuild()
+	require.Equal(t, names, client.AttributeNames())
+
+	item := kvs.NewItem("user:1", Test{ID: 1, Name: "John"}, time.Hour)
+	require.NoError(t, client.Save("user:1", item))
+
+	output, err := client.AWSClient.GetItem(ctx, &awsdynamodb.GetItemInput{
+		TableName: aws.String("__kvs-test"),
+		Key:       map[string]types.AttributeValue{"pk": &types.AttributeValueMemberS{Value: "user:1"}},
+	})
+	require.NoError(t, err)
+	require.Contains(t, output.Item, "data")
+	require.Contains(t, output.Item, "expires_at")
+
+	got, err := client.Get("user:1")
+	require.NoError(t, err)
+	require.Equal(t, item.TTL, got.TTL)
+	require.JSONEq(t, `{"Name":"John","ID":1}`, got.Value.(string))
+
+	count, err := client.Increment(ctx, "hits", 3, time.Minute)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, count)
+
+	require.ErrorIs(t, client.SaveIfAbsent(ctx, "user:1", item), kvs.ErrConditionFailed)
+	require.NoError(t, client.CompareAndSwap(ctx, "user:1", got, kvs.NewItem("user:1", "v2")))
+
+	require.Equal(t, []string{"user:1"}, scanKeys(t, client, kvs.ScanOptions{Prefix: "user:"}))
+	require.ElementsMatch(t, []string{"hits", "user:1"}, scanKeys(t, client, kvs.ScanOptions{KeysOnly: true}))
+
+	require.NoError(t, client.Delete("user:1"))
+	_, err = client.Get("user:1")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+}
+
+func TestKeySchema_CompositeKey(t *testing.T) {
+	ctx := t.Context()
+	client := dynamodb.NewBuilder(
+		dynamodb.WithContainerName("__kvs-test"),
+		dynamodb.WithAttributeNames(dynamodb.AttributeNames{Key: "pk", SortKey: "sk"}),
+	).FakeBuild()
+
+	items := new(kvs.Items)
+	for _, key := range []string{"USER#1|PROFILE", "USER#1|ORDER#1", "USER#1|ORDER#2", "USER#2|PROFILE"} {
+		items.Add(kvs.NewItem(key, key))
+	}
+	require.NoError(t, client.BulkSave(items))
+
+	output, err := client.AWSClient.GetItem(ctx, &awsdynamodb.GetItemInput{
+		TableName: aws.String("__kvs-test"),
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: "USER#1"},
+			"sk": &types.AttributeValueMemberS{Value: "ORDER#2"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &types.AttributeValueMemberS{Value: `"USER#1|ORDER#2"`}, output.Item["value"])
+
+	got, err := client.Get("USER#1|ORDER#1")
+	require.NoError(t, err)
+	require.Equal(t, "USER#1|ORDER#1", got.Key)
+
+	bulk, err := client.BulkGet([]string{"USER#1|PROFILE", "USER#2|PROFILE", "USER#3|PROFILE"})
+	require.NoError(t, err)
+	require.Equal(t, 2, bulk.Len())
+
+	require.Equal(t, []string{"USER#1|ORDER#1", "USER#1|ORDER#2"},
+		scanKeys(t, client, kvs.ScanOptions{Prefix: "USER#1|ORDER", PageSize: 1}))
+	require.Equal(t, []string{"USER#1|ORDER#1", "USER#1|ORDER#2", "USER#1|PROFILE"},
+		scanKeys(t, client, kvs.ScanOptions{Prefix: "USER#1|"}))
+	require.Len(t, scanKeys(t, client, kvs.ScanOptions{Prefix: "USER#", KeysOnly: true}), 4)
+
+	require.NoError(t, client.Delete("USER#1|PROFILE"))
+	_, err = client.Get("USER#1|PROFILE")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+
+	require.ErrorIs(t, client.Save("USER#1", kvs.NewItem("USER#1", "v")), dynamodb.ErrKeySchema)
+	_, err = client.Get("USER#1")
+	require.ErrorIs(t, err, dynamodb.ErrKeySchema)
+	_, err = client.BulkGet([]string{"USER#1"})
+	require.ErrorIs(t, err, dynamodb.ErrKeySchema)
+	require.ErrorIs(t, client.Delete("USER#1"), dynamodb.ErrKeySchema)
+}
+
+// colonCodec maps "partition:sort" keys.
+type colonCodec struct{}
+
+func (colonCodec) Split(key string) (string, string, error) {
+	partition, sort, found := strings.Cut(key, ":")
+	if !found {
+		return "", "", dynamodb.ErrKeySchema
+	}
+	return partition, sort, nil
+}
+
+func (colonCodec) Join(partition, sort string) string { return partition + ":" + sort }
+
+func TestKeySchema_CompositeKey_Attributes(t *testing.T) {
+	ctx := t.Context()
+	llc := dynamodb.NewBuilder().
+		WithContainerName("__kvs-test").
+		WithAttributeNames(dynamodb.AttributeNames{Key: "pk", SortKey: "sk"}).
+		WithKeyCodec(colonCodec{}).
+		WithStorageMode(dynamodb.StorageAttributes).
+		FakeBuild()
+	client := kvs.NewKVSClient[profile](llc)
+
+	require.NoError(t, client.Save("tenant:1", &profile{Name: "John", Age: 42}))
+	require.NoError(t, llc.Patch(ctx, "tenant:1", map[string]any{"age": 43}))
+
+	got, err := client.Get("tenant:1")
+	require.NoError(t, err)
+	require.Equal(t, &profile{Name: "John", Age: 43}, got)
+
+	fields, err := llc.GetFields(ctx, "tenant:1", "name")
+	require.NoError(t, err)
+	require.Equal(t, "tenant:1", fields.Key)
+	require.Equal(t, dynamodb.AttributeValues{"name": &types.AttributeValueMemberS{Value: "John"}}, fields.Value)
+
+	require.ErrorIs(t, llc.Patch(ctx, "tenant:1", map[string]any{"sk": "x"}), kvs.ErrFieldPath)
+	err = llc.Save("tenant:2", kvs.NewItem("tenant:2", map[string]any{"sk": "x"}))
+	require.ErrorIs(t, err, dynamodb.ErrReservedAttribute)
+}
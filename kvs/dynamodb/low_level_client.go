@@ -3,14 +3,17 @@ package dynamodb
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
+	"math"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"golang.org/x/sync/singleflight"
@@ -24,9 +27,17 @@ import (
 type LowLevelClient struct {
 	AWSClient // Embedded AWS DynamoDB client
 
-	read      singleflight.Group // Group for deduplicating concurrent reads
-	tableName string             // Name of the DynamoDB table
-	ttl       time.Duration      // Default Time To Live for items in seconds
+	read           singleflight.Group // Group for deduplicating concurrent eventually consistent reads
+	consistentRead singleflight.Group // Group for deduplicating concurrent strongly consistent reads
+	tableName      string             // Name of the DynamoDB table
+	ttl            time.Duration      // Default Time To Live for items in seconds
+	storage        StorageMode        // How item values are stored
+	names          AttributeNames     // Attribute names of the table
+	codec          KeyCodec           // Maps keys to composite primary keys
+	indexes        []SecondaryIndex   // Global secondary indexes projected on save
+	now            func() time.Time   // Clock of the TTL of items
+	consistent     bool               // Whether reads are strongly consistent by default
+	evictExpired   bool               // Whether expired items are deleted when read
 }
 
 // NewLowLevelClient creates a new LowLevelClient with the provided AWS client and container name.
@@ -37,6 +48,9 @@ func NewLowLevelClient(awsClient AWSClient, containerName string, ttl ...time.Du
 	lowLevelClient := &LowLevelClient{
 		tableName: containerName,
 		AWSClient: awsClient,
+		names:     AttributeNames{}.withDefaults(),
+		codec:     SeparatorKeyCodec{Separator: DefaultKeySeparator},
+		now:       time.Now,
 	}
 
 	if len(ttl) > 0 {
@@ -54,7 +68,36 @@ func (r *LowLevelClient) TTL() time.Duration {
 	return r.ttl
 }
 
-// Constants for DynamoDB attribute names.
+// AttributeNames returns the attribute names of the table.
+func (r *LowLevelClient) AttributeNames() AttributeNames {
+	return r.names
+}
+
+// ConsistentRead returns whether Get and BulkGet issue strongly consistent reads by default.
+func (r *LowLevelClient) ConsistentRead() bool {
+	return r.consistent
+}
+
+// isConsistentRead returns whether the reads made with ctx are strongly consistent:
+// as requested by kvs.ContextWithConsistentRead, or the default of the client.
+func (r *LowLevelClient) isConsistentRead(ctx context.Context) bool {
+	if consistent, ok := kvs.ConsistentReadFromContext(ctx); ok {
+		return consistent
+	}
+	return r.consistent
+}
+
+// ExpiredItemEviction returns whether expired items found by reads are deleted in the background.
+func (r *LowLevelClient) ExpiredItemEviction() bool {
+	return r.evictExpired
+}
+
+// StorageMode returns how the client stores item values.
+func (r *LowLevelClient) StorageMode() StorageMode {
+	return r.storage
+}
+
+// Default DynamoDB attribute names (see AttributeNames).
 const (
 	KeyName   = "key"   // Attribute name for the item's key
 	ValueName = "value" // Attribute name for the item's value
@@ -76,25 +119,36 @@ func (r *LowLevelClient) Get(key string) (*kvs.Item, error) {
 
 // GetWithContext retrieves an item by its key using the provided context.
 // The context can be used for cancellation and timeouts.
-// This method uses a singleflight to deduplicate concurrent reads for the same key.
+// This method uses a singleflight to deduplicate concurrent reads for the same key; strongly
+// consistent reads (see ConsistentRead and kvs.ContextWithConsistentRead) are never merged
+// with eventually consistent ones.
+// An item whose TTL has elapsed, but which DynamoDB has not purged yet, is not found.
 // Returns the item if found, or an error if not found or if retrieval fails.
 func (r *LowLevelClient) GetWithContext(ctx context.Context, key string) (*kvs.Item, error) {
 	if strings.TrimSpace(key) == "" {
 		return nil, kvs.ErrEmptyKey
 	}
 
-	result, err, _ := r.read.Do(key, func() (any, error) {
+	consistent := r.isConsistentRead(ctx)
+	group := &r.read
+	if consistent {
+		group = &r.consistentRead
+	}
+
+	result, err, _ := group.Do(key, func() (any, error) {
 		select {
 		case <-ctx.Done():
 			return nil, fmt.Errorf("GetWithContext: operation cancelled or timed out: %w", ctx.Err())
 		default:
+			primaryKey, err := r.primaryKey(key)
+			if err != nil {
+				return nil, err
+			}
+
 			input := &dynamodb.GetItemInput{
-				TableName: r.getTableName(),
-				Key: map[string]types.AttributeValue{
-					KeyName: &types.AttributeValueMemberS{
-						Value: key,
-					},
-				},
+				TableName:      r.getTableName(),
+				Key:            primaryKey,
+				ConsistentRead: aws.Bool(consistent),
 			}
 
 			getItemOutput, err := r.AWSClient.GetItem(ctx, input)
@@ -104,17 +158,16 @@ func (r *LowLevelClient) GetWithContext(ctx context.Context, key string) (*kvs.I
 				return nil, kvs.ErrKeyNotFound
 			}
 
-			var item Item
-			err = attributevalue.UnmarshalMap(getItemOutput.Item, &item)
+			item, err := r.unmarshalItem(getItemOutput.Item)
 			if err != nil {
 				return nil, err
 			}
+			if r.expired(item) {
+				r.evict(item)
+				return nil, kvs.ErrKeyNotFound
+			}
 
-			return &kvs.Item{
-				Key:   item.Key,
-				Value: item.Value,
-				TTL:   item.TTL,
-			}, nil
+			return item, nil
 		}
 	})
 	if err != nil {
@@ -134,7 +187,7 @@ func (r *LowLevelClient) Save(key string, kvsItem *kvs.Item) error {
 // SaveWithContext stores an item with the specified key using the provided context.
 // The context can be used for cancellation and timeouts.
 // If the item has no TTL and the client has a default TTL, the default TTL is applied.
-// The item is marshaled to JSON and stored in DynamoDB.
+// The item is marshaled according to the storage mode of the client and stored in DynamoDB.
 // Returns an error if the key is empty, the item is nil, marshaling fails, or the save operation fails.
 func (r *LowLevelClient) SaveWithContext(ctx context.Context, key string, item *kvs.Item) error {
 	if strings.TrimSpace(key) == "" {
@@ -146,17 +199,17 @@ func (r *LowLevelClient) SaveWithContext(ctx context.Context, key string, item *
 	}
 
 	if r.ttl > 0 && item.TTL == 0 {
-		item.TTL = time.Now().Add(r.ttl).Unix()
+		item.TTL = r.now().Add(r.ttl).Unix()
 	}
 
-	bytes, err := json.Marshal(item.Value)
+	attributes, err := r.marshalItem(item)
 	if err != nil {
 		return err
 	}
 
 	_, err = r.AWSClient.PutItem(ctx, &dynamodb.PutItemInput{
 		TableName: r.getTableName(),
-		Item:      r.newItem(item, bytes),
+		Item:      attributes,
 	})
 	if err != nil {
 		return err
@@ -174,55 +227,93 @@ func (r *LowLevelClient) BulkGet(keys []string) (*kvs.Items, error) {
 
 // BulkGetWithContext retrieves multiple items by their keys using the provided context.
 // The context can be used for cancellation and timeouts.
-// This method has a limit of 100 keys per request.
-// Returns a collection of items that were found, or an error if retrieval fails.
-// If more than 100 keys are provided, ErrTooManyKeys is returned.
+// This method has a limit of 100 keys per request. Reads follow the consistency of the client
+// or of ctx, as in GetWithContext.
+// Duplicate and empty keys are read once and skipped respectively, and the keys left unprocessed
+// by DynamoDB are requested again, with an exponential backoff. Items whose TTL has elapsed are
+// left out, as in GetWithContext.
+// Returns a collection of the items that were found, in the order of keys, or an error if
+// retrieval fails.
+// If more than 100 keys are provided, ErrTooManyKeys is returned; ErrUnprocessed if keys are
+// still unprocessed after the retries.
 func (r *LowLevelClient) BulkGetWithContext(ctx context.Context, keys []string) (*kvs.Items, error) {
-	if len(keys) > 100 {
+	if len(keys) > MaxBatchGetKeys {
 		return nil, kvs.ErrTooManyKeys
 	}
 
-	inputKeys := make([]map[string]types.AttributeValue, len(keys))
-	for i := range keys {
-		inputKeys[i] = map[string]types.AttributeValue{
-			KeyName: &types.AttributeValueMemberS{
-				Value: keys[i],
-			},
+	items := new(kvs.Items)
+	inputKeys := make([]map[string]types.AttributeValue, 0, len(keys))
+	positions := make(map[string]int, len(keys))
+	for _, key := range keys {
+		if _, seen := positions[key]; seen || strings.TrimSpace(key) == "" {
+			continue
 		}
+		positions[key] = len(positions)
+
+		primaryKey, err := r.primaryKey(key)
+		if err != nil {
+			return nil, err
+		}
+		inputKeys = append(inputKeys, primaryKey)
+	}
+	if len(inputKeys) == 0 {
+		return items, nil
 	}
 
 	input := &dynamodb.BatchGetItemInput{
 		RequestItems: map[string]types.KeysAndAttributes{
 			aws.ToString(r.getTableName()): {
-				Keys: inputKeys,
+				Keys:           inputKeys,
+				ConsistentRead: aws.Bool(r.isConsistentRead(ctx)),
 			},
 		},
 	}
 
-	batchGetItemOutput, err := r.AWSClient.BatchGetItem(ctx, input)
-	if err != nil {
-		return nil, err
-	}
-
-	items := new(kvs.Items)
-
-	for _, value := range batchGetItemOutput.Responses {
-		var item []Item
-		err = attributevalue.UnmarshalListOfMaps(value, &item)
+	// DynamoDB returns the items in no particular order: they are put back in the order of keys.
+	found := make([]*kvs.Item, 0, len(inputKeys))
+	for attempt := 1; ; attempt++ {
+		batchGetItemOutput, err := r.AWSClient.BatchGetItem(ctx, input)
 		if err != nil {
 			return nil, err
 		}
 
-		for i := range item {
-			items.Add(&kvs.Item{
-				Key:   item[i].Key,
-				Value: item[i].Value,
-				TTL:   item[i].TTL,
+		for _, responses := range batchGetItemOutput.Responses {
+			for _, attributes := range responses {
+				item, err := r.unmarshalItem(attributes)
+				if err != nil {
+					return nil, err
+				}
+				if r.expired(item) {
+					r.evict(item)
+					continue
+				}
+				found = append(found, item)
+			}
+		}
+
+		if len(batchGetItemOutput.UnprocessedKeys) == 0 {
+			slices.SortStableFunc(found, func(a, b *kvs.Item) int {
+				return positionOf(positions, a.Key) - positionOf(positions, b.Key)
 			})
+			for _, item := range found {
+				items.Add(item)
+			}
+			return items, nil
 		}
+		if err = waitBatchRetry(ctx, attempt); err != nil {
+			return nil, err
+		}
+		input.RequestItems = batchGetItemOutput.UnprocessedKeys
 	}
+}
 
-	return items, nil
+// positionOf returns the position of key in positions, or the number of positions for a key
+// that was not requested, which sorts it last.
+func positionOf(positions map[string]int, key string) int {
+	if position, found := positions[key]; found {
+		return position
+	}
+	return len(positions)
 }
 
 // BulkSave stores multiple items.
@@ -234,32 +325,84 @@ func (r *LowLevelClient) BulkSave(items *kvs.Items) error {
 
 // BulkSaveWithContext stores multiple items using the provided context.
 // The context can be used for cancellation and timeouts.
-// Each item is marshalled to JSON and stored in DynamoDB.
+// Each item is marshalled according to the storage mode of the client and stored in DynamoDB.
 // If marshalling of an individual item fails, it is skipped and an error is logged.
-// Returns an error if the batch write operation fails.
+// Nil items and items with an empty key are skipped too, and the default TTL applies to the
+// items without a TTL, as in SaveWithContext.
+// Items are written in batches of MaxBatchWriteItems; when several items share a key, the last
+// one is written. The items left unprocessed by DynamoDB are written again, with an exponential
+// backoff.
+// Returns an error if a batch write operation fails, ErrUnprocessed if items are still
+// unprocessed after the retries.
 func (r *LowLevelClient) BulkSaveWithContext(ctx context.Context, kvsItems *kvs.Items) error {
+	if kvsItems == nil {
+		return nil
+	}
+
 	items := make([]types.WriteRequest, 0, kvsItems.Len())
+	positions := make(map[string]int, kvsItems.Len())
 
 	for item := range kvsItems.All() {
-		bytes, err := json.Marshal(item.Value)
+		if item == nil || strings.TrimSpace(item.Key) == "" {
+			continue
+		}
+		if r.ttl > 0 && item.TTL == 0 {
+			expiring := *item
+			expiring.TTL = r.now().Add(r.ttl).Unix()
+			item = &expiring
+		}
+
+		attributes, err := r.marshalItem(item)
 		if err != nil {
 			continue
 		}
 
-		items = append(items, types.WriteRequest{
+		request := types.WriteRequest{
 			PutRequest: &types.PutRequest{
-				Item: r.newItem(item, bytes),
+				Item: attributes,
 			},
-		})
+		}
+		if position, found := positions[item.Key]; found {
+			items[position] = request
+			continue
+		}
+		positions[item.Key] = len(items)
+		items = append(items, request)
 	}
 
-	batchInput := &dynamodb.BatchWriteItemInput{
-		RequestItems: map[string][]types.WriteRequest{
-			aws.ToString(r.getTableName()): items,
-		},
+	for batch := range slices.Chunk(items, MaxBatchWriteItems) {
+		if err := r.batchWrite(ctx, batch); err != nil {
+			return err
+		}
 	}
 
-	_, err := r.AWSClient.BatchWriteItem(ctx, batchInput)
+	return nil
+}
+
+// Delete removes the item stored under key.
+// It uses a background context and delegates to DeleteWithContext.
+// Returns an error if the delete operation fails.
+func (r *LowLevelClient) Delete(key string) error {
+	return r.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext removes the item stored under key using the provided context.
+// Deleting a missing key is not an error.
+// Returns an error if the key is empty or the delete operation fails.
+func (r *LowLevelClient) DeleteWithContext(ctx context.Context, key string) error {
+	if strings.TrimSpace(key) == "" {
+		return kvs.ErrEmptyKey
+	}
+
+	primaryKey, err := r.primaryKey(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.AWSClient.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: r.getTableName(),
+		Key:       primaryKey,
+	})
 	if err != nil {
 		return err
 	}
@@ -267,6 +410,418 @@ func (r *LowLevelClient) BulkSaveWithContext(ctx context.Context, kvsItems *kvs.
 	return nil
 }
 
+// SaveIfAbsent stores an item with the specified key only if the key is not taken, using a
+// PutItem condition. An item whose TTL attribute has elapsed (but which DynamoDB has not purged
+// yet) counts as absent. TTL semantics are those of SaveWithContext.
+// Returns kvs.ErrConditionFailed if a live item is already stored under key.
+func (r *LowLevelClient) SaveIfAbsent(ctx context.Context, key string, item *kvs.Item) error {
+	attributes, err := r.conditionalItem(key, item)
+	if err != nil {
+		return err
+	}
+
+	condition := r.newAbsentCondition()
+	_, err = r.AWSClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                 r.getTableName(),
+		Item:                      attributes,
+		ConditionExpression:       condition.expression,
+		ExpressionAttributeNames:  condition.names,
+		ExpressionAttributeValues: condition.values,
+	})
+
+	return conditionError(err)
+}
+
+// CompareAndSwap replaces the item stored under key with item using a PutItem condition on the
+// stored value, which must equal the value of expected (as returned by Get) and must not have expired.
+// TTL semantics are those of SaveWithContext.
+// Returns kvs.ErrConditionFailed if the stored item is missing, expired or has changed.
+func (r *LowLevelClient) CompareAndSwap(ctx context.Context, key string, expected, item *kvs.Item) error {
+	attributes, err := r.conditionalItem(key, item)
+	if err != nil {
+		return err
+	}
+
+	condition, err := r.newValueCondition(expected)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.AWSClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                 r.getTableName(),
+		Item:                      attributes,
+		ConditionExpression:       condition.expression,
+		ExpressionAttributeNames:  condition.names,
+		ExpressionAttributeValues: condition.values,
+	})
+
+	return conditionError(err)
+}
+
+// CompareAndDelete removes the item stored under key using a DeleteItem condition on the stored
+// value, which must equal the value of expected (as returned by Get) and must not have expired.
+// Returns kvs.ErrConditionFailed if the stored item is missing, expired or has changed.
+func (r *LowLevelClient) CompareAndDelete(ctx context.Context, key string, expected *kvs.Item) error {
+	if strings.TrimSpace(key) == "" {
+		return kvs.ErrEmptyKey
+	}
+
+	primaryKey, err := r.primaryKey(key)
+	if err != nil {
+		return err
+	}
+
+	condition, err := r.newValueCondition(expected)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.AWSClient.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:                 r.getTableName(),
+		Key:                       primaryKey,
+		ConditionExpression:       condition.expression,
+		ExpressionAttributeNames:  condition.names,
+		ExpressionAttributeValues: condition.values,
+	})
+
+	return conditionError(err)
+}
+
+// conditionalItem validates and marshals an item written by a conditional operation.
+// The item is stored under key, and the client default TTL applies when it has none.
+func (r *LowLevelClient) conditionalItem(key string, item *kvs.Item) (map[string]types.AttributeValue, error) {
+	if strings.TrimSpace(key) == "" {
+		return nil, kvs.ErrEmptyKey
+	}
+
+	if item == nil {
+		return nil, kvs.ErrNilItem
+	}
+
+	stored := *item
+	stored.Key = key
+	if r.ttl > 0 && stored.TTL == 0 {
+		stored.TTL = r.now().Add(r.ttl).Unix()
+	}
+
+	return r.marshalItem(&stored)
+}
+
+// valueCondition is a condition expression with its attribute names and values.
+type valueCondition struct {
+	expression *string
+	names      map[string]string
+	values     map[string]types.AttributeValue
+}
+
+// newAbsentCondition returns the condition matching a missing or expired item.
+func (r *LowLevelClient) newAbsentCondition() *valueCondition {
+	return &valueCondition{
+		expression: aws.String("attribute_not_exists(#key) OR #ttl <= :now"),
+		names: map[string]string{
+			"#key": r.names.Key,
+			"#ttl": r.names.TTL,
+		},
+		values: map[string]types.AttributeValue{
+			":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(r.now().Unix(), 10)},
+		},
+	}
+}
+
+// newValueCondition returns the condition matching a live item whose value is the value of expected:
+// its JSON value, or its native attributes (see newAttributesCondition).
+func (r *LowLevelClient) newValueCondition(expected *kvs.Item) (*valueCondition, error) {
+	if expected == nil {
+		return nil, kvs.ErrNilItem
+	}
+
+	if attributes, ok := expected.Value.(AttributeValues); ok {
+		return r.newAttributesCondition(attributes), nil
+	}
+
+	value, ok := expected.Value.(string)
+	if !ok {
+		return nil, kvs.ErrConvert
+	}
+
+	return &valueCondition{
+		expression: aws.String("#value = :expected AND (attribute_not_exists(#ttl) OR #ttl > :now)"),
+		names: map[string]string{
+			"#value": r.names.Value,
+			"#ttl":   r.names.TTL,
+		},
+		values: map[string]types.AttributeValue{
+			":expected": &types.AttributeValueMemberS{Value: value},
+			":now":      &types.AttributeValueMemberN{Value: strconv.FormatInt(r.now().Unix(), 10)},
+		},
+	}, nil
+}
+
+// conditionError maps a failed condition check to kvs.ErrConditionFailed and returns any other error unchanged.
+func conditionError(err error) error {
+	var conditionalCheckFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionalCheckFailed) {
+		return kvs.ErrConditionFailed
+	}
+	return err
+}
+
+// Increment atomically adds delta to the counter stored under key using an UpdateItem ADD expression.
+// The counter is kept as a number attribute in the value attribute, which reads back through Get
+// as its JSON representation. The TTL attribute is only set when it is absent, so the expiration
+// is fixed when the counter is created; a non-positive ttl falls back to the client default.
+// A counter whose TTL has elapsed restarts from delta with a new TTL, as with Redis, even
+// though DynamoDB has not deleted it yet.
+// Returns the new value of the counter.
+func (r *LowLevelClient) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	value, err := r.increment(ctx, key, strconv.FormatInt(delta, 10), ttl)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// IncrementFloat is like Increment for floating-point counters.
+// Returns the new value of the counter.
+func (r *LowLevelClient) IncrementFloat(
+	ctx context.Context,
+	key string,
+	delta float64,
+	ttl time.Duration,
+) (float64, error) {
+	value, err := r.increment(ctx, key, strconv.FormatFloat(delta, 'f', -1, 64), ttl)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseFloat(value, 64)
+}
+
+// increment issues the UpdateItem request shared by Increment and IncrementFloat
+// and returns the updated value as a DynamoDB number string.
+func (r *LowLevelClient) increment(ctx context.Context, key string, delta string, ttl time.Duration) (string, error) {
+	if strings.TrimSpace(key) == "" {
+		return "", kvs.ErrEmptyKey
+	}
+
+	if ttl <= 0 {
+		ttl = r.ttl
+	}
+
+	primaryKey, err := r.primaryKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	// The counter is added to while it is live. Once its TTL has elapsed, DynamoDB may
+	// keep it for up to 48 hours, so it is restarted instead: value and TTL are reset.
+	now := strconv.FormatInt(r.now().Unix(), 10)
+	live := &dynamodb.UpdateItemInput{
+		TableName:           r.getTableName(),
+		Key:                 primaryKey,
+		UpdateExpression:    aws.String("ADD #value :delta"),
+		ConditionExpression: aws.String("attribute_not_exists(#ttl) OR #ttl > :now"),
+		ExpressionAttributeNames: map[string]string{
+			"#value": r.names.Value,
+			"#ttl":   r.names.TTL,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":delta": &types.AttributeValueMemberN{Value: delta},
+			":now":   &types.AttributeValueMemberN{Value: now},
+		},
+		ReturnValues: types.ReturnValueUpdatedNew,
+	}
+	expired := &dynamodb.UpdateItemInput{
+		TableName:                 r.getTableName(),
+		Key:                       primaryKey,
+		UpdateExpression:          aws.String("SET #value = :delta REMOVE #ttl"),
+		ConditionExpression:       aws.String("#ttl <= :now"),
+		ExpressionAttributeNames:  live.ExpressionAttributeNames,
+		ExpressionAttributeValues: live.ExpressionAttributeValues,
+		ReturnValues:              types.ReturnValueUpdatedNew,
+	}
+
+	if ttl > 0 {
+		live.UpdateExpression = aws.String("SET #ttl = if_not_exists(#ttl, :ttl) ADD #value :delta")
+		expired.UpdateExpression = aws.String("SET #value = :delta, #ttl = :ttl")
+		live.ExpressionAttributeValues[":ttl"] = &types.AttributeValueMemberN{
+			Value: strconv.FormatInt(r.now().Add(ttl).Unix(), 10),
+		}
+	}
+
+	output, err := r.updateCounter(ctx, live, expired)
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := output.Attributes[r.names.Value].(*types.AttributeValueMemberN)
+	if !ok {
+		return "", kvs.ErrConvert
+	}
+
+	return value.Value, nil
+}
+
+// maxCounterAttempts bounds the rounds of updateCounter, each of which only fails when a
+// concurrent increment has expired or restarted the counter in between.
+const maxCounterAttempts = 3
+
+// updateCounter applies live to a live or missing counter, or else expired to an expired
+// one, retrying when the counter changes state between the two conditional updates.
+func (r *LowLevelClient) updateCounter(
+	ctx context.Context,
+	live, expired *dynamodb.UpdateItemInput,
+) (*dynamodb.UpdateItemOutput, error) {
+	var err error
+	for range maxCounterAttempts {
+		var output *dynamodb.UpdateItemOutput
+		for _, input := range []*dynamodb.UpdateItemInput{live, expired} {
+			output, err = r.AWSClient.UpdateItem(ctx, input)
+			if !errors.Is(conditionError(err), kvs.ErrConditionFailed) {
+				return output, err
+			}
+		}
+	}
+	return nil, conditionError(err)
+}
+
+// Scan enumerates the items stored in the table using the provided context.
+// Pages of opts.PageSize items are requested lazily while the sequence is consumed.
+// A non-empty opts.Prefix is applied as a begins_with filter on the key attribute or, with a
+// composite primary key, on the sort key of the partition when the prefix holds a partition key
+// value. opts.KeysOnly projects only the key and TTL attributes.
+// Expired items not yet purged by DynamoDB are skipped, like in Get.
+// When opts.Segments is 2 or more, the table is read with a parallel scan: each
+// segment is scanned by its own goroutine and items are yielded as they arrive,
+// so no ordering is guaranteed.
+func (r *LowLevelClient) Scan(ctx context.Context, opts kvs.ScanOptions) iter.Seq2[*kvs.Item, error] {
+	return func(yield func(*kvs.Item, error) bool) {
+		if opts.Segments < 2 {
+			r.scanSegment(ctx, r.newScanInput(opts), opts.KeysOnly, yield)
+			return
+		}
+
+		type scanResult struct {
+			item *kvs.Item
+			err  error
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		results := make(chan scanResult)
+		total := int32(min(opts.Segments, math.MaxInt32))
+		var wg sync.WaitGroup
+		for segment := range total {
+			input := r.newScanInput(opts)
+			input.Segment = aws.Int32(segment)
+			input.TotalSegments = aws.Int32(total)
+
+			wg.Go(func() {
+				r.scanSegment(ctx, input, opts.KeysOnly, func(item *kvs.Item, err error) bool {
+					select {
+					case results <- scanResult{item: item, err: err}:
+						return err == nil
+					case <-ctx.Done():
+						return false
+					}
+				})
+			})
+		}
+
+		go func() {
+			wg.Wait()
+			close(results)
+		}()
+
+		for result := range results {
+			if !yield(result.item, result.err) || result.err != nil {
+				return
+			}
+		}
+	}
+}
+
+// newScanInput builds the ScanInput shared by every segment of a scan.
+func (r *LowLevelClient) newScanInput(opts kvs.ScanOptions) *dynamodb.ScanInput {
+	input := &dynamodb.ScanInput{
+		TableName: r.getTableName(),
+		Limit:     aws.Int32(int32(min(opts.PageSizeOrDefault(), math.MaxInt32))),
+	}
+
+	names := map[string]string{}
+	if opts.Prefix != "" {
+		names["#key"] = r.names.Key
+		input.FilterExpression = aws.String("begins_with(#key, :prefix)")
+		input.ExpressionAttributeValues = map[string]types.AttributeValue{
+			":prefix": &types.AttributeValueMemberS{Value: opts.Prefix},
+		}
+		if partition, sort, err := r.splitPrefix(opts.Prefix); err == nil {
+			names["#sort"] = r.names.SortKey
+			input.FilterExpression = aws.String("#key = :partition AND begins_with(#sort, :prefix)")
+			input.ExpressionAttributeValues = map[string]types.AttributeValue{
+				":partition": &types.AttributeValueMemberS{Value: partition},
+				":prefix":    &types.AttributeValueMemberS{Value: sort},
+			}
+		}
+	}
+	if opts.KeysOnly {
+		names["#key"] = r.names.Key
+		names["#ttl"] = r.names.TTL
+		input.ProjectionExpression = aws.String("#key, #ttl")
+		if r.names.SortKey != "" {
+			names["#sort"] = r.names.SortKey
+			input.ProjectionExpression = aws.String("#key, #sort, #ttl")
+		}
+	}
+	if len(names) > 0 {
+		input.ExpressionAttributeNames = names
+	}
+
+	return input
+}
+
+// scanSegment pages through a single scan segment, following LastEvaluatedKey,
+// and hands every live item to yield. It stops as soon as yield returns false.
+func (r *LowLevelClient) scanSegment(
+	ctx context.Context,
+	input *dynamodb.ScanInput,
+	keysOnly bool,
+	yield func(*kvs.Item, error) bool,
+) {
+	for {
+		output, err := r.AWSClient.Scan(ctx, input)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		for _, attributes := range output.Items {
+			item, err := r.unmarshalItem(attributes)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if r.expired(item) {
+				r.evict(item)
+				continue
+			}
+			if keysOnly {
+				item.Value, item.Codec = nil, ""
+			}
+			if !yield(item, nil) {
+				return
+			}
+		}
+
+		if len(output.LastEvaluatedKey) == 0 {
+			return
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
 // ContainerName returns the name of the container or service that this client interacts with.
 // Used for metrics and logging.
 func (r *LowLevelClient) ContainerName() string {
@@ -275,13 +830,16 @@ func (r *LowLevelClient) ContainerName() string {
 
 // newItem creates a new DynamoDB item from a KVS item and its marshalled value.
 // The item is represented as a map of attribute names to attribute values.
-// The key, value, and TTL are stored as attributes.
-func (r *LowLevelClient) newItem(item *kvs.Item, bytes []byte) map[string]types.AttributeValue {
-	attributes := map[string]types.AttributeValue{}
-	attributes[KeyName] = &types.AttributeValueMemberS{Value: item.Key}
-	attributes[ValueName] = &types.AttributeValueMemberS{Value: string(bytes)}
+// The primary key, value, and TTL are stored as attributes.
+// Returns ErrKeySchema if the key does not fit a composite primary key.
+func (r *LowLevelClient) newItem(item *kvs.Item, bytes []byte) (map[string]types.AttributeValue, error) {
+	attributes, err := r.primaryKey(item.Key)
+	if err != nil {
+		return nil, err
+	}
+	attributes[r.names.Value] = &types.AttributeValueMemberS{Value: string(bytes)}
 	if item.TTL > 0 {
-		attributes[TTLName] = &types.AttributeValueMemberN{Value: strconv.FormatInt(item.TTL, 10)}
+		attributes[r.names.TTL] = &types.AttributeValueMemberN{Value: strconv.FormatInt(item.TTL, 10)}
 	}
-	return attributes
+	return attributes, nil
 }
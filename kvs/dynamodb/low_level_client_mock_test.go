@@ -251,3 +251,131 @@ func TestLowLevelClient_BulkGetWithContext_UnmarshalError_Propagates(t *testing.
 	require.Error(t, err)
 	require.Nil(t, items)
 }
+
+func TestLowLevelClient_Scan_ScanError_Propagates(t *testing.T) {
+	awsMock := mockdb.NewMockAWSClient(t)
+	awsMock.EXPECT().
+		Scan(matchAny(), matchAny()).
+		Return(nil, errBoom).
+		Once()
+
+	client := dynamodb.NewLowLevelClient(awsMock, "t")
+
+	var err error
+	for _, err = range client.Scan(context.Background(), kvs.ScanOptions{}) {
+	}
+	require.ErrorIs(t, err, errBoom)
+}
+
+func TestLowLevelClient_Scan_ParallelSegmentError_Propagates(t *testing.T) {
+	awsMock := mockdb.NewMockAWSClient(t)
+	awsMock.EXPECT().
+		Scan(matchAny(), matchAny()).
+		Return(nil, errBoom)
+
+	client := dynamodb.NewLowLevelClient(awsMock, "t")
+
+	var err error
+	for _, err = range client.Scan(context.Background(), kvs.ScanOptions{Segments: 3}) {
+	}
+	require.ErrorIs(t, err, errBoom)
+}
+
+func TestLowLevelClient_Scan_BuildsPrefixFilterAndProjection(t *testing.T) {
+	awsMock := mockdb.NewMockAWSClient(t)
+	awsMock.EXPECT().
+		Scan(matchAny(), mock.MatchedBy(func(input *awsdynamodb.ScanInput) bool {
+			return *input.FilterExpression == "begins_with(#key, :prefix)" &&
+				*input.ProjectionExpression == "#key, #ttl" &&
+				*input.Limit == 5 &&
+				input.ExpressionAttributeNames["#key"] == dynamodb.KeyName &&
+				input.ExpressionAttributeNames["#ttl"] == dynamodb.TTLName
+		})).
+		Return(&awsdynamodb.ScanOutput{
+			Items: []map[string]types.AttributeValue{
+				{
+					dynamodb.KeyName: &types.AttributeValueMemberS{Value: "p1"},
+					dynamodb.TTLName: &types.AttributeValueMemberN{Value: "4102444800"},
+				},
+			},
+		}, nil).
+		Once()
+
+	client := dynamodb.NewLowLevelClient(awsMock, "t")
+
+	items := make([]*kvs.Item, 0, 1)
+	for item, err := range client.Scan(context.Background(), kvs.ScanOptions{
+		Prefix:   "p",
+		PageSize: 5,
+		KeysOnly: true,
+	}) {
+		require.NoError(t, err)
+		items = append(items, item)
+	}
+	require.Len(t, items, 1)
+	require.Equal(t, "p1", items[0].Key)
+	require.Equal(t, int64(4102444800), items[0].TTL)
+	require.Nil(t, items[0].Value)
+}
+
+func TestLowLevelClient_Increment_BuildsAddExpressionWithTTLOnCreate(t *testing.T) {
+	awsMock := mockdb.NewMockAWSClient(t)
+	awsMock.EXPECT().
+		UpdateItem(matchAny(), mock.MatchedBy(func(input *awsdynamodb.UpdateItemInput) bool {
+			delta, ok := input.ExpressionAttributeValues[":delta"].(*types.AttributeValueMemberN)
+			_, hasTTL := input.ExpressionAttributeValues[":ttl"]
+			return ok && delta.Value == "3" && hasTTL &&
+				*input.UpdateExpression == "SET #ttl = if_not_exists(#ttl, :ttl) ADD #value :delta" &&
+				input.ReturnValues == types.ReturnValueUpdatedNew
+		})).
+		Return(&awsdynamodb.UpdateItemOutput{
+			Attributes: map[string]types.AttributeValue{
+				dynamodb.ValueName: &types.AttributeValueMemberN{Value: "10"},
+			},
+		}, nil).
+		Once()
+
+	client := dynamodb.NewLowLevelClient(awsMock, "t", time.Hour)
+
+	value, err := client.Increment(context.Background(), "k", 3, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), value)
+}
+
+func TestLowLevelClient_Increment_UpdateItemError_Propagates(t *testing.T) {
+	awsMock := mockdb.NewMockAWSClient(t)
+	awsMock.EXPECT().
+		UpdateItem(matchAny(), matchAny()).
+		Return(nil, errBoom).
+		Once()
+
+	client := dynamodb.NewLowLevelClient(awsMock, "t")
+
+	_, err := client.IncrementFloat(context.Background(), "k", 1.5, 0)
+	require.ErrorIs(t, err, errBoom)
+}
+
+func TestLowLevelClient_Increment_MissingValueAttribute_ReturnsErrConvert(t *testing.T) {
+	awsMock := mockdb.NewMockAWSClient(t)
+	awsMock.EXPECT().
+		UpdateItem(matchAny(), matchAny()).
+		Return(&awsdynamodb.UpdateItemOutput{}, nil).
+		Once()
+
+	client := dynamodb.NewLowLevelClient(awsMock, "t")
+
+	_, err := client.Increment(context.Background(), "k", 1, 0)
+	require.ErrorIs(t, err, kvs.ErrConvert)
+}
+
+func TestLowLevelClient_DeleteWithContext_DeleteItemError_Propagates(t *testing.T) {
+	awsMock := mockdb.NewMockAWSClient(t)
+	awsMock.EXPECT().
+		DeleteItem(matchAny(), matchAny()).
+		Return(nil, errBoom).
+		Once()
+
+	client := dynamodb.NewLowLevelClient(awsMock, "t")
+
+	require.ErrorIs(t, client.Delete("k"), errBoom)
+}
@@ -2,12 +2,15 @@ package dynamodb_test
 
 import (
 	"slices"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/arielsrv/go-kvs-client/kvs"
 	"github.com/arielsrv/go-kvs-client/kvs/dynamodb"
+	"github.com/arielsrv/go-kvs-client/kvs/model"
 )
 
 type Test struct {
@@ -128,3 +131,185 @@ func TestEmptyKeyInGet(t *testing.T) {
 	require.Error(t, err)
 	require.Equal(t, kvs.ErrNilItem, err)
 }
+
+func TestClient_Scan_FiltersByPrefix(t *testing.T) {
+	kvsClient := dynamodb.NewLowLevelClient(dynamodb.NewAWSFakeClient(), "__kvs-test")
+
+	for _, key := range []string{"user:1", "user:2", "order:1"} {
+		require.NoError(t, kvsClient.Save(key, kvs.NewItem(key, Test{Name: key})))
+	}
+
+	keys := make([]string, 0, 2)
+	for item, err := range kvsClient.Scan(t.Context(), kvs.ScanOptions{Prefix: "user:"}) {
+		require.NoError(t, err)
+		actualValue := new(Test)
+		require.NoError(t, item.TryGetValueAsObjectType(&actualValue))
+		require.Equal(t, item.Key, actualValue.Name)
+		keys = append(keys, item.Key)
+	}
+
+	require.Equal(t, []string{"user:1", "user:2"}, keys)
+}
+
+func TestClient_Scan_KeysOnlyAndPagination(t *testing.T) {
+	kvsClient := dynamodb.NewLowLevelClient(dynamodb.NewAWSFakeClient(), "__kvs-test")
+
+	for i := range 25 {
+		key := strconv.Itoa(i)
+		require.NoError(t, kvsClient.Save(key, kvs.NewItem(key, Test{ID: i})))
+	}
+
+	count := 0
+	for item, err := range kvsClient.Scan(t.Context(), kvs.ScanOptions{PageSize: 10, KeysOnly: true}) {
+		require.NoError(t, err)
+		require.Nil(t, item.Value)
+		count++
+	}
+	require.Equal(t, 25, count)
+}
+
+func TestClient_Scan_ParallelSegments(t *testing.T) {
+	kvsClient := dynamodb.NewLowLevelClient(dynamodb.NewAWSFakeClient(), "__kvs-test")
+
+	expected := make([]string, 0, 40)
+	for i := range 40 {
+		key := strconv.Itoa(i)
+		expected = append(expected, key)
+		require.NoError(t, kvsClient.Save(key, kvs.NewItem(key, Test{ID: i})))
+	}
+
+	keys := make([]string, 0, 40)
+	for item, err := range kvsClient.Scan(t.Context(), kvs.ScanOptions{PageSize: 3, Segments: 4}) {
+		require.NoError(t, err)
+		keys = append(keys, item.Key)
+	}
+	require.ElementsMatch(t, expected, keys)
+
+	seen := 0
+	for _, err := range kvsClient.Scan(t.Context(), kvs.ScanOptions{PageSize: 3, Segments: 4}) {
+		require.NoError(t, err)
+		seen++
+		if seen == 5 {
+			break
+		}
+	}
+	require.Equal(t, 5, seen)
+}
+
+func TestClient_IncrementAndDelete(t *testing.T) {
+	kvsClient := dynamodb.NewLowLevelClient(dynamodb.NewAWSFakeClient(), "__kvs-test")
+
+	value, err := kvsClient.Increment(t.Context(), "hits", 2, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), value)
+
+	value, err = kvsClient.Increment(t.Context(), "hits", 3, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), value)
+
+	floatValue, err := kvsClient.IncrementFloat(t.Context(), "hits", 0.5, 0)
+	require.NoError(t, err)
+	require.InDelta(t, 5.5, floatValue, 1e-9)
+
+	require.NoError(t, kvsClient.Delete("hits"))
+	require.NoError(t, kvsClient.Delete("hits"))
+
+	_, err = kvsClient.Get("hits")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+
+	_, err = kvsClient.Increment(t.Context(), " ", 1, 0)
+	require.ErrorIs(t, err, kvs.ErrEmptyKey)
+	require.ErrorIs(t, kvsClient.Delete(""), kvs.ErrEmptyKey)
+}
+
+func TestClient_ConditionalWrites(t *testing.T) {
+	kvsClient := dynamodb.NewLowLevelClient(dynamodb.NewAWSFakeClient(), "__kvs-test")
+	ctx := t.Context()
+
+	require.NoError(t, kvsClient.SaveIfAbsent(ctx, "1", kvs.NewItem("1", "a")))
+	require.ErrorIs(t, kvsClient.SaveIfAbsent(ctx, "1", kvs.NewItem("1", "b")), kvs.ErrConditionFailed)
+
+	current, err := kvsClient.Get("1")
+	require.NoError(t, err)
+
+	require.NoError(t, kvsClient.CompareAndSwap(ctx, "1", current, kvs.NewItem("1", "c")))
+	require.ErrorIs(t, kvsClient.CompareAndSwap(ctx, "1", current, kvs.NewItem("1", "d")), kvs.ErrConditionFailed)
+	require.ErrorIs(t, kvsClient.CompareAndDelete(ctx, "1", current), kvs.ErrConditionFailed)
+
+	current, err = kvsClient.Get("1")
+	require.NoError(t, err)
+	require.Equal(t, `"c"`, current.Value)
+
+	require.NoError(t, kvsClient.CompareAndDelete(ctx, "1", current))
+	_, err = kvsClient.Get("1")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+	require.ErrorIs(t, kvsClient.CompareAndDelete(ctx, "1", current), kvs.ErrConditionFailed)
+	require.NoError(t, kvsClient.SaveIfAbsent(ctx, "1", kvs.NewItem("1", "e")))
+
+	require.ErrorIs(t, kvsClient.SaveIfAbsent(ctx, "", kvs.NewItem("", "a")), kvs.ErrEmptyKey)
+}
+
+func TestClient_PatchAndGetFields(t *testing.T) {
+	ctx := t.Context()
+	client := dynamodb.NewLowLevelClient(dynamodb.NewAWSFakeClient(), "__kvs-test")
+
+	user := model.NewUserDTO("John", "Doe")
+	user.ID = 1
+	item := kvs.NewItem("1", user, time.Hour)
+	require.NoError(t, client.Save("1", item))
+
+	require.NoError(t, client.Patch(ctx, "1", map[string]any{"last_name": "Smith", "full_name": nil}))
+
+	got, err := client.Get("1")
+	require.NoError(t, err)
+	var patched model.UserDTO
+	require.NoError(t, got.TryGetValueAsObjectType(&patched))
+	require.Equal(t, model.UserDTO{FirstName: "John", LastName: "Smith", ID: 1}, patched)
+
+	fields, err := client.GetFields(ctx, "1", "first_name", "id")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"first_name":"John","id":1}`, fields.Value.(string))
+
+	require.ErrorIs(t, client.Patch(ctx, "", nil), kvs.ErrEmptyKey)
+	require.ErrorIs(t, client.Patch(ctx, "missing", map[string]any{"a": 1}), kvs.ErrKeyNotFound)
+	require.ErrorIs(t, client.Patch(ctx, "1", map[string]any{"a.b": 1}), kvs.ErrFieldPath)
+	_, err = client.GetFields(ctx, "missing", "id")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+
+	require.NoError(t, client.Save("text", kvs.NewItem("text", "plain")))
+	require.ErrorIs(t, client.Patch(ctx, "text", map[string]any{"a": 1}), kvs.ErrConvert)
+	_, err = client.GetFields(ctx, "text", "a")
+	require.ErrorIs(t, err, kvs.ErrConvert)
+}
+
+func TestLowLevelClient_BulkSave_Batches(t *testing.T) {
+	ctx := t.Context()
+	fake := dynamodb.NewAWSFakeClient()
+	llc := dynamodb.NewLowLevelClient(fake, "__kvs-test")
+
+	items := new(kvs.Items)
+	keys := make([]string, 60)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+		items.Add(kvs.NewItem(keys[i], i))
+	}
+	items.Add(kvs.NewItem("0", "last"))
+
+	// Unprocessed items are written again.
+	fake.InjectUnprocessed(30)
+	require.NoError(t, llc.BulkSaveWithContext(ctx, items))
+
+	fake.InjectUnprocessed(50)
+	result, err := llc.BulkGetWithContext(ctx, append(keys, "0", "1"))
+	require.NoError(t, err)
+	require.ElementsMatch(t, keys, itemKeys(result))
+
+	item, err := llc.GetWithContext(ctx, "0")
+	require.NoError(t, err)
+	require.Equal(t, `"last"`, item.Value)
+
+	fake.InjectUnprocessed(1000)
+	_, err = llc.BulkGetWithContext(ctx, keys)
+	require.ErrorIs(t, err, dynamodb.ErrUnprocessed)
+	require.ErrorIs(t, llc.BulkSaveWithContext(ctx, items), dynamodb.ErrUnprocessed)
+}
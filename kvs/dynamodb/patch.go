@@ -0,0 +1,369 @@
+// Package dynamodb provides AWS DynamoDB specific implementation of the KVS client.
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// maxPatchAttempts bounds the optimistic retries of Patch when the item keeps
+// changing between the read and the conditional write.
+const maxPatchAttempts = 10
+
+// Patch updates fields of the object stored under key.
+//
+// Values stored as native attributes (see StorageAttributes) are updated in place by a single
+// UpdateItem SET/REMOVE expression; field paths name attributes, as set by dynamodbav tags.
+// When secondary indexes are declared, the item is read first so that the expression also
+// sets the index attributes of the patched value, on the condition that the item is unchanged.
+// JSON values are updated with an optimistic read-modify-write: the item is read, patched with
+// kvs.PatchJSON and written back through CompareAndSwap with its TTL, retrying when it has
+// changed in between.
+// Returns kvs.ErrConditionFailed when every attempt lost a race against other writers.
+func (r *LowLevelClient) Patch(ctx context.Context, key string, updates map[string]any) error {
+	if strings.TrimSpace(key) == "" {
+		return kvs.ErrEmptyKey
+	}
+
+	if len(updates) == 0 {
+		_, err := r.GetWithContext(ctx, key)
+		return err
+	}
+
+	for range maxPatchAttempts {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("Patch: operation cancelled or timed out: %w", err)
+		}
+
+		if r.storage == StorageAttributes && len(r.indexes) == 0 {
+			// Fails unless a live item with native attributes is stored under key.
+			err := r.updateAttributes(ctx, key, updates, nil)
+			if !errors.Is(err, kvs.ErrConditionFailed) {
+				return err
+			}
+		}
+
+		current, err := r.GetWithContext(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		value, ok := current.Value.(string)
+		if !ok {
+			attributes, native := current.Value.(AttributeValues)
+			if !native || r.storage != StorageAttributes {
+				return kvs.ErrConvert
+			}
+			if len(r.indexes) > 0 {
+				err = r.updateAttributes(ctx, key, updates, attributes)
+				if !errors.Is(err, kvs.ErrConditionFailed) {
+					return err
+				}
+			}
+			continue
+		}
+
+		patched, err := kvs.PatchJSON(value, updates)
+		if err != nil {
+			return err
+		}
+
+		err = r.compareAndSwapValue(ctx, key, current, patched)
+		if !errors.Is(err, kvs.ErrConditionFailed) {
+			return err
+		}
+	}
+
+	return kvs.ErrConditionFailed
+}
+
+// compareAndSwapValue replaces the value stored under key with the already encoded value,
+// keeping the TTL of current, only if the stored value is still the value of current.
+func (r *LowLevelClient) compareAndSwapValue(ctx context.Context, key string, current *kvs.Item, value string) error {
+	condition, err := r.newValueCondition(current)
+	if err != nil {
+		return err
+	}
+
+	attributes, err := r.newItem(&kvs.Item{Key: key, TTL: current.TTL}, []byte(value))
+	if err != nil {
+		return err
+	}
+	r.projectIndexes(attributes, json.RawMessage(value))
+
+	_, err = r.AWSClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                 r.getTableName(),
+		Item:                      attributes,
+		ConditionExpression:       condition.expression,
+		ExpressionAttributeNames:  condition.names,
+		ExpressionAttributeValues: condition.values,
+	})
+
+	return conditionError(err)
+}
+
+// updateAttributes applies updates to the native attributes of the live item stored under key
+// with an UpdateItem SET/REMOVE expression. When current is not nil, the update also sets the
+// index attributes of current patched with updates, and requires the item to still hold current.
+// Returns kvs.ErrConditionFailed if no such item is stored under key.
+func (r *LowLevelClient) updateAttributes(
+	ctx context.Context,
+	key string,
+	updates map[string]any,
+	current AttributeValues,
+) error {
+	primaryKey, err := r.primaryKey(key)
+	if err != nil {
+		return err
+	}
+
+	expression := r.newFieldExpression()
+	var sets, removes []string
+	for _, field := range slices.Sorted(maps.Keys(updates)) {
+		path, err := expression.path(field)
+		if err != nil {
+			return err
+		}
+
+		if updates[field] == nil {
+			removes = append(removes, path)
+			continue
+		}
+
+		value, err := attributevalue.Marshal(updates[field])
+		if err != nil {
+			return kvs.ErrMarshal
+		}
+		placeholder := ":v" + strconv.Itoa(len(expression.values))
+		expression.values[placeholder] = value
+		sets = append(sets, path+" = "+placeholder)
+	}
+
+	condition := aws.String(
+		"attribute_exists(#key) AND attribute_not_exists(#value) AND (attribute_not_exists(#ttl) OR #ttl > :now)",
+	)
+	if current != nil {
+		indexSets, indexRemoves, err := r.indexUpdates(expression, current, updates)
+		if err != nil {
+			return err
+		}
+		sets, removes = append(sets, indexSets...), append(removes, indexRemoves...)
+
+		unchanged := r.newAttributesCondition(current)
+		maps.Copy(expression.names, unchanged.names)
+		maps.Copy(expression.values, unchanged.values)
+		condition = unchanged.expression
+	}
+
+	var clauses []string
+	if len(sets) > 0 {
+		clauses = append(clauses, "SET "+strings.Join(sets, ", "))
+	}
+	if len(removes) > 0 {
+		clauses = append(clauses, "REMOVE "+strings.Join(removes, ", "))
+	}
+
+	expression.names["#key"] = r.names.Key
+	expression.names["#value"] = r.names.Value
+	expression.names["#ttl"] = r.names.TTL
+	expression.values[":now"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(r.now().Unix(), 10)}
+
+	_, err = r.AWSClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 r.getTableName(),
+		Key:                       primaryKey,
+		UpdateExpression:          aws.String(strings.Join(clauses, " ")),
+		ConditionExpression:       condition,
+		ExpressionAttributeNames:  expression.names,
+		ExpressionAttributeValues: expression.values,
+	})
+
+	return fieldPathError(conditionError(err))
+}
+
+// indexUpdates returns the SET and REMOVE clauses, added to expression, that project the
+// secondary indexes on current patched with updates. Index attributes named by an update
+// are left to it.
+func (r *LowLevelClient) indexUpdates(
+	expression *fieldExpression,
+	current AttributeValues,
+	updates map[string]any,
+) (sets, removes []string, err error) {
+	encoded, err := marshalJSON(current)
+	if err != nil {
+		return nil, nil, kvs.ErrConvert
+	}
+	patched, err := kvs.PatchJSON(string(encoded), updates)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updated := make(map[string]bool, len(updates))
+	for field := range updates {
+		segments, _ := kvs.SplitFieldPath(field)
+		if len(segments) > 0 {
+			updated[segments[0]] = true
+		}
+	}
+
+	for i, index := range r.indexes {
+		if updated[index.Attribute] {
+			continue
+		}
+		name := "#i" + strconv.Itoa(i)
+		expression.names[name] = index.Attribute
+		key := index.key(json.RawMessage(patched))
+		if key == "" {
+			removes = append(removes, name)
+			continue
+		}
+		placeholder := ":i" + strconv.Itoa(i)
+		expression.values[placeholder] = &types.AttributeValueMemberS{Value: key}
+		sets = append(sets, name+" = "+placeholder)
+	}
+	return sets, removes, nil
+}
+
+// fieldExpression collects the attribute names and values of an expression on field paths.
+type fieldExpression struct {
+	names    map[string]string
+	values   map[string]types.AttributeValue
+	reserved []string
+}
+
+func (r *LowLevelClient) newFieldExpression() *fieldExpression {
+	return &fieldExpression{
+		names:    map[string]string{},
+		values:   map[string]types.AttributeValue{},
+		reserved: r.names.reserved(),
+	}
+}
+
+// path returns the document path of a field path, made of name placeholders.
+// Returns kvs.ErrFieldPath if the path is invalid or starts with a reserved attribute.
+func (r *fieldExpression) path(field string) (string, error) {
+	segments, err := kvs.SplitFieldPath(field)
+	if err != nil {
+		return "", err
+	}
+	if slices.Contains(r.reserved, segments[0]) {
+		return "", kvs.ErrFieldPath
+	}
+
+	placeholders := make([]string, len(segments))
+	for i, segment := range segments {
+		placeholders[i] = "#f" + strconv.Itoa(len(r.names))
+		r.names[placeholders[i]] = segment
+	}
+	return strings.Join(placeholders, "."), nil
+}
+
+// fieldPathError maps the rejection of an invalid document path by DynamoDB to
+// kvs.ErrFieldPath and returns any other error unchanged.
+func fieldPathError(err error) error {
+	var apiError smithy.APIError
+	if errors.As(err, &apiError) && apiError.ErrorCode() == "ValidationException" &&
+		strings.Contains(apiError.ErrorMessage(), "document path") {
+		return kvs.ErrFieldPath
+	}
+	return err
+}
+
+// GetFields retrieves the object stored under key restricted to fields. Values stored as
+// native attributes are read with a ProjectionExpression and returned as AttributeValues;
+// JSON values are projected with kvs.ProjectJSON.
+func (r *LowLevelClient) GetFields(ctx context.Context, key string, fields ...string) (*kvs.Item, error) {
+	item, err := r.getFields(ctx, key, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, native := item.Value.(AttributeValues); native {
+		return item, nil
+	}
+
+	value, ok := item.Value.(string)
+	if !ok {
+		return nil, kvs.ErrConvert
+	}
+
+	projected, err := kvs.ProjectJSON(value, fields...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := *item
+	result.Value = projected
+	return &result, nil
+}
+
+// getFields reads the item stored under key. In StorageAttributes mode, only the requested
+// fields are read, along with the value attribute of JSON values.
+func (r *LowLevelClient) getFields(ctx context.Context, key string, fields []string) (*kvs.Item, error) {
+	if r.storage != StorageAttributes {
+		return r.GetWithContext(ctx, key)
+	}
+
+	if strings.TrimSpace(key) == "" {
+		return nil, kvs.ErrEmptyKey
+	}
+
+	primaryKey, err := r.primaryKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	expression := r.newFieldExpression()
+	projection := []string{"#key", "#ttl", "#value"}
+	for _, field := range fields {
+		path, err := expression.path(field)
+		if err != nil {
+			return nil, err
+		}
+		projection = append(projection, path)
+	}
+	expression.names["#key"] = r.names.Key
+	expression.names["#ttl"] = r.names.TTL
+	expression.names["#value"] = r.names.Value
+	if r.names.SortKey != "" {
+		expression.names["#sort"] = r.names.SortKey
+		projection = append(projection, "#sort")
+	}
+
+	output, err := r.AWSClient.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:                r.getTableName(),
+		Key:                      primaryKey,
+		ProjectionExpression:     aws.String(strings.Join(projection, ", ")),
+		ExpressionAttributeNames: expression.names,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if output.Item == nil {
+		return nil, kvs.ErrKeyNotFound
+	}
+
+	item, err := r.unmarshalItem(output.Item)
+	if err != nil {
+		return nil, err
+	}
+	if r.expired(item) {
+		r.evict(item)
+		return nil, kvs.ErrKeyNotFound
+	}
+
+	return item, nil
+}
@@ -0,0 +1,218 @@
+// Package dynamodb provides AWS DynamoDB specific implementation of the KVS client.
+package dynamodb
+
+import (
+	"context"
+	"encoding/base64"
+	"iter"
+	"math"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// sortExpressions maps the sort key operators to their key condition expressions.
+var sortExpressions = map[kvs.SortOperator]string{
+	kvs.SortEqual:          "#sort = :sort",
+	kvs.SortLessThan:       "#sort < :sort",
+	kvs.SortLessOrEqual:    "#sort <= :sort",
+	kvs.SortGreaterThan:    "#sort > :sort",
+	kvs.SortGreaterOrEqual: "#sort >= :sort",
+	kvs.SortBetween:        "#sort BETWEEN :sort AND :to",
+	kvs.SortBeginsWith:     "begins_with(#sort, :sort)",
+}
+
+// Query enumerates the items of partition whose sort key matches condition, in sort key
+// order, using the provided context. Pages of opts.PageSize items are requested lazily
+// while the sequence is consumed, until opts.Limit items have been yielded. Expired items
+// not yet purged by DynamoDB are skipped, like in Get.
+// Returns ErrKeySchema for a sort key condition on a table with a simple primary key,
+// and kvs.ErrInvalidToken if opts.StartToken was not returned by QueryPage.
+func (r *LowLevelClient) Query(
+	ctx context.Context,
+	partition string,
+	condition kvs.SortCondition,
+	opts kvs.QueryOptions,
+) iter.Seq2[*kvs.Item, error] {
+	return func(yield func(*kvs.Item, error) bool) {
+		input, err := r.newQueryInput(partition, condition, opts)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		r.query(ctx, input, opts.Limit, yield)
+	}
+}
+
+// query pages through the results of input, following LastEvaluatedKey, and hands every
+// live item to yield until limit items have been yielded (no limit when zero). Expired
+// items not yet purged by DynamoDB are skipped. It stops as soon as yield returns false.
+func (r *LowLevelClient) query(
+	ctx context.Context,
+	input *dynamodb.QueryInput,
+	limit int,
+	yield func(*kvs.Item, error) bool,
+) {
+	pageSize := int(aws.ToInt32(input.Limit))
+	remaining := limit
+	for {
+		if limit > 0 {
+			input.Limit = aws.Int32(int32(min(pageSize, remaining)))
+		}
+
+		output, err := r.AWSClient.Query(ctx, input)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		for _, attributes := range output.Items {
+			item, err := r.unmarshalItem(attributes)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if r.expired(item) {
+				r.evict(item)
+				continue
+			}
+			if !yield(item, nil) {
+				return
+			}
+			remaining--
+			if limit > 0 && remaining == 0 {
+				return
+			}
+		}
+
+		if len(output.LastEvaluatedKey) == 0 {
+			return
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
+// QueryPage returns up to opts.PageSize items of Query, and the token resuming the query
+// after them, empty on the last page. A page may hold fewer items when DynamoDB stops at
+// its 1 MB page limit or when some of its items have expired.
+func (r *LowLevelClient) QueryPage(
+	ctx context.Context,
+	partition string,
+	condition kvs.SortCondition,
+	opts kvs.QueryOptions,
+) (*kvs.Items, string, error) {
+	input, err := r.newQueryInput(partition, condition, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	output, err := r.AWSClient.Query(ctx, input)
+	if err != nil {
+		return nil, "", err
+	}
+
+	items := new(kvs.Items)
+	for _, attributes := range output.Items {
+		item, err := r.unmarshalItem(attributes)
+		if err != nil {
+			return nil, "", err
+		}
+		if r.expired(item) {
+			r.evict(item)
+			continue
+		}
+		items.Add(item)
+	}
+
+	token, err := encodeToken(output.LastEvaluatedKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return items, token, nil
+}
+
+// newQueryInput builds the QueryInput of the first page of a query.
+func (r *LowLevelClient) newQueryInput(
+	partition string,
+	condition kvs.SortCondition,
+	opts kvs.QueryOptions,
+) (*dynamodb.QueryInput, error) {
+	if partition == "" {
+		return nil, kvs.ErrEmptyKey
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:              r.getTableName(),
+		KeyConditionExpression: aws.String("#key = :partition"),
+		ExpressionAttributeNames: map[string]string{
+			"#key": r.names.Key,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":partition": &types.AttributeValueMemberS{Value: partition},
+		},
+		ScanIndexForward: aws.Bool(!opts.Descending),
+		Limit:            aws.Int32(int32(min(opts.PageSizeOrDefault(), math.MaxInt32))),
+	}
+
+	if condition.Operator != kvs.SortAny {
+		expression, found := sortExpressions[condition.Operator]
+		if !found {
+			return nil, kvs.ErrUnsupported
+		}
+		if r.names.SortKey == "" {
+			return nil, ErrKeySchema
+		}
+
+		input.KeyConditionExpression = aws.String(*input.KeyConditionExpression + " AND " + expression)
+		input.ExpressionAttributeNames["#sort"] = r.names.SortKey
+		input.ExpressionAttributeValues[":sort"] = &types.AttributeValueMemberS{Value: condition.Value}
+		if condition.Operator == kvs.SortBetween {
+			input.ExpressionAttributeValues[":to"] = &types.AttributeValueMemberS{Value: condition.To}
+		}
+	}
+
+	if opts.StartToken != "" {
+		startKey, err := decodeToken(opts.StartToken)
+		if err != nil {
+			return nil, err
+		}
+		input.ExclusiveStartKey = startKey
+	}
+
+	return input, nil
+}
+
+// encodeToken encodes a LastEvaluatedKey as an opaque pagination token, empty when
+// there is no next page.
+func encodeToken(lastEvaluatedKey map[string]types.AttributeValue) (string, error) {
+	if len(lastEvaluatedKey) == 0 {
+		return "", nil
+	}
+
+	bytes, err := encodeItem(lastEvaluatedKey)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// decodeToken decodes a token returned by encodeToken.
+func decodeToken(token string) (map[string]types.AttributeValue, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, kvs.ErrInvalidToken
+	}
+
+	startKey, err := decodeItem(bytes)
+	if err != nil || len(startKey) == 0 {
+		return nil, kvs.ErrInvalidToken
+	}
+
+	return startKey, nil
+}
@@ -0,0 +1,157 @@
+package dynamodb_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	"github.com/arielsrv/go-kvs-client/kvs/dynamodb"
+)
+
+type order struct {
+	ID    string `json:"id"`
+	Total int    `json:"total"`
+}
+
+func newQueryClient(t *testing.T) *dynamodb.LowLevelClient {
+	t.Helper()
+
+	client := dynamodb.NewBuilder(
+		dynamodb.WithContainerName("__kvs-test"),
+		dynamodb.WithAttributeNames(dynamodb.AttributeNames{Key: "pk", SortKey: "sk"}),
+	).FakeBuild()
+
+	items := new(kvs.Items)
+	for _, id := range []string{"ORDER#1", "ORDER#2", "ORDER#3", "ORDER#4", "PROFILE"} {
+		items.Add(kvs.NewItem("USER#42|"+id, order{ID: id}))
+	}
+	items.Add(kvs.NewItem("USER#7|ORDER#1", order{ID: "other"}))
+	require.NoError(t, client.BulkSave(items))
+
+	return client
+}
+
+func queryKeys(
+	t *testing.T,
+	client *dynamodb.LowLevelClient,
+	condition kvs.SortCondition,
+	opts kvs.QueryOptions,
+) []string {
+	t.Helper()
+
+	var keys []string
+	for item, err := range client.Query(t.Context(), "USER#42", condition, opts) {
+		require.NoError(t, err)
+		keys = append(keys, item.Key)
+	}
+	return keys
+}
+
+func TestLowLevelClient_Query(t *testing.T) {
+	client := newQueryClient(t)
+
+	tests := []struct {
+		name      string
+		condition kvs.SortCondition
+		opts      kvs.QueryOptions
+		expected  []string
+	}{
+		{
+			name:     "whole partition",
+			expected: []string{"USER#42|ORDER#1", "USER#42|ORDER#2", "USER#42|ORDER#3", "USER#42|ORDER#4", "USER#42|PROFILE"},
+		},
+		{
+			name:      "begins with",
+			condition: kvs.SortKeyBeginsWith("ORDER#"),
+			opts:      kvs.QueryOptions{PageSize: 1},
+			expected:  []string{"USER#42|ORDER#1", "USER#42|ORDER#2", "USER#42|ORDER#3", "USER#42|ORDER#4"},
+		},
+		{
+			name:      "between",
+			condition: kvs.SortKeyBetween("ORDER#2", "ORDER#3"),
+			expected:  []string{"USER#42|ORDER#2", "USER#42|ORDER#3"},
+		},
+		{
+			name:      "equal",
+			condition: kvs.SortKeyEquals("PROFILE"),
+			expected:  []string{"USER#42|PROFILE"},
+		},
+		{
+			name:      "less than",
+			condition: kvs.SortKeyLessThan("ORDER#2"),
+			expected:  []string{"USER#42|ORDER#1"},
+		},
+		{
+			name:      "greater or equal",
+			condition: kvs.SortKeyGreaterOrEqual("ORDER#4"),
+			expected:  []string{"USER#42|ORDER#4", "USER#42|PROFILE"},
+		},
+		{
+			name:      "descending with limit",
+			condition: kvs.SortKeyBeginsWith("ORDER#"),
+			opts:      kvs.QueryOptions{Descending: true, Limit: 3, PageSize: 2},
+			expected:  []string{"USER#42|ORDER#4", "USER#42|ORDER#3", "USER#42|ORDER#2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, queryKeys(t, client, tt.condition, tt.opts))
+		})
+	}
+}
+
+func TestLowLevelClient_QueryPage(t *testing.T) {
+	ctx := t.Context()
+	client := newQueryClient(t)
+	opts := kvs.QueryOptions{PageSize: 3, Descending: true}
+
+	page, token, err := client.QueryPage(ctx, "USER#42", kvs.SortKeyBeginsWith("ORDER#"), opts)
+	require.NoError(t, err)
+	require.Equal(t, 3, page.Len())
+	require.NotEmpty(t, token)
+
+	opts.StartToken = token
+	page, token, err = client.QueryPage(ctx, "USER#42", kvs.SortKeyBeginsWith("ORDER#"), opts)
+	require.NoError(t, err)
+	require.Equal(t, 1, page.Len())
+	require.Empty(t, token)
+	for item := range page.All() {
+		require.Equal(t, "USER#42|ORDER#1", item.Key)
+	}
+
+	_, _, err = client.QueryPage(ctx, "USER#42", kvs.SortCondition{}, kvs.QueryOptions{StartToken: "!"})
+	require.ErrorIs(t, err, kvs.ErrInvalidToken)
+	_, _, err = client.QueryPage(ctx, "", kvs.SortCondition{}, kvs.QueryOptions{})
+	require.ErrorIs(t, err, kvs.ErrEmptyKey)
+}
+
+func TestLowLevelClient_Query_SimpleKey(t *testing.T) {
+	client := dynamodb.NewBuilder(dynamodb.WithContainerName("__kvs-test")).FakeBuild()
+	require.NoError(t, client.Save("USER#42", kvs.NewItem("USER#42", order{ID: "1"})))
+
+	require.Equal(t, []string{"USER#42"}, queryKeys(t, client, kvs.SortCondition{}, kvs.QueryOptions{}))
+
+	for _, err := range client.Query(t.Context(), "USER#42", kvs.SortKeyEquals("x"), kvs.QueryOptions{}) {
+		require.ErrorIs(t, err, dynamodb.ErrKeySchema)
+	}
+}
+
+func TestKVSClient_Query(t *testing.T) {
+	ctx := t.Context()
+	client := kvs.NewKVSClient[order](newQueryClient(t))
+
+	var ids []string
+	for value, err := range client.Query(ctx, "USER#42", kvs.SortKeyBeginsWith("ORDER#"), kvs.QueryOptions{Limit: 2}) {
+		require.NoError(t, err)
+		ids = append(ids, value.ID)
+	}
+	require.Equal(t, []string{"ORDER#1", "ORDER#2"}, ids)
+
+	values, token, err := client.QueryPage(ctx, "USER#42", kvs.SortKeyGreaterThan("ORDER#3"),
+		kvs.QueryOptions{PageSize: 5})
+	require.NoError(t, err)
+	require.Empty(t, token)
+	require.Equal(t, []order{{ID: "ORDER#4"}, {ID: "PROFILE"}}, values)
+}
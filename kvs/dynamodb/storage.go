@@ -0,0 +1,185 @@
+// Package dynamodb provides AWS DynamoDB specific implementation of the KVS client.
+package dynamodb
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// ErrReservedAttribute is returned when a value stored with StorageAttributes has
+// an attribute named after the key, value or TTL attributes (see AttributeNames).
+const ErrReservedAttribute = kvs.KeyValueError("[kvs]: value uses a reserved attribute name")
+
+// CodecAttributes is the kvs.Item.Codec of the values stored as native attributes,
+// whose kvs.Item.Value is an AttributeValues.
+const CodecAttributes = "dynamodb"
+
+// StorageMode selects how LowLevelClient stores item values in DynamoDB.
+type StorageMode int
+
+// Storage modes.
+const (
+	// StorageString stores each value as its JSON representation in the value attribute.
+	StorageString StorageMode = iota
+	// StorageAttributes stores the values that marshal to a map (structs, maps) with
+	// attributevalue.MarshalMap, as native attributes next to the key and TTL attributes,
+	// so that they can be used in filters, projections and indexes. Other values
+	// (strings, numbers, raw JSON, ...) are stored as in StorageString, and the items
+	// written by StorageString are read transparently.
+	StorageAttributes
+)
+
+// AttributeValues holds the native attributes of a value stored with StorageAttributes.
+// It implements kvs.ValueDecoder, so that kvs.Item.TryGetValueAsObjectType, and thus
+// kvs.KVSClient, decode it with attributevalue.UnmarshalMap.
+type AttributeValues map[string]types.AttributeValue
+
+// DecodeValue decodes the attributes into out with attributevalue.UnmarshalMap.
+func (r AttributeValues) DecodeValue(out any) error {
+	return attributevalue.UnmarshalMap(r, out)
+}
+
+// marshalItem builds the DynamoDB item of item in the storage mode of the client.
+// The item is stored under item.Key.
+func (r *LowLevelClient) marshalItem(item *kvs.Item) (map[string]types.AttributeValue, error) {
+	if r.storage == StorageAttributes {
+		attributes, err := r.marshalAttributes(item.Value)
+		if err != nil {
+			return nil, err
+		}
+		if attributes != nil {
+			primaryKey, err := r.primaryKey(item.Key)
+			if err != nil {
+				return nil, err
+			}
+			maps.Copy(attributes, primaryKey)
+			r.projectIndexes(attributes, item.Value)
+			if item.TTL > 0 {
+				attributes[r.names.TTL] = &types.AttributeValueMemberN{Value: strconv.FormatInt(item.TTL, 10)}
+			}
+			return attributes, nil
+		}
+	}
+
+	bytes, err := marshalJSON(item.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err := r.newItem(item, bytes)
+	if err != nil {
+		return nil, err
+	}
+	r.projectIndexes(attributes, item.Value)
+
+	return attributes, nil
+}
+
+// marshalAttributes returns the native attributes of a value, or nil if it does not
+// marshal to a map.
+func (r *LowLevelClient) marshalAttributes(value any) (map[string]types.AttributeValue, error) {
+	if values, ok := value.(AttributeValues); ok {
+		return r.checkReserved(maps.Clone(values))
+	}
+
+	attribute, err := attributevalue.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	member, ok := attribute.(*types.AttributeValueMemberM)
+	if !ok {
+		return nil, nil
+	}
+
+	return r.checkReserved(member.Value)
+}
+
+// checkReserved returns ErrReservedAttribute if attributes use the name of the key,
+// value or TTL attributes.
+func (r *LowLevelClient) checkReserved(
+	attributes map[string]types.AttributeValue,
+) (map[string]types.AttributeValue, error) {
+	for name := range attributes {
+		if r.isReserved(name) {
+			return nil, ErrReservedAttribute
+		}
+	}
+	return attributes, nil
+}
+
+// marshalJSON encodes a value for the value attribute. Native attributes read from
+// another item are encoded as the JSON object they represent.
+func marshalJSON(value any) ([]byte, error) {
+	if values, ok := value.(AttributeValues); ok {
+		var object map[string]any
+		if err := attributevalue.UnmarshalMap(values, &object); err != nil {
+			return nil, err
+		}
+		value = object
+	}
+
+	return json.Marshal(value)
+}
+
+// unmarshalItem converts a DynamoDB item to a kvs.Item. Items holding a value
+// attribute carry its JSON representation; the others carry their native attributes
+// as AttributeValues, with CodecAttributes.
+func (r *LowLevelClient) unmarshalItem(attributes map[string]types.AttributeValue) (*kvs.Item, error) {
+	result := &kvs.Item{Key: r.itemKey(attributes), TTL: ttlAttribute(attributes[r.names.TTL])}
+	if value, found := attributes[r.names.Value]; found {
+		var encoded string
+		if err := attributevalue.Unmarshal(value, &encoded); err != nil {
+			return nil, err
+		}
+		result.Value = encoded
+		return result, nil
+	}
+
+	values := AttributeValues{}
+	for name, value := range attributes {
+		if !r.isKeyAttribute(name) && name != r.names.TTL {
+			values[name] = value
+		}
+	}
+	result.Value = values
+	result.Codec = CodecAttributes
+
+	return result, nil
+}
+
+// newAttributesCondition returns the condition matching a live item whose native
+// attributes equal expected. Only the attributes of expected are compared.
+func (r *LowLevelClient) newAttributesCondition(expected AttributeValues) *valueCondition {
+	condition := &valueCondition{
+		names: map[string]string{
+			"#key":   r.names.Key,
+			"#value": r.names.Value,
+			"#ttl":   r.names.TTL,
+		},
+		values: map[string]types.AttributeValue{
+			":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(r.now().Unix(), 10)},
+		},
+	}
+
+	clauses := []string{"attribute_exists(#key)", "attribute_not_exists(#value)"}
+	for i, name := range slices.Sorted(maps.Keys(expected)) {
+		placeholder := "a" + strconv.Itoa(i)
+		condition.names["#"+placeholder] = name
+		condition.values[":"+placeholder] = expected[name]
+		clauses = append(clauses, "#"+placeholder+" = :"+placeholder)
+	}
+	clauses = append(clauses, "(attribute_not_exists(#ttl) OR #ttl > :now)")
+	condition.expression = aws.String(strings.Join(clauses, " AND "))
+
+	return condition
+}
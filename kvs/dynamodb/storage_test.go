@@ -0,0 +1,197 @@
+package dynamodb_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsdynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	"github.com/arielsrv/go-kvs-client/kvs/dynamodb"
+)
+
+type address struct {
+	City string `dynamodbav:"city"`
+	Zip  string `dynamodbav:"zip,omitempty"`
+}
+
+type profile struct {
+	Name    string  `dynamodbav:"name"`
+	Address address `dynamodbav:"address"`
+	Tags    []string
+	Age     int `dynamodbav:"age"`
+}
+
+// newAttributesClient returns a client in StorageAttributes mode and a client in
+// StorageString mode sharing its table.
+func newAttributesClient(t *testing.T) (*dynamodb.LowLevelClient, *dynamodb.LowLevelClient) {
+	t.Helper()
+
+	attributes := dynamodb.NewBuilder(
+		dynamodb.WithContainerName("__kvs-test"),
+		dynamodb.WithStorageMode(dynamodb.StorageAttributes),
+	).FakeBuild()
+	require.Equal(t, dynamodb.StorageAttributes, attributes.StorageMode())
+
+	return attributes, dynamodb.NewLowLevelClient(attributes.AWSClient, "__kvs-test")
+}
+
+func getRawItem(t *testing.T, client *dynamodb.LowLevelClient, key string) map[string]types.AttributeValue {
+	t.Helper()
+
+	output, err := client.AWSClient.GetItem(t.Context(), &awsdynamodb.GetItemInput{
+		TableName: aws.String(client.TableName()),
+		Key:       map[string]types.AttributeValue{dynamodb.KeyName: &types.AttributeValueMemberS{Value: key}},
+	})
+	require.NoError(t, err)
+	return output.Item
+}
+
+func TestStorageAttributes_SaveAndGet(t *testing.T) {
+	llc, legacy := newAttributesClient(t)
+	client := kvs.NewKVSClient[profile](llc)
+	john := &profile{Name: "John", Age: 42, Address: address{City: "Paris"}, Tags: []string{"a"}}
+
+	require.NoError(t, client.Save("1", john))
+
+	raw := getRawItem(t, llc, "1")
+	require.Equal(t, &types.AttributeValueMemberS{Value: "1"}, raw[dynamodb.KeyName])
+	require.Equal(t, &types.AttributeValueMemberS{Value: "John"}, raw["name"])
+	require.Equal(t, &types.AttributeValueMemberN{Value: "42"}, raw["age"])
+	require.NotContains(t, raw, dynamodb.ValueName)
+
+	got, err := client.Get("1")
+	require.NoError(t, err)
+	require.Equal(t, john, got)
+
+	item, err := llc.Get("1")
+	require.NoError(t, err)
+	require.Equal(t, dynamodb.CodecAttributes, item.Codec)
+	require.IsType(t, dynamodb.AttributeValues{}, item.Value)
+
+	// Legacy items written as JSON strings are still read.
+	require.NoError(t, legacy.Save("2", kvs.NewItem("2", profile{Name: "Jane", Age: 7})))
+	got, err = client.Get("2")
+	require.NoError(t, err)
+	require.Equal(t, &profile{Name: "Jane", Age: 7}, got)
+
+	values, err := client.BulkGet([]string{"1", "2", "3"})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []profile{*john, {Name: "Jane", Age: 7}}, values)
+
+	var names []string
+	for value, err := range client.All(t.Context()) {
+		require.NoError(t, err)
+		names = append(names, value.Name)
+	}
+	slices.Sort(names)
+	require.Equal(t, []string{"Jane", "John"}, names)
+
+	// A string-mode client reads native items as attributes too.
+	got, err = kvs.NewKVSClient[profile](legacy).Get("1")
+	require.NoError(t, err)
+	require.Equal(t, john, got)
+}
+
+func TestStorageAttributes_NonMapValues(t *testing.T) {
+	ctx := t.Context()
+	llc, _ := newAttributesClient(t)
+
+	require.NoError(t, llc.SaveWithContext(ctx, "text", kvs.NewItem("text", "plain")))
+	require.Contains(t, getRawItem(t, llc, "text"), dynamodb.ValueName)
+
+	item, err := llc.Get("text")
+	require.NoError(t, err)
+	require.JSONEq(t, `"plain"`, item.Value.(string))
+
+	count, err := llc.Increment(ctx, "counter", 2, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, count)
+	item, err = llc.Get("counter")
+	require.NoError(t, err)
+	require.Equal(t, "2", item.Value)
+
+	err = llc.Save("reserved", kvs.NewItem("reserved", map[string]any{"ttl": 1}))
+	require.ErrorIs(t, err, dynamodb.ErrReservedAttribute)
+}
+
+func TestStorageAttributes_ConditionalWrites(t *testing.T) {
+	ctx := t.Context()
+	llc, _ := newAttributesClient(t)
+
+	require.NoError(t, llc.SaveIfAbsent(ctx, "1", kvs.NewItem("1", profile{Name: "John"})))
+	require.ErrorIs(t, llc.SaveIfAbsent(ctx, "1", kvs.NewItem("1", profile{Name: "Jane"})), kvs.ErrConditionFailed)
+
+	current, err := llc.Get("1")
+	require.NoError(t, err)
+	require.NoError(t, llc.CompareAndSwap(ctx, "1", current, kvs.NewItem("1", profile{Name: "Jane"})))
+	require.ErrorIs(t, llc.CompareAndSwap(ctx, "1", current, kvs.NewItem("1", profile{Name: "Joe"})),
+		kvs.ErrConditionFailed)
+	require.ErrorIs(t, llc.CompareAndDelete(ctx, "1", current), kvs.ErrConditionFailed)
+
+	current, err = llc.Get("1")
+	require.NoError(t, err)
+	require.NoError(t, llc.CompareAndDelete(ctx, "1", current))
+	_, err = llc.Get("1")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+}
+
+func TestStorageAttributes_PatchAndGetFields(t *testing.T) {
+	ctx := t.Context()
+	llc, legacy := newAttributesClient(t)
+	client := kvs.NewKVSClient[profile](llc)
+
+	require.NoError(t, client.Save("1", &profile{Name: "John", Age: 42, Address: address{City: "Paris", Zip: "75001"}}))
+
+	err := llc.Patch(ctx, "1", map[string]any{"age": 43, "address.city": "Lyon", "address.zip": nil})
+	require.NoError(t, err)
+
+	got, err := client.Get("1")
+	require.NoError(t, err)
+	require.Equal(t, &profile{Name: "John", Age: 43, Address: address{City: "Lyon"}}, got)
+
+	fields, err := llc.GetFields(ctx, "1", "name", "address.city", "missing")
+	require.NoError(t, err)
+	require.Equal(t, dynamodb.AttributeValues{
+		"name": &types.AttributeValueMemberS{Value: "John"},
+		"address": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"city": &types.AttributeValueMemberS{Value: "Lyon"},
+		}},
+	}, fields.Value)
+	var projected profile
+	require.NoError(t, fields.TryGetValueAsObjectType(&projected))
+	require.Equal(t, profile{Name: "John", Address: address{City: "Lyon"}}, projected)
+
+	require.ErrorIs(t, llc.Patch(ctx, "1", map[string]any{"other.city": "x"}), kvs.ErrFieldPath)
+	require.ErrorIs(t, llc.Patch(ctx, "1", map[string]any{"ttl": 1}), kvs.ErrFieldPath)
+	require.ErrorIs(t, llc.Patch(ctx, "missing", map[string]any{"age": 1}), kvs.ErrKeyNotFound)
+	require.NoError(t, llc.Patch(ctx, "1", nil))
+	_, err = llc.GetFields(ctx, "missing", "name")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+
+	// Legacy JSON values are patched and projected as JSON.
+	require.NoError(t, legacy.Save("2", kvs.NewItem("2", map[string]any{"name": "Jane", "age": 7})))
+	require.NoError(t, llc.Patch(ctx, "2", map[string]any{"age": 8}))
+	fields, err = llc.GetFields(ctx, "2", "age")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"age":8}`, fields.Value.(string))
+}
+
+func TestAttributeValues_DecodeValue(t *testing.T) {
+	values := dynamodb.AttributeValues{"name": &types.AttributeValueMemberS{Value: "John"}}
+
+	var out profile
+	require.NoError(t, values.DecodeValue(&out))
+	require.Equal(t, "John", out.Name)
+
+	// Values read from native items are saved back as JSON by string-mode clients.
+	client := dynamodb.NewLowLevelClient(dynamodb.NewAWSFakeClient(), "__kvs-test")
+	require.NoError(t, client.SaveWithContext(context.Background(), "1", kvs.NewItem("1", values)))
+	item, err := client.Get("1")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"John"}`, item.Value.(string))
+}
@@ -0,0 +1,302 @@
+// Package dynamodb provides AWS DynamoDB specific implementation of the KVS client.
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// ErrTableMismatch is returned by EnsureTable when an existing table does not match the
+// configuration of the Builder. The error details every mismatch.
+const ErrTableMismatch = kvs.KeyValueError("[kvs]: table does not match the client configuration")
+
+// ErrMissingThroughput is returned by EnsureTable when the PROVISIONED billing mode is set
+// without read and write capacity units: use WithProvisionedThroughput instead of WithBillingMode.
+const ErrMissingThroughput = kvs.KeyValueError("[kvs]: PROVISIONED billing mode requires WithProvisionedThroughput")
+
+// DefaultTableWaitTimeout is the maximum time EnsureTable waits for a table to become ACTIVE,
+// unless the context has an earlier deadline.
+const DefaultTableWaitTimeout = 5 * time.Minute
+
+// TableClient is the interface of the DynamoDB control plane operations used by EnsureTable.
+// It is implemented by *dynamodb.Client and by AWSFakeClient.
+type TableClient interface {
+	// DescribeTable returns the description of a table.
+	DescribeTable(
+		ctx context.Context,
+		params *dynamodb.DescribeTableInput,
+		optFns ...func(*dynamodb.Options),
+	) (*dynamodb.DescribeTableOutput, error)
+
+	// CreateTable creates a table.
+	CreateTable(
+		ctx context.Context,
+		params *dynamodb.CreateTableInput,
+		optFns ...func(*dynamodb.Options),
+	) (*dynamodb.CreateTableOutput, error)
+
+	// DescribeTimeToLive returns the TTL settings of a table.
+	DescribeTimeToLive(
+		ctx context.Context,
+		params *dynamodb.DescribeTimeToLiveInput,
+		optFns ...func(*dynamodb.Options),
+	) (*dynamodb.DescribeTimeToLiveOutput, error)
+
+	// UpdateTimeToLive enables or disables TTL on a table.
+	UpdateTimeToLive(
+		ctx context.Context,
+		params *dynamodb.UpdateTimeToLiveInput,
+		optFns ...func(*dynamodb.Options),
+	) (*dynamodb.UpdateTimeToLiveOutput, error)
+}
+
+// EnsureTable makes sure that the table of the container exists and matches the configuration
+// of the Builder, using a DynamoDB client built from awsConfig as in Build.
+// See EnsureTableWithClient.
+func (r *Builder) EnsureTable(ctx context.Context, awsConfig aws.Config) error {
+	return r.EnsureTableWithClient(ctx, r.newClient(awsConfig))
+}
+
+// EnsureTableWithClient makes sure that the table of the container exists and matches the
+// configuration of the Builder. A missing table is created with the key schema of the attribute
+// names, the declared secondary indexes (projecting all attributes) and the billing mode (see
+// WithBillingMode). EnsureTableWithClient then waits for the table to be ACTIVE and enables TTL
+// on the TTL attribute. The key schema, secondary indexes and TTL attribute of an existing table
+// are validated instead: mismatches are reported as ErrTableMismatch. A table created
+// concurrently, by another instance of the service, is validated once ACTIVE.
+// Returns ErrMissingThroughput if the billing mode is PROVISIONED without capacity units.
+func (r *Builder) EnsureTableWithClient(ctx context.Context, client TableClient) error {
+	if r.billingModeOrDefault() == types.BillingModeProvisioned && (r.readCapacity <= 0 || r.writeCapacity <= 0) {
+		return ErrMissingThroughput
+	}
+
+	names := r.names.withDefaults()
+	tableName := aws.String(r.containerName)
+
+	output, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: tableName})
+	var (
+		notFound *types.ResourceNotFoundException
+		inUse    *types.ResourceInUseException
+	)
+	switch {
+	case errors.As(err, &notFound):
+		_, err = client.CreateTable(ctx, r.newCreateTableInput(names))
+		if err != nil && !errors.As(err, &inUse) {
+			return fmt.Errorf("EnsureTable: create table: %w", err)
+		}
+	case err != nil:
+		return fmt.Errorf("EnsureTable: describe table: %w", err)
+	default:
+		if err = r.checkTable(output.Table, names); err != nil {
+			return err
+		}
+	}
+
+	waitTimeout := DefaultTableWaitTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		waitTimeout = min(waitTimeout, time.Until(deadline))
+	}
+	output, err = dynamodb.NewTableExistsWaiter(client).WaitForOutput(
+		ctx, &dynamodb.DescribeTableInput{TableName: tableName}, waitTimeout,
+	)
+	if err != nil {
+		return fmt.Errorf("EnsureTable: wait for table: %w", err)
+	}
+	if inUse != nil {
+		if err = r.checkTable(output.Table, names); err != nil {
+			return err
+		}
+	}
+
+	return r.ensureTTL(ctx, client, names)
+}
+
+// ensureTTL enables TTL on the TTL attribute, unless it is already enabled.
+// Returns ErrTableMismatch if TTL is enabled on another attribute.
+func (r *Builder) ensureTTL(ctx context.Context, client TableClient, names AttributeNames) error {
+	output, err := client.DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{
+		TableName: aws.String(r.containerName),
+	})
+	if err != nil {
+		return fmt.Errorf("EnsureTable: describe TTL: %w", err)
+	}
+
+	if description := output.TimeToLiveDescription; description != nil {
+		switch description.TimeToLiveStatus {
+		case types.TimeToLiveStatusEnabled, types.TimeToLiveStatusEnabling:
+			if attribute := aws.ToString(description.AttributeName); attribute != names.TTL {
+				return fmt.Errorf("%w: TTL is enabled on attribute %q instead of %q", ErrTableMismatch, attribute, names.TTL)
+			}
+			return nil
+		case types.TimeToLiveStatusDisabling:
+			return fmt.Errorf("%w: TTL is being disabled", ErrTableMismatch)
+		}
+	}
+
+	_, err = client.UpdateTimeToLive(ctx, &dynamodb.UpdateTimeToLiveInput{
+		TableName: aws.String(r.containerName),
+		TimeToLiveSpecification: &types.TimeToLiveSpecification{
+			AttributeName: aws.String(names.TTL),
+			Enabled:       aws.Bool(true),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("EnsureTable: enable TTL: %w", err)
+	}
+	return nil
+}
+
+// newCreateTableInput returns the CreateTableInput of the table of the container.
+func (r *Builder) newCreateTableInput(names AttributeNames) *dynamodb.CreateTableInput {
+	input := &dynamodb.CreateTableInput{
+		TableName:   aws.String(r.containerName),
+		KeySchema:   keySchema(names.Key, names.SortKey),
+		BillingMode: r.billingModeOrDefault(),
+	}
+
+	attributes := []string{names.Key}
+	if names.SortKey != "" {
+		attributes = append(attributes, names.SortKey)
+	}
+	for _, index := range r.indexes {
+		input.GlobalSecondaryIndexes = append(input.GlobalSecondaryIndexes, types.GlobalSecondaryIndex{
+			IndexName:             aws.String(index.Name),
+			KeySchema:             keySchema(index.Attribute, ""),
+			Projection:            &types.Projection{ProjectionType: types.ProjectionTypeAll},
+			ProvisionedThroughput: r.provisionedThroughput(),
+		})
+		attributes = append(attributes, index.Attribute)
+	}
+	input.ProvisionedThroughput = r.provisionedThroughput()
+
+	seen := map[string]bool{}
+	for _, attribute := range attributes {
+		if !seen[attribute] {
+			seen[attribute] = true
+			input.AttributeDefinitions = append(input.AttributeDefinitions, types.AttributeDefinition{
+				AttributeName: aws.String(attribute),
+				AttributeType: types.ScalarAttributeTypeS,
+			})
+		}
+	}
+
+	return input
+}
+
+// checkTable returns ErrTableMismatch if the key schema or the secondary indexes of an
+// existing table do not match the configuration of the Builder.
+func (r *Builder) checkTable(table *types.TableDescription, names AttributeNames) error {
+	if table == nil {
+		return fmt.Errorf("%w: table has no description", ErrTableMismatch)
+	}
+
+	attributeTypes := map[string]types.ScalarAttributeType{}
+	for _, definition := range table.AttributeDefinitions {
+		attributeTypes[aws.ToString(definition.AttributeName)] = definition.AttributeType
+	}
+
+	var mismatches []string
+	mismatches = append(mismatches, checkKeySchema("table", table.KeySchema, names.Key, names.SortKey, attributeTypes)...)
+	for _, index := range r.indexes {
+		var found *types.GlobalSecondaryIndexDescription
+		for i := range table.GlobalSecondaryIndexes {
+			if aws.ToString(table.GlobalSecondaryIndexes[i].IndexName) == index.Name {
+				found = &table.GlobalSecondaryIndexes[i]
+			}
+		}
+		if found == nil {
+			mismatches = append(mismatches, fmt.Sprintf("index %q is missing", index.Name))
+			continue
+		}
+
+		mismatches = append(mismatches,
+			checkKeySchema("index "+strconv.Quote(index.Name), found.KeySchema, index.Attribute, "", attributeTypes)...)
+		if found.Projection == nil || found.Projection.ProjectionType != types.ProjectionTypeAll {
+			mismatches = append(mismatches, fmt.Sprintf("index %q does not project all attributes", index.Name))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("%w: %s", ErrTableMismatch, strings.Join(mismatches, "; "))
+	}
+	return nil
+}
+
+// checkKeySchema returns the mismatches of the key schema of a table or index against the
+// expected partition and sort key attributes (sort empty for a simple key), of type string.
+func checkKeySchema(
+	owner string,
+	schema []types.KeySchemaElement,
+	partition, sort string,
+	attributeTypes map[string]types.ScalarAttributeType,
+) []string {
+	var mismatches []string
+	for _, element := range keySchema(partition, sort) {
+		name := aws.ToString(element.AttributeName)
+		found := false
+		for _, actual := range schema {
+			if actual.KeyType == element.KeyType {
+				found = true
+				if actual := aws.ToString(actual.AttributeName); actual != name {
+					mismatches = append(mismatches,
+						fmt.Sprintf("%s %s key is %q instead of %q", owner, element.KeyType, actual, name))
+				}
+			}
+		}
+		switch {
+		case !found:
+			mismatches = append(mismatches, fmt.Sprintf("%s has no %s key %q", owner, element.KeyType, name))
+		case attributeTypes[name] != "" && attributeTypes[name] != types.ScalarAttributeTypeS:
+			mismatches = append(mismatches, fmt.Sprintf("%s key %q is not a string", owner, name))
+		}
+	}
+
+	if sort == "" {
+		for _, actual := range schema {
+			if actual.KeyType == types.KeyTypeRange {
+				mismatches = append(mismatches,
+					fmt.Sprintf("%s has an unexpected RANGE key %q", owner, aws.ToString(actual.AttributeName)))
+			}
+		}
+	}
+	return mismatches
+}
+
+// keySchema returns the key schema of a partition key and an optional sort key.
+func keySchema(partition, sort string) []types.KeySchemaElement {
+	schema := []types.KeySchemaElement{{AttributeName: aws.String(partition), KeyType: types.KeyTypeHash}}
+	if sort != "" {
+		schema = append(schema, types.KeySchemaElement{AttributeName: aws.String(sort), KeyType: types.KeyTypeRange})
+	}
+	return schema
+}
+
+// billingModeOrDefault returns the billing mode of the table, PAY_PER_REQUEST by default.
+func (r *Builder) billingModeOrDefault() types.BillingMode {
+	if r.billingMode == "" {
+		return types.BillingModePayPerRequest
+	}
+	return r.billingMode
+}
+
+// provisionedThroughput returns the throughput of the table and its indexes, nil unless
+// the billing mode is PROVISIONED.
+func (r *Builder) provisionedThroughput() *types.ProvisionedThroughput {
+	if r.billingModeOrDefault() != types.BillingModeProvisioned {
+		return nil
+	}
+	return &types.ProvisionedThroughput{
+		ReadCapacityUnits:  aws.Int64(r.readCapacity),
+		WriteCapacityUnits: aws.Int64(r.writeCapacity),
+	}
+}
@@ -0,0 +1,131 @@
+package dynamodb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsdynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs/dynamodb"
+)
+
+func TestBuilder_EnsureTable_Create(t *testing.T) {
+	ctx := t.Context()
+	fake := dynamodb.NewAWSFakeClient()
+	builder := dynamodb.NewBuilder(
+		dynamodb.WithContainerName("orders"),
+		dynamodb.WithAttributeNames(dynamodb.AttributeNames{Key: "pk", SortKey: "sk", TTL: "expires"}),
+		dynamodb.WithSecondaryIndexes(byEmail),
+		dynamodb.WithProvisionedThroughput(5, 10),
+	)
+
+	require.NoError(t, builder.EnsureTableWithClient(ctx, fake))
+
+	output, err := fake.DescribeTable(ctx, &awsdynamodb.DescribeTableInput{TableName: aws.String("orders")})
+	require.NoError(t, err)
+	table := output.Table
+	require.Equal(t, types.TableStatusActive, table.TableStatus)
+	require.Equal(t, types.BillingModeProvisioned, table.BillingModeSummary.BillingMode)
+	require.Equal(t, []types.KeySchemaElement{
+		{AttributeName: aws.String("pk"), KeyType: types.KeyTypeHash},
+		{AttributeName: aws.String("sk"), KeyType: types.KeyTypeRange},
+	}, table.KeySchema)
+	require.Len(t, table.AttributeDefinitions, 3)
+	require.Len(t, table.GlobalSecondaryIndexes, 1)
+	require.Equal(t, "by-email", aws.ToString(table.GlobalSecondaryIndexes[0].IndexName))
+
+	ttl, err := fake.DescribeTimeToLive(ctx, &awsdynamodb.DescribeTimeToLiveInput{TableName: aws.String("orders")})
+	require.NoError(t, err)
+	require.Equal(t, types.TimeToLiveStatusEnabled, ttl.TimeToLiveDescription.TimeToLiveStatus)
+	require.Equal(t, "expires", aws.ToString(ttl.TimeToLiveDescription.AttributeName))
+
+	// An existing, matching table is left as is.
+	require.NoError(t, builder.EnsureTableWithClient(ctx, fake))
+}
+
+func TestBuilder_EnsureTable_Mismatch(t *testing.T) {
+	ctx := t.Context()
+	fake := dynamodb.NewAWSFakeClient()
+	_, err := fake.CreateTable(ctx, &awsdynamodb.CreateTableInput{
+		TableName: aws.String("users"),
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("id"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("sk"), KeyType: types.KeyTypeRange},
+		},
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("id"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("sk"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		BillingMode: types.BillingModePayPerRequest,
+	})
+	require.NoError(t, err)
+
+	err = dynamodb.NewBuilder().
+		WithContainerName("users").
+		WithSecondaryIndexes(byEmail).
+		EnsureTableWithClient(ctx, fake)
+	require.ErrorIs(t, err, dynamodb.ErrTableMismatch)
+	require.EqualError(t, err, `[kvs]: table does not match the client configuration: `+
+		`table HASH key is "id" instead of "key"; table has an unexpected RANGE key "sk"; index "by-email" is missing`)
+}
+
+func TestBuilder_EnsureTable_TTLMismatch(t *testing.T) {
+	ctx := t.Context()
+	fake := dynamodb.NewAWSFakeClient()
+	builder := dynamodb.NewBuilder().WithContainerName("users")
+	require.NoError(t, builder.EnsureTableWithClient(ctx, fake))
+
+	err := dynamodb.NewBuilder().
+		WithContainerName("users").
+		WithAttributeNames(dynamodb.AttributeNames{TTL: "expires_at"}).
+		EnsureTableWithClient(ctx, fake)
+	require.ErrorIs(t, err, dynamodb.ErrTableMismatch)
+	require.ErrorContains(t, err, `TTL is enabled on attribute "ttl" instead of "expires_at"`)
+}
+
+// racingTableClient reports the table as missing on the first DescribeTable, as when
+// another instance creates it right after.
+type racingTableClient struct {
+	*dynamodb.AWSFakeClient
+	described bool
+}
+
+func (r *racingTableClient) DescribeTable(
+	ctx context.Context,
+	params *awsdynamodb.DescribeTableInput,
+	optFns ...func(*awsdynamodb.Options),
+) (*awsdynamodb.DescribeTableOutput, error) {
+	if !r.described {
+		r.described = true
+		return nil, &types.ResourceNotFoundException{Message: aws.String("Requested resource not found")}
+	}
+	return r.AWSFakeClient.DescribeTable(ctx, params, optFns...)
+}
+
+func TestBuilder_EnsureTable_CreatedConcurrently(t *testing.T) {
+	ctx := t.Context()
+	fake := dynamodb.NewAWSFakeClient()
+	require.NoError(t, dynamodb.NewBuilder().WithContainerName("users").EnsureTableWithClient(ctx, fake))
+
+	builder := dynamodb.NewBuilder().WithContainerName("users")
+	require.NoError(t, builder.EnsureTableWithClient(ctx, &racingTableClient{AWSFakeClient: fake}))
+
+	err := builder.WithSecondaryIndexes(byEmail).EnsureTableWithClient(ctx, &racingTableClient{AWSFakeClient: fake})
+	require.ErrorIs(t, err, dynamodb.ErrTableMismatch, "the concurrently created table is validated")
+}
+
+func TestBuilder_EnsureTable_ProvisionedWithoutThroughput(t *testing.T) {
+	fake := dynamodb.NewAWSFakeClient()
+	err := dynamodb.NewBuilder(
+		dynamodb.WithContainerName("users"),
+		dynamodb.WithBillingMode(types.BillingModeProvisioned),
+	).EnsureTableWithClient(t.Context(), fake)
+	require.ErrorIs(t, err, dynamodb.ErrMissingThroughput)
+
+	_, err = fake.DescribeTable(t.Context(), &awsdynamodb.DescribeTableInput{TableName: aws.String("users")})
+	var notFound *types.ResourceNotFoundException
+	require.ErrorAs(t, err, &notFound, "no table is created")
+}
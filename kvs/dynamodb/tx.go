@@ -0,0 +1,180 @@
+// Package dynamodb provides AWS DynamoDB specific implementation of the KVS client.
+package dynamodb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// conditionalCheckFailed is the cancellation reason of a transaction action whose
+// condition did not hold.
+const conditionalCheckFailed = "ConditionalCheckFailed"
+
+// NewTx starts a transaction committed with a single TransactWriteItems request.
+// TTL semantics of Put are those of SaveWithContext. DynamoDB allows a single action
+// per item, so the ConditionCheck of a written key becomes the condition of its write.
+func (r *LowLevelClient) NewTx() *kvs.Tx {
+	return kvs.NewTx(r.commitTx)
+}
+
+// commitTx is the kvs.TxCommitFunc of the transactions of the client.
+func (r *LowLevelClient) commitTx(ctx context.Context, operations []kvs.TxOperation) error {
+	conditions := map[string]*valueCondition{}
+	written := map[string]bool{}
+	for _, operation := range operations {
+		if operation.Type != kvs.TxConditionCheck {
+			written[operation.Key] = true
+			continue
+		}
+
+		condition := r.newAbsentCondition()
+		if operation.Expected != nil {
+			var err error
+			if condition, err = r.newValueCondition(operation.Expected); err != nil {
+				return err
+			}
+		}
+		conditions[operation.Key] = condition
+	}
+
+	actions := make([]types.TransactWriteItem, 0, len(operations))
+	keys := make([]string, 0, len(operations))
+	for _, operation := range operations {
+		if operation.Type == kvs.TxConditionCheck && written[operation.Key] {
+			continue
+		}
+
+		action, err := r.newTxAction(operation, conditions[operation.Key])
+		if err != nil {
+			return err
+		}
+		actions = append(actions, action)
+		keys = append(keys, operation.Key)
+	}
+
+	_, err := r.AWSClient.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: actions,
+	})
+
+	return txError(err, operations, keys)
+}
+
+// newTxAction returns the TransactWriteItem of an operation, with an optional condition.
+func (r *LowLevelClient) newTxAction(
+	operation kvs.TxOperation,
+	condition *valueCondition,
+) (types.TransactWriteItem, error) {
+	if condition == nil {
+		condition = &valueCondition{}
+	}
+
+	if operation.Type == kvs.TxPut {
+		attributes, err := r.conditionalItem(operation.Key, operation.Item)
+		if err != nil {
+			return types.TransactWriteItem{}, err
+		}
+		return types.TransactWriteItem{Put: &types.Put{
+			TableName:                 r.getTableName(),
+			Item:                      attributes,
+			ConditionExpression:       condition.expression,
+			ExpressionAttributeNames:  condition.names,
+			ExpressionAttributeValues: condition.values,
+		}}, nil
+	}
+
+	primaryKey, err := r.primaryKey(operation.Key)
+	if err != nil {
+		return types.TransactWriteItem{}, err
+	}
+
+	if operation.Type == kvs.TxDelete {
+		return types.TransactWriteItem{Delete: &types.Delete{
+			TableName:                 r.getTableName(),
+			Key:                       primaryKey,
+			ConditionExpression:       condition.expression,
+			ExpressionAttributeNames:  condition.names,
+			ExpressionAttributeValues: condition.values,
+		}}, nil
+	}
+
+	return types.TransactWriteItem{ConditionCheck: &types.ConditionCheck{
+		TableName:                 r.getTableName(),
+		Key:                       primaryKey,
+		ConditionExpression:       condition.expression,
+		ExpressionAttributeNames:  condition.names,
+		ExpressionAttributeValues: condition.values,
+	}}, nil
+}
+
+// txError maps the cancellation of a transaction by a failed condition to a
+// *kvs.TxCanceledError identifying the ConditionCheck of the key of the failed action
+// (keys holds the key of each action). Any other error is returned unchanged.
+func txError(err error, operations []kvs.TxOperation, keys []string) error {
+	var canceled *types.TransactionCanceledException
+	if !errors.As(err, &canceled) {
+		return err
+	}
+
+	for i, reason := range canceled.CancellationReasons {
+		if aws.ToString(reason.Code) == conditionalCheckFailed && i < len(keys) {
+			return kvs.NewTxCanceledError(operations, keys[i])
+		}
+	}
+	return err
+}
+
+// TransactGet reads the items stored under keys with TransactGetItems, as a snapshot
+// consistent across keys. Missing and expired keys are left out of the result.
+// Returns kvs.ErrTooManyKeys if more than 100 keys are provided.
+func (r *LowLevelClient) TransactGet(ctx context.Context, keys []string) (*kvs.Items, error) {
+	if len(keys) > kvs.MaxTxOperations {
+		return nil, kvs.ErrTooManyKeys
+	}
+
+	actions := make([]types.TransactGetItem, len(keys))
+	for i, key := range keys {
+		primaryKey, err := r.primaryKey(key)
+		if err != nil {
+			return nil, err
+		}
+		actions[i] = types.TransactGetItem{Get: &types.Get{
+			TableName: r.getTableName(),
+			Key:       primaryKey,
+		}}
+	}
+
+	items := new(kvs.Items)
+	if len(actions) == 0 {
+		return items, nil
+	}
+
+	output, err := r.AWSClient.TransactGetItems(ctx, &dynamodb.TransactGetItemsInput{
+		TransactItems: actions,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, response := range output.Responses {
+		if len(response.Item) == 0 {
+			continue
+		}
+		item, err := r.unmarshalItem(response.Item)
+		if err != nil {
+			return nil, err
+		}
+		if r.expired(item) {
+			r.evict(item)
+			continue
+		}
+		items.Add(item)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,62 @@
+package dynamodb_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	"github.com/arielsrv/go-kvs-client/kvs/dynamodb"
+)
+
+func TestLowLevelClient_Tx(t *testing.T) {
+	ctx := t.Context()
+	llc := dynamodb.NewBuilder().WithContainerName("__kvs-test").FakeBuild()
+
+	require.NoError(t, llc.Save("inventory:1", kvs.NewItem("inventory:1", 10)))
+	stock, err := llc.Get("inventory:1")
+	require.NoError(t, err)
+
+	// The check of a written key becomes the condition of its write.
+	place := func(stock *kvs.Item, order string) *kvs.Tx {
+		return llc.NewTx().
+			ConditionCheck("inventory:1", stock).
+			ConditionCheck(order, nil).
+			Put(order, kvs.NewItem(order, "placed")).
+			Put("inventory:1", kvs.NewItem("inventory:1", 9))
+	}
+	require.NoError(t, place(stock, "order:1").Commit(ctx))
+
+	items, err := llc.TransactGet(ctx, []string{"inventory:1", "order:1", "order:2"})
+	require.NoError(t, err)
+	values := map[string]any{}
+	for item := range items.All() {
+		values[item.Key] = item.Value
+	}
+	require.Equal(t, map[string]any{"inventory:1": "9", "order:1": `"placed"`}, values)
+
+	// A stale inventory cancels the whole transaction.
+	err = place(stock, "order:2").Commit(ctx)
+	require.ErrorIs(t, err, kvs.ErrConditionFailed)
+	var canceled *kvs.TxCanceledError
+	require.True(t, errors.As(err, &canceled))
+	require.Equal(t, &kvs.TxCanceledError{Key: "inventory:1", Index: 0}, canceled)
+	_, err = llc.Get("order:2")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+
+	// An existing order is reported by its own ConditionCheck.
+	stock, err = llc.Get("inventory:1")
+	require.NoError(t, err)
+	err = place(stock, "order:1").Commit(ctx)
+	require.True(t, errors.As(err, &canceled))
+	require.Equal(t, &kvs.TxCanceledError{Key: "order:1", Index: 1}, canceled)
+
+	// Deletes are applied together with the checks of other keys.
+	require.NoError(t, llc.NewTx().ConditionCheck("inventory:1", stock).Delete("order:1").Commit(ctx))
+	_, err = llc.Get("order:1")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+
+	_, err = llc.TransactGet(ctx, make([]string, kvs.MaxTxOperations+1))
+	require.ErrorIs(t, err, kvs.ErrTooManyKeys)
+}
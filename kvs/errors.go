@@ -17,6 +17,19 @@ const (
 	ErrTooManyKeys = KeyValueError("[kvs]: too many keys")
 	// ErrInternal is returned when an internal error occurs in the key-value store.
 	ErrInternal = KeyValueError("[kvs]: internal error")
+	// ErrConditionFailed is returned when a conditional write is rejected because the
+	// stored item does not match the expected state.
+	ErrConditionFailed = KeyValueError("[kvs]: condition failed")
+	// ErrFieldPath is returned by Patch and GetFields when a field path is empty,
+	// malformed or goes through a value that is not an object.
+	ErrFieldPath = KeyValueError("[kvs]: invalid field path")
+	// ErrUnsupported is returned when the backend does not support an operation.
+	ErrUnsupported = KeyValueError("[kvs]: operation not supported")
+	// ErrInvalidToken is returned when a pagination token is malformed.
+	ErrInvalidToken = KeyValueError("[kvs]: invalid pagination token")
+	// ErrInvalidTx is returned by Tx.Commit when a transaction writes or checks a key
+	// twice, or has too many operations.
+	ErrInvalidTx = KeyValueError("[kvs]: invalid transaction")
 )
 
 // KeyValueError is a custom error type for key-value store operations.
@@ -0,0 +1,128 @@
+// Package kvs provides a generic key-value store client interface and implementation.
+package kvs
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// FieldPathSeparator separates the segments of a field path in Patch and
+// GetFields: "address.city" is the field "city" of the object "address".
+const FieldPathSeparator = "."
+
+// SplitFieldPath splits a field path into its segments.
+// Returns ErrFieldPath if the path or one of its segments is empty.
+func SplitFieldPath(path string) ([]string, error) {
+	segments := strings.Split(path, FieldPathSeparator)
+	for _, segment := range segments {
+		if strings.TrimSpace(segment) == "" {
+			return nil, ErrFieldPath
+		}
+	}
+	return segments, nil
+}
+
+// PatchJSON applies updates to the JSON object value, as described by
+// LowLevelClient.Patch, and returns the updated JSON. The parents of a nested
+// field must exist. Numbers are preserved as written.
+// Returns ErrConvert if value is not a JSON object, ErrFieldPath if a path is invalid,
+// or ErrMarshal if an update cannot be marshalled.
+func PatchJSON(value string, updates map[string]any) (string, error) {
+	object, err := decodeObject(value)
+	if err != nil {
+		return "", err
+	}
+
+	for path, update := range updates {
+		segments, splitErr := SplitFieldPath(path)
+		if splitErr != nil {
+			return "", splitErr
+		}
+
+		parent, found := walk(object, segments[:len(segments)-1])
+		if !found {
+			return "", ErrFieldPath
+		}
+
+		last := segments[len(segments)-1]
+		if update == nil {
+			delete(parent, last)
+			continue
+		}
+		parent[last] = update
+	}
+
+	encoded, err := json.Marshal(object)
+	if err != nil {
+		return "", ErrMarshal
+	}
+	return string(encoded), nil
+}
+
+// ProjectJSON returns a JSON object holding only the fields of the JSON object
+// value at paths, nested as in value. Missing fields are omitted.
+// Returns ErrConvert if value is not a JSON object or ErrFieldPath if a path is invalid.
+func ProjectJSON(value string, paths ...string) (string, error) {
+	object, err := decodeObject(value)
+	if err != nil {
+		return "", err
+	}
+
+	projection := make(map[string]any)
+	for _, path := range paths {
+		segments, splitErr := SplitFieldPath(path)
+		if splitErr != nil {
+			return "", splitErr
+		}
+
+		parent, found := walk(object, segments[:len(segments)-1])
+		if !found {
+			continue
+		}
+		field, found := parent[segments[len(segments)-1]]
+		if !found {
+			continue
+		}
+
+		target := projection
+		for _, segment := range segments[:len(segments)-1] {
+			next, ok := target[segment].(map[string]any)
+			if !ok {
+				next = make(map[string]any)
+				target[segment] = next
+			}
+			target = next
+		}
+		target[segments[len(segments)-1]] = field
+	}
+
+	encoded, err := json.Marshal(projection)
+	if err != nil {
+		return "", ErrMarshal
+	}
+	return string(encoded), nil
+}
+
+// decodeObject decodes a JSON object, keeping numbers as json.Number.
+func decodeObject(value string) (map[string]any, error) {
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+
+	var object map[string]any
+	if err := decoder.Decode(&object); err != nil || object == nil {
+		return nil, ErrConvert
+	}
+	return object, nil
+}
+
+// walk returns the object reached by following segments from object.
+func walk(object map[string]any, segments []string) (map[string]any, bool) {
+	for _, segment := range segments {
+		next, ok := object[segment].(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		object = next
+	}
+	return object, true
+}
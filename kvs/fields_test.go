@@ -0,0 +1,66 @@
+package kvs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+func TestPatchJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		updates map[string]any
+		want    string
+		err     error
+	}{
+		{
+			name:    "sets and removes fields",
+			value:   `{"first_name":"John","last_name":"Doe","id":9007199254740993}`,
+			updates: map[string]any{"last_name": "Smith", "first_name": nil, "age": 42},
+			want:    `{"age":42,"id":9007199254740993,"last_name":"Smith"}`,
+		},
+		{
+			name:    "nested field",
+			value:   `{"address":{"city":"Paris","zip":"75000"}}`,
+			updates: map[string]any{"address.city": "Lyon", "address.zip": nil},
+			want:    `{"address":{"city":"Lyon"}}`,
+		},
+		{name: "missing parent", value: `{}`, updates: map[string]any{"address.city": "Lyon"}, err: kvs.ErrFieldPath},
+		{name: "parent is not an object", value: `{"a":1}`, updates: map[string]any{"a.b": 1}, err: kvs.ErrFieldPath},
+		{name: "empty segment", value: `{}`, updates: map[string]any{"a..b": 1}, err: kvs.ErrFieldPath},
+		{name: "not an object", value: `[1]`, updates: map[string]any{"a": 1}, err: kvs.ErrConvert},
+		{name: "null", value: `null`, updates: map[string]any{"a": 1}, err: kvs.ErrConvert},
+		{name: "unmarshallable update", value: `{}`, updates: map[string]any{"a": make(chan int)}, err: kvs.ErrMarshal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := kvs.PatchJSON(tt.value, tt.updates)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProjectJSON(t *testing.T) {
+	value := `{"first_name":"John","last_name":"Doe","address":{"city":"Paris","zip":"75000"},"tags":[1]}`
+
+	got, err := kvs.ProjectJSON(value, "last_name", "address.city", "missing", "tags.x", "first_name.x")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"last_name":"Doe","address":{"city":"Paris"}}`, got)
+
+	got, err = kvs.ProjectJSON(value)
+	require.NoError(t, err)
+	require.Equal(t, `{}`, got)
+
+	_, err = kvs.ProjectJSON(value, "")
+	require.ErrorIs(t, err, kvs.ErrFieldPath)
+	_, err = kvs.ProjectJSON(`"text"`, "a")
+	require.ErrorIs(t, err, kvs.ErrConvert)
+}
@@ -0,0 +1,30 @@
+// Package idempotency provides an idempotency-key store for request de-duplication,
+// built on the conditional writes of kvs.LowLevelClient.
+//
+// The first request for a key atomically claims it with an in-progress marker that
+// carries a lease. Concurrent duplicates are rejected with ErrInProgress while the
+// lease is live; once the work is done, the claim is completed with the typed result,
+// which is replayed to every later request until the record expires. A claim whose
+// holder crashed is taken over by the first request that sees its lease expired.
+//
+// Key Components:
+//   - Store: claims keys, replays stored results and wraps the whole flow in Do.
+//   - Claim: a held in-progress marker, with Complete, Release and Extend.
+//   - Record: the stored state of a key.
+//
+// Usage:
+//
+//	store := idempotency.NewStore[PaymentResponse](
+//	    llClient, // Redis or DynamoDB kvs.LowLevelClient
+//	    idempotency.WithLease(30*time.Second),
+//	    idempotency.WithTTL(24*time.Hour),
+//	)
+//
+//	response, replayed, err := store.Do(ctx, request.IdempotencyKey,
+//	    func(ctx context.Context) (*PaymentResponse, error) {
+//	        return charge(ctx, request)
+//	    })
+//	if errors.Is(err, idempotency.ErrInProgress) {
+//	    // A duplicate is being processed: answer 409 Conflict.
+//	}
+package idempotency
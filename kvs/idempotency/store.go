@@ -0,0 +1,344 @@
+// Package idempotency provides an idempotency-key store for request de-duplication.
+package idempotency
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// Error constants for idempotency operations.
+const (
+	// ErrInProgress is returned when the key is claimed by another request whose lease is live.
+	ErrInProgress = kvs.KeyValueError("[kvs]: idempotency key is being processed")
+	// ErrClaimLost is returned when completing, releasing or extending a claim that has been
+	// taken over by another request after its lease expired.
+	ErrClaimLost = kvs.KeyValueError("[kvs]: idempotency claim has been lost")
+)
+
+// Default settings of a Store.
+const (
+	DefaultLease = 30 * time.Second // Time a claim is protected from being taken over
+	DefaultTTL   = 24 * time.Hour   // Time a record is kept, from its last write
+)
+
+// maxClaimAttempts bounds the number of conditional writes tried by Store.Claim
+// when racing with other requests.
+const maxClaimAttempts = 5
+
+// Status is the state of an idempotency record.
+type Status string
+
+// Record statuses.
+const (
+	StatusInProgress Status = "in_progress"
+	StatusCompleted  Status = "completed"
+)
+
+// Record is the stored state of an idempotency key.
+type Record[T any] struct {
+	// Result is the result stored on completion.
+	Result *T `json:"result,omitempty"`
+	// Status is the state of the key.
+	Status Status `json:"status"`
+	// Owner is the random token of the request holding an in-progress claim.
+	Owner string `json:"owner,omitempty"`
+	// LeaseExpiresAt is the time, in unix milliseconds, after which an in-progress
+	// claim can be taken over.
+	LeaseExpiresAt int64 `json:"lease_expires_at,omitempty"`
+}
+
+// Store records which requests have been processed and their results.
+type Store[T any] struct {
+	lowLevelClient kvs.LowLevelClientProxy
+	clock          kvs.Clock
+	lease          time.Duration
+	ttl            time.Duration
+}
+
+// storeConfig holds the settings applied by StoreOptions.
+type storeConfig struct {
+	clock kvs.Clock
+	lease time.Duration
+	ttl   time.Duration
+}
+
+// StoreOptions configures a Store. Used with the functional-options pattern.
+type StoreOptions func(*storeConfig)
+
+// WithLease returns a StoreOptions that sets how long a claim is protected from
+// being taken over. It should exceed the expected processing time; long-running
+// work can call Claim.Extend.
+func WithLease(lease time.Duration) StoreOptions {
+	return func(c *storeConfig) { c.lease = lease }
+}
+
+// WithTTL returns a StoreOptions that sets how long records are kept, counted from
+// their last write. Replays are only detected within this period.
+func WithTTL(ttl time.Duration) StoreOptions {
+	return func(c *storeConfig) { c.ttl = ttl }
+}
+
+// WithClock returns a StoreOptions that sets the clock against which leases and record
+// TTLs are computed (kvs.SystemClock by default), so that tests can expire leases with
+// a kvs.FakeClock instead of waiting.
+func WithClock(clock kvs.Clock) StoreOptions {
+	return func(c *storeConfig) { c.clock = clock }
+}
+
+// NewStore creates a new Store backed by the provided LowLevelClient.
+func NewStore[T any](lowLevelClient kvs.LowLevelClient, opts ...StoreOptions) *Store[T] {
+	config := &storeConfig{
+		clock: kvs.SystemClock,
+		lease: DefaultLease,
+		ttl:   DefaultTTL,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return &Store[T]{
+		lowLevelClient: kvs.NewLowLevelClientProxy(lowLevelClient),
+		clock:          config.clock,
+		lease:          config.lease,
+		ttl:            config.ttl,
+	}
+}
+
+// Claim tries to claim key for the current request.
+//
+// It returns a non-nil Claim when the caller must process the request, either
+// because the key is new or because the previous claim was abandoned (its lease
+// expired without completion). It returns a nil Claim and the stored result when
+// the key has already been completed. It returns ErrInProgress when another
+// request holds a live claim.
+func (r *Store[T]) Claim(ctx context.Context, key string) (*Claim[T], *T, error) {
+	if strings.TrimSpace(key) == "" {
+		return nil, nil, kvs.ErrEmptyKey
+	}
+
+	for range maxClaimAttempts {
+		claim, err := r.newClaim(key)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		err = r.lowLevelClient.SaveIfAbsent(ctx, key, claim.item())
+		if err == nil {
+			return claim, nil, nil
+		}
+		if !errors.Is(err, kvs.ErrConditionFailed) {
+			return nil, nil, err
+		}
+
+		current, record, err := r.get(ctx, key)
+		if errors.Is(err, kvs.ErrKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if record.Status == StatusCompleted {
+			return nil, record.Result, nil
+		}
+		if record.LeaseExpiresAt > r.clock.Now().UnixMilli() {
+			return nil, nil, ErrInProgress
+		}
+
+		// The previous claim was abandoned: take it over unless someone else does first.
+		err = r.lowLevelClient.CompareAndSwap(ctx, key, current, claim.item())
+		if err == nil {
+			return claim, nil, nil
+		}
+		if !errors.Is(err, kvs.ErrConditionFailed) {
+			return nil, nil, err
+		}
+	}
+
+	return nil, nil, ErrInProgress
+}
+
+// Get returns the record stored under key, or kvs.ErrKeyNotFound.
+func (r *Store[T]) Get(ctx context.Context, key string) (*Record[T], error) {
+	_, record, err := r.get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Do runs fn at most once for key and returns its result.
+//
+// When key has already been completed, the stored result is returned with replayed
+// set to true and fn is not called. When fn fails, the claim is released so that the
+// request can be retried, and the error is returned. Returns ErrInProgress when
+// another request is processing key.
+func (r *Store[T]) Do(
+	ctx context.Context,
+	key string,
+	fn func(ctx context.Context) (*T, error),
+) (*T, bool, error) {
+	claim, stored, err := r.Claim(ctx, key)
+	if err != nil {
+		return nil, false, err
+	}
+	if claim == nil {
+		return stored, true, nil
+	}
+
+	result, err := fn(ctx)
+	if err != nil {
+		releaseErr := claim.Release(context.WithoutCancel(ctx))
+		if releaseErr != nil && !errors.Is(releaseErr, ErrClaimLost) {
+			return nil, false, errors.Join(err, releaseErr)
+		}
+		return nil, false, err
+	}
+
+	err = claim.Complete(ctx, result)
+	if err != nil {
+		return result, false, err
+	}
+
+	return result, false, nil
+}
+
+// get reads and decodes the record stored under key, along with the raw item
+// used as the expected value of conditional writes.
+func (r *Store[T]) get(ctx context.Context, key string) (*kvs.Item, *Record[T], error) {
+	item, err := r.lowLevelClient.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	record := new(Record[T])
+	err = item.TryGetValueAsObjectType(record)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return item, record, nil
+}
+
+// newClaim builds an in-progress claim on key owned by a fresh random token.
+func (r *Store[T]) newClaim(key string) (*Claim[T], error) {
+	claim := &Claim[T]{
+		store: r,
+		key:   key,
+		owner: rand.Text(),
+	}
+
+	err := claim.setLease(r.clock.Now().Add(r.lease))
+	if err != nil {
+		return nil, err
+	}
+
+	return claim, nil
+}
+
+// Claim is an in-progress marker held by the current request.
+// Exactly one of Complete or Release should be called once the work is done.
+type Claim[T any] struct {
+	store          *Store[T]
+	key            string
+	owner          string
+	value          string
+	leaseExpiresAt time.Time
+}
+
+// Key returns the claimed idempotency key.
+func (r *Claim[T]) Key() string {
+	return r.key
+}
+
+// LeaseExpiresAt returns the time after which the claim can be taken over.
+func (r *Claim[T]) LeaseExpiresAt() time.Time {
+	return r.leaseExpiresAt
+}
+
+// Complete stores result as the outcome of the request; later claims of the key
+// replay it until the record expires.
+// Returns ErrClaimLost if the claim has been taken over.
+func (r *Claim[T]) Complete(ctx context.Context, result *T) error {
+	bytes, err := json.Marshal(Record[T]{
+		Status: StatusCompleted,
+		Result: result,
+	})
+	if err != nil {
+		return kvs.ErrMarshal
+	}
+
+	err = r.store.lowLevelClient.CompareAndSwap(ctx, r.key, r.stored(),
+		kvs.NewItemWithClock(r.store.clock, r.key, json.RawMessage(bytes), r.store.ttl))
+
+	return claimError(err)
+}
+
+// Release removes the claim so that the request can be processed again, e.g. after
+// a failure that is safe to retry.
+// Returns ErrClaimLost if the claim has been taken over.
+func (r *Claim[T]) Release(ctx context.Context) error {
+	return claimError(r.store.lowLevelClient.CompareAndDelete(ctx, r.key, r.stored()))
+}
+
+// Extend renews the lease of the claim for the lease duration of the Store.
+// Returns ErrClaimLost if the claim has been taken over.
+func (r *Claim[T]) Extend(ctx context.Context) error {
+	previous := r.stored()
+	extended := *r
+
+	err := extended.setLease(r.store.clock.Now().Add(r.store.lease))
+	if err != nil {
+		return err
+	}
+
+	err = r.store.lowLevelClient.CompareAndSwap(ctx, r.key, previous, extended.item())
+	if err != nil {
+		return claimError(err)
+	}
+
+	r.value, r.leaseExpiresAt = extended.value, extended.leaseExpiresAt
+	return nil
+}
+
+// setLease sets the lease expiration and encodes the in-progress record.
+func (r *Claim[T]) setLease(leaseExpiresAt time.Time) error {
+	bytes, err := json.Marshal(Record[T]{
+		Status:         StatusInProgress,
+		Owner:          r.owner,
+		LeaseExpiresAt: leaseExpiresAt.UnixMilli(),
+	})
+	if err != nil {
+		return kvs.ErrMarshal
+	}
+
+	r.value, r.leaseExpiresAt = string(bytes), leaseExpiresAt
+	return nil
+}
+
+// item returns the in-progress record as a kvs.Item to write. The encoded record
+// is passed as raw JSON so that the backends store it verbatim.
+func (r *Claim[T]) item() *kvs.Item {
+	return kvs.NewItemWithClock(r.store.clock, r.key, json.RawMessage(r.value), r.store.ttl)
+}
+
+// stored returns the in-progress record as it is returned by Get, for use as the
+// expected value of conditional writes.
+func (r *Claim[T]) stored() *kvs.Item {
+	return kvs.NewItem(r.key, r.value)
+}
+
+// claimError maps a failed conditional write to ErrClaimLost.
+func claimError(err error) error {
+	if errors.Is(err, kvs.ErrConditionFailed) {
+		return ErrClaimLost
+	}
+	return err
+}
@@ -0,0 +1,202 @@
+package idempotency_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	"github.com/arielsrv/go-kvs-client/kvs/dynamodb"
+	"github.com/arielsrv/go-kvs-client/kvs/idempotency"
+	kvsredis "github.com/arielsrv/go-kvs-client/kvs/redis"
+)
+
+type response struct {
+	ID     string `json:"id"`
+	Amount int    `json:"amount"`
+}
+
+func backends(t *testing.T) map[string]kvs.LowLevelClient {
+	t.Helper()
+
+	srv := miniredis.RunT(t)
+	universal := goredis.NewUniversalClient(&goredis.UniversalOptions{Addrs: []string{srv.Addr()}})
+	t.Cleanup(func() { _ = universal.Close() })
+
+	return map[string]kvs.LowLevelClient{
+		"redis fake":   kvsredis.NewBuilder(kvsredis.WithKeyPrefix("__kvs:idempotency")).FakeBuild(),
+		"redis server": kvsredis.NewLowLevelClient(kvsredis.NewGoRedisClient(universal), "__kvs:idempotency"),
+		"dynamodb":     dynamodb.NewLowLevelClient(dynamodb.NewAWSFakeClient(), "__kvs-idempotency"),
+	}
+}
+
+func TestStore_Do_ReplaysCompletedResult(t *testing.T) {
+	for name, lowLevelClient := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			store := idempotency.NewStore[response](lowLevelClient)
+			calls := 0
+			fn := func(context.Context) (*response, error) {
+				calls++
+				return &response{ID: "tx-1", Amount: 100}, nil
+			}
+
+			result, replayed, err := store.Do(t.Context(), "req-1", fn)
+			require.NoError(t, err)
+			require.False(t, replayed)
+			require.Equal(t, &response{ID: "tx-1", Amount: 100}, result)
+
+			result, replayed, err = store.Do(t.Context(), "req-1", fn)
+			require.NoError(t, err)
+			require.True(t, replayed)
+			require.Equal(t, &response{ID: "tx-1", Amount: 100}, result)
+			require.Equal(t, 1, calls)
+
+			record, err := store.Get(t.Context(), "req-1")
+			require.NoError(t, err)
+			require.Equal(t, idempotency.StatusCompleted, record.Status)
+		})
+	}
+}
+
+func TestStore_Do_ReleasesClaimOnError(t *testing.T) {
+	for name, lowLevelClient := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			store := idempotency.NewStore[response](lowLevelClient)
+			failure := errors.New("payment gateway unavailable")
+
+			_, _, err := store.Do(t.Context(), "req-1", func(context.Context) (*response, error) {
+				return nil, failure
+			})
+			require.ErrorIs(t, err, failure)
+
+			_, err = store.Get(t.Context(), "req-1")
+			require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+
+			result, replayed, err := store.Do(t.Context(), "req-1", func(context.Context) (*response, error) {
+				return &response{ID: "tx-2"}, nil
+			})
+			require.NoError(t, err)
+			require.False(t, replayed)
+			require.Equal(t, "tx-2", result.ID)
+		})
+	}
+}
+
+func TestStore_Claim_RejectsConcurrentDuplicates(t *testing.T) {
+	for name, lowLevelClient := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			store := idempotency.NewStore[response](lowLevelClient)
+
+			var claimed, inProgress atomic.Int32
+			var wg sync.WaitGroup
+			for range 10 {
+				wg.Go(func() {
+					claim, _, err := store.Claim(t.Context(), "req-1")
+					switch {
+					case errors.Is(err, idempotency.ErrInProgress):
+						inProgress.Add(1)
+					case err == nil && claim != nil:
+						claimed.Add(1)
+					default:
+						t.Errorf("unexpected claim result: %v", err)
+					}
+				})
+			}
+			wg.Wait()
+
+			require.Equal(t, int32(1), claimed.Load())
+			require.Equal(t, int32(9), inProgress.Load())
+		})
+	}
+}
+
+func TestStore_Claim_TakesOverExpiredLease(t *testing.T) {
+	for name, lowLevelClient := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			clock := kvs.NewFakeClock(time.Now())
+			store := idempotency.NewStore[response](
+				lowLevelClient,
+				idempotency.WithLease(time.Minute),
+				idempotency.WithClock(clock),
+			)
+
+			abandoned, _, err := store.Claim(t.Context(), "req-1")
+			require.NoError(t, err)
+			require.Equal(t, "req-1", abandoned.Key())
+
+			_, _, err = store.Claim(t.Context(), "req-1")
+			require.ErrorIs(t, err, idempotency.ErrInProgress)
+
+			clock.Advance(time.Minute)
+
+			claim, _, err := store.Claim(t.Context(), "req-1")
+			require.NoError(t, err)
+			require.NotNil(t, claim)
+
+			require.ErrorIs(t, abandoned.Complete(t.Context(), &response{ID: "late"}), idempotency.ErrClaimLost)
+			require.ErrorIs(t, abandoned.Extend(t.Context()), idempotency.ErrClaimLost)
+			require.ErrorIs(t, abandoned.Release(t.Context()), idempotency.ErrClaimLost)
+
+			require.NoError(t, claim.Complete(t.Context(), &response{ID: "tx-1"}))
+
+			_, stored, err := store.Claim(t.Context(), "req-1")
+			require.NoError(t, err)
+			require.Equal(t, "tx-1", stored.ID)
+		})
+	}
+}
+
+func TestClaim_Extend_RenewsLease(t *testing.T) {
+	for name, lowLevelClient := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			clock := kvs.NewFakeClock(time.Now())
+			store := idempotency.NewStore[response](
+				lowLevelClient,
+				idempotency.WithLease(time.Minute),
+				idempotency.WithClock(clock),
+			)
+
+			claim, _, err := store.Claim(t.Context(), "req-1")
+			require.NoError(t, err)
+			before := claim.LeaseExpiresAt()
+			require.Equal(t, clock.Now().Add(time.Minute), before)
+
+			clock.Advance(time.Second)
+			require.NoError(t, claim.Extend(t.Context()))
+			require.Equal(t, before.Add(time.Second), claim.LeaseExpiresAt())
+
+			record, err := store.Get(t.Context(), "req-1")
+			require.NoError(t, err)
+			require.Equal(t, idempotency.StatusInProgress, record.Status)
+			require.Equal(t, claim.LeaseExpiresAt().UnixMilli(), record.LeaseExpiresAt)
+
+			require.NoError(t, claim.Complete(t.Context(), &response{ID: "tx-1"}))
+		})
+	}
+}
+
+func TestStore_Claim_EmptyKey(t *testing.T) {
+	store := idempotency.NewStore[response](kvsredis.NewBuilder().FakeBuild())
+
+	_, _, err := store.Claim(t.Context(), " ")
+	require.ErrorIs(t, err, kvs.ErrEmptyKey)
+}
+
+func TestStore_Do_BackendError_Propagates(t *testing.T) {
+	client := kvsredis.NewFakeClient()
+	require.NoError(t, client.Close())
+	store := idempotency.NewStore[response](kvsredis.NewLowLevelClient(client, "idempotency"))
+
+	_, _, err := store.Do(t.Context(), "req-1", func(context.Context) (*response, error) {
+		t.Fatal("fn must not run when the key cannot be claimed")
+		return nil, nil
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,108 @@
+// Package rate implements the state transitions of the rate-limiting algorithms
+// shared by the in-memory Redis fake and the DynamoDB rate-limit backend.
+// The Redis backend runs the same algorithms as Lua scripts.
+//
+// All timestamps are unix milliseconds, which is the resolution of every backend.
+package rate
+
+import (
+	"math"
+	"time"
+)
+
+// Decision is the outcome of a single rate-limit check.
+type Decision struct {
+	// Remaining is the number of units that can still be consumed right now.
+	Remaining int64
+	// RetryAfter is the time to wait before the same request can be allowed.
+	// It is zero when the request was allowed.
+	RetryAfter time.Duration
+	// ResetAfter is the time after which the limiter is back to its full capacity.
+	ResetAfter time.Duration
+	// Allowed reports whether the request was allowed (and its units consumed).
+	Allowed bool
+}
+
+// FixedWindow checks n units against a counter that resets every window.
+// count and resetAt describe the current window; a resetAt not after now means
+// there is no active window. It returns the new counter and window end.
+func FixedWindow(count, resetAt, now, n, limit int64, window time.Duration) (int64, int64, Decision) {
+	if resetAt <= now {
+		count, resetAt = 0, now+window.Milliseconds()
+	}
+
+	decision := Decision{ResetAfter: millis(resetAt - now)}
+	if count+n <= limit {
+		count += n
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = decision.ResetAfter
+	}
+	decision.Remaining = limit - count
+
+	return count, resetAt, decision
+}
+
+// SlidingWindowLog checks n units against the log of the timestamps consumed
+// during the last window. log must be sorted in ascending order; entries are
+// live while they are strictly newer than now - window. It returns the trimmed
+// (and, when allowed, extended) log.
+func SlidingWindowLog(log []int64, now, n, limit int64, window time.Duration) ([]int64, Decision) {
+	windowMillis := window.Milliseconds()
+
+	live := 0
+	for live < len(log) && log[live] <= now-windowMillis {
+		live++
+	}
+	log = log[live:]
+
+	var decision Decision
+	if int64(len(log))+n <= limit {
+		for range n {
+			log = append(log, now)
+		}
+		decision.Allowed = true
+	} else {
+		// The request fits once enough of the oldest entries have left the window.
+		oldest := log[int64(len(log))+n-limit-1]
+		decision.RetryAfter = millis(oldest + windowMillis - now)
+	}
+
+	decision.Remaining = limit - int64(len(log))
+	if len(log) > 0 {
+		decision.ResetAfter = millis(log[len(log)-1] + windowMillis - now)
+	}
+
+	return log, decision
+}
+
+// TokenBucket checks n units against a bucket of capacity tokens refilled with
+// one token every interval. tokens is the level of the bucket at updatedAt;
+// a bucket that is not found starts full. It returns the new level of the bucket.
+func TokenBucket(tokens float64, updatedAt int64, found bool, now, n, capacity int64, interval time.Duration) (float64, Decision) {
+	intervalMillis := float64(interval.Milliseconds())
+	if !found {
+		tokens, updatedAt = float64(capacity), now
+	}
+
+	elapsed := max(0, now-updatedAt)
+	tokens = math.Min(float64(capacity), tokens+float64(elapsed)/intervalMillis)
+
+	var decision Decision
+	if tokens >= float64(n) {
+		tokens -= float64(n)
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = millis(int64(math.Ceil((float64(n) - tokens) * intervalMillis)))
+	}
+
+	decision.Remaining = int64(math.Floor(tokens))
+	decision.ResetAfter = millis(int64(math.Ceil((float64(capacity) - tokens) * intervalMillis)))
+
+	return tokens, decision
+}
+
+// millis converts a number of milliseconds to a time.Duration.
+func millis(ms int64) time.Duration {
+	return time.Duration(ms) * time.Millisecond
+}
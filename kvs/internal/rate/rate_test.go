@@ -0,0 +1,66 @@
+package rate_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs/internal/rate"
+)
+
+func TestFixedWindow(t *testing.T) {
+	count, resetAt, decision := rate.FixedWindow(0, 0, 1000, 2, 3, time.Second)
+	require.Equal(t, int64(2), count)
+	require.Equal(t, int64(2000), resetAt)
+	require.Equal(t, rate.Decision{Allowed: true, Remaining: 1, ResetAfter: time.Second}, decision)
+
+	count, resetAt, decision = rate.FixedWindow(count, resetAt, 1400, 2, 3, time.Second)
+	require.Equal(t, int64(2), count)
+	require.Equal(t, int64(2000), resetAt)
+	require.Equal(t, rate.Decision{Remaining: 1, RetryAfter: 600 * time.Millisecond, ResetAfter: 600 * time.Millisecond}, decision)
+
+	count, resetAt, decision = rate.FixedWindow(count, resetAt, 2000, 2, 3, time.Second)
+	require.Equal(t, int64(2), count)
+	require.Equal(t, int64(3000), resetAt)
+	require.True(t, decision.Allowed)
+}
+
+func TestSlidingWindowLog(t *testing.T) {
+	log, decision := rate.SlidingWindowLog(nil, 1000, 1, 3, time.Second)
+	require.Equal(t, []int64{1000}, log)
+	require.Equal(t, rate.Decision{Allowed: true, Remaining: 2, ResetAfter: time.Second}, decision)
+
+	log, decision = rate.SlidingWindowLog(log, 1500, 2, 3, time.Second)
+	require.Equal(t, []int64{1000, 1500, 1500}, log)
+	require.Equal(t, rate.Decision{Allowed: true, Remaining: 0, ResetAfter: time.Second}, decision)
+
+	// Two units fit once the 1000 and one of the 1500 entries have left the window.
+	log, decision = rate.SlidingWindowLog(log, 1800, 2, 3, time.Second)
+	require.Len(t, log, 3)
+	require.False(t, decision.Allowed)
+	require.Equal(t, 700*time.Millisecond, decision.RetryAfter)
+	require.Equal(t, 700*time.Millisecond, decision.ResetAfter)
+
+	log, decision = rate.SlidingWindowLog(log, 2000, 1, 3, time.Second)
+	require.Equal(t, []int64{1500, 1500, 2000}, log)
+	require.True(t, decision.Allowed)
+	require.Equal(t, time.Second, decision.ResetAfter)
+}
+
+func TestTokenBucket(t *testing.T) {
+	tokens, decision := rate.TokenBucket(0, 0, false, 1000, 3, 4, 100*time.Millisecond)
+	require.InDelta(t, 1, tokens, 1e-9)
+	require.Equal(t, rate.Decision{Allowed: true, Remaining: 1, ResetAfter: 300 * time.Millisecond}, decision)
+
+	tokens, decision = rate.TokenBucket(tokens, 1000, true, 1050, 3, 4, 100*time.Millisecond)
+	require.InDelta(t, 1.5, tokens, 1e-9)
+	require.False(t, decision.Allowed)
+	require.Equal(t, int64(1), decision.Remaining)
+	require.Equal(t, 150*time.Millisecond, decision.RetryAfter)
+	require.Equal(t, 250*time.Millisecond, decision.ResetAfter)
+
+	tokens, decision = rate.TokenBucket(tokens, 1050, true, 5000, 0, 4, 100*time.Millisecond)
+	require.InDelta(t, 4, tokens, 1e-9)
+	require.Equal(t, rate.Decision{Allowed: true, Remaining: 4}, decision)
+}
@@ -8,38 +8,72 @@ import (
 
 // Item represents a key-value pair in the store.
 // It contains the key, the value, and an optional TTL (Time To Live).
+//
+// Codec, Version and CreatedAt are metadata filled by the backends that record
+// them (e.g. the Redis hash storage mode) when the item is read; they are zero
+// otherwise and ignored on writes.
 type Item struct {
 	// Value is the data stored in the item. It can be of any type.
 	Value any
 	// Key is the unique identifier for the item in the store.
 	Key string
+	// Codec names the encoding of the stored value (e.g. CodecJSON).
+	Codec string
 	// TTL is the Unix timestamp when the item will expire.
 	// If zero, the item does not expire.
 	TTL int64
+	// Version is the number of times the item has been written.
+	Version int64
+	// CreatedAt is the Unix timestamp of the first write of the item.
+	CreatedAt int64
 }
 
+// CodecJSON is the Item.Codec of JSON-encoded values, the encoding used by
+// every backend.
+const CodecJSON = "json"
+
 // NewItem creates a new Item with the specified key and value.
 // Optional TTL (Time To Live) in seconds can be provided to automatically expire the item.
 // The TTL is converted to a Unix timestamp by adding it to the current time.
 // Returns a pointer to the new Item.
 func NewItem(key string, value any, ttl ...time.Duration) *Item {
+	return NewItemWithClock(SystemClock, key, value, ttl...)
+}
+
+// NewItemWithClock is like NewItem, with the TTL added to the current time of clock.
+func NewItemWithClock(clock Clock, key string, value any, ttl ...time.Duration) *Item {
 	item := &Item{
 		Key:   key,
 		Value: value,
 	}
 
 	if len(ttl) > 0 && ttl[0] > 0 {
-		expiresAt := time.Now().Add(ttl[0]).Unix()
+		expiresAt := clockOrSystem(clock).Now().Add(ttl[0]).Unix()
 		item.TTL = expiresAt
 	}
 
 	return item
 }
 
+// ValueDecoder is implemented by the item values that a backend returns in a native
+// representation instead of a JSON string (e.g. the DynamoDB attribute-map storage).
+type ValueDecoder interface {
+	// DecodeValue decodes the value into out, a pointer.
+	DecodeValue(out any) error
+}
+
 // TryGetValueAsObjectType attempts to convert the item's value to the type of the provided output parameter.
-// The value is expected to be a JSON string that can be unmarshalled into the output parameter.
-// Returns ErrConvert if the value is not a string, or ErrMarshal if unmarshalling fails.
+// The value is expected to be a JSON string that can be unmarshalled into the output parameter,
+// or a ValueDecoder, which decodes itself.
+// Returns ErrConvert if the value is neither, or ErrMarshal if unmarshalling fails.
 func (r Item) TryGetValueAsObjectType(out any) error {
+	if decoder, ok := r.Value.(ValueDecoder); ok {
+		if err := decoder.DecodeValue(out); err != nil {
+			return ErrMarshal
+		}
+		return nil
+	}
+
 	value, ok := r.Value.(string)
 	if !ok {
 		return ErrConvert
@@ -31,3 +31,21 @@ func TestNewItem_Err(t *testing.T) {
 	var keyValueError kvs.KeyValueError
 	require.ErrorAs(t, err, &keyValueError)
 }
+
+type decoderFunc func(out any) error
+
+func (r decoderFunc) DecodeValue(out any) error { return r(out) }
+
+func TestItem_TryGetValueAsObjectType_ValueDecoder(t *testing.T) {
+	item := kvs.NewItem("key", decoderFunc(func(out any) error {
+		*out.(*string) = "decoded"
+		return nil
+	}))
+
+	var out string
+	require.NoError(t, item.TryGetValueAsObjectType(&out))
+	require.Equal(t, "decoded", out)
+
+	item.Value = decoderFunc(func(any) error { return assert.AnError })
+	require.ErrorIs(t, item.TryGetValueAsObjectType(&out), kvs.ErrMarshal)
+}
@@ -4,6 +4,7 @@ package kvs
 
 import (
 	"context"
+	"iter"
 	"time"
 )
 
@@ -45,4 +46,26 @@ type Client[T any] interface {
 
 	// BulkSaveWithContext is like BulkSave but with context support for cancellation and timeouts.
 	BulkSaveWithContext(ctx context.Context, items []T, keyMapper KeyMapperFunc[T], ttl ...time.Duration) error
+
+	// GetWithOptions is like GetWithContext with per-call options (see CallOptions).
+	// Returns ErrUnsupported if an option does not apply to reads.
+	GetWithOptions(ctx context.Context, key string, opts ...CallOption) (*T, error)
+
+	// BulkGetWithOptions is like BulkGetWithContext with per-call options (see CallOptions).
+	// Returns ErrUnsupported if an option does not apply to reads.
+	BulkGetWithOptions(ctx context.Context, keys []string, opts ...CallOption) ([]T, error)
+
+	// SaveWithOptions is like SaveWithContext with per-call options such as WithTTL,
+	// IfAbsent or WithTimeout (see CallOptions).
+	// Returns ErrUnsupported if an option does not apply to saves.
+	SaveWithOptions(ctx context.Context, key string, item *T, opts ...CallOption) error
+
+	// BulkSaveWithOptions is like BulkSaveWithContext with per-call options (see CallOptions).
+	// Returns ErrUnsupported if an option does not apply to bulk saves.
+	BulkSaveWithOptions(ctx context.Context, items []T, keyMapper KeyMapperFunc[T], opts ...CallOption) error
+
+	// All returns a sequence of every item stored in the container.
+	// Items are fetched lazily, page by page, while the sequence is consumed.
+	// An error is yielded at most once and terminates the sequence.
+	All(ctx context.Context) iter.Seq2[T, error]
 }
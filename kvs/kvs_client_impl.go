@@ -2,6 +2,7 @@ package kvs
 
 import (
 	"context"
+	"iter"
 	"time"
 )
 
@@ -16,15 +17,38 @@ import (
 //
 // The struct is parameterised over the value type T stored in the KVS.
 type KVSClient[T any] struct {
+	clock          Clock
 	lowLevelClient LowLevelClientProxy
 }
 
+// clientConfig holds the settings applied by ClientOptions.
+type clientConfig struct {
+	clock Clock
+}
+
+// ClientOptions configures a KVSClient. Used with the functional-options pattern.
+type ClientOptions func(*clientConfig)
+
+// WithClock returns a ClientOptions that sets the clock against which the TTLs of saved
+// items are computed (SystemClock by default). Backends read their own clock, which is
+// configured on their builder; tests should give both the same FakeClock.
+func WithClock(clock Clock) ClientOptions {
+	return func(c *clientConfig) { c.clock = clock }
+}
+
 // NewKVSClient creates a new KVSClient backed by the provided LowLevelClient.
 // The low-level client is wrapped in a LowLevelClientProxy so that metrics and
 // other cross-cutting concerns are applied uniformly across backends.
-func NewKVSClient[T any](lowLevelClient LowLevelClient) *KVSClient[T] {
+func NewKVSClient[T any](lowLevelClient LowLevelClient, opts ...ClientOptions) *KVSClient[T] {
+	config := &clientConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	clock := clockOrSystem(config.clock)
+
 	return &KVSClient[T]{
-		lowLevelClient: NewLowLevelClientProxy(lowLevelClient),
+		clock:          clock,
+		lowLevelClient: LowLevelClientProxy{lowLevelClient: lowLevelClient, clock: clock},
 	}
 }
 
@@ -63,7 +87,19 @@ func (r KVSClient[T]) BulkSave(items []T, keyMapper KeyMapperFunc[T], ttl ...tim
 // The context can be used for cancellation and timeouts.
 // Returns a pointer to the item if found, or an error if not found or if retrieval fails.
 func (r KVSClient[T]) GetWithContext(ctx context.Context, key string) (*T, error) {
-	item, err := r.lowLevelClient.GetWithContext(ctx, key)
+	return decodeValue[T](r.lowLevelClient.GetWithContext(ctx, key))
+}
+
+// BulkGetWithContext retrieves multiple items by their keys using the provided context.
+// The context can be used for cancellation and timeouts.
+// Returns a slice of items that were found, or an error if retrieval fails.
+// If unmarshalling of an individual item fails, it is skipped and an error is logged.
+func (r KVSClient[T]) BulkGetWithContext(ctx context.Context, keys []string) ([]T, error) {
+	return decodeItems[T](r.lowLevelClient.BulkGetWithContext(ctx, keys))
+}
+
+// decodeValue returns the value of an item read by Get, unmarshalled into T.
+func decodeValue[T any](item *Item, err error) (*T, error) {
 	if err != nil {
 		return nil, err
 	}
@@ -77,18 +113,14 @@ func (r KVSClient[T]) GetWithContext(ctx context.Context, key string) (*T, error
 	return value, nil
 }
 
-// BulkGetWithContext retrieves multiple items by their keys using the provided context.
-// The context can be used for cancellation and timeouts.
-// Returns a slice of items that were found, or an error if retrieval fails.
-// If unmarshalling of an individual item fails, it is skipped and an error is logged.
-func (r KVSClient[T]) BulkGetWithContext(ctx context.Context, keys []string) ([]T, error) {
-	result := make([]T, 0)
-
-	items, err := r.lowLevelClient.BulkGetWithContext(ctx, keys)
+// decodeItems returns the values of items read by BulkGet, unmarshalled into T.
+// Items whose value cannot be unmarshalled are skipped.
+func decodeItems[T any](items *Items, err error) ([]T, error) {
 	if err != nil {
 		return nil, err
 	}
 
+	result := make([]T, 0)
 	for item := range items.All() {
 		value := new(T)
 		mErr := item.TryGetValueAsObjectType(&value)
@@ -107,7 +139,7 @@ func (r KVSClient[T]) BulkGetWithContext(ctx context.Context, keys []string) ([]
 // Optional TTL can be provided to automatically expire the item.
 // Returns an error if the save operation fails.
 func (r KVSClient[T]) SaveWithContext(ctx context.Context, key string, value *T, ttl ...time.Duration) error {
-	item := NewItem(key, value, ttl...)
+	item := NewItemWithClock(r.clock, key, value, ttl...)
 	err := r.lowLevelClient.SaveWithContext(ctx, key, item)
 	if err != nil {
 		return err
@@ -127,16 +159,121 @@ func (r KVSClient[T]) BulkSaveWithContext(
 	keyMapper KeyMapperFunc[T],
 	ttl ...time.Duration,
 ) error {
-	kvsItems := new(Items)
-	for i := range items {
-		item := items[i]
-		kvsItems.Add(NewItem(keyMapper(item), &item, ttl...))
-	}
-
-	err := r.lowLevelClient.BulkSaveWithContext(ctx, kvsItems)
+	err := r.lowLevelClient.BulkSaveWithContext(ctx, newItems(r.clock, items, keyMapper, ttl...))
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// GetWithOptions retrieves an item by its key with per-call options (see CallOptions).
+// Returns ErrUnsupported if an option does not apply to reads.
+func (r KVSClient[T]) GetWithOptions(ctx context.Context, key string, opts ...CallOption) (*T, error) {
+	return decodeValue[T](r.lowLevelClient.GetWithOptions(ctx, key, opts...))
+}
+
+// BulkGetWithOptions retrieves multiple items by their keys with per-call options (see CallOptions).
+// Items whose value cannot be unmarshalled are skipped, as in BulkGetWithContext.
+// Returns ErrUnsupported if an option does not apply to reads.
+func (r KVSClient[T]) BulkGetWithOptions(ctx context.Context, keys []string, opts ...CallOption) ([]T, error) {
+	return decodeItems[T](r.lowLevelClient.BulkGetWithOptions(ctx, keys, opts...))
+}
+
+// SaveWithOptions stores an item with the specified key with per-call options, such as
+// WithTTL, IfAbsent or WithTimeout (see CallOptions).
+// Returns ErrConditionFailed if IfAbsent is set and the key is taken, and ErrUnsupported
+// if an option does not apply to saves.
+func (r KVSClient[T]) SaveWithOptions(ctx context.Context, key string, value *T, opts ...CallOption) error {
+	return r.lowLevelClient.SaveWithOptions(ctx, key, NewItemWithClock(r.clock, key, value), opts...)
+}
+
+// BulkSaveWithOptions stores multiple items with per-call options (see CallOptions).
+// The keyMapper function is used to extract the key from each item.
+// Returns ErrUnsupported if an option does not apply to bulk saves.
+func (r KVSClient[T]) BulkSaveWithOptions(
+	ctx context.Context,
+	items []T,
+	keyMapper KeyMapperFunc[T],
+	opts ...CallOption,
+) error {
+	return r.lowLevelClient.BulkSaveWithOptions(ctx, newItems(r.clock, items, keyMapper), opts...)
+}
+
+// newItems returns the Items of values, keyed by keyMapper, whose TTL is counted from
+// the current time of clock.
+func newItems[T any](clock Clock, values []T, keyMapper KeyMapperFunc[T], ttl ...time.Duration) *Items {
+	items := new(Items)
+	for i := range values {
+		value := values[i]
+		items.Add(NewItemWithClock(clock, keyMapper(value), &value, ttl...))
+	}
+	return items
+}
+
+// All returns a sequence of every item stored in the container.
+// The underlying scan is paginated, so the container is never loaded into memory at once.
+// Items whose value cannot be unmarshalled into T are skipped, consistent with BulkGetWithContext.
+// Breaking out of the loop stops the scan.
+func (r KVSClient[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return decodeValues[T](r.lowLevelClient.Scan(ctx, ScanOptions{}))
+}
+
+// Query returns a sequence of the values of a partition whose sort key matches condition,
+// in sort key order (see Querier). Values that cannot be unmarshalled into T are skipped,
+// consistent with All. Yields ErrUnsupported if the backend cannot query partitions.
+func (r KVSClient[T]) Query(
+	ctx context.Context,
+	partition string,
+	condition SortCondition,
+	opts QueryOptions,
+) iter.Seq2[T, error] {
+	return decodeValues[T](r.lowLevelClient.Query(ctx, partition, condition, opts))
+}
+
+// QueryPage returns a single page of the values of Query, and the token resuming the query
+// after it, empty on the last page. Returns ErrUnsupported if the backend cannot query partitions.
+func (r KVSClient[T]) QueryPage(
+	ctx context.Context,
+	partition string,
+	condition SortCondition,
+	opts QueryOptions,
+) ([]T, string, error) {
+	items, token, err := r.lowLevelClient.QueryPage(ctx, partition, condition, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	values, err := decodeItems[T](items, nil)
+	return values, token, err
+}
+
+// QueryIndex returns a sequence of the values whose key in the secondary index is value
+// (see IndexQuerier). Values that cannot be unmarshalled into T are skipped, consistent
+// with All. Yields ErrUnsupported if the backend has no secondary indexes.
+func (r KVSClient[T]) QueryIndex(ctx context.Context, index, value string) iter.Seq2[T, error] {
+	return decodeValues[T](r.lowLevelClient.QueryIndex(ctx, index, value))
+}
+
+// decodeValues returns the values of a sequence of items, unmarshalled into T. Items whose
+// value cannot be unmarshalled are skipped; an error is yielded once and ends the sequence.
+func decodeValues[T any](items iter.Seq2[*Item, error]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for item, err := range items {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			value := new(T)
+			if mErr := item.TryGetValueAsObjectType(&value); mErr != nil {
+				continue
+			}
+
+			if !yield(*value, nil) {
+				return
+			}
+		}
+	}
+}
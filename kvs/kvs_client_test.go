@@ -197,3 +197,40 @@ func TestKVSClient_BulkSaveWithContext(t *testing.T) {
 	require.NotNil(t, result)
 	require.Len(t, result, 2)
 }
+
+func TestKVSClient_All(t *testing.T) {
+	lowLevelClient := dynamodb.NewLowLevelClient(dynamodb.NewAWSFakeClient(), "__kvs-test")
+	kvsClient := kvs.NewKVSClient[model.UserDTO](lowLevelClient)
+
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, kvsClient.Save(strconv.Itoa(i), &model.UserDTO{ID: i}))
+	}
+
+	ids := make([]int, 0, 5)
+	for user, err := range kvsClient.All(t.Context()) {
+		require.NoError(t, err)
+		ids = append(ids, user.ID)
+	}
+
+	require.ElementsMatch(t, []int{1, 2, 3, 4, 5}, ids)
+}
+
+func TestKVSClient_All_StopsOnBreak(t *testing.T) {
+	lowLevelClient := dynamodb.NewLowLevelClient(dynamodb.NewAWSFakeClient(), "__kvs-test")
+	kvsClient := kvs.NewKVSClient[model.UserDTO](lowLevelClient)
+
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, kvsClient.Save(strconv.Itoa(i), &model.UserDTO{ID: i}))
+	}
+
+	count := 0
+	for _, err := range kvsClient.All(t.Context()) {
+		require.NoError(t, err)
+		count++
+		if count == 2 {
+			break
+		}
+	}
+
+	require.Equal(t, 2, count)
+}
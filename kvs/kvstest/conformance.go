@@ -0,0 +1,484 @@
+// Package kvstest provides a conformance suite for kvs.LowLevelClient implementations.
+package kvstest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// DefaultMaxBulkKeys is the maximum number of keys of a BulkGet assumed when
+// Backend.MaxBulkKeys is zero, the limit of the backends of this module.
+const DefaultMaxBulkKeys = 100
+
+// Backend is a kvs.LowLevelClient under test, along with the hooks the suite
+// needs to control it.
+type Backend struct {
+	// Client is the client under test. It must hold no items.
+	Client kvs.LowLevelClient
+	// Now returns the current time of the clock against which the TTLs of the
+	// items are set. time.Now is used when nil.
+	Now func() time.Time
+	// Advance moves the clock of the backend forward by d, expiring the items
+	// whose TTL elapses. The TTL rules are skipped when nil.
+	Advance func(d time.Duration)
+	// MaxBulkKeys is the maximum number of keys of a BulkGet, or
+	// DefaultMaxBulkKeys when zero.
+	MaxBulkKeys int
+}
+
+// Factory returns a new, empty Backend whose default TTL is ttl, zero meaning
+// that items without a TTL never expire. Resources held by the backend are
+// released through t.Cleanup.
+type Factory func(t *testing.T, ttl time.Duration) Backend
+
+// user is the value stored by the suite.
+type user struct {
+	Name string `json:"name"`
+	ID   int    `json:"id"`
+}
+
+// RunConformance runs the conformance suite against the backends built by
+// factory, as subtests of t. Each subtest builds its own backend.
+func RunConformance(t *testing.T, factory Factory) {
+	t.Helper()
+
+	rules := []struct {
+		name string
+		run  func(t *testing.T, factory Factory)
+	}{
+		{"NotFound", testNotFound},
+		{"EmptyKeys", testEmptyKeys},
+		{"RoundTrip", testRoundTrip},
+		{"TTL", testTTL},
+		{"ExpiredScansAndQueries", testExpiredScansAndQueries},
+		{"CounterTTL", testCounterTTL},
+		{"BulkLimits", testBulkLimits},
+		{"BulkOrdering", testBulkOrdering},
+		{"ConditionalWrites", testConditionalWrites},
+		{"Context", testContext},
+		{"Concurrency", testConcurrency},
+	}
+	for _, rule := range rules {
+		t.Run(rule.name, func(t *testing.T) {
+			rule.run(t, factory)
+		})
+	}
+}
+
+// testNotFound verifies that missing keys are reported by ErrKeyNotFound on
+// reads of one key, skipped by bulk reads and ignored by deletes.
+func testNotFound(t *testing.T, factory Factory) {
+	ctx := t.Context()
+	client := factory(t, 0).Client
+
+	item, err := client.Get("missing")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+	require.Nil(t, item)
+	_, err = client.GetWithContext(ctx, "missing")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+	_, err = client.GetFields(ctx, "missing", "name")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+	require.ErrorIs(t, client.Patch(ctx, "missing", map[string]any{"name": "John"}), kvs.ErrKeyNotFound)
+
+	items, err := client.BulkGet([]string{"missing", "absent"})
+	require.NoError(t, err)
+	require.Equal(t, 0, items.Len())
+
+	require.NoError(t, client.Delete("missing"))
+	require.NoError(t, client.Save("1", kvs.NewItem("1", user{ID: 1})))
+	require.NoError(t, client.DeleteWithContext(ctx, "1"))
+	_, err = client.Get("1")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+}
+
+// testEmptyKeys verifies that empty keys and nil items are rejected by the
+// operations on one key, and skipped by the bulk operations.
+func testEmptyKeys(t *testing.T, factory Factory) {
+	ctx := t.Context()
+	client := factory(t, 0).Client
+
+	for _, key := range []string{"", " "} {
+		_, err := client.Get(key)
+		require.ErrorIs(t, err, kvs.ErrEmptyKey)
+		require.ErrorIs(t, client.Save(key, kvs.NewItem(key, user{})), kvs.ErrEmptyKey)
+		require.ErrorIs(t, client.Delete(key), kvs.ErrEmptyKey)
+		require.ErrorIs(t, client.SaveIfAbsent(ctx, key, kvs.NewItem(key, user{})), kvs.ErrEmptyKey)
+	}
+	require.ErrorIs(t, client.Save("1", nil), kvs.ErrNilItem)
+
+	require.NoError(t, client.BulkSave(nil))
+	require.NoError(t, client.BulkSave(new(kvs.Items)))
+
+	items := new(kvs.Items)
+	items.Add(kvs.NewItem("", user{}))
+	items.Add(nil)
+	items.Add(kvs.NewItem("1", user{ID: 1}))
+	require.NoError(t, client.BulkSave(items))
+
+	found, err := client.BulkGet([]string{"", "1"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"1"}, keysOf(found))
+
+	found, err = client.BulkGet(nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, found.Len())
+}
+
+// testRoundTrip verifies that items read back hold the key and value saved,
+// and that a save replaces the item stored under the key.
+func testRoundTrip(t *testing.T, factory Factory) {
+	client := factory(t, 0).Client
+
+	require.NoError(t, client.Save("1", kvs.NewItem("1", user{ID: 1, Name: "John"})))
+	item, err := client.Get("1")
+	require.NoError(t, err)
+	require.Equal(t, "1", item.Key)
+	require.Equal(t, user{ID: 1, Name: "John"}, decode(t, item))
+
+	require.NoError(t, client.Save("1", kvs.NewItem("1", user{ID: 1, Name: "Jane"})))
+	item, err = client.Get("1")
+	require.NoError(t, err)
+	require.Equal(t, user{ID: 1, Name: "Jane"}, decode(t, item))
+
+	items := new(kvs.Items)
+	items.Add(kvs.NewItem("2", user{ID: 2, Name: "Joe"}))
+	items.Add(kvs.NewItem("2", user{ID: 2, Name: "Jim"}))
+	require.NoError(t, client.BulkSave(items))
+	item, err = client.Get("2")
+	require.NoError(t, err)
+	require.Equal(t, user{ID: 2, Name: "Jim"}, decode(t, item))
+}
+
+// testTTL verifies that items expire once their TTL, or else the default TTL,
+// elapses: they are then missing for every read and absent for SaveIfAbsent.
+func testTTL(t *testing.T, factory Factory) {
+	ctx := t.Context()
+	backend := factory(t, time.Minute)
+	if backend.Advance == nil {
+		t.Skip("the backend clock cannot be advanced")
+	}
+	client, now := backend.Client, backend.now()
+
+	require.NoError(t, client.Save("default", kvs.NewItem("default", user{ID: 1})))
+	require.NoError(t, client.Save("hour", &kvs.Item{Key: "hour", Value: user{ID: 2}, TTL: now.Add(time.Hour).Unix()}))
+	require.NoError(t, client.Save("past", &kvs.Item{Key: "past", Value: user{ID: 3}, TTL: now.Add(-time.Hour).Unix()}))
+	items := new(kvs.Items)
+	items.Add(kvs.NewItem("bulk", user{ID: 4}))
+	require.NoError(t, client.BulkSave(items))
+
+	_, err := client.Get("past")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+	found, err := client.BulkGet([]string{"default", "hour", "past", "bulk"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"default", "hour", "bulk"}, keysOf(found))
+
+	backend.Advance(2 * time.Minute)
+
+	for _, key := range []string{"default", "bulk"} {
+		_, err = client.GetWithContext(ctx, key)
+		require.ErrorIs(t, err, kvs.ErrKeyNotFound, key)
+	}
+	found, err = client.BulkGet([]string{"default", "hour", "bulk"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"hour"}, keysOf(found))
+	require.NoError(t, client.SaveIfAbsent(ctx, "default", kvs.NewItem("default", user{ID: 5})))
+
+	backend.Advance(time.Hour)
+
+	_, err = client.Get("hour")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+
+	persistent := factory(t, 0)
+	require.NoError(t, persistent.Client.Save("1", kvs.NewItem("1", user{ID: 1})))
+	persistent.Advance(24 * time.Hour)
+	_, err = persistent.Client.Get("1")
+	require.NoError(t, err)
+}
+
+// testExpiredScansAndQueries verifies that expired items are skipped by Scan,
+// sequential, parallel or keys only, and by Query and QueryPage when the client
+// is a kvs.Querier that does not return kvs.ErrUnsupported, so that KVSClient.All
+// and Query never return them.
+func testExpiredScansAndQueries(t *testing.T, factory Factory) {
+	ctx := t.Context()
+	backend := factory(t, time.Minute)
+	if backend.Advance == nil {
+		t.Skip("the backend clock cannot be advanced")
+	}
+	client, now := backend.Client, backend.now()
+
+	require.NoError(t, client.Save("default", kvs.NewItem("default", user{ID: 1})))
+	require.NoError(t, client.Save("hour", &kvs.Item{Key: "hour", Value: user{ID: 2}, TTL: now.Add(time.Hour).Unix()}))
+
+	backend.Advance(2 * time.Minute)
+
+	for _, opts := range []kvs.ScanOptions{{}, {Segments: 2}, {KeysOnly: true}} {
+		var keys []string
+		for item, err := range client.Scan(ctx, opts) {
+			require.NoError(t, err)
+			keys = append(keys, item.Key)
+		}
+		require.Equal(t, []string{"hour"}, keys, "%+v", opts)
+	}
+
+	var values []user
+	for value, err := range kvs.NewKVSClient[user](client).All(ctx) {
+		require.NoError(t, err)
+		values = append(values, value)
+	}
+	require.Equal(t, []user{{ID: 2}}, values)
+
+	querier, ok := client.(kvs.Querier)
+	if !ok {
+		return
+	}
+	for key, want := range map[string][]string{"default": nil, "hour": {"hour"}} {
+		page, _, err := querier.QueryPage(ctx, key, kvs.SortCondition{}, kvs.QueryOptions{})
+		if errors.Is(err, kvs.ErrUnsupported) {
+			return
+		}
+		require.NoError(t, err)
+		require.Equal(t, len(want), page.Len(), key)
+
+		var keys []string
+		for item, err := range querier.Query(ctx, key, kvs.SortCondition{}, kvs.QueryOptions{}) {
+			require.NoError(t, err)
+			keys = append(keys, item.Key)
+		}
+		require.Equal(t, want, keys, key)
+	}
+}
+
+// testCounterTTL verifies that a counter expires once the TTL set at its creation
+// elapses, and that the next increment restarts it from the delta with a new TTL.
+func testCounterTTL(t *testing.T, factory Factory) {
+	ctx := t.Context()
+	backend := factory(t, 0)
+	if backend.Advance == nil {
+		t.Skip("the backend clock cannot be advanced")
+	}
+	client := backend.Client
+
+	count, err := client.Increment(ctx, "hits", 5, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), count)
+
+	backend.Advance(2 * time.Minute)
+
+	_, err = client.Get("hits")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+	count, err = client.Increment(ctx, "hits", 1, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+	item, err := client.Get("hits")
+	require.NoError(t, err)
+	var stored int64
+	require.NoError(t, item.TryGetValueAsObjectType(&stored))
+	require.Equal(t, int64(1), stored)
+
+	backend.Advance(30 * time.Second)
+
+	count, err = client.Increment(ctx, "hits", 1, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count, "the TTL is set when the counter is created")
+
+	backend.Advance(time.Minute)
+
+	_, err = client.Get("hits")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+}
+
+// testBulkLimits verifies that BulkGet rejects more than MaxBulkKeys keys with
+// ErrTooManyKeys, while BulkSave accepts any number of items.
+func testBulkLimits(t *testing.T, factory Factory) {
+	backend := factory(t, 0)
+	client, limit := backend.Client, backend.maxBulkKeys()
+
+	keys := make([]string, 0, limit+1)
+	items := new(kvs.Items)
+	for i := range 2*limit + limit/2 {
+		key := strconv.Itoa(i)
+		items.Add(kvs.NewItem(key, user{ID: i}))
+		if len(keys) < cap(keys) {
+			keys = append(keys, key)
+		}
+	}
+	require.NoError(t, client.BulkSave(items))
+
+	_, err := client.BulkGet(keys)
+	require.ErrorIs(t, err, kvs.ErrTooManyKeys)
+
+	found, err := client.BulkGet(keys[:limit])
+	require.NoError(t, err)
+	require.Equal(t, keys[:limit], keysOf(found))
+
+	last := strconv.Itoa(items.Len() - 1)
+	item, err := client.Get(last)
+	require.NoError(t, err)
+	require.Equal(t, last, item.Key)
+}
+
+// testBulkOrdering verifies that BulkGet returns the items found in the order
+// of the keys, once per key.
+func testBulkOrdering(t *testing.T, factory Factory) {
+	client := factory(t, 0).Client
+
+	items := new(kvs.Items)
+	for i := range 10 {
+		key := strconv.Itoa(i)
+		items.Add(kvs.NewItem(key, user{ID: i}))
+	}
+	require.NoError(t, client.BulkSaveWithContext(t.Context(), items))
+
+	keys := []string{"7", "missing", "2", "9", "0", "2", "5", "7"}
+	found, err := client.BulkGetWithContext(t.Context(), keys)
+	require.NoError(t, err)
+	require.Equal(t, []string{"7", "2", "9", "0", "5"}, keysOf(found))
+	for item := range found.All() {
+		id, _ := strconv.Atoi(item.Key)
+		require.Equal(t, user{ID: id}, decode(t, item))
+	}
+}
+
+// testConditionalWrites verifies SaveIfAbsent, CompareAndSwap and
+// CompareAndDelete.
+func testConditionalWrites(t *testing.T, factory Factory) {
+	ctx := t.Context()
+	client := factory(t, 0).Client
+
+	require.NoError(t, client.SaveIfAbsent(ctx, "1", kvs.NewItem("1", user{ID: 1, Name: "John"})))
+	require.ErrorIs(t, client.SaveIfAbsent(ctx, "1", kvs.NewItem("1", user{ID: 1})), kvs.ErrConditionFailed)
+
+	stored, err := client.Get("1")
+	require.NoError(t, err)
+	require.NoError(t, client.CompareAndSwap(ctx, "1", stored, kvs.NewItem("1", user{ID: 1, Name: "Jane"})))
+	require.ErrorIs(t, client.CompareAndSwap(ctx, "1", stored, kvs.NewItem("1", user{ID: 1})), kvs.ErrConditionFailed)
+	require.ErrorIs(t, client.CompareAndDelete(ctx, "1", stored), kvs.ErrConditionFailed)
+	require.ErrorIs(t, client.CompareAndSwap(ctx, "2", stored, kvs.NewItem("2", user{ID: 2})), kvs.ErrConditionFailed)
+
+	current, err := client.Get("1")
+	require.NoError(t, err)
+	require.Equal(t, user{ID: 1, Name: "Jane"}, decode(t, current))
+	require.NoError(t, client.CompareAndDelete(ctx, "1", current))
+	_, err = client.Get("1")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+}
+
+// testContext verifies that reads and writes fail with the error of a canceled
+// context.
+func testContext(t *testing.T, factory Factory) {
+	client := factory(t, 0).Client
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, err := client.GetWithContext(ctx, "1")
+	require.ErrorIs(t, err, context.Canceled)
+	require.Error(t, client.SaveWithContext(ctx, "1", kvs.NewItem("1", user{ID: 1})))
+}
+
+// testConcurrency verifies that the client is safe for concurrent use: writes
+// of distinct keys are all applied, reads see whole items and counters lose no
+// increment. Run the suite with -race to detect data races.
+func testConcurrency(t *testing.T, factory Factory) {
+	const workers, rounds = 8, 20
+
+	ctx := t.Context()
+	client := factory(t, 0).Client
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		failures []error
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		failures = append(failures, err)
+	}
+	for worker := range workers {
+		wg.Go(func() {
+			for round := range rounds {
+				key := fmt.Sprintf("%d-%d", worker, round)
+				if err := client.Save(key, kvs.NewItem(key, user{ID: round})); err != nil {
+					fail(err)
+					continue
+				}
+				if err := client.Save("shared", kvs.NewItem("shared", user{ID: worker})); err != nil {
+					fail(err)
+				}
+				if _, err := client.Get("shared"); err != nil {
+					fail(err)
+				}
+				if _, err := client.BulkGet([]string{key, "shared"}); err != nil {
+					fail(err)
+				}
+				if _, err := client.Increment(ctx, "counter", 1, 0); err != nil {
+					fail(err)
+				}
+			}
+		})
+	}
+	wg.Wait()
+	require.Empty(t, failures)
+
+	for worker := range workers {
+		keys := make([]string, 0, rounds)
+		for round := range rounds {
+			keys = append(keys, fmt.Sprintf("%d-%d", worker, round))
+		}
+		found, err := client.BulkGet(keys)
+		require.NoError(t, err)
+		require.Equal(t, keys, keysOf(found))
+	}
+
+	shared, err := client.Get("shared")
+	require.NoError(t, err)
+	require.Less(t, decode(t, shared).ID, workers)
+
+	count, err := client.Increment(ctx, "counter", 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(workers*rounds), count)
+}
+
+// now returns the current time of the clock of the backend.
+func (r Backend) now() time.Time {
+	if r.Now == nil {
+		return time.Now()
+	}
+	return r.Now()
+}
+
+// maxBulkKeys returns the maximum number of keys of a BulkGet.
+func (r Backend) maxBulkKeys() int {
+	if r.MaxBulkKeys == 0 {
+		return DefaultMaxBulkKeys
+	}
+	return r.MaxBulkKeys
+}
+
+// keysOf returns the keys of items, in order.
+func keysOf(items *kvs.Items) []string {
+	keys := make([]string, 0, items.Len())
+	for item := range items.All() {
+		keys = append(keys, item.Key)
+	}
+	return keys
+}
+
+// decode returns the user stored in item.
+func decode(t *testing.T, item *kvs.Item) user {
+	t.Helper()
+
+	var value user
+	require.NoError(t, item.TryGetValueAsObjectType(&value))
+	return value
+}
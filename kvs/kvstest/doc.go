@@ -0,0 +1,28 @@
+// Package kvstest provides a conformance suite for kvs.LowLevelClient implementations.
+//
+// RunConformance verifies the contract documented by kvs.LowLevelClient against a
+// backend built by a Factory, so that every backend behaves the same way behind
+// kvs.KVSClient[T]. The suite runs against the in-memory fakes and miniredis in this
+// module, and third-party backends can run it from their own tests.
+//
+// Key Components:
+//   - RunConformance: runs the suite, one subtest per group of rules: not-found
+//     semantics, empty keys and nil items, round trips, TTL expiry of items and
+//     counters, bulk limits and ordering, conditional writes and concurrency safety.
+//   - Factory: builds a fresh, empty Backend for each subtest.
+//   - Backend: the client under test, along with the hooks controlling its clock.
+//     The TTL rules are skipped when the backend cannot advance its clock.
+//
+// Usage:
+//
+//	func TestConformance(t *testing.T) {
+//	    kvstest.RunConformance(t, func(t *testing.T, ttl time.Duration) kvstest.Backend {
+//	        clock := kvs.NewFakeClock(time.Now())
+//	        return kvstest.Backend{
+//	            Client:  newMyClient(t, ttl, clock),
+//	            Now:     clock.Now,
+//	            Advance: clock.Advance,
+//	        }
+//	    })
+//	}
+package kvstest
@@ -0,0 +1,36 @@
+// Package lock provides a distributed lock built on top of the kvs backends.
+//
+// A Locker hands out leases on named locks. Each lease is owned by a random
+// token, so only the holder can refresh or release it, and carries a fencing
+// token that increases with every successful acquisition of the same name.
+// Downstream systems can reject writes carrying a fencing token lower than the
+// last one they have seen, which protects against holders that were paused past
+// their lease.
+//
+// Key Components:
+//   - Locker: acquires locks and, by default, renews their lease in a goroutine.
+//   - Lock: a held lease, with Refresh, Release and a Lost channel.
+//   - Backend: the storage contract implemented by RedisBackend (Lua scripts
+//     on a go-redis Scripter: the lock write with its fencing INCR, and
+//     owner-checked refresh and delete),
+//     DynamoDBBackend (conditional writes with a lease expiry attribute) and
+//     FakeBackend (in-memory, for tests).
+//
+// Usage:
+//
+//	locker := lock.NewLocker(
+//	    lock.NewRedisBackend(universalClient, "__kvs:locks"),
+//	)
+//
+//	held, err := locker.Acquire(ctx, "nightly-report", 30*time.Second)
+//	if err != nil {
+//	    // Handle error (ctx expired while waiting for the lock, backend failure, ...)
+//	}
+//	defer held.Release(context.Background())
+//
+//	select {
+//	case <-held.Lost():
+//	    // The lease could not be renewed; stop working.
+//	default:
+//	}
+package lock
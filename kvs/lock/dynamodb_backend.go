@@ -0,0 +1,218 @@
+// Package lock provides a distributed lock built on top of the kvs backends.
+package lock
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	kvsdynamodb "github.com/arielsrv/go-kvs-client/kvs/dynamodb"
+)
+
+// Constants for the DynamoDB lock attribute names.
+const (
+	OwnerName   = "owner"   // Attribute name for the token of the current holder
+	ExpiresName = "expires" // Attribute name for the lease expiration, in unix milliseconds
+	FenceName   = "fence"   // Attribute name for the last issued fencing token
+)
+
+// DynamoDBBackend is the DynamoDB implementation of Backend.
+//
+// Each lock is an item keyed by the lock name in the "key" attribute, so the
+// table can be shared with (or created like) a regular kvs table. Tables with
+// other attribute names, or a composite primary key, are configured with
+// WithDynamoDBAttributeNames: lock names are then split into the partition and
+// sort key values by the KeyCodec of WithDynamoDBKeyCodec, as kvs keys. Every
+// operation is a single conditional UpdateItem: Acquire succeeds only when the
+// item has no owner or its lease has expired, and increments the fence
+// attribute in the same write. Release removes the owner but keeps the item,
+// so fencing tokens stay monotonic across acquisitions. Lock items are never
+// given the table TTL attribute.
+type DynamoDBBackend struct {
+	client    kvsdynamodb.AWSClient
+	clock     kvs.Clock
+	codec     kvsdynamodb.KeyCodec
+	names     kvsdynamodb.AttributeNames
+	tableName string
+}
+
+// DynamoDBBackendOptions configures a DynamoDBBackend.
+type DynamoDBBackendOptions func(*DynamoDBBackend)
+
+// WithDynamoDBClock returns a DynamoDBBackendOptions that sets the clock used to
+// compute and check lease expirations (kvs.SystemClock by default).
+func WithDynamoDBClock(clock kvs.Clock) DynamoDBBackendOptions {
+	return func(b *DynamoDBBackend) { b.clock = clock }
+}
+
+// WithDynamoDBAttributeNames returns a DynamoDBBackendOptions that sets the key attribute
+// names of the table (kvsdynamodb.KeyName, with no sort key, by default). Only Key and
+// SortKey are used.
+func WithDynamoDBAttributeNames(names kvsdynamodb.AttributeNames) DynamoDBBackendOptions {
+	return func(b *DynamoDBBackend) { b.names = names }
+}
+
+// WithDynamoDBKeyCodec returns a DynamoDBBackendOptions that sets how lock names are split
+// into the partition and sort key values of a composite primary key (a
+// kvsdynamodb.SeparatorKeyCodec on kvsdynamodb.DefaultKeySeparator by default).
+func WithDynamoDBKeyCodec(codec kvsdynamodb.KeyCodec) DynamoDBBackendOptions {
+	return func(b *DynamoDBBackend) { b.codec = codec }
+}
+
+// NewDynamoDBBackend creates a new DynamoDBBackend storing locks in the provided table.
+func NewDynamoDBBackend(
+	client kvsdynamodb.AWSClient,
+	tableName string,
+	opts ...DynamoDBBackendOptions,
+) *DynamoDBBackend {
+	backend := &DynamoDBBackend{
+		client:    client,
+		clock:     kvs.SystemClock,
+		codec:     kvsdynamodb.SeparatorKeyCodec{Separator: kvsdynamodb.DefaultKeySeparator},
+		names:     kvsdynamodb.AttributeNames{Key: kvsdynamodb.KeyName},
+		tableName: tableName,
+	}
+	for _, opt := range opts {
+		opt(backend)
+	}
+	if backend.names.Key == "" {
+		backend.names.Key = kvsdynamodb.KeyName
+	}
+	return backend
+}
+
+// Acquire implements Backend.
+func (r *DynamoDBBackend) Acquire(ctx context.Context, name, owner string, lease time.Duration) (int64, error) {
+	key, err := r.key(name)
+	if err != nil {
+		return 0, err
+	}
+
+	now := r.clock.Now()
+	output, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(r.tableName),
+		Key:                 key,
+		UpdateExpression:    aws.String("SET #owner = :owner, #expires = :expires ADD #fence :one"),
+		ConditionExpression: aws.String("attribute_not_exists(#owner) OR #expires <= :now"),
+		ExpressionAttributeNames: map[string]string{
+			"#owner":   OwnerName,
+			"#expires": ExpiresName,
+			"#fence":   FenceName,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":owner":   &types.AttributeValueMemberS{Value: owner},
+			":expires": unixMilli(now.Add(lease)),
+			":now":     unixMilli(now),
+			":one":     &types.AttributeValueMemberN{Value: "1"},
+		},
+		ReturnValues: types.ReturnValueUpdatedNew,
+	})
+	if err != nil {
+		return 0, conditionError(err, ErrNotAcquired)
+	}
+
+	fence, ok := output.Attributes[FenceName].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, kvs.ErrConvert
+	}
+
+	return strconv.ParseInt(fence.Value, 10, 64)
+}
+
+// Refresh implements Backend.
+func (r *DynamoDBBackend) Refresh(ctx context.Context, name, owner string, lease time.Duration) error {
+	key, err := r.key(name)
+	if err != nil {
+		return err
+	}
+
+	now := r.clock.Now()
+	_, err = r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(r.tableName),
+		Key:                 key,
+		UpdateExpression:    aws.String("SET #expires = :expires"),
+		ConditionExpression: aws.String("#owner = :owner AND #expires > :now"),
+		ExpressionAttributeNames: map[string]string{
+			"#owner":   OwnerName,
+			"#expires": ExpiresName,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":owner":   &types.AttributeValueMemberS{Value: owner},
+			":expires": unixMilli(now.Add(lease)),
+			":now":     unixMilli(now),
+		},
+	})
+	if err != nil {
+		return conditionError(err, ErrNotHeld)
+	}
+
+	return nil
+}
+
+// Release implements Backend.
+func (r *DynamoDBBackend) Release(ctx context.Context, name, owner string) error {
+	key, err := r.key(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(r.tableName),
+		Key:                 key,
+		UpdateExpression:    aws.String("REMOVE #owner, #expires"),
+		ConditionExpression: aws.String("#owner = :owner AND #expires > :now"),
+		ExpressionAttributeNames: map[string]string{
+			"#owner":   OwnerName,
+			"#expires": ExpiresName,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":owner": &types.AttributeValueMemberS{Value: owner},
+			":now":   unixMilli(r.clock.Now()),
+		},
+	})
+	if err != nil {
+		return conditionError(err, ErrNotHeld)
+	}
+
+	return nil
+}
+
+// key returns the primary key of the lock item.
+// Returns kvsdynamodb.ErrKeySchema if name does not fit a composite primary key.
+func (r *DynamoDBBackend) key(name string) (map[string]types.AttributeValue, error) {
+	if r.names.SortKey == "" {
+		return map[string]types.AttributeValue{
+			r.names.Key: &types.AttributeValueMemberS{Value: name},
+		}, nil
+	}
+
+	partition, sort, err := r.codec.Split(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]types.AttributeValue{
+		r.names.Key:     &types.AttributeValueMemberS{Value: partition},
+		r.names.SortKey: &types.AttributeValueMemberS{Value: sort},
+	}, nil
+}
+
+// unixMilli encodes t as a DynamoDB number of milliseconds since the epoch.
+func unixMilli(t time.Time) *types.AttributeValueMemberN {
+	return &types.AttributeValueMemberN{Value: strconv.FormatInt(t.UnixMilli(), 10)}
+}
+
+// conditionError maps a failed condition check to target and returns any other error unchanged.
+func conditionError(err error, target error) error {
+	var conditionalCheckFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionalCheckFailed) {
+		return target
+	}
+	return err
+}
@@ -0,0 +1,138 @@
+package lock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsdynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	kvsdynamodb "github.com/arielsrv/go-kvs-client/kvs/dynamodb"
+	"github.com/arielsrv/go-kvs-client/kvs/lock"
+	mockdb "github.com/arielsrv/go-kvs-client/resources/mocks/kvs/dynamodb"
+)
+
+func TestDynamoDBBackend_Acquire_ReturnsFence(t *testing.T) {
+	clock := kvs.NewFakeClock(time.UnixMilli(1_700_000_000_000))
+	awsMock := mockdb.NewMockAWSClient(t)
+	awsMock.EXPECT().
+		UpdateItem(mock.Anything, mock.MatchedBy(func(input *awsdynamodb.UpdateItemInput) bool {
+			owner, _ := input.ExpressionAttributeValues[":owner"].(*types.AttributeValueMemberS)
+			now, _ := input.ExpressionAttributeValues[":now"].(*types.AttributeValueMemberN)
+			expires, _ := input.ExpressionAttributeValues[":expires"].(*types.AttributeValueMemberN)
+			key, _ := input.Key["key"].(*types.AttributeValueMemberS)
+			return *input.TableName == "locks" && key.Value == "job" && owner.Value == "a" &&
+				now.Value == "1700000000000" && expires.Value == "1700000060000" &&
+				*input.ConditionExpression == "attribute_not_exists(#owner) OR #expires <= :now"
+		})).
+		Return(&awsdynamodb.UpdateItemOutput{
+			Attributes: map[string]types.AttributeValue{
+				lock.FenceName: &types.AttributeValueMemberN{Value: "7"},
+			},
+		}, nil).
+		Once()
+
+	backend := lock.NewDynamoDBBackend(awsMock, "locks", lock.WithDynamoDBClock(clock))
+	fence, err := backend.Acquire(context.Background(), "job", "a", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, int64(7), fence)
+}
+
+func TestDynamoDBBackend_Acquire_MissingFence_ReturnsErrConvert(t *testing.T) {
+	awsMock := mockdb.NewMockAWSClient(t)
+	awsMock.EXPECT().
+		UpdateItem(mock.Anything, mock.Anything).
+		Return(&awsdynamodb.UpdateItemOutput{}, nil).
+		Once()
+
+	_, err := lock.NewDynamoDBBackend(awsMock, "locks").Acquire(context.Background(), "job", "a", time.Minute)
+	require.ErrorIs(t, err, kvs.ErrConvert)
+}
+
+func TestDynamoDBBackend_ConditionFailures_AreMapped(t *testing.T) {
+	awsMock := mockdb.NewMockAWSClient(t)
+	awsMock.EXPECT().
+		UpdateItem(mock.Anything, mock.Anything).
+		Return(nil, &types.ConditionalCheckFailedException{}).
+		Times(3)
+
+	backend := lock.NewDynamoDBBackend(awsMock, "locks")
+	ctx := context.Background()
+
+	_, err := backend.Acquire(ctx, "job", "a", time.Minute)
+	require.ErrorIs(t, err, lock.ErrNotAcquired)
+	require.ErrorIs(t, backend.Refresh(ctx, "job", "a", time.Minute), lock.ErrNotHeld)
+	require.ErrorIs(t, backend.Release(ctx, "job", "a"), lock.ErrNotHeld)
+}
+
+func TestDynamoDBBackend_Refresh_And_Release(t *testing.T) {
+	awsMock := mockdb.NewMockAWSClient(t)
+	awsMock.EXPECT().
+		UpdateItem(mock.Anything, mock.MatchedBy(func(input *awsdynamodb.UpdateItemInput) bool {
+			return *input.UpdateExpression == "SET #expires = :expires"
+		})).
+		Return(&awsdynamodb.UpdateItemOutput{}, nil).
+		Once()
+	awsMock.EXPECT().
+		UpdateItem(mock.Anything, mock.MatchedBy(func(input *awsdynamodb.UpdateItemInput) bool {
+			return *input.UpdateExpression == "REMOVE #owner, #expires"
+		})).
+		Return(&awsdynamodb.UpdateItemOutput{}, nil).
+		Once()
+
+	backend := lock.NewDynamoDBBackend(awsMock, "locks")
+	ctx := context.Background()
+
+	require.NoError(t, backend.Refresh(ctx, "job", "a", time.Minute))
+	require.NoError(t, backend.Release(ctx, "job", "a"))
+}
+
+func TestDynamoDBBackend_OtherErrors_Propagate(t *testing.T) {
+	awsMock := mockdb.NewMockAWSClient(t)
+	awsMock.EXPECT().
+		UpdateItem(mock.Anything, mock.Anything).
+		Return(nil, errBoom).
+		Once()
+
+	_, err := lock.NewDynamoDBBackend(awsMock, "locks").Acquire(context.Background(), "job", "a", time.Minute)
+	require.ErrorIs(t, err, errBoom)
+}
+
+func TestDynamoDBBackend_CompositeKey(t *testing.T) {
+	ctx := context.Background()
+	names := kvsdynamodb.AttributeNames{Key: "pk", SortKey: "sk"}
+	fake := kvsdynamodb.NewAWSFakeClient(kvsdynamodb.WithFakeAttributeNames(names))
+	backend := lock.NewDynamoDBBackend(fake, "locks", lock.WithDynamoDBAttributeNames(names))
+
+	fence, err := backend.Acquire(ctx, "LOCK|job", "a", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), fence)
+	_, err = backend.Acquire(ctx, "LOCK|job", "b", time.Minute)
+	require.ErrorIs(t, err, lock.ErrNotAcquired)
+	require.NoError(t, backend.Refresh(ctx, "LOCK|job", "a", time.Minute))
+	require.NoError(t, backend.Release(ctx, "LOCK|job", "a"))
+
+	output, err := fake.GetItem(ctx, &awsdynamodb.GetItemInput{
+		TableName: aws.String("locks"),
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: "LOCK"},
+			"sk": &types.AttributeValueMemberS{Value: "job"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &types.AttributeValueMemberN{Value: "1"}, output.Item[lock.FenceName])
+
+	fence, err = backend.Acquire(ctx, "LOCK|job", "b", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), fence)
+
+	_, err = backend.Acquire(ctx, "job", "a", time.Minute)
+	require.ErrorIs(t, err, kvsdynamodb.ErrKeySchema)
+	require.ErrorIs(t, backend.Refresh(ctx, "job", "a", time.Minute), kvsdynamodb.ErrKeySchema)
+	require.ErrorIs(t, backend.Release(ctx, "job", "a"), kvsdynamodb.ErrKeySchema)
+}
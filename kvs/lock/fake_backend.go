@@ -0,0 +1,116 @@
+// Package lock provides a distributed lock built on top of the kvs backends.
+package lock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// FakeBackend is an in-memory implementation of Backend for tests.
+// Its clock can be moved forward with Advance to expire leases without sleeping.
+type FakeBackend struct {
+	clock  kvs.Clock
+	locks  map[string]fakeLock
+	fences map[string]int64
+	mu     sync.Mutex
+}
+
+// fakeLock is a lease held in a FakeBackend.
+type fakeLock struct {
+	expiresAt time.Time
+	owner     string
+}
+
+// FakeBackendOptions configures a FakeBackend.
+type FakeBackendOptions func(*FakeBackend)
+
+// WithFakeClock returns a FakeBackendOptions that sets the clock against which
+// leases expire (kvs.SystemClock by default).
+func WithFakeClock(clock kvs.Clock) FakeBackendOptions {
+	return func(f *FakeBackend) { f.clock = clock }
+}
+
+// NewFakeBackend creates a new, empty FakeBackend.
+func NewFakeBackend(opts ...FakeBackendOptions) *FakeBackend {
+	backend := &FakeBackend{
+		clock:  kvs.SystemClock,
+		locks:  make(map[string]fakeLock),
+		fences: make(map[string]int64),
+	}
+	for _, opt := range opts {
+		opt(backend)
+	}
+	return backend
+}
+
+// Advance moves the clock of the backend forward by d. A kvs.FakeClock given
+// with WithFakeClock is advanced; any other clock is replaced by a
+// kvs.FakeClock d ahead of it, which then only moves with Advance.
+func (r *FakeBackend) Advance(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if clock, ok := r.clock.(*kvs.FakeClock); ok {
+		clock.Advance(d)
+		return
+	}
+	r.clock = kvs.NewFakeClock(r.clock.Now().Add(d))
+}
+
+// Acquire implements Backend.
+func (r *FakeBackend) Acquire(_ context.Context, name, owner string, lease time.Duration) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, held := r.held(name); held {
+		return 0, ErrNotAcquired
+	}
+
+	r.locks[name] = fakeLock{owner: owner, expiresAt: r.clock.Now().Add(lease)}
+	r.fences[name]++
+
+	return r.fences[name], nil
+}
+
+// Refresh implements Backend.
+func (r *FakeBackend) Refresh(_ context.Context, name, owner string, lease time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, held := r.held(name)
+	if !held || current.owner != owner {
+		return ErrNotHeld
+	}
+
+	current.expiresAt = r.clock.Now().Add(lease)
+	r.locks[name] = current
+
+	return nil
+}
+
+// Release implements Backend.
+func (r *FakeBackend) Release(_ context.Context, name, owner string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, held := r.held(name)
+	if !held || current.owner != owner {
+		return ErrNotHeld
+	}
+
+	delete(r.locks, name)
+
+	return nil
+}
+
+// held returns the lease on name if it has not expired. Must be called with mu held.
+func (r *FakeBackend) held(name string) (fakeLock, bool) {
+	current, found := r.locks[name]
+	if !found || !r.clock.Now().Before(current.expiresAt) {
+		return fakeLock{}, false
+	}
+	return current, true
+}
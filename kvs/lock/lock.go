@@ -0,0 +1,244 @@
+// Package lock provides a distributed lock built on top of the kvs backends.
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// Error constants for lock operations.
+const (
+	// ErrNotAcquired is returned when the lock is currently held by another owner.
+	ErrNotAcquired = kvs.KeyValueError("[kvs]: lock is held by another owner")
+	// ErrNotHeld is returned when refreshing or releasing a lock whose lease has expired
+	// or that has been acquired by another owner.
+	ErrNotHeld = kvs.KeyValueError("[kvs]: lock is not held")
+	// ErrInvalidLease is returned when a lease shorter than MinLease is requested.
+	ErrInvalidLease = kvs.KeyValueError("[kvs]: lock lease must be at least 1ms")
+)
+
+// MinLease is the shortest lease accepted by Locker, the resolution at which the
+// backends expire locks.
+const MinLease = time.Millisecond
+
+// DefaultRetryInterval is the delay between two acquisition attempts in Locker.Acquire.
+const DefaultRetryInterval = 100 * time.Millisecond
+
+// Backend is the storage contract used by Locker.
+// Implementations MUST be safe for concurrent use and MUST perform every operation atomically.
+type Backend interface {
+	// Acquire takes the lock for owner if it is free or its lease has expired, and returns
+	// a fencing token strictly greater than the one of any previous acquisition of name.
+	// Returns ErrNotAcquired if the lock is held by someone else.
+	Acquire(ctx context.Context, name, owner string, lease time.Duration) (int64, error)
+
+	// Refresh extends the lease of a lock held by owner.
+	// Returns ErrNotHeld if owner does not hold the lock anymore.
+	Refresh(ctx context.Context, name, owner string, lease time.Duration) error
+
+	// Release frees a lock held by owner.
+	// Returns ErrNotHeld if owner does not hold the lock anymore.
+	Release(ctx context.Context, name, owner string) error
+}
+
+// Locker hands out distributed locks stored in a Backend.
+type Locker struct {
+	backend       Backend
+	clock         kvs.Clock
+	retryInterval time.Duration
+	autoRefresh   bool
+}
+
+// LockerOptions configures a Locker. Used with the functional-options pattern.
+type LockerOptions func(*Locker)
+
+// WithRetryInterval returns a LockerOptions that sets the delay between two
+// acquisition attempts in Locker.Acquire.
+func WithRetryInterval(interval time.Duration) LockerOptions {
+	return func(l *Locker) { l.retryInterval = interval }
+}
+
+// WithAutoRefresh returns a LockerOptions that toggles the automatic lease renewal.
+// It is enabled by default: every acquired Lock renews its lease every third of the
+// lease duration until it is released.
+func WithAutoRefresh(enabled bool) LockerOptions {
+	return func(l *Locker) { l.autoRefresh = enabled }
+}
+
+// WithClock returns a LockerOptions that sets the clock against which the automatic
+// renewal decides that a lease is lost (kvs.SystemClock by default).
+func WithClock(clock kvs.Clock) LockerOptions {
+	return func(l *Locker) { l.clock = clock }
+}
+
+// NewLocker creates a new Locker backed by the provided Backend.
+func NewLocker(backend Backend, opts ...LockerOptions) *Locker {
+	locker := &Locker{
+		backend:       backend,
+		clock:         kvs.SystemClock,
+		retryInterval: DefaultRetryInterval,
+		autoRefresh:   true,
+	}
+	for _, opt := range opts {
+		opt(locker)
+	}
+	return locker
+}
+
+// Acquire blocks until the named lock is acquired for the given lease or ctx is done.
+// While the lock is held by someone else, acquisition is retried every retry interval.
+// Returns an error wrapping both ErrNotAcquired and the context error when ctx ends first.
+func (r *Locker) Acquire(ctx context.Context, name string, lease time.Duration) (*Lock, error) {
+	for {
+		held, err := r.TryAcquire(ctx, name, lease)
+		if !errors.Is(err, ErrNotAcquired) {
+			return held, err
+		}
+
+		timer := time.NewTimer(r.retryInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("lock Acquire: %w: %w", ErrNotAcquired, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// TryAcquire makes a single attempt to acquire the named lock for the given lease.
+// Returns ErrInvalidLease if lease is shorter than MinLease and ErrNotAcquired if the
+// lock is held by someone else.
+func (r *Locker) TryAcquire(ctx context.Context, name string, lease time.Duration) (*Lock, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, kvs.ErrEmptyKey
+	}
+	if lease < MinLease {
+		return nil, ErrInvalidLease
+	}
+
+	owner := rand.Text()
+	fencingToken, err := r.backend.Acquire(ctx, name, owner, lease)
+	if err != nil {
+		return nil, err
+	}
+
+	held := &Lock{
+		backend:      r.backend,
+		clock:        r.clock,
+		name:         name,
+		owner:        owner,
+		fencingToken: fencingToken,
+		lease:        lease,
+		lost:         make(chan struct{}),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+
+	if r.autoRefresh {
+		go held.keepAlive(lease / 3)
+	} else {
+		close(held.done)
+	}
+
+	return held, nil
+}
+
+// Lock is a lease on a named lock.
+// It is safe for concurrent use.
+type Lock struct {
+	backend      Backend
+	clock        kvs.Clock
+	lost         chan struct{}
+	stop         chan struct{}
+	done         chan struct{}
+	name         string
+	owner        string
+	fencingToken int64
+	lease        time.Duration
+	lostOnce     sync.Once
+	stopOnce     sync.Once
+}
+
+// Name returns the name of the lock.
+func (r *Lock) Name() string {
+	return r.name
+}
+
+// Owner returns the random token identifying this holder of the lock.
+func (r *Lock) Owner() string {
+	return r.owner
+}
+
+// FencingToken returns the fencing token issued when the lock was acquired.
+// It is strictly greater than the token of any previous holder of the same lock.
+func (r *Lock) FencingToken() int64 {
+	return r.fencingToken
+}
+
+// Lost returns a channel that is closed when the lease is known to be lost,
+// either because a renewal reported ErrNotHeld or because renewals kept failing
+// for a whole lease duration.
+func (r *Lock) Lost() <-chan struct{} {
+	return r.lost
+}
+
+// Refresh extends the lease by its original duration.
+// Returns ErrNotHeld (and marks the lock as lost) if the lease has already been lost.
+func (r *Lock) Refresh(ctx context.Context) error {
+	err := r.backend.Refresh(ctx, r.name, r.owner, r.lease)
+	if errors.Is(err, ErrNotHeld) {
+		r.markLost()
+	}
+	return err
+}
+
+// Release stops the automatic renewal and frees the lock.
+// Returns ErrNotHeld if the lease had already been lost.
+func (r *Lock) Release(ctx context.Context) error {
+	r.stopOnce.Do(func() { close(r.stop) })
+	<-r.done
+
+	return r.backend.Release(ctx, r.name, r.owner)
+}
+
+// keepAlive renews the lease every interval until the lock is released or lost.
+// Transient failures are retried on the next tick; the lock is considered lost once
+// no renewal has succeeded for a whole lease.
+func (r *Lock) keepAlive(interval time.Duration) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	renewedAt := r.clock.Now()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			err := r.Refresh(ctx)
+			cancel()
+
+			switch {
+			case err == nil:
+				renewedAt = r.clock.Now()
+			case errors.Is(err, ErrNotHeld), r.clock.Now().Sub(renewedAt) >= r.lease:
+				r.markLost()
+				return
+			}
+		}
+	}
+}
+
+// markLost closes the Lost channel once.
+func (r *Lock) markLost() {
+	r.lostOnce.Do(func() { close(r.lost) })
+}
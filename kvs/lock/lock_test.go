@@ -0,0 +1,225 @@
+package lock_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	"github.com/arielsrv/go-kvs-client/kvs/lock"
+)
+
+var errBoom = errors.New("boom")
+
+func TestLocker_TryAcquire_ExclusiveUntilReleased(t *testing.T) {
+	ctx := context.Background()
+	locker := lock.NewLocker(lock.NewFakeBackend(), lock.WithAutoRefresh(false))
+
+	held, err := locker.TryAcquire(ctx, "job", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, "job", held.Name())
+	require.NotEmpty(t, held.Owner())
+	require.Equal(t, int64(1), held.FencingToken())
+
+	_, err = locker.TryAcquire(ctx, "job", time.Minute)
+	require.ErrorIs(t, err, lock.ErrNotAcquired)
+
+	require.NoError(t, held.Release(ctx))
+
+	next, err := locker.TryAcquire(ctx, "job", time.Minute)
+	require.NoError(t, err)
+	require.NotEqual(t, held.Owner(), next.Owner())
+	require.Equal(t, int64(2), next.FencingToken())
+}
+
+func TestLocker_TryAcquire_InvalidArguments(t *testing.T) {
+	locker := lock.NewLocker(lock.NewFakeBackend())
+
+	_, err := locker.TryAcquire(context.Background(), " ", time.Minute)
+	require.ErrorIs(t, err, kvs.ErrEmptyKey)
+
+	_, err = locker.TryAcquire(context.Background(), "job", 0)
+	require.ErrorIs(t, err, lock.ErrInvalidLease)
+
+	_, err = locker.TryAcquire(context.Background(), "job", 2*time.Nanosecond)
+	require.ErrorIs(t, err, lock.ErrInvalidLease)
+
+	held, err := locker.TryAcquire(context.Background(), "job", lock.MinLease)
+	require.NoError(t, err)
+	_ = held.Release(context.Background()) // the lease may already be over
+}
+
+func TestLocker_ExpiredLease_CanBeTakenOver(t *testing.T) {
+	ctx := context.Background()
+	clock := kvs.NewFakeClock(time.Unix(1_700_000_000, 0))
+	locker := lock.NewLocker(lock.NewFakeBackend(lock.WithFakeClock(clock)), lock.WithAutoRefresh(false))
+
+	stale, err := locker.TryAcquire(ctx, "job", time.Second)
+	require.NoError(t, err)
+
+	clock.Advance(time.Second)
+
+	fresh, err := locker.TryAcquire(ctx, "job", time.Second)
+	require.NoError(t, err)
+	require.Greater(t, fresh.FencingToken(), stale.FencingToken())
+
+	require.ErrorIs(t, stale.Refresh(ctx), lock.ErrNotHeld)
+	require.ErrorIs(t, stale.Release(ctx), lock.ErrNotHeld)
+
+	select {
+	case <-stale.Lost():
+	default:
+		t.Fatal("expected stale lock to be marked as lost")
+	}
+
+	require.NoError(t, fresh.Release(ctx))
+}
+
+func TestFakeBackend_Advance_WithoutFakeClock(t *testing.T) {
+	ctx := context.Background()
+	backend := lock.NewFakeBackend()
+
+	_, err := backend.Acquire(ctx, "job", "a", time.Hour)
+	require.NoError(t, err)
+	_, err = backend.Acquire(ctx, "job", "b", time.Hour)
+	require.ErrorIs(t, err, lock.ErrNotAcquired)
+
+	backend.Advance(time.Hour)
+	fence, err := backend.Acquire(ctx, "job", "b", time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), fence)
+}
+
+func TestLocker_Acquire_WaitsForRelease(t *testing.T) {
+	ctx := context.Background()
+	locker := lock.NewLocker(lock.NewFakeBackend(), lock.WithRetryInterval(5*time.Millisecond))
+
+	first, err := locker.Acquire(ctx, "job", time.Minute)
+	require.NoError(t, err)
+
+	time.AfterFunc(20*time.Millisecond, func() { _ = first.Release(ctx) })
+
+	second, err := locker.Acquire(ctx, "job", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), second.FencingToken())
+	require.NoError(t, second.Release(ctx))
+}
+
+func TestLocker_Acquire_ContextDone(t *testing.T) {
+	locker := lock.NewLocker(lock.NewFakeBackend(), lock.WithRetryInterval(5*time.Millisecond))
+
+	held, err := locker.Acquire(context.Background(), "job", time.Minute)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = held.Release(context.Background()) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = locker.Acquire(ctx, "job", time.Minute)
+	require.ErrorIs(t, err, lock.ErrNotAcquired)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestLocker_Acquire_BackendError_IsNotRetried(t *testing.T) {
+	backend := &stubBackend{acquireErr: errBoom}
+	locker := lock.NewLocker(backend)
+
+	_, err := locker.Acquire(context.Background(), "job", time.Minute)
+	require.ErrorIs(t, err, errBoom)
+	require.Equal(t, int32(1), backend.acquires.Load())
+}
+
+func TestLock_AutoRefresh_KeepsLeaseAlive(t *testing.T) {
+	ctx := context.Background()
+	backend := &stubBackend{}
+	locker := lock.NewLocker(backend)
+
+	held, err := locker.TryAcquire(ctx, "job", 30*time.Millisecond)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return backend.refreshes.Load() >= 3 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, held.Release(ctx))
+
+	refreshes := backend.refreshes.Load()
+	time.Sleep(30 * time.Millisecond)
+	require.Equal(t, refreshes, backend.refreshes.Load(), "no refresh after release")
+}
+
+func TestLock_AutoRefresh_NotHeld_MarksLost(t *testing.T) {
+	backend := &stubBackend{}
+	backend.setRefreshErr(lock.ErrNotHeld)
+	locker := lock.NewLocker(backend)
+
+	held, err := locker.TryAcquire(context.Background(), "job", 30*time.Millisecond)
+	require.NoError(t, err)
+
+	select {
+	case <-held.Lost():
+	case <-time.After(time.Second):
+		t.Fatal("expected lock to be lost")
+	}
+}
+
+func TestLock_AutoRefresh_TransientErrors_MarkLostAfterLease(t *testing.T) {
+	backend := &stubBackend{}
+	backend.setRefreshErr(errBoom)
+	clock := kvs.NewFakeClock(time.Unix(1_700_000_000, 0))
+	locker := lock.NewLocker(backend, lock.WithClock(clock))
+
+	held, err := locker.TryAcquire(context.Background(), "job", 30*time.Millisecond)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return backend.refreshes.Load() >= 2 }, time.Second, 5*time.Millisecond)
+	select {
+	case <-held.Lost():
+		t.Fatal("lock lost before a whole lease without renewal")
+	default:
+	}
+
+	clock.Advance(30 * time.Millisecond)
+	select {
+	case <-held.Lost():
+	case <-time.After(time.Second):
+		t.Fatal("expected lock to be lost")
+	}
+}
+
+// stubBackend is a Backend whose Acquire always succeeds unless acquireErr is set,
+// and whose Refresh returns the configured error.
+type stubBackend struct {
+	acquireErr error
+	refreshErr error
+	mu         sync.Mutex
+	acquires   atomic.Int32
+	refreshes  atomic.Int32
+}
+
+func (r *stubBackend) setRefreshErr(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.refreshErr = err
+}
+
+func (r *stubBackend) Acquire(context.Context, string, string, time.Duration) (int64, error) {
+	r.acquires.Add(1)
+	if r.acquireErr != nil {
+		return 0, r.acquireErr
+	}
+	return 1, nil
+}
+
+func (r *stubBackend) Refresh(context.Context, string, string, time.Duration) error {
+	r.refreshes.Add(1)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.refreshErr
+}
+
+func (r *stubBackend) Release(context.Context, string, string) error {
+	return nil
+}
@@ -0,0 +1,134 @@
+// Package lock provides a distributed lock built on top of the kvs backends.
+package lock
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// acquireScript increments the fence counter at KEYS[2] and stores the owner
+// ARGV[1] at KEYS[1], with a lease of ARGV[2] milliseconds (0 means no
+// expiration), when KEYS[1] does not exist. The increment comes first: Redis
+// does not roll back a failing script, so a counter that is not an integer
+// leaves the lock untouched. It returns the new fence, or 0 when the lock is
+// held.
+var acquireScript = goredis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return 0
+end
+local fence = redis.call('INCR', KEYS[2])
+if tonumber(ARGV[2]) > 0 then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+else
+	redis.call('SET', KEYS[1], ARGV[1])
+end
+return fence
+`)
+
+// refreshScript resets the lease of KEYS[1] to ARGV[2] milliseconds only when
+// it is held by ARGV[1]; a lease of 0 removes the expiration.
+var refreshScript = goredis.NewScript(`
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+if tonumber(ARGV[2]) > 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+else
+	redis.call('PERSIST', KEYS[1])
+end
+return 1
+`)
+
+// releaseScript deletes KEYS[1] only when it is held by ARGV[1].
+var releaseScript = goredis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// RedisBackend is the Redis implementation of Backend.
+//
+// The lock is a plain key holding the owner token, written with an expiration
+// equal to the lease so that an abandoned lock expires on its own. Each
+// operation runs as a single Lua script that checks the owner token and acts,
+// so a holder whose lease has expired can never extend or delete a lock taken
+// over by someone else.
+// Fencing tokens come from a companion counter key ("<lock key>:fence"),
+// incremented by the same script that writes the lock, so that tokens are
+// handed out in acquisition order and a lock is never taken without one.
+// Under Redis Cluster both keys must hash to the same slot: use a hash-tagged
+// keyPrefix such as "{__kvs:locks}".
+type RedisBackend struct {
+	client    goredis.Scripter
+	keyPrefix string
+}
+
+// NewRedisBackend creates a new RedisBackend running its scripts on client,
+// typically a goredis.UniversalClient. Lock keys are namespaced with
+// keyPrefix (e.g. "__kvs:locks:<name>"); it should not contain a trailing
+// separator.
+func NewRedisBackend(client goredis.Scripter, keyPrefix string) *RedisBackend {
+	return &RedisBackend{
+		client:    client,
+		keyPrefix: strings.TrimSuffix(strings.TrimSpace(keyPrefix), ":"),
+	}
+}
+
+// Acquire implements Backend.
+func (r *RedisBackend) Acquire(ctx context.Context, name, owner string, lease time.Duration) (int64, error) {
+	key := r.lockKey(name)
+	fence, err := acquireScript.Run(ctx, r.client, []string{key, key + ":fence"}, owner, milliseconds(lease)).Int64()
+	if err != nil {
+		return 0, err
+	}
+	if fence == 0 {
+		return 0, ErrNotAcquired
+	}
+	return fence, nil
+}
+
+// Refresh implements Backend.
+func (r *RedisBackend) Refresh(ctx context.Context, name, owner string, lease time.Duration) error {
+	refreshed, err := refreshScript.Run(ctx, r.client, []string{r.lockKey(name)}, owner, milliseconds(lease)).Int64()
+	if err != nil {
+		return err
+	}
+	if refreshed == 0 {
+		return ErrNotHeld
+	}
+	return nil
+}
+
+// Release implements Backend.
+func (r *RedisBackend) Release(ctx context.Context, name, owner string) error {
+	released, err := releaseScript.Run(ctx, r.client, []string{r.lockKey(name)}, owner).Int64()
+	if err != nil {
+		return err
+	}
+	if released == 0 {
+		return ErrNotHeld
+	}
+	return nil
+}
+
+// lockKey joins the configured prefix and the lock name.
+func (r *RedisBackend) lockKey(name string) string {
+	if r.keyPrefix == "" {
+		return name
+	}
+	return r.keyPrefix + ":" + name
+}
+
+// milliseconds converts a lease to the millisecond argument of the scripts.
+// A positive lease under 1ms is rounded up, so that it never means "no
+// expiration".
+func milliseconds(lease time.Duration) int64 {
+	if lease <= 0 {
+		return 0
+	}
+	return max(lease.Milliseconds(), 1)
+}
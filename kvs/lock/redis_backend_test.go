@@ -0,0 +1,119 @@
+package lock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs/lock"
+)
+
+func TestRedisBackend_OnMiniredis(t *testing.T) {
+	srv, client := startMiniredis(t)
+	ctx := context.Background()
+	backend := lock.NewRedisBackend(client, "__kvs:locks:")
+
+	fence, err := backend.Acquire(ctx, "job", "a", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), fence)
+	require.Equal(t, "a", mustGet(t, srv, "__kvs:locks:job"))
+	require.Equal(t, time.Minute, srv.TTL("__kvs:locks:job"))
+
+	_, err = backend.Acquire(ctx, "job", "b", time.Minute)
+	require.ErrorIs(t, err, lock.ErrNotAcquired)
+
+	require.NoError(t, backend.Refresh(ctx, "job", "a", time.Hour))
+	require.Equal(t, time.Hour, srv.TTL("__kvs:locks:job"))
+	require.ErrorIs(t, backend.Refresh(ctx, "job", "b", time.Hour), lock.ErrNotHeld)
+	require.ErrorIs(t, backend.Release(ctx, "job", "b"), lock.ErrNotHeld)
+
+	srv.FastForward(2 * time.Hour)
+
+	fence, err = backend.Acquire(ctx, "job", "b", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), fence)
+	require.ErrorIs(t, backend.Release(ctx, "job", "a"), lock.ErrNotHeld)
+	require.NoError(t, backend.Release(ctx, "job", "b"))
+	require.False(t, srv.Exists("__kvs:locks:job"))
+}
+
+func TestRedisBackend_Acquire_SubMillisecondLease_StillExpires(t *testing.T) {
+	srv, client := startMiniredis(t)
+	ctx := context.Background()
+	backend := lock.NewRedisBackend(client, "locks")
+
+	_, err := backend.Acquire(ctx, "job", "a", 500*time.Microsecond)
+	require.NoError(t, err)
+	require.Equal(t, time.Millisecond, srv.TTL("locks:job"))
+
+	require.NoError(t, backend.Refresh(ctx, "job", "a", 500*time.Microsecond))
+	require.True(t, srv.Exists("locks:job"))
+	require.Equal(t, time.Millisecond, srv.TTL("locks:job"))
+	require.Equal(t, time.Duration(0), srv.TTL("locks:job:fence"))
+}
+
+func TestRedisBackend_Acquire_CounterError_DoesNotLeakLock(t *testing.T) {
+	srv, client := startMiniredis(t)
+	ctx := context.Background()
+	backend := lock.NewRedisBackend(client, "locks")
+	require.NoError(t, srv.Set("locks:job:fence", "not a number"))
+
+	_, err := backend.Acquire(ctx, "job", "a", time.Minute)
+	require.Error(t, err)
+	require.False(t, srv.Exists("locks:job"))
+
+	srv.Del("locks:job:fence")
+	fence, err := backend.Acquire(ctx, "job", "a", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), fence)
+}
+
+func TestRedisBackend_Locker(t *testing.T) {
+	_, client := startMiniredis(t)
+	ctx := context.Background()
+	locker := lock.NewLocker(lock.NewRedisBackend(client, ""), lock.WithAutoRefresh(false))
+
+	held, err := locker.TryAcquire(ctx, "job", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), held.FencingToken())
+
+	_, err = locker.TryAcquire(ctx, "job", time.Minute)
+	require.ErrorIs(t, err, lock.ErrNotAcquired)
+
+	require.NoError(t, held.Refresh(ctx))
+	require.NoError(t, held.Release(ctx))
+
+	next, err := locker.TryAcquire(ctx, "job", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), next.FencingToken())
+}
+
+func TestRedisBackend_ClientError_Propagates(t *testing.T) {
+	_, client := startMiniredis(t)
+	require.NoError(t, client.Close())
+	backend := lock.NewRedisBackend(client, "locks")
+
+	_, err := backend.Acquire(context.Background(), "job", "a", time.Minute)
+	require.ErrorIs(t, err, goredis.ErrClosed)
+	require.ErrorIs(t, backend.Refresh(context.Background(), "job", "a", time.Minute), goredis.ErrClosed)
+	require.ErrorIs(t, backend.Release(context.Background(), "job", "a"), goredis.ErrClosed)
+}
+
+func startMiniredis(t *testing.T) (*miniredis.Miniredis, goredis.UniversalClient) {
+	t.Helper()
+	srv := miniredis.RunT(t)
+	client := goredis.NewUniversalClient(&goredis.UniversalOptions{Addrs: []string{srv.Addr()}})
+	t.Cleanup(func() { _ = client.Close() })
+	return srv, client
+}
+
+func mustGet(t *testing.T, srv *miniredis.Miniredis, key string) string {
+	t.Helper()
+	value, err := srv.Get(key)
+	require.NoError(t, err)
+	return value
+}
@@ -2,6 +2,8 @@ package kvs
 
 import (
 	"context"
+	"iter"
+	"time"
 )
 
 // LowLevelClient is the interface for low-level key-value store operations.
@@ -11,13 +13,16 @@ type LowLevelClient interface {
 	// Get retrieves an item by its key.
 	Get(key string) (*Item, error)
 
-	// BulkGet retrieves multiple items by their keys.
+	// BulkGet retrieves multiple items by their keys. Missing, expired and empty keys
+	// are skipped, and the items found come in the order of keys, once per key.
+	// Returns ErrTooManyKeys when the backend limit on keys (100) is exceeded.
 	BulkGet(keys []string) (*Items, error)
 
 	// Save stores an item with the specified key.
 	Save(key string, item *Item) error
 
-	// BulkSave stores multiple items.
+	// BulkSave stores multiple items. Nil items and items with an empty key are skipped;
+	// when several items share a key, the last one is stored.
 	BulkSave(items *Items) error
 
 	// GetWithContext retrieves an item by its key using the provided context.
@@ -32,6 +37,56 @@ type LowLevelClient interface {
 	// BulkSaveWithContext stores multiple items using the provided context.
 	BulkSaveWithContext(ctx context.Context, items *Items) error
 
+	// Delete removes the item stored under key. Deleting a missing key is not an error.
+	Delete(key string) error
+
+	// DeleteWithContext removes the item stored under key using the provided context.
+	DeleteWithContext(ctx context.Context, key string) error
+
+	// SaveIfAbsent stores an item with the specified key only if no live item is stored
+	// under that key yet (an expired item counts as absent).
+	// Returns ErrConditionFailed if the key is already taken.
+	SaveIfAbsent(ctx context.Context, key string, item *Item) error
+
+	// CompareAndSwap atomically replaces the item stored under key with item, only if
+	// the stored value is still the value of expected. expected must be an item returned
+	// by Get, whose Value is the stored representation (ErrConvert otherwise).
+	// Returns ErrConditionFailed if the item is missing, expired or has changed.
+	CompareAndSwap(ctx context.Context, key string, expected, item *Item) error
+
+	// CompareAndDelete atomically removes the item stored under key, only if the stored
+	// value is still the value of expected, as returned by Get.
+	// Returns ErrConditionFailed if the item is missing, expired or has changed.
+	CompareAndDelete(ctx context.Context, key string, expected *Item) error
+
+	// Increment atomically adds delta to the integer counter stored under key and
+	// returns the new value. A missing counter starts at zero. The ttl is applied
+	// only when the counter is created; a non-positive ttl falls back to the
+	// backend's default TTL.
+	Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
+
+	// IncrementFloat is like Increment for floating-point counters.
+	IncrementFloat(ctx context.Context, key string, delta float64, ttl time.Duration) (float64, error)
+
+	// Patch atomically updates fields of the JSON object stored under key, leaving its
+	// other fields and its TTL unchanged. The keys of updates are field names, or
+	// dot-separated paths to nested fields whose parents exist; a nil value removes the
+	// field. Returns ErrKeyNotFound if no item is stored under key, ErrConvert if the
+	// stored value is not a JSON object and ErrFieldPath if a path is invalid.
+	Patch(ctx context.Context, key string, updates map[string]any) error
+
+	// GetFields retrieves only the given fields (names or dot-separated paths) of the
+	// JSON object stored under key. The Value of the returned item is a JSON object
+	// holding the fields found, nested as stored. Returns ErrKeyNotFound if no item is
+	// stored under key and ErrConvert if the stored value is not a JSON object.
+	GetFields(ctx context.Context, key string, fields ...string) (*Item, error)
+
+	// Scan enumerates the items stored in the container, optionally filtered by key prefix.
+	// Pages are fetched lazily while the sequence is consumed; breaking out of the loop
+	// stops the scan. An error is yielded at most once and terminates the sequence.
+	// No ordering is guaranteed.
+	Scan(ctx context.Context, opts ScanOptions) iter.Seq2[*Item, error]
+
 	// ContainerName returns the name of the container or service that this client interacts with.
 	// Used for metrics and logging.
 	ContainerName() string
@@ -41,6 +96,8 @@ type LowLevelClient interface {
 // It implements the same interface as the wrapped client, but adds metrics for each operation.
 type LowLevelClientProxy struct {
 	lowLevelClient LowLevelClient
+	// clock is the clock of the TTLs set by WithTTL; SystemClock when nil.
+	clock Clock
 }
 
 // NewLowLevelClientProxy creates a new LowLevelClientProxy with the provided client.
@@ -131,6 +188,182 @@ func (r LowLevelClientProxy) BulkSaveWithContext(ctx context.Context, items *Ite
 	return nil
 }
 
+// GetWithOptions retrieves an item by its key with per-call options (see CallOptions).
+// Returns ErrUnsupported if an option does not apply to reads.
+func (r LowLevelClientProxy) GetWithOptions(ctx context.Context, key string, opts ...CallOption) (*Item, error) {
+	options := NewCallOptions(opts...)
+	if err := options.checkRead("GetWithOptions"); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := options.context(ctx)
+	defer cancel()
+	return r.GetWithContext(ctx, key)
+}
+
+// BulkGetWithOptions retrieves multiple items by their keys with per-call options (see CallOptions).
+// Returns ErrUnsupported if an option does not apply to reads.
+func (r LowLevelClientProxy) BulkGetWithOptions(ctx context.Context, keys []string, opts ...CallOption) (*Items, error) {
+	options := NewCallOptions(opts...)
+	if err := options.checkRead("BulkGetWithOptions"); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := options.context(ctx)
+	defer cancel()
+	return r.BulkGetWithContext(ctx, keys)
+}
+
+// SaveWithOptions stores an item with the specified key with per-call options (see CallOptions).
+// WithTTL overrides the TTL of the item; IfAbsent delegates to SaveIfAbsent.
+// Returns ErrUnsupported if an option does not apply to saves.
+func (r LowLevelClientProxy) SaveWithOptions(ctx context.Context, key string, item *Item, opts ...CallOption) error {
+	options := NewCallOptions(opts...)
+	if err := options.checkWrite("SaveWithOptions", true); err != nil {
+		return err
+	}
+
+	ctx, cancel := options.context(ctx)
+	defer cancel()
+	if options.IfAbsent {
+		return r.SaveIfAbsent(ctx, key, options.expiring(item, clockOrSystem(r.clock)))
+	}
+	return r.SaveWithContext(ctx, key, options.expiring(item, clockOrSystem(r.clock)))
+}
+
+// BulkSaveWithOptions stores multiple items with per-call options (see CallOptions).
+// WithTTL overrides the TTL of every item.
+// Returns ErrUnsupported if an option does not apply to bulk saves.
+func (r LowLevelClientProxy) BulkSaveWithOptions(ctx context.Context, items *Items, opts ...CallOption) error {
+	options := NewCallOptions(opts...)
+	if err := options.checkWrite("BulkSaveWithOptions", false); err != nil {
+		return err
+	}
+
+	ctx, cancel := options.context(ctx)
+	defer cancel()
+	if options.TTL > 0 && items != nil {
+		expiring := new(Items)
+		for item := range items.All() {
+			expiring.Add(options.expiring(item, clockOrSystem(r.clock)))
+		}
+		items = expiring
+	}
+	return r.BulkSaveWithContext(ctx, items)
+}
+
+// Delete removes the item stored under key.
+// It uses a background context and delegates to DeleteWithContext.
+func (r LowLevelClientProxy) Delete(key string) error {
+	return r.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext removes the item stored under key using the provided context.
+// It delegates to the wrapped client's DeleteWithContext method.
+func (r LowLevelClientProxy) DeleteWithContext(ctx context.Context, key string) error {
+	return r.lowLevelClient.DeleteWithContext(ctx, key)
+}
+
+// SaveIfAbsent stores an item with the specified key only if the key is not taken.
+// It delegates to the wrapped client's SaveIfAbsent method.
+func (r LowLevelClientProxy) SaveIfAbsent(ctx context.Context, key string, item *Item) error {
+	return r.lowLevelClient.SaveIfAbsent(ctx, key, item)
+}
+
+// CompareAndSwap replaces the item stored under key only if it still matches expected.
+// It delegates to the wrapped client's CompareAndSwap method.
+func (r LowLevelClientProxy) CompareAndSwap(ctx context.Context, key string, expected, item *Item) error {
+	return r.lowLevelClient.CompareAndSwap(ctx, key, expected, item)
+}
+
+// CompareAndDelete removes the item stored under key only if it still matches expected.
+// It delegates to the wrapped client's CompareAndDelete method.
+func (r LowLevelClientProxy) CompareAndDelete(ctx context.Context, key string, expected *Item) error {
+	return r.lowLevelClient.CompareAndDelete(ctx, key, expected)
+}
+
+// Increment atomically adds delta to the integer counter stored under key.
+// It delegates to the wrapped client's Increment method.
+func (r LowLevelClientProxy) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	return r.lowLevelClient.Increment(ctx, key, delta, ttl)
+}
+
+// IncrementFloat atomically adds delta to the floating-point counter stored under key.
+// It delegates to the wrapped client's IncrementFloat method.
+func (r LowLevelClientProxy) IncrementFloat(
+	ctx context.Context,
+	key string,
+	delta float64,
+	ttl time.Duration,
+) (float64, error) {
+	return r.lowLevelClient.IncrementFloat(ctx, key, delta, ttl)
+}
+
+// Patch atomically updates fields of the JSON object stored under key.
+// It delegates to the wrapped client's Patch method.
+func (r LowLevelClientProxy) Patch(ctx context.Context, key string, updates map[string]any) error {
+	return r.lowLevelClient.Patch(ctx, key, updates)
+}
+
+// GetFields retrieves only the given fields of the JSON object stored under key.
+// It delegates to the wrapped client's GetFields method.
+func (r LowLevelClientProxy) GetFields(ctx context.Context, key string, fields ...string) (*Item, error) {
+	return r.lowLevelClient.GetFields(ctx, key, fields...)
+}
+
+// Scan enumerates the items stored in the container using the provided context.
+// It delegates to the wrapped client's Scan method.
+func (r LowLevelClientProxy) Scan(ctx context.Context, opts ScanOptions) iter.Seq2[*Item, error] {
+	return r.lowLevelClient.Scan(ctx, opts)
+}
+
+// Query enumerates the items of a partition using the provided context.
+// It delegates to the wrapped client's Query method, and yields ErrUnsupported if
+// the wrapped client is not a Querier.
+func (r LowLevelClientProxy) Query(
+	ctx context.Context,
+	partition string,
+	condition SortCondition,
+	opts QueryOptions,
+) iter.Seq2[*Item, error] {
+	querier, ok := r.lowLevelClient.(Querier)
+	if !ok {
+		return func(yield func(*Item, error) bool) {
+			yield(nil, ErrUnsupported)
+		}
+	}
+	return querier.Query(ctx, partition, condition, opts)
+}
+
+// QueryPage returns a page of the items of a partition using the provided context.
+// It delegates to the wrapped client's QueryPage method, and returns ErrUnsupported if
+// the wrapped client is not a Querier.
+func (r LowLevelClientProxy) QueryPage(
+	ctx context.Context,
+	partition string,
+	condition SortCondition,
+	opts QueryOptions,
+) (*Items, string, error) {
+	querier, ok := r.lowLevelClient.(Querier)
+	if !ok {
+		return nil, "", ErrUnsupported
+	}
+	return querier.QueryPage(ctx, partition, condition, opts)
+}
+
+// QueryIndex enumerates the items of a secondary index value using the provided context.
+// It delegates to the wrapped client's QueryIndex method, and yields ErrUnsupported if
+// the wrapped client is not an IndexQuerier.
+func (r LowLevelClientProxy) QueryIndex(ctx context.Context, index, value string) iter.Seq2[*Item, error] {
+	querier, ok := r.lowLevelClient.(IndexQuerier)
+	if !ok {
+		return func(yield func(*Item, error) bool) {
+			yield(nil, ErrUnsupported)
+		}
+	}
+	return querier.QueryIndex(ctx, index, value)
+}
+
 // ContainerName returns the name of the container or service that this client interacts with.
 // It delegates to the wrapped client's ContainerName method.
 // Used for metrics and logging.
@@ -0,0 +1,122 @@
+// Package kvs provides a generic key-value store client interface and implementation.
+package kvs
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CallOptions holds the per-call options of a *WithOptions operation, set by CallOption
+// functions. Each operation accepts a subset of them and returns ErrUnsupported when
+// given another one:
+//
+//   - GetWithOptions and BulkGetWithOptions: WithTimeout, WithConsistentRead.
+//   - SaveWithOptions: WithTimeout, WithTTL, IfAbsent.
+//   - BulkSaveWithOptions: WithTimeout, WithTTL.
+type CallOptions struct {
+	// ConsistentRead, when not nil, overrides the read consistency of the backend
+	// (see ContextWithConsistentRead).
+	ConsistentRead *bool
+	// TTL expires the written items after the duration; zero keeps the item TTL.
+	TTL time.Duration
+	// Timeout bounds the duration of the call; zero means no timeout.
+	Timeout time.Duration
+	// IfAbsent writes the item only if no live item is stored under its key (see
+	// LowLevelClient.SaveIfAbsent).
+	IfAbsent bool
+}
+
+// CallOption is a function that sets a per-call option.
+type CallOption func(opts *CallOptions)
+
+// NewCallOptions returns the CallOptions set by opts.
+func NewCallOptions(opts ...CallOption) CallOptions {
+	var options CallOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// WithTTL returns a CallOption that expires the written items after ttl.
+func WithTTL(ttl time.Duration) CallOption {
+	return func(opts *CallOptions) {
+		opts.TTL = ttl
+	}
+}
+
+// IfAbsent returns a CallOption that makes a save conditional on the key being absent.
+// The save then returns ErrConditionFailed if the key is already taken.
+func IfAbsent() CallOption {
+	return func(opts *CallOptions) {
+		opts.IfAbsent = true
+	}
+}
+
+// WithTimeout returns a CallOption that cancels the call after timeout.
+func WithTimeout(timeout time.Duration) CallOption {
+	return func(opts *CallOptions) {
+		opts.Timeout = timeout
+	}
+}
+
+// WithConsistentRead returns a CallOption that requests strongly consistent (consistent
+// true) or eventually consistent reads.
+func WithConsistentRead(consistent bool) CallOption {
+	return func(opts *CallOptions) {
+		opts.ConsistentRead = &consistent
+	}
+}
+
+// checkRead returns ErrUnsupported if the options do not apply to the read operation.
+func (r CallOptions) checkRead(operation string) error {
+	switch {
+	case r.TTL != 0:
+		return unsupportedOption(operation, "WithTTL")
+	case r.IfAbsent:
+		return unsupportedOption(operation, "IfAbsent")
+	default:
+		return nil
+	}
+}
+
+// checkWrite returns ErrUnsupported if the options do not apply to the write operation.
+func (r CallOptions) checkWrite(operation string, conditional bool) error {
+	switch {
+	case r.ConsistentRead != nil:
+		return unsupportedOption(operation, "WithConsistentRead")
+	case r.IfAbsent && !conditional:
+		return unsupportedOption(operation, "IfAbsent")
+	default:
+		return nil
+	}
+}
+
+// unsupportedOption returns the ErrUnsupported error of an option passed to an operation.
+func unsupportedOption(operation, option string) error {
+	return fmt.Errorf("%w: %s does not accept %s", ErrUnsupported, operation, option)
+}
+
+// context returns the context of the call, bounded by the timeout and carrying the read
+// consistency, and the function releasing it.
+func (r CallOptions) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.ConsistentRead != nil {
+		ctx = ContextWithConsistentRead(ctx, *r.ConsistentRead)
+	}
+	if r.Timeout > 0 {
+		return context.WithTimeout(ctx, r.Timeout)
+	}
+	return ctx, func() {}
+}
+
+// expiring returns item with the TTL of the options applied, counted from the current
+// time of clock, as a copy.
+func (r CallOptions) expiring(item *Item, clock Clock) *Item {
+	if r.TTL <= 0 || item == nil {
+		return item
+	}
+	expiring := *item
+	expiring.TTL = clock.Now().Add(r.TTL).Unix()
+	return &expiring
+}
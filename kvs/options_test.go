@@ -0,0 +1,88 @@
+package kvs_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	"github.com/arielsrv/go-kvs-client/kvs/dynamodb"
+	"github.com/arielsrv/go-kvs-client/kvs/model"
+	"github.com/arielsrv/go-kvs-client/kvs/redis"
+	mockkvs "github.com/arielsrv/go-kvs-client/resources/mocks/kvs"
+)
+
+func TestKVSClient_WithOptions(t *testing.T) {
+	backends := map[string]kvs.LowLevelClient{
+		"dynamodb": dynamodb.NewBuilder().WithContainerName("__kvs-test").FakeBuild(),
+		"redis":    redis.NewBuilder(redis.WithKeyPrefix("__kvs:users"), redis.WithStorageMode(redis.StorageHash)).FakeBuild(),
+	}
+	for name, lowLevelClient := range backends {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			kvsClient := kvs.NewKVSClient[model.UserDTO](lowLevelClient)
+
+			user := model.NewUserDTO("John", "Doe")
+			require.NoError(t, kvsClient.SaveWithOptions(ctx, "1", user,
+				kvs.WithTTL(time.Hour), kvs.IfAbsent(), kvs.WithTimeout(time.Second)))
+			require.ErrorIs(t, kvsClient.SaveWithOptions(ctx, "1", user, kvs.IfAbsent()), kvs.ErrConditionFailed)
+
+			item, err := lowLevelClient.Get("1")
+			require.NoError(t, err)
+			require.InDelta(t, time.Now().Add(time.Hour).Unix(), item.TTL, 2)
+
+			got, err := kvsClient.GetWithOptions(ctx, "1", kvs.WithConsistentRead(true), kvs.WithTimeout(time.Second))
+			require.NoError(t, err)
+			require.Equal(t, "John", got.FirstName)
+
+			users := []model.UserDTO{{ID: 2, FirstName: "Jane"}, {ID: 3, FirstName: "Joe"}}
+			require.NoError(t, kvsClient.BulkSaveWithOptions(ctx, users, func(user model.UserDTO) string {
+				return strconv.Itoa(user.ID)
+			}, kvs.WithTTL(time.Hour)))
+			item, err = lowLevelClient.Get("2")
+			require.NoError(t, err)
+			require.Positive(t, item.TTL)
+
+			values, err := kvsClient.BulkGetWithOptions(ctx, []string{"2", "3"}, kvs.WithConsistentRead(false))
+			require.NoError(t, err)
+			require.Len(t, values, 2)
+		})
+	}
+}
+
+func TestKVSClient_WithOptions_Unsupported(t *testing.T) {
+	ctx := t.Context()
+	kvsClient := kvs.NewKVSClient[model.UserDTO](mockkvs.NewMockLowLevelClient(t))
+	keyMapper := func(user model.UserDTO) string { return strconv.Itoa(user.ID) }
+
+	_, err := kvsClient.GetWithOptions(ctx, "1", kvs.IfAbsent())
+	require.ErrorIs(t, err, kvs.ErrUnsupported)
+	_, err = kvsClient.BulkGetWithOptions(ctx, []string{"1"}, kvs.WithTTL(time.Hour))
+	require.ErrorIs(t, err, kvs.ErrUnsupported)
+	err = kvsClient.SaveWithOptions(ctx, "1", new(model.UserDTO), kvs.WithConsistentRead(true))
+	require.ErrorIs(t, err, kvs.ErrUnsupported)
+	err = kvsClient.BulkSaveWithOptions(ctx, nil, keyMapper, kvs.IfAbsent())
+	require.ErrorIs(t, err, kvs.ErrUnsupported)
+	require.EqualError(t, err, "[kvs]: operation not supported: BulkSaveWithOptions does not accept IfAbsent")
+}
+
+func TestKVSClient_WithOptions_Context(t *testing.T) {
+	lowLevelClient := mockkvs.NewMockLowLevelClient(t)
+	kvsClient := kvs.NewKVSClient[model.UserDTO](lowLevelClient)
+
+	lowLevelClient.EXPECT().
+		GetWithContext(mock.MatchedBy(func(ctx context.Context) bool {
+			_, deadline := ctx.Deadline()
+			consistent, ok := kvs.ConsistentReadFromContext(ctx)
+			return deadline && ok && consistent
+		}), "1").
+		Return(kvs.NewItem("1", `{"first_name":"John"}`), nil)
+
+	got, err := kvsClient.GetWithOptions(t.Context(), "1", kvs.WithTimeout(time.Second), kvs.WithConsistentRead(true))
+	require.NoError(t, err)
+	require.Equal(t, "John", got.FirstName)
+}
@@ -0,0 +1,118 @@
+// Package kvs provides a generic key-value store client interface and implementation.
+package kvs
+
+import (
+	"context"
+	"iter"
+)
+
+// SortOperator is the comparison applied by a SortCondition to the sort key.
+type SortOperator int
+
+// Sort key operators.
+const (
+	// SortAny matches every sort key of the partition.
+	SortAny SortOperator = iota
+	// SortEqual matches the sort key equal to Value.
+	SortEqual
+	// SortLessThan matches the sort keys lower than Value.
+	SortLessThan
+	// SortLessOrEqual matches the sort keys lower than or equal to Value.
+	SortLessOrEqual
+	// SortGreaterThan matches the sort keys greater than Value.
+	SortGreaterThan
+	// SortGreaterOrEqual matches the sort keys greater than or equal to Value.
+	SortGreaterOrEqual
+	// SortBetween matches the sort keys between Value and To, both included.
+	SortBetween
+	// SortBeginsWith matches the sort keys starting with Value.
+	SortBeginsWith
+)
+
+// SortCondition restricts the items of a query to a range of sort keys, compared as
+// strings. The zero value matches the whole partition.
+type SortCondition struct {
+	Operator SortOperator
+	Value    string
+	To       string // Upper bound of SortBetween
+}
+
+// SortKeyEquals returns the condition matching the sort key value.
+func SortKeyEquals(value string) SortCondition {
+	return SortCondition{Operator: SortEqual, Value: value}
+}
+
+// SortKeyLessThan returns the condition matching the sort keys lower than value.
+func SortKeyLessThan(value string) SortCondition {
+	return SortCondition{Operator: SortLessThan, Value: value}
+}
+
+// SortKeyLessOrEqual returns the condition matching the sort keys lower than or equal to value.
+func SortKeyLessOrEqual(value string) SortCondition {
+	return SortCondition{Operator: SortLessOrEqual, Value: value}
+}
+
+// SortKeyGreaterThan returns the condition matching the sort keys greater than value.
+func SortKeyGreaterThan(value string) SortCondition {
+	return SortCondition{Operator: SortGreaterThan, Value: value}
+}
+
+// SortKeyGreaterOrEqual returns the condition matching the sort keys greater than or equal to value.
+func SortKeyGreaterOrEqual(value string) SortCondition {
+	return SortCondition{Operator: SortGreaterOrEqual, Value: value}
+}
+
+// SortKeyBetween returns the condition matching the sort keys between from and to, both included.
+func SortKeyBetween(from, to string) SortCondition {
+	return SortCondition{Operator: SortBetween, Value: from, To: to}
+}
+
+// SortKeyBeginsWith returns the condition matching the sort keys starting with prefix.
+func SortKeyBeginsWith(prefix string) SortCondition {
+	return SortCondition{Operator: SortBeginsWith, Value: prefix}
+}
+
+// QueryOptions configures a query. The zero value returns every matching item in
+// ascending sort key order, fetched in pages of DefaultScanPageSize.
+type QueryOptions struct {
+	// Descending returns the items in descending sort key order.
+	Descending bool
+	// Limit caps the number of items returned by Query. Zero means no limit.
+	Limit int
+	// PageSize is the number of items fetched per round-trip, and the maximum number of
+	// items returned by QueryPage.
+	PageSize int
+	// StartToken resumes a query after the last item of the page that returned it.
+	StartToken string
+}
+
+// PageSizeOrDefault returns the configured page size, falling back to
+// DefaultScanPageSize when it is not positive.
+func (r QueryOptions) PageSizeOrDefault() int {
+	if r.PageSize <= 0 {
+		return DefaultScanPageSize
+	}
+	return r.PageSize
+}
+
+// Querier is implemented by the LowLevelClients whose containers keep the items of a
+// partition ordered by sort key, such as DynamoDB tables with a composite primary key.
+type Querier interface {
+	// Query enumerates the items of a partition whose sort key matches condition, in
+	// sort key order. Pages are fetched lazily while the sequence is consumed; an error
+	// is yielded at most once and terminates the sequence.
+	Query(ctx context.Context, partition string, condition SortCondition, opts QueryOptions) iter.Seq2[*Item, error]
+
+	// QueryPage returns a single page of the items of Query, and the token resuming the
+	// query after it (see QueryOptions.StartToken), empty on the last page.
+	QueryPage(ctx context.Context, partition string, condition SortCondition, opts QueryOptions) (*Items, string, error)
+}
+
+// IndexQuerier is implemented by the LowLevelClients that can look items up by the value
+// of a secondary index, such as DynamoDB tables with global secondary indexes.
+type IndexQuerier interface {
+	// QueryIndex enumerates the items whose key in the secondary index is value. Pages
+	// are fetched lazily while the sequence is consumed; an error is yielded at most once
+	// and terminates the sequence. Secondary indexes are eventually consistent.
+	QueryIndex(ctx context.Context, index, value string) iter.Seq2[*Item, error]
+}
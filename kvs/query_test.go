@@ -0,0 +1,27 @@
+package kvs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	"github.com/arielsrv/go-kvs-client/kvs/model"
+	mockkvs "github.com/arielsrv/go-kvs-client/resources/mocks/kvs"
+)
+
+func TestKVSClient_Query_Unsupported(t *testing.T) {
+	kvsClient := kvs.NewKVSClient[model.UserDTO](mockkvs.NewMockLowLevelClient(t))
+
+	for _, err := range kvsClient.Query(t.Context(), "USER#1", kvs.SortKeyBeginsWith("ORDER#"), kvs.QueryOptions{}) {
+		require.ErrorIs(t, err, kvs.ErrUnsupported)
+	}
+
+	_, _, err := kvsClient.QueryPage(t.Context(), "USER#1", kvs.SortCondition{}, kvs.QueryOptions{})
+	require.ErrorIs(t, err, kvs.ErrUnsupported)
+}
+
+func TestQueryOptions_PageSizeOrDefault(t *testing.T) {
+	require.Equal(t, kvs.DefaultScanPageSize, kvs.QueryOptions{}.PageSizeOrDefault())
+	require.Equal(t, 10, kvs.QueryOptions{PageSize: 10}.PageSizeOrDefault())
+}
@@ -0,0 +1,40 @@
+// Package ratelimit provides rate limiters whose state lives in a kvs backend,
+// so that every instance of a service shares the same limits.
+//
+// Three algorithms are available, each exposing Allow(ctx, key, n):
+//   - FixedWindow: at most limit units per window, the window starting with the
+//     first request. Cheapest, but allows bursts of up to 2*limit around a
+//     window boundary.
+//   - SlidingWindowLog: at most limit units during any window-long period.
+//     Exact, at the cost of storing one timestamp per consumed unit.
+//   - TokenBucket: bursts of up to capacity units, refilled with one token every
+//     interval.
+//
+// Every call returns a Result with the remaining units and the time after which
+// the request can be retried and the limiter is back to full capacity.
+//
+// Key Components:
+//   - Backend: the storage contract implemented by RedisBackend (one Lua script
+//     per algorithm, run on a go-redis Scripter and using the server clock) and DynamoDBBackend (conditional
+//     writes with optimistic concurrency).
+//   - FixedWindow, SlidingWindowLog and TokenBucket: the limiters.
+//
+// Usage:
+//
+//	limiter := ratelimit.NewTokenBucket(
+//	    ratelimit.NewRedisBackend(universalClient, "__kvs:ratelimit"),
+//	    100,                  // burst capacity
+//	    100*time.Millisecond, // one token every 100ms (10 req/s sustained)
+//	)
+//
+//	result, err := limiter.Allow(ctx, "user:42", 1)
+//	if err != nil {
+//	    // Handle error
+//	}
+//	if !result.Allowed {
+//	    // Reject, e.g. with a Retry-After header of result.RetryAfter
+//	}
+//
+// Limiters of different kinds must not share keys, since each algorithm stores
+// its state in its own format.
+package ratelimit
@@ -0,0 +1,387 @@
+// Package ratelimit provides rate limiters whose state lives in a kvs backend.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	kvsdynamodb "github.com/arielsrv/go-kvs-client/kvs/dynamodb"
+	"github.com/arielsrv/go-kvs-client/kvs/internal/rate"
+)
+
+// Constants for the DynamoDB rate-limit attribute names.
+const (
+	CountName   = "count"   // Attribute name for the fixed-window counter
+	ResetName   = "reset"   // Attribute name for the end of the fixed window, in unix milliseconds
+	LogName     = "log"     // Attribute name for the sliding-window log, a list of unix milliseconds
+	TokensName  = "tokens"  // Attribute name for the token-bucket level
+	UpdatedName = "updated" // Attribute name for the time of the token-bucket level, in unix milliseconds
+	VersionName = "ver"     // Attribute name for the optimistic-concurrency version
+)
+
+// maxAttempts bounds the number of conditional writes tried by DynamoDBBackend
+// before giving up with ErrContention.
+const maxAttempts = 5
+
+// DynamoDBBackend is the DynamoDB implementation of Backend.
+//
+// The fixed window is updated in place with an ADD expression guarded by a
+// condition on the window end and the counter, so the common case takes a
+// single write. The sliding window log and the token bucket are read with a
+// consistent read, computed client-side and written back with a condition on
+// a version attribute, retrying when another writer got there first. Denied
+// requests and requests for zero units never write. Every item gets the table TTL attribute, set to the
+// time the limiter is back to full capacity, so idle keys are purged by
+// DynamoDB. Timestamps come from the local clock, so the instances sharing a
+// table should keep their clocks synchronized.
+type DynamoDBBackend struct {
+	client    kvsdynamodb.AWSClient
+	clock     kvs.Clock
+	tableName string
+}
+
+// DynamoDBBackendOptions configures a DynamoDBBackend.
+type DynamoDBBackendOptions func(*DynamoDBBackend)
+
+// WithClock returns a DynamoDBBackendOptions that sets the clock the timestamps
+// and windows are computed from (kvs.SystemClock by default).
+func WithClock(clock kvs.Clock) DynamoDBBackendOptions {
+	return func(b *DynamoDBBackend) { b.clock = clock }
+}
+
+// NewDynamoDBBackend creates a new DynamoDBBackend storing limiter state in the provided table.
+func NewDynamoDBBackend(
+	client kvsdynamodb.AWSClient,
+	tableName string,
+	opts ...DynamoDBBackendOptions,
+) *DynamoDBBackend {
+	backend := &DynamoDBBackend{
+		client:    client,
+		clock:     kvs.SystemClock,
+		tableName: tableName,
+	}
+	for _, opt := range opts {
+		opt(backend)
+	}
+	return backend
+}
+
+// FixedWindow implements Backend.
+func (r *DynamoDBBackend) FixedWindow(
+	ctx context.Context,
+	key string,
+	n, limit int64,
+	window time.Duration,
+) (Result, error) {
+	if n == 0 {
+		return r.peekFixedWindow(ctx, key, limit, window)
+	}
+
+	for range maxAttempts {
+		now := r.clock.Now()
+
+		// Consume from the current window, if any.
+		output, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:           aws.String(r.tableName),
+			Key:                 r.key(key),
+			UpdateExpression:    aws.String("ADD #count :n"),
+			ConditionExpression: aws.String("#reset > :now AND #count <= :max"),
+			ExpressionAttributeNames: map[string]string{
+				"#count": CountName,
+				"#reset": ResetName,
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":n":   number(n),
+				":now": number(now.UnixMilli()),
+				":max": number(limit - n),
+			},
+			ReturnValues:                        types.ReturnValueAllNew,
+			ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+		})
+		if err == nil {
+			count, resetAt, parseErr := parseFixedWindow(output.Attributes)
+			if parseErr != nil {
+				return Result{}, parseErr
+			}
+			_, _, decision := rate.FixedWindow(count-n, resetAt, now.UnixMilli(), n, limit, window)
+			return newDecisionResult(decision), nil
+		}
+
+		var conditionalCheckFailed *types.ConditionalCheckFailedException
+		if !errors.As(err, &conditionalCheckFailed) {
+			return Result{}, err
+		}
+
+		if len(conditionalCheckFailed.Item) > 0 {
+			count, resetAt, parseErr := parseFixedWindow(conditionalCheckFailed.Item)
+			if parseErr != nil {
+				return Result{}, parseErr
+			}
+			if resetAt > now.UnixMilli() {
+				// The window is active and has no room left for n units.
+				_, _, decision := rate.FixedWindow(count, resetAt, now.UnixMilli(), n, limit, window)
+				return newDecisionResult(decision), nil
+			}
+		}
+
+		// Start a new window, unless another writer has just done so.
+		_, resetAt, decision := rate.FixedWindow(0, 0, now.UnixMilli(), n, limit, window)
+		_, err = r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:           aws.String(r.tableName),
+			Key:                 r.key(key),
+			UpdateExpression:    aws.String("SET #count = :n, #reset = :reset, #ttl = :ttl"),
+			ConditionExpression: aws.String("attribute_not_exists(#reset) OR #reset <= :now"),
+			ExpressionAttributeNames: map[string]string{
+				"#count": CountName,
+				"#reset": ResetName,
+				"#ttl":   kvsdynamodb.TTLName,
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":n":     number(n),
+				":reset": number(resetAt),
+				":now":   number(now.UnixMilli()),
+				":ttl":   expiresAt(now, decision),
+			},
+		})
+		if err == nil {
+			return newDecisionResult(decision), nil
+		}
+		if !errors.As(err, &conditionalCheckFailed) {
+			return Result{}, err
+		}
+	}
+
+	return Result{}, ErrContention
+}
+
+// peekFixedWindow reports the state of a fixed window with a consistent read,
+// without starting a window when there is none.
+func (r *DynamoDBBackend) peekFixedWindow(
+	ctx context.Context,
+	key string,
+	limit int64,
+	window time.Duration,
+) (Result, error) {
+	output, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(r.tableName),
+		Key:            r.key(key),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	var count, resetAt int64
+	if len(output.Item) > 0 {
+		count, resetAt, err = parseFixedWindow(output.Item)
+		if err != nil {
+			return Result{}, err
+		}
+	}
+
+	_, _, decision := rate.FixedWindow(count, resetAt, r.clock.Now().UnixMilli(), 0, limit, window)
+	return newDecisionResult(decision), nil
+}
+
+// SlidingWindowLog implements Backend.
+func (r *DynamoDBBackend) SlidingWindowLog(
+	ctx context.Context,
+	key string,
+	n, limit int64,
+	window time.Duration,
+) (Result, error) {
+	return r.optimistic(ctx, key, n, func(item map[string]types.AttributeValue, now time.Time) (
+		map[string]types.AttributeValue, rate.Decision, error,
+	) {
+		var log []int64
+		if attribute, ok := item[LogName].(*types.AttributeValueMemberL); ok {
+			for _, value := range attribute.Value {
+				timestamp, err := parseNumber(value)
+				if err != nil {
+					return nil, rate.Decision{}, err
+				}
+				log = append(log, timestamp)
+			}
+		}
+
+		log, decision := rate.SlidingWindowLog(log, now.UnixMilli(), n, limit, window)
+
+		values := make([]types.AttributeValue, len(log))
+		for i, timestamp := range log {
+			values[i] = number(timestamp)
+		}
+		return map[string]types.AttributeValue{
+			LogName: &types.AttributeValueMemberL{Value: values},
+		}, decision, nil
+	})
+}
+
+// TokenBucket implements Backend.
+func (r *DynamoDBBackend) TokenBucket(
+	ctx context.Context,
+	key string,
+	n, capacity int64,
+	interval time.Duration,
+) (Result, error) {
+	return r.optimistic(ctx, key, n, func(item map[string]types.AttributeValue, now time.Time) (
+		map[string]types.AttributeValue, rate.Decision, error,
+	) {
+		var (
+			tokens    float64
+			updatedAt int64
+		)
+		tokensValue, found := item[TokensName].(*types.AttributeValueMemberN)
+		if found {
+			var err error
+			tokens, err = strconv.ParseFloat(tokensValue.Value, 64)
+			if err != nil {
+				return nil, rate.Decision{}, kvs.ErrConvert
+			}
+			updatedAt, err = parseNumber(item[UpdatedName])
+			if err != nil {
+				return nil, rate.Decision{}, err
+			}
+		}
+
+		tokens, decision := rate.TokenBucket(tokens, updatedAt, found, now.UnixMilli(), n, capacity, interval)
+
+		return map[string]types.AttributeValue{
+			TokensName:  &types.AttributeValueMemberN{Value: strconv.FormatFloat(tokens, 'f', -1, 64)},
+			UpdatedName: number(now.UnixMilli()),
+		}, decision, nil
+	})
+}
+
+// optimistic runs a read-compute-write cycle: the item is read with a consistent
+// read, compute returns the new state attributes, and the item is written back
+// only if its version did not change in the meantime. Denied requests and
+// requests for zero units are not written.
+func (r *DynamoDBBackend) optimistic(
+	ctx context.Context,
+	key string,
+	n int64,
+	compute func(item map[string]types.AttributeValue, now time.Time) (map[string]types.AttributeValue, rate.Decision, error),
+) (Result, error) {
+	for range maxAttempts {
+		output, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+			TableName:      aws.String(r.tableName),
+			Key:            r.key(key),
+			ConsistentRead: aws.Bool(true),
+		})
+		if err != nil {
+			return Result{}, err
+		}
+
+		now := r.clock.Now()
+		state, decision, err := compute(output.Item, now)
+		if err != nil {
+			return Result{}, err
+		}
+		if !decision.Allowed || n == 0 {
+			return newDecisionResult(decision), nil
+		}
+
+		var version int64
+		condition := "attribute_not_exists(#ver)"
+		values := map[string]types.AttributeValue{}
+		if current, ok := output.Item[VersionName]; ok {
+			version, err = parseNumber(current)
+			if err != nil {
+				return Result{}, err
+			}
+			condition = "#ver = :ver"
+			values[":ver"] = current
+		}
+
+		state[kvsdynamodb.KeyName] = &types.AttributeValueMemberS{Value: key}
+		state[VersionName] = number(version + 1)
+		state[kvsdynamodb.TTLName] = expiresAt(now, decision)
+
+		input := &dynamodb.PutItemInput{
+			TableName:                aws.String(r.tableName),
+			Item:                     state,
+			ConditionExpression:      aws.String(condition),
+			ExpressionAttributeNames: map[string]string{"#ver": VersionName},
+		}
+		if len(values) > 0 {
+			input.ExpressionAttributeValues = values
+		}
+
+		_, err = r.client.PutItem(ctx, input)
+		if err == nil {
+			return newDecisionResult(decision), nil
+		}
+
+		var conditionalCheckFailed *types.ConditionalCheckFailedException
+		if !errors.As(err, &conditionalCheckFailed) {
+			return Result{}, err
+		}
+	}
+
+	return Result{}, ErrContention
+}
+
+// key returns the primary key of the limiter item.
+func (r *DynamoDBBackend) key(key string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		kvsdynamodb.KeyName: &types.AttributeValueMemberS{Value: key},
+	}
+}
+
+// parseFixedWindow reads the counter and window end of a fixed-window item.
+func parseFixedWindow(item map[string]types.AttributeValue) (int64, int64, error) {
+	count, err := parseNumber(item[CountName])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	resetAt, err := parseNumber(item[ResetName])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return count, resetAt, nil
+}
+
+// parseNumber decodes an integer number attribute.
+func parseNumber(value types.AttributeValue) (int64, error) {
+	attribute, ok := value.(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, kvs.ErrConvert
+	}
+
+	number, err := strconv.ParseInt(attribute.Value, 10, 64)
+	if err != nil {
+		return 0, kvs.ErrConvert
+	}
+
+	return number, nil
+}
+
+// number encodes an integer as a number attribute.
+func number(value int64) *types.AttributeValueMemberN {
+	return &types.AttributeValueMemberN{Value: strconv.FormatInt(value, 10)}
+}
+
+// expiresAt returns the TTL attribute (unix seconds) of an item whose limiter is
+// back to full capacity after decision.ResetAfter.
+func expiresAt(now time.Time, decision rate.Decision) *types.AttributeValueMemberN {
+	return number(now.Add(decision.ResetAfter + time.Second - 1).Unix())
+}
+
+// newDecisionResult converts a rate.Decision into a Result.
+func newDecisionResult(decision rate.Decision) Result {
+	return Result{
+		Allowed:    decision.Allowed,
+		Remaining:  decision.Remaining,
+		RetryAfter: decision.RetryAfter,
+		ResetAfter: decision.ResetAfter,
+	}
+}
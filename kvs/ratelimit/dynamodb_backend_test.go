@@ -0,0 +1,258 @@
+package ratelimit_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	"github.com/arielsrv/go-kvs-client/kvs/ratelimit"
+	mockdb "github.com/arielsrv/go-kvs-client/resources/mocks/kvs/dynamodb"
+)
+
+func TestDynamoDBBackend_FixedWindow_StartsWindowThenConsumes(t *testing.T) {
+	awsMock := mockdb.NewMockAWSClient(t)
+	awsMock.EXPECT().
+		UpdateItem(mock.Anything, updateExpression("ADD #count :n")).
+		Return(nil, &types.ConditionalCheckFailedException{}).
+		Once()
+	awsMock.EXPECT().
+		UpdateItem(mock.Anything, updateExpression("SET #count = :n, #reset = :reset, #ttl = :ttl")).
+		Return(&awsdynamodb.UpdateItemOutput{}, nil).
+		Once()
+
+	clock := kvs.NewFakeClock(time.UnixMilli(1_700_000_000_000))
+	backend := ratelimit.NewDynamoDBBackend(awsMock, "limits", ratelimit.WithClock(clock))
+	limiter := ratelimit.NewFixedWindow(backend, 3, time.Minute)
+
+	result, err := limiter.Allow(context.Background(), "k", 2)
+	require.NoError(t, err)
+	require.Equal(t, ratelimit.Result{Allowed: true, Limit: 3, Remaining: 1, ResetAfter: time.Minute}, result)
+
+	clock.Advance(30 * time.Second)
+	resetAt := clock.Now().Add(30 * time.Second).UnixMilli()
+	awsMock.EXPECT().
+		UpdateItem(mock.Anything, updateExpression("ADD #count :n")).
+		Return(&awsdynamodb.UpdateItemOutput{Attributes: fixedWindowItem(3, resetAt)}, nil).
+		Once()
+
+	result, err = limiter.Allow(context.Background(), "k", 1)
+	require.NoError(t, err)
+	require.Equal(t, ratelimit.Result{Allowed: true, Limit: 3, Remaining: 0, ResetAfter: 30 * time.Second}, result)
+}
+
+func TestDynamoDBBackend_FixedWindow_Denied(t *testing.T) {
+	clock := kvs.NewFakeClock(time.UnixMilli(1_700_000_000_000))
+	resetAt := clock.Now().Add(30 * time.Second).UnixMilli()
+	awsMock := mockdb.NewMockAWSClient(t)
+	awsMock.EXPECT().
+		UpdateItem(mock.Anything, updateExpression("ADD #count :n")).
+		Return(nil, &types.ConditionalCheckFailedException{Item: fixedWindowItem(3, resetAt)}).
+		Once()
+
+	backend := ratelimit.NewDynamoDBBackend(awsMock, "limits", ratelimit.WithClock(clock))
+	result, err := ratelimit.NewFixedWindow(backend, 3, time.Minute).Allow(context.Background(), "k", 1)
+	require.NoError(t, err)
+	require.Equal(t, ratelimit.Result{Limit: 3, RetryAfter: 30 * time.Second, ResetAfter: 30 * time.Second}, result)
+}
+
+func TestDynamoDBBackend_FixedWindow_ZeroUnits_DoesNotWrite(t *testing.T) {
+	clock := kvs.NewFakeClock(time.UnixMilli(1_700_000_000_000))
+	awsMock := mockdb.NewMockAWSClient(t)
+	awsMock.EXPECT().
+		GetItem(mock.Anything, mock.Anything).
+		Return(&awsdynamodb.GetItemOutput{}, nil).
+		Once()
+	awsMock.EXPECT().
+		GetItem(mock.Anything, mock.Anything).
+		Return(&awsdynamodb.GetItemOutput{Item: fixedWindowItem(2, clock.Now().Add(30*time.Second).UnixMilli())}, nil).
+		Once()
+
+	backend := ratelimit.NewDynamoDBBackend(awsMock, "limits", ratelimit.WithClock(clock))
+	limiter := ratelimit.NewFixedWindow(backend, 3, time.Minute)
+
+	result, err := limiter.Allow(context.Background(), "k", 0)
+	require.NoError(t, err)
+	require.Equal(t, ratelimit.Result{Allowed: true, Limit: 3, Remaining: 3, ResetAfter: time.Minute}, result)
+
+	result, err = limiter.Allow(context.Background(), "k", 0)
+	require.NoError(t, err)
+	require.Equal(t, ratelimit.Result{Allowed: true, Limit: 3, Remaining: 1, ResetAfter: 30 * time.Second}, result)
+}
+
+func TestDynamoDBBackend_FixedWindow_Contention(t *testing.T) {
+	awsMock := mockdb.NewMockAWSClient(t)
+	awsMock.EXPECT().
+		UpdateItem(mock.Anything, mock.Anything).
+		Return(nil, &types.ConditionalCheckFailedException{})
+
+	_, err := ratelimit.NewFixedWindow(ratelimit.NewDynamoDBBackend(awsMock, "limits"), 3, time.Minute).
+		Allow(context.Background(), "k", 1)
+	require.ErrorIs(t, err, ratelimit.ErrContention)
+}
+
+func TestDynamoDBBackend_FixedWindow_ErrorPropagates(t *testing.T) {
+	awsMock := mockdb.NewMockAWSClient(t)
+	awsMock.EXPECT().
+		UpdateItem(mock.Anything, mock.Anything).
+		Return(nil, errBoom).
+		Once()
+
+	_, err := ratelimit.NewFixedWindow(ratelimit.NewDynamoDBBackend(awsMock, "limits"), 3, time.Minute).
+		Allow(context.Background(), "k", 1)
+	require.ErrorIs(t, err, errBoom)
+}
+
+func TestDynamoDBBackend_OptimisticLimiters(t *testing.T) {
+	tests := []struct {
+		newLimiter func(ratelimit.Backend) ratelimit.Limiter
+		name       string
+	}{
+		{
+			name: "sliding window log",
+			newLimiter: func(backend ratelimit.Backend) ratelimit.Limiter {
+				return ratelimit.NewSlidingWindowLog(backend, 3, time.Minute)
+			},
+		},
+		{
+			name: "token bucket",
+			newLimiter: func(backend ratelimit.Backend) ratelimit.Limiter {
+				return ratelimit.NewTokenBucket(backend, 3, time.Minute)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := newVersionedTable(t)
+			clock := kvs.NewFakeClock(time.UnixMilli(1_700_000_000_000))
+			limiter := tt.newLimiter(ratelimit.NewDynamoDBBackend(table.mock, "limits", ratelimit.WithClock(clock)))
+			ctx := context.Background()
+
+			result, err := limiter.Allow(ctx, "k", 2)
+			require.NoError(t, err)
+			require.True(t, result.Allowed)
+			require.Equal(t, int64(1), result.Remaining)
+
+			result, err = limiter.Allow(ctx, "k", 2)
+			require.NoError(t, err)
+			require.False(t, result.Allowed)
+			require.Equal(t, time.Minute, result.RetryAfter)
+
+			// A concurrent writer bumps the version between the read and the write.
+			table.conflicts = 1
+			result, err = limiter.Allow(ctx, "k", 1)
+			require.NoError(t, err)
+			require.True(t, result.Allowed)
+			require.Equal(t, int64(0), result.Remaining)
+			require.Equal(t, "2", table.version("k"), "denied requests are not written")
+		})
+	}
+}
+
+func TestDynamoDBBackend_Optimistic_Contention(t *testing.T) {
+	table := newVersionedTable(t)
+	table.conflicts = 10
+
+	_, err := ratelimit.NewTokenBucket(ratelimit.NewDynamoDBBackend(table.mock, "limits"), 3, time.Minute).
+		Allow(context.Background(), "k", 1)
+	require.ErrorIs(t, err, ratelimit.ErrContention)
+}
+
+func TestDynamoDBBackend_Optimistic_GetItemError_Propagates(t *testing.T) {
+	awsMock := mockdb.NewMockAWSClient(t)
+	awsMock.EXPECT().
+		GetItem(mock.Anything, mock.Anything).
+		Return(nil, errBoom).
+		Once()
+
+	_, err := ratelimit.NewSlidingWindowLog(ratelimit.NewDynamoDBBackend(awsMock, "limits"), 3, time.Minute).
+		Allow(context.Background(), "k", 1)
+	require.ErrorIs(t, err, errBoom)
+}
+
+func updateExpression(expression string) any {
+	return mock.MatchedBy(func(input *awsdynamodb.UpdateItemInput) bool {
+		return *input.UpdateExpression == expression
+	})
+}
+
+func fixedWindowItem(count, resetAt int64) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		ratelimit.CountName: &types.AttributeValueMemberN{Value: strconv.FormatInt(count, 10)},
+		ratelimit.ResetName: &types.AttributeValueMemberN{Value: strconv.FormatInt(resetAt, 10)},
+	}
+}
+
+// versionedTable backs a MockAWSClient with a map honouring the version
+// conditions written by DynamoDBBackend. Each pending conflict makes the next
+// PutItem fail as if another writer had updated the item.
+type versionedTable struct {
+	mock      *mockdb.MockAWSClient
+	items     map[string]map[string]types.AttributeValue
+	mu        sync.Mutex
+	conflicts int
+}
+
+func newVersionedTable(t *testing.T) *versionedTable {
+	table := &versionedTable{
+		mock:  mockdb.NewMockAWSClient(t),
+		items: make(map[string]map[string]types.AttributeValue),
+	}
+
+	table.mock.EXPECT().
+		GetItem(mock.Anything, mock.Anything).
+		RunAndReturn(func(
+			_ context.Context, input *awsdynamodb.GetItemInput, _ ...func(*awsdynamodb.Options),
+		) (*awsdynamodb.GetItemOutput, error) {
+			table.mu.Lock()
+			defer table.mu.Unlock()
+			return &awsdynamodb.GetItemOutput{Item: table.items[keyOf(input.Key)]}, nil
+		}).
+		Maybe()
+
+	table.mock.EXPECT().
+		PutItem(mock.Anything, mock.Anything).
+		RunAndReturn(func(
+			_ context.Context, input *awsdynamodb.PutItemInput, _ ...func(*awsdynamodb.Options),
+		) (*awsdynamodb.PutItemOutput, error) {
+			table.mu.Lock()
+			defer table.mu.Unlock()
+
+			key := keyOf(input.Item)
+			if table.conflicts > 0 {
+				table.conflicts--
+				return nil, &types.ConditionalCheckFailedException{}
+			}
+
+			current, found := table.items[key][ratelimit.VersionName]
+			expected, conditional := input.ExpressionAttributeValues[":ver"]
+			if found != conditional || (found && current.(*types.AttributeValueMemberN).Value !=
+				expected.(*types.AttributeValueMemberN).Value) {
+				return nil, &types.ConditionalCheckFailedException{}
+			}
+
+			table.items[key] = input.Item
+			return &awsdynamodb.PutItemOutput{}, nil
+		}).
+		Maybe()
+
+	return table
+}
+
+func (r *versionedTable) version(key string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.items[key][ratelimit.VersionName].(*types.AttributeValueMemberN).Value
+}
+
+func keyOf(item map[string]types.AttributeValue) string {
+	return item["key"].(*types.AttributeValueMemberS).Value
+}
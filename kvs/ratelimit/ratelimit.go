@@ -0,0 +1,173 @@
+// Package ratelimit provides rate limiters whose state lives in a kvs backend.
+package ratelimit
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// Error constants for rate-limit operations.
+const (
+	// ErrInvalidLimit is returned when a limiter is configured with a non-positive
+	// limit (or capacity) or a period shorter than a millisecond.
+	ErrInvalidLimit = kvs.KeyValueError("[kvs]: rate limit and period must be positive")
+	// ErrInvalidCost is returned when Allow is called with a negative number of units
+	// or with more units than the limiter can ever allow.
+	ErrInvalidCost = kvs.KeyValueError("[kvs]: rate limit cost must be between zero and the limit")
+	// ErrContention is returned when the backend could not apply a conditional update
+	// because of concurrent writers, after several attempts.
+	ErrContention = kvs.KeyValueError("[kvs]: rate limit state is under contention")
+)
+
+// Result is the outcome of a call to Allow.
+type Result struct {
+	// Limit is the maximum number of units the limiter allows at once.
+	Limit int64
+	// Remaining is the number of units that can still be consumed right now.
+	Remaining int64
+	// RetryAfter is the time to wait before the same request can be allowed.
+	// It is zero when the request was allowed.
+	RetryAfter time.Duration
+	// ResetAfter is the time after which the limiter is back to its full capacity.
+	ResetAfter time.Duration
+	// Allowed reports whether the request was allowed and its units consumed.
+	Allowed bool
+}
+
+// Limiter is implemented by FixedWindow, SlidingWindowLog and TokenBucket.
+type Limiter interface {
+	// Allow consumes n units for key if the limit allows it.
+	// A request with n == 0 only reports the current state of the limiter.
+	Allow(ctx context.Context, key string, n int64) (Result, error)
+}
+
+// Backend is the storage contract used by the limiters.
+// Implementations MUST be safe for concurrent use and MUST apply every check atomically.
+// The returned Result does not need to carry the Limit, which is set by the limiter.
+type Backend interface {
+	// FixedWindow consumes n units from the counter of the current window of key.
+	FixedWindow(ctx context.Context, key string, n, limit int64, window time.Duration) (Result, error)
+
+	// SlidingWindowLog consumes n units from the log of the requests made for key
+	// during the last window.
+	SlidingWindowLog(ctx context.Context, key string, n, limit int64, window time.Duration) (Result, error)
+
+	// TokenBucket takes n tokens from the bucket of key.
+	TokenBucket(ctx context.Context, key string, n, capacity int64, interval time.Duration) (Result, error)
+}
+
+// FixedWindow allows up to limit units per window. The window of a key starts
+// with its first request.
+type FixedWindow struct {
+	backend Backend
+	limit   int64
+	window  time.Duration
+}
+
+// NewFixedWindow creates a new FixedWindow limiter.
+func NewFixedWindow(backend Backend, limit int64, window time.Duration) *FixedWindow {
+	return &FixedWindow{
+		backend: backend,
+		limit:   limit,
+		window:  window,
+	}
+}
+
+// Allow implements Limiter.
+func (r *FixedWindow) Allow(ctx context.Context, key string, n int64) (Result, error) {
+	err := validate(key, n, r.limit, r.window)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result, err := r.backend.FixedWindow(ctx, key, n, r.limit, r.window)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result.Limit = r.limit
+	return result, nil
+}
+
+// SlidingWindowLog allows up to limit units during any window-long period.
+type SlidingWindowLog struct {
+	backend Backend
+	limit   int64
+	window  time.Duration
+}
+
+// NewSlidingWindowLog creates a new SlidingWindowLog limiter.
+func NewSlidingWindowLog(backend Backend, limit int64, window time.Duration) *SlidingWindowLog {
+	return &SlidingWindowLog{
+		backend: backend,
+		limit:   limit,
+		window:  window,
+	}
+}
+
+// Allow implements Limiter.
+func (r *SlidingWindowLog) Allow(ctx context.Context, key string, n int64) (Result, error) {
+	err := validate(key, n, r.limit, r.window)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result, err := r.backend.SlidingWindowLog(ctx, key, n, r.limit, r.window)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result.Limit = r.limit
+	return result, nil
+}
+
+// TokenBucket allows bursts of up to capacity units and refills one token
+// every interval.
+type TokenBucket struct {
+	backend  Backend
+	capacity int64
+	interval time.Duration
+}
+
+// NewTokenBucket creates a new TokenBucket limiter.
+// Buckets start full; the interval has a millisecond resolution.
+func NewTokenBucket(backend Backend, capacity int64, interval time.Duration) *TokenBucket {
+	return &TokenBucket{
+		backend:  backend,
+		capacity: capacity,
+		interval: interval,
+	}
+}
+
+// Allow implements Limiter.
+func (r *TokenBucket) Allow(ctx context.Context, key string, n int64) (Result, error) {
+	err := validate(key, n, r.capacity, r.interval)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result, err := r.backend.TokenBucket(ctx, key, n, r.capacity, r.interval)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result.Limit = r.capacity
+	return result, nil
+}
+
+// validate checks the arguments shared by every limiter.
+func validate(key string, n, limit int64, period time.Duration) error {
+	if strings.TrimSpace(key) == "" {
+		return kvs.ErrEmptyKey
+	}
+	if limit <= 0 || period < time.Millisecond {
+		return ErrInvalidLimit
+	}
+	if n < 0 || n > limit {
+		return ErrInvalidCost
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	"github.com/arielsrv/go-kvs-client/kvs/ratelimit"
+)
+
+var errBoom = errors.New("boom")
+
+func TestLimiters_OnMiniredis(t *testing.T) {
+	_, universal := startMiniredis(t)
+	backend := ratelimit.NewRedisBackend(universal, "__kvs:ratelimit")
+
+	tests := []struct {
+		limiter ratelimit.Limiter
+		name    string
+	}{
+		{name: "fixed window", limiter: ratelimit.NewFixedWindow(backend, 3, time.Minute)},
+		{name: "sliding window log", limiter: ratelimit.NewSlidingWindowLog(backend, 3, time.Minute)},
+		{name: "token bucket", limiter: ratelimit.NewTokenBucket(backend, 3, time.Minute)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			result, err := tt.limiter.Allow(ctx, tt.name, 2)
+			require.NoError(t, err)
+			require.True(t, result.Allowed)
+			require.Equal(t, int64(3), result.Limit)
+			require.Equal(t, int64(1), result.Remaining)
+			require.Zero(t, result.RetryAfter)
+			require.Positive(t, result.ResetAfter)
+
+			result, err = tt.limiter.Allow(ctx, tt.name, 2)
+			require.NoError(t, err)
+			require.False(t, result.Allowed)
+			require.Equal(t, int64(1), result.Remaining)
+			require.Positive(t, result.RetryAfter)
+
+			result, err = tt.limiter.Allow(ctx, tt.name, 1)
+			require.NoError(t, err)
+			require.True(t, result.Allowed)
+			require.Equal(t, int64(0), result.Remaining)
+
+			result, err = tt.limiter.Allow(ctx, tt.name+":other", 3)
+			require.NoError(t, err)
+			require.True(t, result.Allowed, "keys are limited independently")
+		})
+	}
+}
+
+func TestLimiters_InvalidArguments(t *testing.T) {
+	_, universal := startMiniredis(t)
+	backend := ratelimit.NewRedisBackend(universal, "")
+	ctx := context.Background()
+
+	_, err := ratelimit.NewFixedWindow(backend, 3, time.Minute).Allow(ctx, " ", 1)
+	require.ErrorIs(t, err, kvs.ErrEmptyKey)
+
+	_, err = ratelimit.NewSlidingWindowLog(backend, 3, time.Minute).Allow(ctx, "k", 4)
+	require.ErrorIs(t, err, ratelimit.ErrInvalidCost)
+
+	_, err = ratelimit.NewTokenBucket(backend, 3, time.Minute).Allow(ctx, "k", -1)
+	require.ErrorIs(t, err, ratelimit.ErrInvalidCost)
+
+	_, err = ratelimit.NewTokenBucket(backend, 0, time.Minute).Allow(ctx, "k", 0)
+	require.ErrorIs(t, err, ratelimit.ErrInvalidLimit)
+
+	_, err = ratelimit.NewFixedWindow(backend, 3, time.Microsecond).Allow(ctx, "k", 1)
+	require.ErrorIs(t, err, ratelimit.ErrInvalidLimit)
+}
+
+func TestLimiters_BackendError_Propagates(t *testing.T) {
+	_, universal := startMiniredis(t)
+	require.NoError(t, universal.Close())
+	backend := ratelimit.NewRedisBackend(universal, "")
+	ctx := context.Background()
+
+	_, err := ratelimit.NewFixedWindow(backend, 3, time.Minute).Allow(ctx, "k", 1)
+	require.ErrorIs(t, err, goredis.ErrClosed)
+
+	_, err = ratelimit.NewSlidingWindowLog(backend, 3, time.Minute).Allow(ctx, "k", 1)
+	require.ErrorIs(t, err, goredis.ErrClosed)
+
+	_, err = ratelimit.NewTokenBucket(backend, 3, time.Minute).Allow(ctx, "k", 1)
+	require.ErrorIs(t, err, goredis.ErrClosed)
+}
@@ -0,0 +1,181 @@
+// Package ratelimit provides rate limiters whose state lives in a kvs backend.
+package ratelimit
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// fixedWindowScript consumes ARGV[1] units from the counter at KEYS[1] unless
+// that would exceed ARGV[2]; a new window of ARGV[3] milliseconds starts when
+// the counter does not exist. It returns {allowed, remaining, retry, reset}.
+var fixedWindowScript = goredis.NewScript(`
+local n, limit, window = tonumber(ARGV[1]), tonumber(ARGV[2]), tonumber(ARGV[3])
+local count, ttl = 0, redis.call('PTTL', KEYS[1])
+local fresh = ttl < 0
+if fresh then
+	ttl = window
+else
+	count = tonumber(redis.call('GET', KEYS[1]))
+end
+if count + n > limit then
+	return {0, limit - count, ttl, ttl}
+end
+if n > 0 then
+	count = count + n
+	if fresh then
+		redis.call('SET', KEYS[1], count, 'PX', window)
+	else
+		redis.call('INCRBY', KEYS[1], n)
+	end
+end
+return {1, limit - count, 0, ttl}
+`)
+
+// slidingWindowLogScript keeps the timestamps (in milliseconds, taken from the
+// server clock) of the units consumed during the last ARGV[3] milliseconds in
+// the sorted set at KEYS[1], and adds ARGV[1] of them unless the set would
+// hold more than ARGV[2]. It returns {allowed, remaining, retry, reset}.
+var slidingWindowLogScript = goredis.NewScript(`
+redis.replicate_commands()
+local n, limit, window = tonumber(ARGV[1]), tonumber(ARGV[2]), tonumber(ARGV[3])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed, retry = 0, 0
+if count + n <= limit then
+	allowed = 1
+	for i = 1, n do
+		redis.call('ZADD', KEYS[1], now, now .. '-' .. (count + i))
+	end
+	count = count + n
+else
+	local index = count + n - limit - 1
+	local oldest = redis.call('ZRANGE', KEYS[1], index, index, 'WITHSCORES')
+	retry = tonumber(oldest[2]) + window - now
+end
+local reset = 0
+if count > 0 then
+	local newest = redis.call('ZRANGE', KEYS[1], -1, -1, 'WITHSCORES')
+	reset = tonumber(newest[2]) + window - now
+	redis.call('PEXPIRE', KEYS[1], reset)
+end
+return {allowed, limit - count, retry, reset}
+`)
+
+// tokenBucketScript takes ARGV[1] tokens from the bucket at KEYS[1], a hash
+// holding the token level and the server time (in milliseconds) it was
+// computed at. The bucket holds up to ARGV[2] tokens and gains one every
+// ARGV[3] milliseconds; it expires once full again. It returns
+// {allowed, remaining, retry, reset}.
+var tokenBucketScript = goredis.NewScript(`
+redis.replicate_commands()
+local n, capacity, interval = tonumber(ARGV[1]), tonumber(ARGV[2]), tonumber(ARGV[3])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens, ts = tonumber(state[1]), tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens, ts = capacity, now
+end
+tokens = math.min(capacity, tokens + math.max(0, now - ts) / interval)
+local allowed, retry = 0, 0
+if tokens >= n then
+	tokens = tokens - n
+	allowed = 1
+else
+	retry = math.ceil((n - tokens) * interval)
+end
+local reset = math.ceil((capacity - tokens) * interval)
+if reset > 0 then
+	redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+	redis.call('PEXPIRE', KEYS[1], reset)
+else
+	redis.call('DEL', KEYS[1])
+end
+return {allowed, math.floor(tokens), retry, reset}
+`)
+
+// RedisBackend is the Redis implementation of Backend.
+// Each algorithm runs as a single Lua script, so checks are atomic without
+// round-trips between the read and the write. The sliding window log and the
+// token bucket use the server clock (TIME), so clients with skewed clocks
+// share one view.
+type RedisBackend struct {
+	client    goredis.Scripter
+	keyPrefix string
+}
+
+// NewRedisBackend creates a new RedisBackend running its scripts on client,
+// typically a goredis.UniversalClient. Limiter keys are namespaced with
+// keyPrefix (e.g. "__kvs:ratelimit:<key>"); it should not contain a trailing
+// separator.
+func NewRedisBackend(client goredis.Scripter, keyPrefix string) *RedisBackend {
+	return &RedisBackend{
+		client:    client,
+		keyPrefix: strings.TrimSuffix(strings.TrimSpace(keyPrefix), ":"),
+	}
+}
+
+// FixedWindow implements Backend.
+func (r *RedisBackend) FixedWindow(
+	ctx context.Context,
+	key string,
+	n, limit int64,
+	window time.Duration,
+) (Result, error) {
+	return r.run(ctx, fixedWindowScript, key, n, limit, window.Milliseconds())
+}
+
+// SlidingWindowLog implements Backend.
+func (r *RedisBackend) SlidingWindowLog(
+	ctx context.Context,
+	key string,
+	n, limit int64,
+	window time.Duration,
+) (Result, error) {
+	return r.run(ctx, slidingWindowLogScript, key, n, limit, window.Milliseconds())
+}
+
+// TokenBucket implements Backend.
+func (r *RedisBackend) TokenBucket(
+	ctx context.Context,
+	key string,
+	n, capacity int64,
+	interval time.Duration,
+) (Result, error) {
+	return r.run(ctx, tokenBucketScript, key, n, capacity, interval.Milliseconds())
+}
+
+// run runs one of the rate-limit scripts on the limiter key and decodes its
+// {allowed, remaining, retry, reset} reply.
+func (r *RedisBackend) run(ctx context.Context, script *goredis.Script, key string, args ...any) (Result, error) {
+	reply, err := script.Run(ctx, r.client, []string{r.fullKey(key)}, args...).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	if len(reply) != 4 {
+		return Result{}, kvs.ErrConvert
+	}
+
+	return Result{
+		Allowed:    reply[0] == 1,
+		Remaining:  reply[1],
+		RetryAfter: time.Duration(reply[2]) * time.Millisecond,
+		ResetAfter: time.Duration(reply[3]) * time.Millisecond,
+	}, nil
+}
+
+// fullKey joins the configured prefix and the limiter key.
+func (r *RedisBackend) fullKey(key string) string {
+	if r.keyPrefix == "" {
+		return key
+	}
+	return r.keyPrefix + ":" + key
+}
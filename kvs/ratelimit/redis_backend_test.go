@@ -0,0 +1,171 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs/ratelimit"
+)
+
+func TestRedisBackend_OnMiniredis(t *testing.T) {
+	srv, universal := startMiniredis(t)
+
+	now := time.UnixMilli(1_700_000_000_000)
+	srv.SetTime(now)
+
+	ctx := context.Background()
+	backend := ratelimit.NewRedisBackend(universal, "__kvs:ratelimit:")
+	limiter := ratelimit.NewTokenBucket(backend, 10, 100*time.Millisecond)
+
+	for range 10 {
+		result, err := limiter.Allow(ctx, "user:42", 1)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+	}
+	require.True(t, srv.Exists("__kvs:ratelimit:user:42"))
+
+	result, err := limiter.Allow(ctx, "user:42", 1)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, 100*time.Millisecond, result.RetryAfter)
+	require.Equal(t, time.Second, result.ResetAfter)
+
+	srv.SetTime(now.Add(250 * time.Millisecond))
+
+	result, err = limiter.Allow(ctx, "user:42", 2)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+	require.Equal(t, int64(0), result.Remaining)
+	require.Equal(t, int64(10), result.Limit)
+}
+
+func TestRedisBackend_FixedWindow_ZeroUnits_DoesNotWrite(t *testing.T) {
+	srv, universal := startMiniredis(t)
+
+	ctx := context.Background()
+	limiter := ratelimit.NewFixedWindow(
+		ratelimit.NewRedisBackend(universal, "__kvs:ratelimit"), 3, time.Minute,
+	)
+
+	result, err := limiter.Allow(ctx, "k", 0)
+	require.NoError(t, err)
+	require.Equal(t, ratelimit.Result{Allowed: true, Limit: 3, Remaining: 3, ResetAfter: time.Minute}, result)
+	require.False(t, srv.Exists("__kvs:ratelimit:k"))
+
+	_, err = limiter.Allow(ctx, "k", 2)
+	require.NoError(t, err)
+	srv.FastForward(30 * time.Second)
+
+	result, err = limiter.Allow(ctx, "k", 0)
+	require.NoError(t, err)
+	require.Equal(t, ratelimit.Result{Allowed: true, Limit: 3, Remaining: 1, ResetAfter: 30 * time.Second}, result)
+}
+
+func TestRedisBackend_FixedWindow(t *testing.T) {
+	srv, universal := startMiniredis(t)
+	backend := ratelimit.NewRedisBackend(universal, "")
+	ctx := context.Background()
+
+	result, err := backend.FixedWindow(ctx, "fw", 2, 3, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, ratelimit.Result{Allowed: true, Remaining: 1, ResetAfter: time.Minute}, result)
+
+	srv.FastForward(20 * time.Second)
+
+	result, err = backend.FixedWindow(ctx, "fw", 2, 3, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, ratelimit.Result{Remaining: 1, RetryAfter: 40 * time.Second, ResetAfter: 40 * time.Second}, result)
+
+	result, err = backend.FixedWindow(ctx, "fw", 1, 3, time.Minute)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+	require.Equal(t, int64(0), result.Remaining)
+	require.Equal(t, 40*time.Second, srv.TTL("fw"))
+
+	srv.FastForward(40 * time.Second)
+
+	result, err = backend.FixedWindow(ctx, "fw", 3, 3, time.Minute)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+	require.Equal(t, time.Minute, srv.TTL("fw"))
+}
+
+func TestRedisBackend_SlidingWindowLog(t *testing.T) {
+	srv, universal := startMiniredis(t)
+	backend := ratelimit.NewRedisBackend(universal, "")
+	ctx := context.Background()
+	now := time.UnixMilli(1_700_000_000_000)
+	advance := func(d time.Duration) {
+		now = now.Add(d)
+		srv.SetTime(now)
+		srv.FastForward(d)
+	}
+	srv.SetTime(now)
+
+	result, err := backend.SlidingWindowLog(ctx, "sw", 2, 3, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, ratelimit.Result{Allowed: true, Remaining: 1, ResetAfter: time.Second}, result)
+
+	advance(400 * time.Millisecond)
+
+	result, err = backend.SlidingWindowLog(ctx, "sw", 1, 3, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, ratelimit.Result{Allowed: true, Remaining: 0, ResetAfter: time.Second}, result)
+
+	result, err = backend.SlidingWindowLog(ctx, "sw", 1, 3, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, ratelimit.Result{Remaining: 0, RetryAfter: 600 * time.Millisecond, ResetAfter: time.Second}, result)
+
+	advance(600 * time.Millisecond)
+
+	result, err = backend.SlidingWindowLog(ctx, "sw", 2, 3, time.Second)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+	require.Equal(t, int64(0), result.Remaining)
+
+	members, err := srv.ZMembers("sw")
+	require.NoError(t, err)
+	require.Len(t, members, 3)
+}
+
+func TestRedisBackend_TokenBucket(t *testing.T) {
+	srv, universal := startMiniredis(t)
+	backend := ratelimit.NewRedisBackend(universal, "")
+	ctx := context.Background()
+	now := time.UnixMilli(1_700_000_000_000)
+	srv.SetTime(now)
+
+	result, err := backend.TokenBucket(ctx, "tb", 3, 4, 100*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, ratelimit.Result{Allowed: true, Remaining: 1, ResetAfter: 300 * time.Millisecond}, result)
+	require.Equal(t, 300*time.Millisecond, srv.TTL("tb"))
+
+	srv.SetTime(now.Add(50 * time.Millisecond))
+
+	result, err = backend.TokenBucket(ctx, "tb", 3, 4, 100*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, ratelimit.Result{
+		Remaining: 1, RetryAfter: 150 * time.Millisecond, ResetAfter: 250 * time.Millisecond,
+	}, result)
+
+	srv.SetTime(now.Add(time.Second))
+
+	result, err = backend.TokenBucket(ctx, "tb", 0, 4, 100*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, ratelimit.Result{Allowed: true, Remaining: 4}, result)
+	require.False(t, srv.Exists("tb"), "a full bucket is not stored")
+}
+
+// startMiniredis returns a miniredis server and a go-redis client connected to it.
+func startMiniredis(t *testing.T) (*miniredis.Miniredis, goredis.UniversalClient) {
+	t.Helper()
+	srv := miniredis.RunT(t)
+	universal := goredis.NewUniversalClient(&goredis.UniversalOptions{Addrs: []string{srv.Addr()}})
+	t.Cleanup(func() { _ = universal.Close() })
+	return srv, universal
+}
@@ -2,11 +2,14 @@
 package redis
 
 import (
+	"crypto/rand"
 	"crypto/tls"
 	"time"
 
 	"github.com/redis/go-redis/extra/redisotel/v9"
 	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
 )
 
 // Builder is a fluent / functional-options builder for the Redis LowLevelClient.
@@ -14,6 +17,9 @@ import (
 // UniversalOptions: standalone, Sentinel and Cluster.
 type Builder struct {
 	tlsConfig      *tls.Config
+	bus            InvalidationBus
+	clock          kvs.Clock
+	shard          ShardFunc
 	username       string
 	password       string
 	keyPrefix      string
@@ -22,14 +28,22 @@ type Builder struct {
 	tracingOpts    []redisotel.TracingOption
 	metricsOpts    []redisotel.MetricsOption
 	dialTimeout    time.Duration
+	trackingMaxAge time.Duration
 	readTimeout    time.Duration
 	writeTimeout   time.Duration
 	ttl            time.Duration
 	poolSize       int
 	db             int
+	trackingSize   int
+	trackingMode   TrackingMode
+	keyLayout      KeyLayout
+	storage        StorageMode
 	routeRandom    bool
+	migrateOnRead  bool
 	tracingEnabled bool
 	metricsEnabled bool
+	invalidation   bool
+	tracking       bool
 }
 
 // BuilderOptions configures a Builder. Used with the functional-options pattern.
@@ -62,12 +76,56 @@ func (r *Builder) WithKeyPrefix(prefix string) *Builder {
 	return r
 }
 
+// WithHashTaggedPrefix wraps the key prefix in a hash tag ("{prefix}:key") so
+// that every key of the container is stored in the same Redis Cluster hash
+// slot. Bulk operations then run as single MGET / MSET commands, and keys can
+// be combined in Lua scripts and transactions. It concentrates the container
+// on one Cluster node. See KeyLayoutHashTagPrefix.
+func (r *Builder) WithHashTaggedPrefix() *Builder {
+	r.keyLayout = KeyLayoutHashTagPrefix
+	return r
+}
+
+// WithShardFunc stores keys as "prefix:{shard(key)}:key" so that the keys
+// sharing a shard co-locate in one hash slot. See KeyLayoutShard.
+func (r *Builder) WithShardFunc(shard ShardFunc) *Builder {
+	r.keyLayout = KeyLayoutShard
+	r.shard = shard
+	return r
+}
+
+// WithStorageMode selects how items are stored: plain JSON strings
+// (StorageString, the default), hashes carrying metadata (StorageHash) or
+// RedisJSON documents carrying metadata (StorageJSON).
+func (r *Builder) WithStorageMode(mode StorageMode) *Builder {
+	r.storage = mode
+	return r
+}
+
+// WithLegacyMigration makes a StorageHash or StorageJSON client rewrite the
+// plain strings it reads as records, keeping their value and expiration.
+// Without it, they are only read. See also LowLevelClient.MigrateLegacy.
+func (r *Builder) WithLegacyMigration() *Builder {
+	r.migrateOnRead = true
+	return r
+}
+
 // WithTTL sets the default TTL applied to items that do not carry one.
 func (r *Builder) WithTTL(ttl time.Duration) *Builder {
 	r.ttl = ttl
 	return r
 }
 
+// WithClock sets the clock against which the absolute TTLs of items are turned
+// into Redis expirations, the records of StorageHash and StorageJSON are dated and the values of
+// the client-side cache age (kvs.SystemClock by default). FakeBuild gives it to
+// the FakeClient too, so that a kvs.FakeClock expires keys without waiting; the
+// clock of a MiniServer is moved with FastForward.
+func (r *Builder) WithClock(clock kvs.Clock) *Builder {
+	r.clock = clock
+	return r
+}
+
 // WithUsername sets the ACL username (Redis >= 6).
 func (r *Builder) WithUsername(username string) *Builder {
 	r.username = username
@@ -147,6 +205,43 @@ func (r *Builder) WithMetrics(opts ...redisotel.MetricsOption) *Builder {
 	return r
 }
 
+// WithInvalidation makes the built client publish an InvalidationEvent on the
+// topic of its container after every successful write, so that in-process
+// caches of other processes can evict stale entries. Build publishes through
+// Redis Pub/Sub on the same connection pool (see PubSubBus); FakeBuild uses a
+// private FakeBus.
+func (r *Builder) WithInvalidation() *Builder {
+	r.invalidation = true
+	return r
+}
+
+// WithInvalidationBus is like WithInvalidation but publishes through the
+// supplied bus, which is not closed with the client. Use it to share a bus
+// between clients, e.g. a FakeBus simulating several processes in tests.
+func (r *Builder) WithInvalidationBus(bus InvalidationBus) *Builder {
+	r.invalidation = true
+	r.bus = bus
+	return r
+}
+
+// WithClientTracking enables server-assisted client-side caching (Redis >= 6):
+// values read are kept in an in-process LRU of size entries, and Redis pushes
+// an invalidation whenever one of them changes (CLIENT TRACKING over RESP3).
+// TrackingBroadcast scopes the notifications to the configured key prefix.
+//
+// The cache is flushed whenever a connection is (re)established, since the
+// server forgets what a closed connection was tracking. Pushes are read when
+// go-redis next uses the receiving connection, so maxAge bounds how long a
+// value can be served after a change on an idle pool. Zero size and maxAge
+// select DefaultTrackingCacheSize and DefaultTrackingMaxAge.
+func (r *Builder) WithClientTracking(mode TrackingMode, size int, maxAge time.Duration) *Builder {
+	r.tracking = true
+	r.trackingMode = mode
+	r.trackingSize = size
+	r.trackingMaxAge = maxAge
+	return r
+}
+
 // ---------------------------------------------------------------------------
 // Functional options (mirror of the fluent setters)
 // ---------------------------------------------------------------------------
@@ -161,11 +256,45 @@ func WithKeyPrefix(prefix string) BuilderOptions {
 	return func(b *Builder) { b.keyPrefix = prefix }
 }
 
+// WithHashTaggedPrefix returns a BuilderOptions that wraps the key prefix in a
+// hash tag. See Builder.WithHashTaggedPrefix for details.
+func WithHashTaggedPrefix() BuilderOptions {
+	return func(b *Builder) { b.keyLayout = KeyLayoutHashTagPrefix }
+}
+
+// WithShardFunc returns a BuilderOptions that tags keys with the shard returned
+// by shard. See Builder.WithShardFunc for details.
+func WithShardFunc(shard ShardFunc) BuilderOptions {
+	return func(b *Builder) {
+		b.keyLayout = KeyLayoutShard
+		b.shard = shard
+	}
+}
+
+// WithStorageMode returns a BuilderOptions that selects how items are stored.
+// See Builder.WithStorageMode for details.
+func WithStorageMode(mode StorageMode) BuilderOptions {
+	return func(b *Builder) { b.storage = mode }
+}
+
+// WithLegacyMigration returns a BuilderOptions that migrates the legacy strings
+// read in StorageHash or StorageJSON mode. See Builder.WithLegacyMigration for
+// details.
+func WithLegacyMigration() BuilderOptions {
+	return func(b *Builder) { b.migrateOnRead = true }
+}
+
 // WithTTL returns a BuilderOptions that sets the default TTL.
 func WithTTL(ttl time.Duration) BuilderOptions {
 	return func(b *Builder) { b.ttl = ttl }
 }
 
+// WithClock returns a BuilderOptions that sets the clock of the TTL of items.
+// See Builder.WithClock for details.
+func WithClock(clock kvs.Clock) BuilderOptions {
+	return func(b *Builder) { b.clock = clock }
+}
+
 // WithUsername returns a BuilderOptions that sets the ACL username.
 func WithUsername(username string) BuilderOptions {
 	return func(b *Builder) { b.username = username }
@@ -228,6 +357,32 @@ func WithMetrics(opts ...redisotel.MetricsOption) BuilderOptions {
 	}
 }
 
+// WithInvalidation returns a BuilderOptions that enables invalidation events.
+// See Builder.WithInvalidation for details.
+func WithInvalidation() BuilderOptions {
+	return func(b *Builder) { b.invalidation = true }
+}
+
+// WithInvalidationBus returns a BuilderOptions that enables invalidation events
+// on the supplied bus. See Builder.WithInvalidationBus for details.
+func WithInvalidationBus(bus InvalidationBus) BuilderOptions {
+	return func(b *Builder) {
+		b.invalidation = true
+		b.bus = bus
+	}
+}
+
+// WithClientTracking returns a BuilderOptions that enables client-side caching.
+// See Builder.WithClientTracking for details.
+func WithClientTracking(mode TrackingMode, size int, maxAge time.Duration) BuilderOptions {
+	return func(b *Builder) {
+		b.tracking = true
+		b.trackingMode = mode
+		b.trackingSize = size
+		b.trackingMaxAge = maxAge
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Build
 // ---------------------------------------------------------------------------
@@ -244,7 +399,7 @@ func (r *Builder) Build() *LowLevelClient {
 		addrs = []string{"127.0.0.1:6379"}
 	}
 
-	universal := goredis.NewUniversalClient(&goredis.UniversalOptions{
+	options := &goredis.UniversalOptions{
 		Addrs:                 addrs,
 		MasterName:            r.masterName,
 		Username:              r.username,
@@ -257,11 +412,26 @@ func (r *Builder) Build() *LowLevelClient {
 		WriteTimeout:          r.writeTimeout,
 		RouteRandomly:         r.routeRandom,
 		ContextTimeoutEnabled: true,
-	})
+	}
+
+	var tracking *goRedisTracking
+	if r.tracking {
+		tracking = &goRedisTracking{}
+		options.Protocol = 3
+		options.OnConnect = tracking.onConnect
+	}
 
+	universal := goredis.NewUniversalClient(options)
 	r.instrument(universal)
 
-	return NewLowLevelClient(NewGoRedisClient(universal), r.keyPrefix, r.ttl)
+	llc := r.newLowLevelClient(NewGoRedisClient(universal), func() InvalidationBus {
+		return NewPubSubBus(universal)
+	})
+	if tracking != nil {
+		tracking.register(universal)
+		llc.enableTracking(tracking, r.trackingMode, r.trackingSize, r.trackingMaxAge)
+	}
+	return llc
 }
 
 // instrument attaches the requested OpenTelemetry hooks to the given client.
@@ -279,12 +449,49 @@ func (r *Builder) instrument(client goredis.UniversalClient) {
 
 // BuildWithClient creates a LowLevelClient using the provided Client.
 // Useful to inject custom adapters (for instrumentation, testing, etc.).
+// Invalidation events require a bus supplied through WithInvalidationBus, and
+// client tracking requires a client implementing Tracker.
 func (r *Builder) BuildWithClient(client Client) *LowLevelClient {
-	return NewLowLevelClient(client, r.keyPrefix, r.ttl)
+	return r.newLowLevelClient(client, nil)
 }
 
 // FakeBuild creates a LowLevelClient backed by an in-memory FakeClient.
 // Mirrors dynamodb.Builder.FakeBuild for symmetric ergonomics in tests.
 func (r *Builder) FakeBuild() *LowLevelClient {
-	return NewLowLevelClient(NewFakeClient(), r.keyPrefix, r.ttl)
+	var opts []FakeClientOptions
+	if r.clock != nil {
+		opts = append(opts, WithFakeClock(r.clock))
+	}
+	return r.newLowLevelClient(NewFakeClient(opts...), func() InvalidationBus {
+		return NewFakeBus()
+	})
+}
+
+// newLowLevelClient creates a LowLevelClient on top of client and attaches the
+// invalidation bus when enabled: the one supplied by WithInvalidationBus, or
+// else the one returned by newBus, which is then owned by the client.
+func (r *Builder) newLowLevelClient(client Client, newBus func() InvalidationBus) *LowLevelClient {
+	llc := NewLowLevelClient(client, r.keyPrefix, r.ttl)
+	llc.keyLayout, llc.shard = r.keyLayout, r.shard
+	llc.storage, llc.migrateOnRead = r.storage, r.migrateOnRead
+	if r.clock != nil {
+		llc.now = r.clock.Now
+	}
+	if tracker, ok := client.(Tracker); ok && r.tracking {
+		llc.enableTracking(tracker, r.trackingMode, r.trackingSize, r.trackingMaxAge)
+	}
+	if !r.invalidation {
+		return llc
+	}
+
+	switch {
+	case r.bus != nil:
+		llc.bus = r.bus
+	case newBus != nil:
+		llc.bus, llc.ownsBus = newBus(), true
+	default:
+		return llc
+	}
+	llc.origin = rand.Text()
+	return llc
 }
@@ -3,6 +3,7 @@ package redis
 
 import (
 	"context"
+	"iter"
 	"time"
 )
 
@@ -11,6 +12,10 @@ import (
 // kvs.Client[T] contract is consistent across providers.
 const MaxBulkKeys = 100
 
+// KeepTTL is the ttl passed to Client.CompareAndSwap to keep the current
+// expiration of the key.
+const KeepTTL time.Duration = -1
+
 // Pair represents a single key/value pair to be written through MSet.
 // TTL is the duration relative to "now" after which the entry must expire;
 // a zero or negative TTL means the entry should be persisted without expiration.
@@ -28,6 +33,88 @@ type GetResult struct {
 	Found bool
 }
 
+// Record is an item stored with its metadata: as a Redis hash by StorageHash,
+// with the fields "value", "ver", "codec", "created" and "expires", or as a
+// RedisJSON document with the same members by StorageJSON.
+type Record struct {
+	// Value is the encoded value.
+	Value string
+	// Codec names the encoding of Value (see kvs.CodecJSON).
+	Codec string
+	// Version is the number of writes of the record. It is maintained by the
+	// Client: the value passed to SetRecord and SetRecords is ignored.
+	Version int64
+	// Created is the Unix timestamp of the first write. It is maintained by the
+	// Client from the value passed on the first write.
+	Created int64
+	// Expires is the Unix timestamp after which the record is stale; zero means
+	// no expiration.
+	Expires int64
+	// Legacy reports that the key holds a plain string written by StorageString,
+	// returned as the Value of the record. Never set on writes.
+	Legacy bool
+	// Document stores the record as a RedisJSON document instead of a hash;
+	// Value must then be JSON. Set on reads of such records.
+	Document bool
+}
+
+// RecordResult represents the result of fetching a single key through
+// GetRecords. Found is false when the key does not exist or holds another type.
+type RecordResult struct {
+	Key    string
+	Record Record
+	Found  bool
+}
+
+// RecordPair represents a single record to be written through SetRecords.
+// TTL has the semantics of Pair.TTL.
+type RecordPair struct {
+	Key    string
+	Record Record
+	TTL    time.Duration
+}
+
+// RecordCondition makes SetRecord conditional on the current value of the key,
+// whether it is stored as a record or as a legacy string.
+type RecordCondition struct {
+	// Expected, when not nil, requires the current value to equal *Expected.
+	Expected *string
+	// IfAbsent requires the key not to exist.
+	IfAbsent bool
+	// KeepTTL preserves the current expiration of the key instead of applying
+	// the TTL passed to SetRecord.
+	KeepTTL bool
+}
+
+// FieldUpdate is an update applied by Client.PatchRecord to the field of the
+// value of a record at Path (see kvs.FieldPathSeparator). Delete removes the
+// field; otherwise it is set to Value, which must be JSON.
+type FieldUpdate struct {
+	Path   string
+	Value  string
+	Delete bool
+}
+
+// TxCheck is a condition of Client.Transact on the current value of a key,
+// compared like in CompareAndDelete.
+type TxCheck struct {
+	// Expected, when not nil, requires the current value to equal *Expected;
+	// nil requires the key not to exist.
+	Expected *string
+	Key      string
+}
+
+// TxWrite is a write applied by Client.Transact. Delete removes the key; otherwise
+// Record, when not nil, is stored as by SetRecord, and Value as by Set.
+// TTL has the semantics of Pair.TTL.
+type TxWrite struct {
+	Record *Record
+	Key    string
+	Value  string
+	TTL    time.Duration
+	Delete bool
+}
+
 // Client is the minimal Redis interface required by LowLevelClient.
 //
 // Keeping this interface narrow has two benefits:
@@ -48,13 +135,86 @@ type Client interface {
 
 	// MGet fetches multiple keys in a single round-trip when possible.
 	// The returned slice has the same length as the input keys and preserves
-	// their order; missing keys are reported with Found == false.
+	// their order; missing keys are reported with Found == false. As with
+	// MGET, a key holding another type than a string (such as a Record) is
+	// reported as missing rather than failing with WRONGTYPE.
 	MGet(ctx context.Context, keys []string) ([]GetResult, error)
 
 	// MSet stores multiple pairs in a single round-trip when possible.
 	// Implementations SHOULD honour per-item TTL.
 	MSet(ctx context.Context, pairs []Pair) error
 
+	// SetNX stores the value for the given key only if the key does not exist
+	// (SET NX PX) and reports whether it was stored. A non-positive ttl means
+	// the entry has no expiration.
+	SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+
+	// CompareAndDelete atomically removes key only if its current value equals
+	// expected, and reports whether it was removed. The value of a key stored
+	// as a Record is its Value.
+	CompareAndDelete(ctx context.Context, key, expected string) (bool, error)
+
+	// CompareAndSwap atomically replaces the value of key with value only if
+	// its current value equals expected, and reports whether it was replaced.
+	// A zero ttl means the new value has no expiration; a negative ttl (KeepTTL)
+	// keeps the current expiration.
+	CompareAndSwap(ctx context.Context, key, expected, value string, ttl time.Duration) (bool, error)
+
+	// Del removes the given key. Removing a missing key is not an error.
+	Del(ctx context.Context, key string) error
+
+	// IncrBy atomically increments the integer stored at key by delta and
+	// returns the new value. When the key is created by this call and ttl is
+	// positive, the key is set to expire after ttl. A key stored as a Record has
+	// its Value incremented and its Version bumped.
+	IncrBy(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
+
+	// IncrByFloat is like IncrBy for floating-point values.
+	IncrByFloat(ctx context.Context, key string, delta float64, ttl time.Duration) (float64, error)
+
+	// GetRecords fetches the records stored at keys, in a single round-trip when
+	// possible. Keys holding a plain string are returned as Legacy records. The
+	// returned slice has the same length as the input keys and preserves their order.
+	GetRecords(ctx context.Context, keys []string) ([]RecordResult, error)
+
+	// SetRecords stores multiple records, replacing any previous value, in a
+	// single round-trip when possible.
+	SetRecords(ctx context.Context, pairs []RecordPair) error
+
+	// SetRecord atomically stores record at key when condition holds, replacing
+	// any previous value, and reports whether it was stored. A non-positive ttl
+	// means the record has no expiration.
+	SetRecord(
+		ctx context.Context,
+		key string,
+		record Record,
+		ttl time.Duration,
+		condition RecordCondition,
+	) (bool, error)
+
+	// PatchRecord atomically applies updates to the value of the record stored
+	// as a document at key (see Record.Document), bumps its version and keeps
+	// its expiration. It reports false when key does not hold a document. No
+	// update is applied when the value is not a JSON object (kvs.ErrConvert) or
+	// when the parent of a field is not an object (kvs.ErrFieldPath).
+	PatchRecord(ctx context.Context, key string, updates []FieldUpdate) (bool, error)
+
+	// GetRecordFields fetches the record stored as a document at key with its
+	// value projected on the field paths, as by kvs.ProjectJSON. Found is false
+	// when key does not hold a document.
+	GetRecordFields(ctx context.Context, key string, paths []string) (RecordResult, error)
+
+	// Transact atomically applies writes, in order, when every check holds, and
+	// returns -1. Otherwise nothing is written and the index of the first check
+	// that does not hold is returned.
+	Transact(ctx context.Context, checks []TxCheck, writes []TxWrite) (int, error)
+
+	// Scan iterates over the keys matching the glob-style pattern (as accepted by
+	// the Redis SCAN MATCH option). count is a hint for the number of keys
+	// examined per round-trip. Keys are yielded lazily; an error is yielded at
+	// most once and terminates the sequence.
+	Scan(ctx context.Context, match string, count int64) iter.Seq2[string, error]
+
 	// Close releases any resources held by the client.
 	// Calling Close on an already closed client is a no-op.
 	Close() error
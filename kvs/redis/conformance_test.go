@@ -0,0 +1,61 @@
+package redis_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	"github.com/arielsrv/go-kvs-client/kvs/kvstest"
+	kvsredis "github.com/arielsrv/go-kvs-client/kvs/redis"
+)
+
+func TestConformance_FakeBuild(t *testing.T) {
+	for name, mode := range map[string]kvsredis.StorageMode{
+		"string": kvsredis.StorageString,
+		"hash":   kvsredis.StorageHash,
+		"json":   kvsredis.StorageJSON,
+	} {
+		t.Run(name, func(t *testing.T) {
+			kvstest.RunConformance(t, func(t *testing.T, ttl time.Duration) kvstest.Backend {
+				clock := kvs.NewFakeClock(time.Unix(1_700_000_000, 0))
+				return kvstest.Backend{
+					Client: kvsredis.NewBuilder(
+						kvsredis.WithKeyPrefix("__kvs:users"),
+						kvsredis.WithTTL(ttl),
+						kvsredis.WithClock(clock),
+						kvsredis.WithStorageMode(mode),
+					).FakeBuild(),
+					Now:         clock.Now,
+					Advance:     clock.Advance,
+					MaxBulkKeys: kvsredis.MaxBulkKeys,
+				}
+			})
+		})
+	}
+}
+
+func TestConformance_MiniBuild(t *testing.T) {
+	for name, mode := range map[string]kvsredis.StorageMode{
+		"string": kvsredis.StorageString,
+		"hash":   kvsredis.StorageHash,
+	} {
+		t.Run(name, func(t *testing.T) {
+			kvstest.RunConformance(t, func(t *testing.T, ttl time.Duration) kvstest.Backend {
+				client, server, err := kvsredis.NewBuilder(
+					kvsredis.WithKeyPrefix("__kvs:users"),
+					kvsredis.WithTTL(ttl),
+					kvsredis.WithStorageMode(mode),
+				).MiniBuild()
+				require.NoError(t, err)
+				t.Cleanup(func() { _ = client.Close() })
+				return kvstest.Backend{
+					Client:      client,
+					Advance:     server.FastForward,
+					MaxBulkKeys: kvsredis.MaxBulkKeys,
+				}
+			})
+		})
+	}
+}
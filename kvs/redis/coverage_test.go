@@ -83,17 +83,18 @@ func TestLowLevelClient_BulkSaveWithContext_AllItemsFiltered_IsNoOp(t *testing.T
 	require.NoError(t, client.BulkSave(items))
 }
 
-func TestGoRedisClient_MGet_PropagatesPerKeyError(t *testing.T) {
-	// Store one key as a hash so the per-key GET returns WRONGTYPE, which
-	// flows through pipeline.Exec as a non-Nil error but the individual
-	// cmd.Result() also fails — exercising the `case err != nil` branch of
-	// MGet that is otherwise hard to reach.
+func TestGoRedisClient_MGet_NonStringKey_IsNotFound(t *testing.T) {
+	// MGET reports keys holding another type as missing instead of failing
+	// with WRONGTYPE, as a per-key GET would.
 	srv, client := startMiniredis(t)
 
 	require.NoError(t, client.Set(context.Background(), "ok", "1", 0))
-	// HSET makes "wrong" a hash; GET on it returns WRONGTYPE.
 	srv.HSet("wrong", "field", "value")
 
-	_, err := client.MGet(context.Background(), []string{"ok", "wrong"})
-	require.Error(t, err)
+	results, err := client.MGet(context.Background(), []string{"ok", "wrong"})
+	require.NoError(t, err)
+	require.Equal(t, []kvsredis.GetResult{
+		{Key: "ok", Value: "1", Found: true},
+		{Key: "wrong"},
+	}, results)
 }
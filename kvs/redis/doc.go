@@ -14,7 +14,21 @@
 //     redis.UniversalClient.
 //   - FakeClient: in-memory implementation of Client used for unit tests (and
 //     exposed through Builder.FakeBuild).
+//   - MiniServer: embedded miniredis server started by Builder.MiniBuild, which
+//     returns a GoRedisClient-backed LowLevelClient for tests without Redis.
 //   - Builder: fluent / functional-options builder that wires everything together.
+//   - InvalidationBus: broadcasts key-change events so that in-process caches of
+//     other processes can evict stale entries. PubSubBus uses Redis Pub/Sub;
+//     FakeBus is its in-memory counterpart for tests.
+//   - Client tracking: an opt-in in-process LRU of the values read, invalidated
+//     by Redis through CLIENT TRACKING pushes (Builder.WithClientTracking).
+//   - KeyLayout: optional hash-tagged key layouts (Builder.WithHashTaggedPrefix,
+//     Builder.WithShardFunc) that co-locate related keys in one Cluster hash slot.
+//   - StorageMode: items are stored as JSON strings or, with StorageHash and
+//     StorageJSON, as hashes or RedisJSON documents carrying version, codec
+//     and timestamps (Builder.WithStorageMode).
+//   - Transactions: LowLevelClient.NewTx commits multi-key writes and condition
+//     checks atomically with WATCH and MULTI/EXEC (Client.Transact).
 //
 // Usage:
 //
@@ -3,6 +3,7 @@ package redis_test
 import (
 	"context"
 	"errors"
+	"iter"
 	"strings"
 	"testing"
 	"time"
@@ -34,6 +35,62 @@ func (c *erroringClient) MSet(_ context.Context, _ []kvsredis.Pair) error {
 	return c.err
 }
 
+func (c *erroringClient) SetNX(_ context.Context, _, _ string, _ time.Duration) (bool, error) {
+	return false, c.err
+}
+
+func (c *erroringClient) CompareAndDelete(_ context.Context, _, _ string) (bool, error) {
+	return false, c.err
+}
+
+func (c *erroringClient) CompareAndSwap(_ context.Context, _, _, _ string, _ time.Duration) (bool, error) {
+	return false, c.err
+}
+
+func (c *erroringClient) Del(_ context.Context, _ string) error {
+	return c.err
+}
+
+func (c *erroringClient) IncrBy(_ context.Context, _ string, _ int64, _ time.Duration) (int64, error) {
+	return 0, c.err
+}
+
+func (c *erroringClient) IncrByFloat(_ context.Context, _ string, _ float64, _ time.Duration) (float64, error) {
+	return 0, c.err
+}
+
+func (c *erroringClient) GetRecords(_ context.Context, _ []string) ([]kvsredis.RecordResult, error) {
+	return nil, c.err
+}
+
+func (c *erroringClient) SetRecords(_ context.Context, _ []kvsredis.RecordPair) error {
+	return c.err
+}
+
+func (c *erroringClient) SetRecord(
+	_ context.Context, _ string, _ kvsredis.Record, _ time.Duration, _ kvsredis.RecordCondition,
+) (bool, error) {
+	return false, c.err
+}
+
+func (c *erroringClient) PatchRecord(_ context.Context, _ string, _ []kvsredis.FieldUpdate) (bool, error) {
+	return false, c.err
+}
+
+func (c *erroringClient) GetRecordFields(_ context.Context, _ string, _ []string) (kvsredis.RecordResult, error) {
+	return kvsredis.RecordResult{}, c.err
+}
+
+func (c *erroringClient) Transact(
+	_ context.Context, _ []kvsredis.TxCheck, _ []kvsredis.TxWrite,
+) (int, error) {
+	return -1, c.err
+}
+
+func (c *erroringClient) Scan(_ context.Context, _ string, _ int64) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) { yield("", c.err) }
+}
+
 func (c *erroringClient) Close() error { return nil }
 
 func TestLowLevelClient_Get_PropagatesClientError(t *testing.T) {
@@ -117,3 +174,13 @@ func TestFakeClient_Keys_FiltersByPrefix(t *testing.T) {
 
 	require.Len(t, fake.Keys(""), 3)
 }
+
+func TestLowLevelClient_Scan_PropagatesClientError(t *testing.T) {
+	want := errors.New("boom")
+	client := kvsredis.NewLowLevelClient(&erroringClient{err: want}, "p")
+
+	var err error
+	for _, err = range client.Scan(context.Background(), kvs.ScanOptions{}) {
+	}
+	require.ErrorIs(t, err, want)
+}
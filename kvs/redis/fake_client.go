@@ -3,7 +3,11 @@ package redis
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"iter"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -15,49 +19,83 @@ import (
 // and for the Builder.FakeBuild constructor. It honours TTL semantics
 // (entries are evicted lazily on access) and is safe for concurrent use.
 type FakeClient struct {
-	now     func() time.Time
-	entries map[string]fakeEntry
-	mu      sync.RWMutex
-	closed  bool
+	now      func() time.Time
+	entries  map[string]fakeEntry
+	tracking *fakeTracking
+	mu       sync.RWMutex
+	closed   bool
 }
 
 type fakeEntry struct {
 	expiresAt time.Time // zero means "no expiration"
+	record    *Record   // non-nil for a Record (a Redis hash)
 	value     string
 }
 
+// current returns the value compared by the conditional operations: the value
+// of a Record, or the string itself.
+func (r fakeEntry) current() string {
+	if r.record != nil {
+		return r.record.Value
+	}
+	return r.value
+}
+
+// FakeClientOptions configures a FakeClient.
+type FakeClientOptions func(*FakeClient)
+
+// WithFakeClock returns a FakeClientOptions that sets the clock against which
+// entries expire (kvs.SystemClock by default), so that TTLs can be fast-forwarded
+// with a kvs.FakeClock.
+func WithFakeClock(clock kvs.Clock) FakeClientOptions {
+	return func(f *FakeClient) {
+		f.now = clock.Now
+	}
+}
+
 // NewFakeClient returns a fresh FakeClient backed by an internal map.
-func NewFakeClient() *FakeClient {
-	return &FakeClient{
+func NewFakeClient(opts ...FakeClientOptions) *FakeClient {
+	fake := &FakeClient{
 		entries: make(map[string]fakeEntry),
 		now:     time.Now,
 	}
+	for _, opt := range opts {
+		opt(fake)
+	}
+	return fake
 }
 
 // Get implements Client.
 func (r *FakeClient) Get(_ context.Context, key string) (string, error) {
-	r.mu.RLock()
-	entry, ok := r.entries[key]
-	closed := r.closed
-	r.mu.RUnlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	if closed {
+	if r.closed {
 		return "", kvs.ErrInternal
 	}
-	if !ok || r.expired(entry) {
-		// Best-effort lazy eviction.
-		if ok {
-			r.mu.Lock()
-			if e, stillThere := r.entries[key]; stillThere && r.expired(e) {
-				delete(r.entries, key)
-			}
-			r.mu.Unlock()
-		}
+
+	entry, ok := r.entries[key]
+	if ok && r.expired(entry) {
+		// Lazy eviction, reported to tracking like a Redis expiration.
+		delete(r.entries, key)
+		r.touch(key)
+		ok = false
+	}
+	r.track(key)
+
+	if !ok {
 		return "", kvs.ErrKeyNotFound
 	}
+	if entry.record != nil {
+		return "", errFakeWrongType
+	}
 	return entry.value, nil
 }
 
+// errFakeWrongType is returned by FakeClient.Get for keys holding a Record,
+// like the WRONGTYPE error of Redis.
+const errFakeWrongType = kvs.KeyValueError("WRONGTYPE Operation against a key holding the wrong kind of value")
+
 // Set implements Client.
 func (r *FakeClient) Set(_ context.Context, key, value string, ttl time.Duration) error {
 	r.mu.Lock()
@@ -72,6 +110,7 @@ func (r *FakeClient) Set(_ context.Context, key, value string, ttl time.Duration
 		entry.expiresAt = r.now().Add(ttl)
 	}
 	r.entries[key] = entry
+	r.touch(key)
 	return nil
 }
 
@@ -83,7 +122,8 @@ func (r *FakeClient) MGet(ctx context.Context, keys []string) ([]GetResult, erro
 		switch {
 		case err == nil:
 			results[i] = GetResult{Key: key, Value: value, Found: true}
-		case errors.Is(err, kvs.ErrKeyNotFound):
+		case errors.Is(err, kvs.ErrKeyNotFound), errors.Is(err, errFakeWrongType):
+			// Like MGET, keys holding another type are reported as missing.
 			results[i] = GetResult{Key: key, Found: false}
 		default:
 			return nil, err
@@ -102,6 +142,416 @@ func (r *FakeClient) MSet(ctx context.Context, pairs []Pair) error {
 	return nil
 }
 
+// SetNX implements Client.
+func (r *FakeClient) SetNX(_ context.Context, key, value string, ttl time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return false, kvs.ErrInternal
+	}
+
+	if entry, ok := r.entries[key]; ok && !r.expired(entry) {
+		return false, nil
+	}
+
+	entry := fakeEntry{value: value}
+	if ttl > 0 {
+		entry.expiresAt = r.now().Add(ttl)
+	}
+	r.entries[key] = entry
+	r.touch(key)
+	return true, nil
+}
+
+// CompareAndDelete implements Client.
+func (r *FakeClient) CompareAndDelete(_ context.Context, key, expected string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return false, kvs.ErrInternal
+	}
+
+	entry, ok := r.entries[key]
+	if !ok || r.expired(entry) || entry.current() != expected {
+		return false, nil
+	}
+
+	delete(r.entries, key)
+	r.touch(key)
+	return true, nil
+}
+
+// CompareAndSwap implements Client.
+func (r *FakeClient) CompareAndSwap(
+	_ context.Context,
+	key, expected, value string,
+	ttl time.Duration,
+) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return false, kvs.ErrInternal
+	}
+
+	entry, ok := r.entries[key]
+	if !ok || r.expired(entry) || entry.record != nil || entry.value != expected {
+		return false, nil
+	}
+
+	expiresAt := entry.expiresAt
+	entry = fakeEntry{value: value}
+	switch {
+	case ttl > 0:
+		entry.expiresAt = r.now().Add(ttl)
+	case ttl < 0:
+		entry.expiresAt = expiresAt
+	}
+	r.entries[key] = entry
+	r.touch(key)
+	return true, nil
+}
+
+// Del implements Client.
+func (r *FakeClient) Del(_ context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return kvs.ErrInternal
+	}
+
+	delete(r.entries, key)
+	r.touch(key)
+	return nil
+}
+
+// IncrBy implements Client.
+// Incrementing a value that is not an integer returns kvs.ErrConvert.
+func (r *FakeClient) IncrBy(_ context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	var result int64
+	err := r.increment(key, ttl, func(current string) (string, error) {
+		value, err := strconv.ParseInt(current, 10, 64)
+		if err != nil {
+			return "", kvs.ErrConvert
+		}
+		result = value + delta
+		return strconv.FormatInt(result, 10), nil
+	})
+	return result, err
+}
+
+// IncrByFloat implements Client.
+// Incrementing a value that is not a number returns kvs.ErrConvert.
+func (r *FakeClient) IncrByFloat(_ context.Context, key string, delta float64, ttl time.Duration) (float64, error) {
+	var result float64
+	err := r.increment(key, ttl, func(current string) (string, error) {
+		value, err := strconv.ParseFloat(current, 64)
+		if err != nil {
+			return "", kvs.ErrConvert
+		}
+		result = value + delta
+		return strconv.FormatFloat(result, 'f', -1, 64), nil
+	})
+	return result, err
+}
+
+// increment applies update to the current value of key (which reads as "0"
+// when missing or expired) under the write lock. The expiration is only set
+// when the entry is created.
+func (r *FakeClient) increment(key string, ttl time.Duration, update func(current string) (string, error)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return kvs.ErrInternal
+	}
+
+	entry, ok := r.entries[key]
+	if !ok || r.expired(entry) {
+		entry = fakeEntry{value: "0"}
+		if ttl > 0 {
+			entry.expiresAt = r.now().Add(ttl)
+		}
+	}
+
+	value, err := update(entry.current())
+	if err != nil {
+		return err
+	}
+
+	if entry.record != nil {
+		record := *entry.record
+		record.Value = value
+		record.Version++
+		entry.record = &record
+	} else {
+		entry.value = value
+	}
+	r.entries[key] = entry
+	r.touch(key)
+	return nil
+}
+
+// GetRecords implements Client.
+func (r *FakeClient) GetRecords(_ context.Context, keys []string) ([]RecordResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil, kvs.ErrInternal
+	}
+
+	results := make([]RecordResult, len(keys))
+	for i, key := range keys {
+		results[i] = RecordResult{Key: key}
+		entry, ok := r.entries[key]
+		if ok && r.expired(entry) {
+			delete(r.entries, key)
+			r.touch(key)
+			ok = false
+		}
+		r.track(key)
+
+		switch {
+		case !ok:
+		case entry.record != nil:
+			results[i].Record, results[i].Found = *entry.record, true
+		default:
+			results[i].Record, results[i].Found = Record{Value: entry.value, Legacy: true}, true
+		}
+	}
+	return results, nil
+}
+
+// SetRecords implements Client.
+func (r *FakeClient) SetRecords(ctx context.Context, pairs []RecordPair) error {
+	for _, pair := range pairs {
+		if _, err := r.SetRecord(ctx, pair.Key, pair.Record, pair.TTL, RecordCondition{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetRecord implements Client.
+func (r *FakeClient) SetRecord(
+	_ context.Context,
+	key string,
+	record Record,
+	ttl time.Duration,
+	condition RecordCondition,
+) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return false, kvs.ErrInternal
+	}
+
+	current, ok := r.entries[key]
+	if ok && r.expired(current) {
+		current, ok = fakeEntry{}, false
+	}
+	if (condition.IfAbsent && ok) ||
+		(condition.Expected != nil && (!ok || current.current() != *condition.Expected)) {
+		return false, nil
+	}
+
+	record.Legacy, record.Version = false, 1
+	if current.record != nil {
+		record.Version = current.record.Version + 1
+		record.Created = current.record.Created
+	}
+
+	entry := fakeEntry{record: &record}
+	switch {
+	case condition.KeepTTL:
+		entry.expiresAt = current.expiresAt
+	case ttl > 0:
+		entry.expiresAt = r.now().Add(ttl)
+	}
+	r.entries[key] = entry
+	r.touch(key)
+	return true, nil
+}
+
+// PatchRecord implements Client with kvs.PatchJSON.
+func (r *FakeClient) PatchRecord(_ context.Context, key string, updates []FieldUpdate) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return false, kvs.ErrInternal
+	}
+
+	entry, ok := r.entries[key]
+	if !ok || r.expired(entry) || entry.record == nil || !entry.record.Document {
+		return false, nil
+	}
+
+	patch := make(map[string]any, len(updates))
+	for _, update := range updates {
+		if update.Delete {
+			patch[update.Path] = nil
+			continue
+		}
+		patch[update.Path] = json.RawMessage(update.Value)
+	}
+	value, err := kvs.PatchJSON(entry.record.Value, patch)
+	if err != nil {
+		return false, err
+	}
+
+	record := *entry.record
+	record.Value = value
+	record.Version++
+	entry.record = &record
+	r.entries[key] = entry
+	r.touch(key)
+	return true, nil
+}
+
+// GetRecordFields implements Client with kvs.ProjectJSON.
+func (r *FakeClient) GetRecordFields(_ context.Context, key string, paths []string) (RecordResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := RecordResult{Key: key}
+	if r.closed {
+		return result, kvs.ErrInternal
+	}
+
+	entry, ok := r.entries[key]
+	if ok && r.expired(entry) {
+		delete(r.entries, key)
+		r.touch(key)
+		ok = false
+	}
+	r.track(key)
+	if !ok || entry.record == nil || !entry.record.Document {
+		return result, nil
+	}
+
+	value, err := kvs.ProjectJSON(entry.record.Value, paths...)
+	if err != nil {
+		return result, err
+	}
+	result.Found, result.Record = true, *entry.record
+	result.Record.Value = value
+	return result, nil
+}
+
+// Transact implements Client. Checks and writes are applied under the write lock.
+func (r *FakeClient) Transact(_ context.Context, checks []TxCheck, writes []TxWrite) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return -1, kvs.ErrInternal
+	}
+
+	for i, check := range checks {
+		current, ok := r.entries[check.Key]
+		if ok && r.expired(current) {
+			ok = false
+		}
+		if (check.Expected == nil && ok) ||
+			(check.Expected != nil && (!ok || current.current() != *check.Expected)) {
+			return i, nil
+		}
+	}
+
+	for _, write := range writes {
+		current, ok := r.entries[write.Key]
+		switch {
+		case write.Delete:
+			delete(r.entries, write.Key)
+		case write.Record != nil:
+			record := *write.Record
+			record.Legacy, record.Version = false, 1
+			if ok && !r.expired(current) && current.record != nil {
+				record.Version = current.record.Version + 1
+				record.Created = current.record.Created
+			}
+			r.entries[write.Key] = r.newEntry(fakeEntry{record: &record}, write.TTL)
+		default:
+			r.entries[write.Key] = r.newEntry(fakeEntry{value: write.Value}, write.TTL)
+		}
+		r.touch(write.Key)
+	}
+	return -1, nil
+}
+
+// newEntry sets the expiration of entry to ttl from now, when positive.
+func (r *FakeClient) newEntry(entry fakeEntry, ttl time.Duration) fakeEntry {
+	if ttl > 0 {
+		entry.expiresAt = r.now().Add(ttl)
+	}
+	return entry
+}
+
+// Scan implements Client.
+// The key space is snapshotted (in lexical order) when iteration starts;
+// expired entries are skipped. The match pattern supports the "*", "?" and
+// backslash-escape subset of the Redis glob syntax. count is ignored.
+func (r *FakeClient) Scan(_ context.Context, match string, _ int64) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		r.mu.RLock()
+		if r.closed {
+			r.mu.RUnlock()
+			yield("", kvs.ErrInternal)
+			return
+		}
+		keys := make([]string, 0, len(r.entries))
+		for key, entry := range r.entries {
+			if !r.expired(entry) && matchGlob(match, key) {
+				keys = append(keys, key)
+			}
+		}
+		r.mu.RUnlock()
+
+		slices.Sort(keys)
+		for _, key := range keys {
+			if !yield(key, nil) {
+				return
+			}
+		}
+	}
+}
+
+// EnableTracking implements Tracker. Like Redis, the default mode reports a
+// key once after it has been read (reading it again re-arms the notification),
+// and the broadcast mode reports every change of a key under prefixes. Writes
+// made through the client itself are reported too. The callback is invoked
+// synchronously, before the write returns.
+func (r *FakeClient) EnableTracking(mode TrackingMode, prefixes []string, onInvalidate func(keys []string)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tracking = &fakeTracking{
+		mode:         mode,
+		prefixes:     prefixes,
+		onInvalidate: onInvalidate,
+		keys:         make(map[string]struct{}),
+	}
+}
+
+// Reconnect simulates the connection being re-established: tracking state is
+// lost on the server, so the tracking callback is asked to flush everything.
+func (r *FakeClient) Reconnect() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.tracking == nil {
+		return
+	}
+	clear(r.tracking.keys)
+	r.tracking.onInvalidate(nil)
+}
+
 // Close implements Client.
 func (r *FakeClient) Close() error {
 	r.mu.Lock()
@@ -134,9 +584,83 @@ func (r *FakeClient) Keys(prefix string) []string {
 	return out
 }
 
+// fakeTracking is the client tracking state of a FakeClient, guarded by the
+// FakeClient lock.
+type fakeTracking struct {
+	onInvalidate func(keys []string)
+	keys         map[string]struct{}
+	prefixes     []string
+	mode         TrackingMode
+}
+
+// track records that key has been read, in the default tracking mode.
+// It must be called with the write lock held.
+func (r *FakeClient) track(key string) {
+	tracking := r.tracking
+	if tracking == nil || tracking.mode != TrackingDefault {
+		return
+	}
+	tracking.keys[key] = struct{}{}
+}
+
+// touch reports a change of key to the tracking callback, if the key is
+// tracked. It must be called with the write lock held.
+func (r *FakeClient) touch(key string) {
+	tracking := r.tracking
+	if tracking == nil {
+		return
+	}
+
+	notify := false
+	switch tracking.mode {
+	case TrackingBroadcast:
+		notify = len(tracking.prefixes) == 0 ||
+			slices.ContainsFunc(tracking.prefixes, func(prefix string) bool { return strings.HasPrefix(key, prefix) })
+	default:
+		_, notify = tracking.keys[key]
+		delete(tracking.keys, key)
+	}
+
+	if notify {
+		tracking.onInvalidate([]string{key})
+	}
+}
+
 func (r *FakeClient) expired(entry fakeEntry) bool {
 	if entry.expiresAt.IsZero() {
 		return false
 	}
 	return !r.now().Before(entry.expiresAt)
 }
+
+// matchGlob reports whether key matches the Redis glob-style pattern.
+// Only "*", "?" and backslash escapes are supported, which covers the
+// patterns produced by LowLevelClient.Scan.
+func matchGlob(pattern, key string) bool {
+	for pattern != "" {
+		switch pattern[0] {
+		case '*':
+			rest := pattern[1:]
+			for i := len(key); i >= 0; i-- {
+				if matchGlob(rest, key[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if key == "" {
+				return false
+			}
+			pattern, key = pattern[1:], key[1:]
+		default:
+			if pattern[0] == '\\' && len(pattern) > 1 {
+				pattern = pattern[1:]
+			}
+			if key == "" || key[0] != pattern[0] {
+				return false
+			}
+			pattern, key = pattern[1:], key[1:]
+		}
+	}
+	return key == ""
+}
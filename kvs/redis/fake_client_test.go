@@ -96,3 +96,104 @@ func TestFakeClient_ConcurrentAccess(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "v", value)
 }
+
+func TestFakeClient_Scan_MatchesGlobAndSkipsExpired(t *testing.T) {
+	fake := kvsredis.NewFakeClient()
+	ctx := context.Background()
+
+	require.NoError(t, fake.Set(ctx, "users:1", "a", 0))
+	require.NoError(t, fake.Set(ctx, "users:2", "b", time.Millisecond))
+	require.NoError(t, fake.Set(ctx, "users:10", "c", 0))
+	require.NoError(t, fake.Set(ctx, "carts:1", "d", 0))
+
+	time.Sleep(10 * time.Millisecond)
+
+	keys := make([]string, 0, 2)
+	for key, err := range fake.Scan(ctx, "users:*", 10) {
+		require.NoError(t, err)
+		keys = append(keys, key)
+	}
+	require.Equal(t, []string{"users:1", "users:10"}, keys)
+
+	keys = keys[:0]
+	for key, err := range fake.Scan(ctx, "users:?", 10) {
+		require.NoError(t, err)
+		keys = append(keys, key)
+	}
+	require.Equal(t, []string{"users:1"}, keys)
+}
+
+func TestFakeClient_Scan_AfterClose_YieldsError(t *testing.T) {
+	fake := kvsredis.NewFakeClient()
+	require.NoError(t, fake.Close())
+
+	var err error
+	for _, err = range fake.Scan(context.Background(), "*", 10) {
+	}
+	require.ErrorIs(t, err, kvs.ErrInternal)
+}
+
+func TestFakeClient_IncrBy_TTLOnCreate(t *testing.T) {
+	fake := kvsredis.NewFakeClient()
+	ctx := context.Background()
+
+	value, err := fake.IncrBy(ctx, "c", 1, 30*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), value)
+
+	value, err = fake.IncrBy(ctx, "c", 1, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), value)
+
+	time.Sleep(80 * time.Millisecond)
+
+	value, err = fake.IncrBy(ctx, "c", 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), value)
+}
+
+func TestFakeClient_IncrBy_NonNumeric_ReturnsErrConvert(t *testing.T) {
+	fake := kvsredis.NewFakeClient()
+	ctx := context.Background()
+
+	require.NoError(t, fake.Set(ctx, "s", `"text"`, 0))
+
+	_, err := fake.IncrBy(ctx, "s", 1, 0)
+	require.ErrorIs(t, err, kvs.ErrConvert)
+
+	_, err = fake.IncrByFloat(ctx, "s", 1, 0)
+	require.ErrorIs(t, err, kvs.ErrConvert)
+}
+
+func TestFakeClient_SetNX_CompareAndDelete(t *testing.T) {
+	fake := kvsredis.NewFakeClient()
+	ctx := context.Background()
+
+	ok, err := fake.SetNX(ctx, "l", "a", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = fake.SetNX(ctx, "l", "b", time.Minute)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = fake.CompareAndDelete(ctx, "l", "b")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = fake.CompareAndDelete(ctx, "l", "a")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, err = fake.Get(ctx, "l")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+}
+
+func TestFakeClient_Del_AfterClose_ReturnsErrInternal(t *testing.T) {
+	fake := kvsredis.NewFakeClient()
+	require.NoError(t, fake.Close())
+
+	require.ErrorIs(t, fake.Del(context.Background(), "k"), kvs.ErrInternal)
+	_, err := fake.IncrBy(context.Background(), "k", 1, 0)
+	require.ErrorIs(t, err, kvs.ErrInternal)
+}
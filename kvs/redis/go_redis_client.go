@@ -3,7 +3,12 @@ package redis
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"iter"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	goredis "github.com/redis/go-redis/v9"
@@ -15,18 +20,41 @@ import (
 // github.com/redis/go-redis/v9. It transparently supports standalone,
 // Sentinel and Cluster deployments through redis.UniversalClient.
 //
-// All bulk operations are implemented with pipelines so they remain
-// correct under Redis Cluster (where MGET/MSET are not allowed across
-// hash slots), while still keeping the number of round-trips minimal.
+// Bulk operations use MGET / MSET. Under Redis Cluster, where they are not
+// allowed across hash slots, keys are grouped per slot in a single pipeline;
+// a hash-tagged key layout (see KeyLayout) keeps related keys in one slot.
 type GoRedisClient struct {
-	client goredis.UniversalClient
+	client  goredis.UniversalClient
+	cluster bool
 }
 
+// incrByScript increments a key and sets its expiration only when the key is
+// created by the call, so repeated increments never extend the TTL.
+// A key stored as a Record has its value incremented and its version bumped.
+var incrByScript = goredis.NewScript(`
+local kind = redis.call('TYPE', KEYS[1])['ok']
+if kind == 'hash' then
+	redis.call('HINCRBY', KEYS[1], 'ver', 1)
+	return redis.call('H' .. ARGV[1], KEYS[1], 'value', ARGV[2])
+end
+if kind == 'ReJSON-RL' then
+	redis.call('JSON.NUMINCRBY', KEYS[1], '.ver', 1)
+	return redis.call('JSON.NUMINCRBY', KEYS[1], '.value', ARGV[2])
+end
+local created = redis.call('EXISTS', KEYS[1]) == 0
+local value = redis.call(ARGV[1], KEYS[1], ARGV[2])
+if created and tonumber(ARGV[3]) > 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[3])
+end
+return value
+`)
+
 // NewGoRedisClient wraps the provided go-redis UniversalClient as a Client.
 // The caller retains ownership of the underlying client; calling Close on
 // GoRedisClient will close the wrapped instance.
 func NewGoRedisClient(client goredis.UniversalClient) *GoRedisClient {
-	return &GoRedisClient{client: client}
+	_, cluster := client.(*goredis.ClusterClient)
+	return &GoRedisClient{client: client, cluster: cluster}
 }
 
 // Get implements Client.
@@ -48,55 +76,786 @@ func (r *GoRedisClient) Set(ctx context.Context, key, value string, ttl time.Dur
 	return r.client.Set(ctx, key, value, expiration).Err()
 }
 
-// MGet implements Client using a single pipeline of GET commands so that the
-// operation is correct under Redis Cluster regardless of hash-slot distribution.
+// MGet implements Client. Keys are grouped by hash slot under Redis Cluster,
+// where MGET is not allowed across slots: each group is one MGET (or GET) of a
+// single pipeline. Other deployments read every key with one MGET. Keys
+// holding another type than a string are reported as missing, as MGET does;
+// only connection and server errors fail the call.
 func (r *GoRedisClient) MGet(ctx context.Context, keys []string) ([]GetResult, error) {
 	if len(keys) == 0 {
 		return nil, nil
 	}
 
+	groups := r.slotGroups(keys)
+	if len(groups) == 1 {
+		values, err := r.client.MGet(ctx, keys...).Result()
+		if err != nil {
+			return nil, err
+		}
+		return mGetResults(keys, values), nil
+	}
+
 	pipe := r.client.Pipeline()
-	cmds := make([]*goredis.StringCmd, len(keys))
+	cmds := make([]goredis.Cmder, len(groups))
+	for i, group := range groups {
+		if len(group) == 1 {
+			cmds[i] = pipe.Get(ctx, group[0])
+			continue
+		}
+		cmds[i] = pipe.MGet(ctx, group...)
+	}
+
+	// go-redis reports the first failed command as the pipeline error, which
+	// includes redis.Nil and the WRONGTYPE of a GET on another type; both mean
+	// "missing" here, so errors are inspected per command instead.
+	_, _ = pipe.Exec(ctx)
+
+	found := make(map[string]GetResult, len(keys))
+	for i, cmd := range cmds {
+		switch cmd := cmd.(type) {
+		case *goredis.StringCmd:
+			switch err := cmd.Err(); {
+			case err == nil:
+				found[groups[i][0]] = GetResult{Key: groups[i][0], Value: cmd.Val(), Found: true}
+			case !errors.Is(err, goredis.Nil) && !isWrongType(err):
+				return nil, err
+			}
+		case *goredis.SliceCmd:
+			if err := cmd.Err(); err != nil {
+				return nil, err
+			}
+			for _, result := range mGetResults(groups[i], cmd.Val()) {
+				found[result.Key] = result
+			}
+		}
+	}
+
+	results := make([]GetResult, len(keys))
 	for i, key := range keys {
-		cmds[i] = pipe.Get(ctx, key)
+		results[i] = found[key]
+		results[i].Key = key
 	}
+	return results, nil
+}
 
-	// go-redis aggregates per-command errors into the pipeline error and
-	// special-cases redis.Nil as "not really an error". If we reach this point
-	// with any non-Nil aggregated error there is no point inspecting individual
-	// commands, because the same error would surface per-key in the loop below.
-	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, goredis.Nil) {
-		return nil, err
+// msetScript stores KEYS[i] = ARGV[2i-1] with a TTL of ARGV[2i] milliseconds
+// (0 means no expiration). All keys must hash to the same slot.
+const msetScript = `
+for i, key in ipairs(KEYS) do
+	local ttl = tonumber(ARGV[i * 2])
+	if ttl > 0 then
+		redis.call('SET', key, ARGV[i * 2 - 1], 'PX', ttl)
+	else
+		redis.call('SET', key, ARGV[i * 2 - 1])
+	end
+end
+return #KEYS
+`
+
+// MSet implements Client. Pairs are grouped by hash slot under Redis Cluster;
+// each group is written atomically in one pipeline round-trip: a MSET when no
+// pair carries a TTL, a Lua script honouring the per-pair TTL otherwise.
+// Empty input is a no-op.
+func (r *GoRedisClient) MSet(ctx context.Context, pairs []Pair) error {
+	if len(pairs) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(pairs))
+	byKey := make(map[string]Pair, len(pairs))
+	for i, pair := range pairs {
+		keys[i] = pair.Key
+		byKey[pair.Key] = pair
 	}
 
+	pipe := r.client.Pipeline()
+	for _, group := range r.slotGroups(keys) {
+		if len(group) == 1 {
+			pair := byKey[group[0]]
+			pipe.Set(ctx, pair.Key, pair.Value, max(pair.TTL, 0))
+			continue
+		}
+
+		values := make([]any, 0, len(group)*2)
+		expires := false
+		for _, key := range group {
+			pair := byKey[key]
+			ttl := milliseconds(pair.TTL)
+			expires = expires || ttl > 0
+			values = append(values, pair.Value, ttl)
+		}
+
+		if !expires {
+			args := make([]any, 0, len(values))
+			for i, key := range group {
+				args = append(args, key, values[i*2])
+			}
+			pipe.MSet(ctx, args...)
+			continue
+		}
+		pipe.Eval(ctx, msetScript, group, values...)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// slotGroups splits keys by hash slot, in order of first appearance and without
+// duplicates, when the client is a Cluster client. Other deployments serve every
+// slot from the same node, so keys form a single group.
+func (r *GoRedisClient) slotGroups(keys []string) [][]string {
+	seen := make(map[string]struct{}, len(keys))
+	unique := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if _, duplicate := seen[key]; !duplicate {
+			seen[key] = struct{}{}
+			unique = append(unique, key)
+		}
+	}
+
+	if !r.cluster {
+		return [][]string{unique}
+	}
+
+	var groups [][]string
+	positions := make(map[int]int)
+	for _, key := range unique {
+		slot := HashSlot(key)
+		position, found := positions[slot]
+		if !found {
+			position = len(groups)
+			positions[slot] = position
+			groups = append(groups, nil)
+		}
+		groups[position] = append(groups[position], key)
+	}
+	return groups
+}
+
+// isWrongType reports whether err is the WRONGTYPE error of a command run
+// against a key holding another type.
+func isWrongType(err error) bool {
+	return strings.HasPrefix(err.Error(), "WRONGTYPE")
+}
+
+// mGetResults converts the reply of a MGET of keys to GetResult values.
+func mGetResults(keys []string, values []any) []GetResult {
 	results := make([]GetResult, len(keys))
+	for i, key := range keys {
+		results[i] = GetResult{Key: key}
+		if i < len(values) {
+			if value, ok := values[i].(string); ok {
+				results[i] = GetResult{Key: key, Value: value, Found: true}
+			}
+		}
+	}
+	return results
+}
+
+// compareAndDeleteScript deletes KEYS[1] only when it holds ARGV[1], either as
+// a string or as the value of a Record.
+var compareAndDeleteScript = goredis.NewScript(`
+local kind, current = redis.call('TYPE', KEYS[1])['ok'], false
+if kind == 'string' then
+	current = redis.call('GET', KEYS[1])
+elseif kind == 'hash' then
+	current = redis.call('HGET', KEYS[1], 'value')
+elseif kind == 'ReJSON-RL' then
+	current = redis.call('JSON.GET', KEYS[1], '.value')
+end
+if current == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// compareAndSwapScript replaces KEYS[1] with ARGV[2] only when it holds
+// ARGV[1]; ARGV[3] is the TTL in milliseconds (0 means no expiration, a
+// negative value keeps the current one).
+var compareAndSwapScript = goredis.NewScript(`
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+local ttl = tonumber(ARGV[3])
+if ttl > 0 then
+	redis.call('SET', KEYS[1], ARGV[2], 'PX', ttl)
+elseif ttl < 0 then
+	redis.call('SET', KEYS[1], ARGV[2], 'KEEPTTL')
+else
+	redis.call('SET', KEYS[1], ARGV[2])
+end
+return 1
+`)
+
+// SetNX implements Client using SET NX PX.
+func (r *GoRedisClient) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, value, max(ttl, 0)).Result()
+}
+
+// CompareAndDelete implements Client through a Lua script, so the check and
+// the deletion happen atomically on the server.
+func (r *GoRedisClient) CompareAndDelete(ctx context.Context, key, expected string) (bool, error) {
+	deleted, err := compareAndDeleteScript.Run(ctx, r.client, []string{key}, expected).Int64()
+	if err != nil {
+		return false, err
+	}
+	return deleted == 1, nil
+}
+
+// CompareAndSwap implements Client through a Lua script, so the check and
+// the write happen atomically on the server.
+func (r *GoRedisClient) CompareAndSwap(
+	ctx context.Context,
+	key, expected, value string,
+	ttl time.Duration,
+) (bool, error) {
+	expiration := milliseconds(ttl)
+	if ttl < 0 {
+		expiration = -1
+	}
+	swapped, err := compareAndSwapScript.Run(ctx, r.client, []string{key}, expected, value, expiration).Int64()
+	if err != nil {
+		return false, err
+	}
+	return swapped == 1, nil
+}
+
+// Del implements Client.
+func (r *GoRedisClient) Del(ctx context.Context, key string) error {
+	return r.client.Del(ctx, key).Err()
+}
+
+// IncrBy implements Client using INCRBY; the TTL is applied atomically on
+// creation through a Lua script.
+func (r *GoRedisClient) IncrBy(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	return incrByScript.Run(ctx, r.client, []string{key}, "INCRBY", delta, milliseconds(ttl)).Int64()
+}
+
+// IncrByFloat implements Client using INCRBYFLOAT; the TTL is applied
+// atomically on creation through a Lua script.
+func (r *GoRedisClient) IncrByFloat(
+	ctx context.Context,
+	key string,
+	delta float64,
+	ttl time.Duration,
+) (float64, error) {
+	return incrByScript.Run(ctx, r.client, []string{key}, "INCRBYFLOAT", delta, milliseconds(ttl)).Float64()
+}
+
+// getRecordScript returns {type} followed, for a string, by its value, for a
+// hash, by the Record fields "value", "ver", "codec", "created" and "expires"
+// and, for a RedisJSON document, by the document.
+var getRecordScript = goredis.NewScript(`
+local kind = redis.call('TYPE', KEYS[1])['ok']
+if kind == 'string' then
+	return {kind, redis.call('GET', KEYS[1])}
+end
+if kind == 'hash' then
+	return {kind, unpack(redis.call('HMGET', KEYS[1], 'value', 'ver', 'codec', 'created', 'expires'))}
+end
+if kind == 'ReJSON-RL' then
+	return {kind, redis.call('JSON.GET', KEYS[1])}
+end
+return {kind}
+`)
+
+// setRecordScript replaces KEYS[1] with a Record hash when the condition
+// ARGV[1] holds: "" (none), "nx" (absent) or "eq" (value equal to ARGV[2]).
+// ARGV[3..6] are the value, codec, creation and expiration timestamps; the
+// version is bumped and the creation timestamp kept from the current record.
+// ARGV[7] is the TTL in milliseconds (0 means no expiration); ARGV[8] == "1"
+// keeps the current TTL instead. ARGV[9] == "1" stores a RedisJSON document
+// instead of a hash; a document is overwritten in place, so that a failing
+// JSON.SET leaves it untouched. It returns 1 when the record was stored.
+var setRecordScript = goredis.NewScript(`
+local kind = redis.call('TYPE', KEYS[1])['ok']
+local current, version, created = false, 0, ARGV[5]
+if kind == 'string' then
+	current = redis.call('GET', KEYS[1])
+elseif kind == 'hash' then
+	local fields = redis.call('HMGET', KEYS[1], 'value', 'ver', 'created')
+	current, version, created = fields[1], tonumber(fields[2]) or 0, fields[3] or ARGV[5]
+elseif kind == 'ReJSON-RL' then
+	current = redis.call('JSON.GET', KEYS[1], '.value')
+	version = tonumber(redis.call('JSON.GET', KEYS[1], '.ver')) or 0
+	created = redis.call('JSON.GET', KEYS[1], '.created')
+end
+if (ARGV[1] == 'nx' and current) or (ARGV[1] == 'eq' and current ~= ARGV[2]) then
+	return 0
+end
+local ttl = tonumber(ARGV[7])
+if ARGV[8] == '1' then
+	ttl = redis.call('PTTL', KEYS[1])
+end
+if ARGV[9] == '1' then
+	if kind ~= 'ReJSON-RL' then
+		redis.call('DEL', KEYS[1])
+	end
+	redis.call('JSON.SET', KEYS[1], '$', '{"value":' .. ARGV[3] .. ',"ver":' .. (version + 1) ..
+		',"codec":' .. cjson.encode(ARGV[4]) .. ',"created":' .. created .. ',"expires":' .. ARGV[6] .. '}')
+else
+	redis.call('DEL', KEYS[1])
+	redis.call('HSET', KEYS[1], 'value', ARGV[3], 'ver', version + 1, 'codec', ARGV[4],
+		'created', created, 'expires', ARGV[6])
+end
+if ttl > 0 then
+	redis.call('PEXPIRE', KEYS[1], ttl)
+else
+	redis.call('PERSIST', KEYS[1])
+end
+return 1
+`)
+
+// GetRecords implements Client through a pipeline of Lua scripts, one per key,
+// so that the operation is correct under Redis Cluster.
+func (r *GoRedisClient) GetRecords(ctx context.Context, keys []string) ([]RecordResult, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	pipe := r.client.Pipeline()
+	cmds := make([]*goredis.Cmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = getRecordScript.Eval(ctx, pipe, []string{key})
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	results := make([]RecordResult, len(keys))
 	for i, cmd := range cmds {
-		if errors.Is(cmd.Err(), goredis.Nil) {
-			results[i] = GetResult{Key: keys[i], Found: false}
-			continue
+		values, err := cmd.Slice()
+		if err != nil {
+			return nil, err
 		}
-		results[i] = GetResult{Key: keys[i], Value: cmd.Val(), Found: true}
+		results[i] = recordResult(keys[i], values)
 	}
 	return results, nil
 }
 
-// MSet implements Client using a pipeline so per-item TTL is honoured.
-// Empty input is a no-op.
-func (r *GoRedisClient) MSet(ctx context.Context, pairs []Pair) error {
+// recordResult converts the reply of getRecordScript to a RecordResult.
+func recordResult(key string, values []any) RecordResult {
+	result := RecordResult{Key: key}
+	if len(values) < 2 {
+		return result
+	}
+
+	value, ok := values[1].(string)
+	if !ok {
+		return result
+	}
+	switch values[0] {
+	case "string":
+		result.Found = true
+		result.Record = Record{Value: value, Legacy: true}
+		return result
+	case "ReJSON-RL":
+		var doc document
+		if err := json.Unmarshal([]byte(value), &doc); err != nil {
+			return result
+		}
+		result.Found = true
+		result.Record = doc.record()
+		return result
+	}
+
+	result.Found = true
+	result.Record.Value = value
+
+	fields := make([]string, 4)
+	for i := range fields {
+		if i+2 < len(values) {
+			fields[i], _ = values[i+2].(string)
+		}
+	}
+	result.Record.Version, _ = strconv.ParseInt(fields[0], 10, 64)
+	result.Record.Codec = fields[1]
+	result.Record.Created, _ = strconv.ParseInt(fields[2], 10, 64)
+	result.Record.Expires, _ = strconv.ParseInt(fields[3], 10, 64)
+	return result
+}
+
+// SetRecords implements Client through a pipeline of Lua scripts, one per
+// record. Empty input is a no-op.
+func (r *GoRedisClient) SetRecords(ctx context.Context, pairs []RecordPair) error {
 	if len(pairs) == 0 {
 		return nil
 	}
 
 	pipe := r.client.Pipeline()
 	for _, pair := range pairs {
-		expiration := max(pair.TTL, 0)
-		pipe.Set(ctx, pair.Key, pair.Value, expiration)
+		setRecordScript.Eval(ctx, pipe, []string{pair.Key}, setRecordArgs(pair.Record, pair.TTL, RecordCondition{})...)
 	}
 	_, err := pipe.Exec(ctx)
 	return err
 }
 
+// SetRecord implements Client through a Lua script, so the check and the write
+// happen atomically on the server.
+func (r *GoRedisClient) SetRecord(
+	ctx context.Context,
+	key string,
+	record Record,
+	ttl time.Duration,
+	condition RecordCondition,
+) (bool, error) {
+	stored, err := setRecordScript.Run(ctx, r.client, []string{key}, setRecordArgs(record, ttl, condition)...).Int64()
+	if err != nil {
+		return false, err
+	}
+	return stored == 1, nil
+}
+
+// setRecordArgs returns the ARGV of setRecordScript.
+func setRecordArgs(record Record, ttl time.Duration, condition RecordCondition) []any {
+	mode, expected := "", ""
+	switch {
+	case condition.IfAbsent:
+		mode = "nx"
+	case condition.Expected != nil:
+		mode, expected = "eq", *condition.Expected
+	}
+
+	keepTTL, doc := "0", "0"
+	if condition.KeepTTL {
+		keepTTL = "1"
+	}
+	if record.Document {
+		doc = "1"
+	}
+	return []any{
+		mode, expected,
+		record.Value, record.Codec, record.Created, record.Expires,
+		milliseconds(ttl), keepTTL, doc,
+	}
+}
+
+// patchRecordScript applies the updates in ARGV to the value of the RedisJSON
+// document at KEYS[1] and bumps its version. Each update takes four arguments:
+// "set" or "del", the path of the parent object, the path of the field and its
+// JSON value. Every parent is checked before the first write, since Redis does
+// not roll back a failing script. It returns 1 when the document was patched,
+// 0 when KEYS[1] is not a document, -1 when its value is not an object and -2
+// when a parent is not an object.
+var patchRecordScript = goredis.NewScript(`
+if redis.call('TYPE', KEYS[1])['ok'] ~= 'ReJSON-RL' then
+	return 0
+end
+if redis.call('JSON.TYPE', KEYS[1], '$.value')[1] ~= 'object' then
+	return -1
+end
+for i = 1, #ARGV, 4 do
+	if redis.call('JSON.TYPE', KEYS[1], ARGV[i + 1])[1] ~= 'object' then
+		return -2
+	end
+end
+for i = 1, #ARGV, 4 do
+	if ARGV[i] == 'del' then
+		redis.call('JSON.DEL', KEYS[1], ARGV[i + 2])
+	else
+		redis.call('JSON.SET', KEYS[1], ARGV[i + 2], ARGV[i + 3])
+	end
+end
+redis.call('JSON.NUMINCRBY', KEYS[1], '.ver', 1)
+return 1
+`)
+
+// getRecordFieldsScript returns {type} followed, for a RedisJSON document, by
+// the type of its value and the reply of JSON.GET on the paths in ARGV.
+var getRecordFieldsScript = goredis.NewScript(`
+local kind = redis.call('TYPE', KEYS[1])['ok']
+if kind ~= 'ReJSON-RL' then
+	return {kind}
+end
+local value = redis.call('JSON.TYPE', KEYS[1], '$.value')[1] or false
+return {kind, value, redis.call('JSON.GET', KEYS[1], unpack(ARGV))}
+`)
+
+// documentMetadata lists the paths of the metadata of a document read by
+// GetRecordFields.
+var documentMetadata = []string{"$.ver", "$.codec", "$.created", "$.expires"}
+
+// PatchRecord implements Client through a Lua script running JSON.SET and
+// JSON.DEL on the paths of the fields, so that the rest of the document is
+// neither read nor rewritten.
+func (r *GoRedisClient) PatchRecord(ctx context.Context, key string, updates []FieldUpdate) (bool, error) {
+	args := make([]any, 0, 4*len(updates))
+	for _, update := range updates {
+		segments, err := kvs.SplitFieldPath(update.Path)
+		if err != nil {
+			return false, err
+		}
+		op := "set"
+		if update.Delete {
+			op = "del"
+		}
+		args = append(args, op, documentPath(segments[:len(segments)-1]), documentPath(segments), update.Value)
+	}
+
+	patched, err := patchRecordScript.Run(ctx, r.client, []string{key}, args...).Int64()
+	if err != nil {
+		return false, err
+	}
+	switch patched {
+	case -1:
+		return false, kvs.ErrConvert
+	case -2:
+		return false, kvs.ErrFieldPath
+	}
+	return patched == 1, nil
+}
+
+// GetRecordFields implements Client through a Lua script running JSON.GET on
+// the paths of the fields and of the metadata.
+func (r *GoRedisClient) GetRecordFields(ctx context.Context, key string, paths []string) (RecordResult, error) {
+	result := RecordResult{Key: key}
+	fields := make([][]string, len(paths))
+	args := make([]any, 0, len(documentMetadata)+len(paths))
+	for _, path := range documentMetadata {
+		args = append(args, path)
+	}
+	for i, path := range paths {
+		segments, err := kvs.SplitFieldPath(path)
+		if err != nil {
+			return result, err
+		}
+		fields[i] = segments
+		args = append(args, documentPath(segments))
+	}
+
+	values, err := getRecordFieldsScript.Run(ctx, r.client, []string{key}, args...).Slice()
+	if err != nil {
+		return result, err
+	}
+	if len(values) < 3 {
+		return result, nil
+	}
+	if values[1] != "object" {
+		return result, kvs.ErrConvert
+	}
+
+	// With several paths, JSON.GET replies with an object holding the array
+	// of the matches of each path; a missing field has no match.
+	reply, _ := values[2].(string)
+	var matches map[string][]json.RawMessage
+	if err = json.Unmarshal([]byte(reply), &matches); err != nil {
+		return result, kvs.ErrConvert
+	}
+	first := func(path string) json.RawMessage {
+		if found := matches[path]; len(found) > 0 {
+			return found[0]
+		}
+		return nil
+	}
+
+	var doc document
+	_ = json.Unmarshal(first("$.ver"), &doc.Version)
+	_ = json.Unmarshal(first("$.codec"), &doc.Codec)
+	_ = json.Unmarshal(first("$.created"), &doc.Created)
+	_ = json.Unmarshal(first("$.expires"), &doc.Expires)
+
+	projection := make(map[string]any)
+	for _, segments := range fields {
+		if field := first(documentPath(segments)); field != nil {
+			projectField(projection, segments, field)
+		}
+	}
+	if doc.Value, err = json.Marshal(projection); err != nil {
+		return result, kvs.ErrMarshal
+	}
+
+	result.Found, result.Record = true, doc.record()
+	return result, nil
+}
+
+// document is a Record stored as a RedisJSON document by StorageJSON.
+type document struct {
+	Codec   string          `json:"codec"`
+	Value   json.RawMessage `json:"value"`
+	Version int64           `json:"ver"`
+	Created int64           `json:"created"`
+	Expires int64           `json:"expires"`
+}
+
+// record converts the document to a Record.
+func (r document) record() Record {
+	return Record{
+		Value:    string(r.Value),
+		Codec:    r.Codec,
+		Version:  r.Version,
+		Created:  r.Created,
+		Expires:  r.Expires,
+		Document: true,
+	}
+}
+
+// documentPath returns the JSONPath of the field at segments of the value of a
+// document, in bracket notation so that any field name is accepted.
+func documentPath(segments []string) string {
+	var path strings.Builder
+	path.WriteString("$.value")
+	for _, segment := range segments {
+		path.WriteString("[" + strconv.Quote(segment) + "]")
+	}
+	return path.String()
+}
+
+// projectField sets value at segments of projection, creating the parents.
+func projectField(projection map[string]any, segments []string, value any) {
+	for _, segment := range segments[:len(segments)-1] {
+		next, ok := projection[segment].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			projection[segment] = next
+		}
+		projection = next
+	}
+	projection[segments[len(segments)-1]] = value
+}
+
+// txAttempts is the number of times Transact runs its WATCH transaction before
+// giving up when a watched key keeps changing concurrently.
+const txAttempts = 3
+
+// Transact implements Client with WATCH and MULTI/EXEC: the checked and written
+// keys are watched, the checks are evaluated on their current values, and the
+// writes are queued in a MULTI/EXEC block that Redis discards if any of them
+// changed meanwhile, in which case the transaction is retried. Records are
+// written by setRecordScript, so their version is bumped as in SetRecord.
+// Under Redis Cluster every key must hash to the same slot (see KeyLayout).
+func (r *GoRedisClient) Transact(ctx context.Context, checks []TxCheck, writes []TxWrite) (int, error) {
+	keys := make([]string, 0, len(checks)+len(writes))
+	for _, check := range checks {
+		keys = append(keys, check.Key)
+	}
+	for _, write := range writes {
+		keys = append(keys, write.Key)
+	}
+
+	var err error
+	for range txAttempts {
+		failed := -1
+		err = r.client.Watch(ctx, func(tx *goredis.Tx) error {
+			var cErr error
+			if failed, cErr = checkTx(ctx, tx, checks); cErr != nil || failed >= 0 {
+				return cErr
+			}
+
+			_, cErr = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+				for _, write := range writes {
+					switch {
+					case write.Delete:
+						pipe.Del(ctx, write.Key)
+					case write.Record != nil:
+						setRecordScript.Eval(ctx, pipe, []string{write.Key},
+							setRecordArgs(*write.Record, write.TTL, RecordCondition{})...)
+					default:
+						pipe.Set(ctx, write.Key, write.Value, max(write.TTL, 0))
+					}
+				}
+				return nil
+			})
+			return cErr
+		}, keys...)
+		if !errors.Is(err, goredis.TxFailedErr) {
+			return failed, err
+		}
+	}
+	return -1, err
+}
+
+// checkTx returns the index of the first check that does not hold on the
+// current values of a watched transaction, or -1.
+func checkTx(ctx context.Context, tx *goredis.Tx, checks []TxCheck) (int, error) {
+	for i, check := range checks {
+		values, err := getRecordScript.Run(ctx, tx, []string{check.Key}).Slice()
+		if err != nil {
+			return -1, err
+		}
+
+		result := recordResult(check.Key, values)
+		if (check.Expected == nil && result.Found) ||
+			(check.Expected != nil && (!result.Found || result.Record.Value != *check.Expected)) {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+// Scan implements Client using SCAN with MATCH/COUNT.
+// Under Redis Cluster every master node is scanned in turn, since a SCAN
+// cursor is only meaningful for the node that issued it.
+func (r *GoRedisClient) Scan(ctx context.Context, match string, count int64) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		cluster, ok := r.client.(*goredis.ClusterClient)
+		if !ok {
+			scanNode(ctx, r.client, match, count, yield)
+			return
+		}
+
+		var (
+			mu      sync.Mutex
+			masters []*goredis.Client
+		)
+		err := cluster.ForEachMaster(ctx, func(_ context.Context, node *goredis.Client) error {
+			mu.Lock()
+			defer mu.Unlock()
+			masters = append(masters, node)
+			return nil
+		})
+		if err != nil {
+			yield("", err)
+			return
+		}
+
+		for _, node := range masters {
+			if !scanNode(ctx, node, match, count, yield) {
+				return
+			}
+		}
+	}
+}
+
+// scanNode walks a single node's keyspace with SCAN until the cursor wraps
+// around. It returns false when the consumer stopped the iteration or an
+// error was yielded.
+func scanNode(
+	ctx context.Context,
+	node goredis.Cmdable,
+	match string,
+	count int64,
+	yield func(string, error) bool,
+) bool {
+	var cursor uint64
+	for {
+		keys, next, err := node.Scan(ctx, cursor, match, count).Result()
+		if err != nil {
+			yield("", err)
+			return false
+		}
+		for _, key := range keys {
+			if !yield(key, nil) {
+				return false
+			}
+		}
+		if next == 0 {
+			return true
+		}
+		cursor = next
+	}
+}
+
 // Close implements Client.
 func (r *GoRedisClient) Close() error {
 	return r.client.Close()
 }
+
+// milliseconds converts a TTL to the milliseconds expected by PX and PEXPIRE.
+// A non-positive ttl is 0, meaning no expiration; a positive ttl under a
+// millisecond is rounded up to 1, as go-redis does for SET, so that it still
+// expires.
+func milliseconds(ttl time.Duration) int64 {
+	if ttl <= 0 {
+		return 0
+	}
+	return max(ttl.Milliseconds(), 1)
+}
@@ -2,6 +2,7 @@ package redis_test
 
 import (
 	"context"
+	"strconv"
 	"testing"
 	"time"
 
@@ -116,6 +117,57 @@ func TestGoRedisClient_MSet_PerItemTTL(t *testing.T) {
 	require.Equal(t, "3", v)
 }
 
+func TestGoRedisClient_MSet_SubMillisecondTTL_StillExpires(t *testing.T) {
+	srv, client := startMiniredis(t)
+	ctx := context.Background()
+
+	require.NoError(t, client.MSet(ctx, []kvsredis.Pair{
+		{Key: "a", Value: "1", TTL: time.Microsecond},
+		{Key: "b", Value: "2", TTL: 1500 * time.Microsecond},
+	}))
+	require.Equal(t, time.Millisecond, srv.TTL("a"))
+	require.Equal(t, time.Millisecond, srv.TTL("b"))
+
+	srv.FastForward(time.Millisecond)
+	require.False(t, srv.Exists("a"))
+	require.False(t, srv.Exists("b"))
+}
+
+func TestGoRedisClient_SubMillisecondRecordTTL_StillExpires(t *testing.T) {
+	srv, client := startMiniredis(t)
+	ctx := context.Background()
+
+	stored, err := client.SetRecord(ctx, "r", kvsredis.Record{Value: "1"}, 500*time.Microsecond, kvsredis.RecordCondition{})
+	require.NoError(t, err)
+	require.True(t, stored)
+	require.Equal(t, time.Millisecond, srv.TTL("r"))
+
+	failed, err := client.Transact(ctx, nil, []kvsredis.TxWrite{
+		{Key: "t", Record: &kvsredis.Record{Value: "2"}, TTL: 500 * time.Microsecond},
+	})
+	require.NoError(t, err)
+	require.Equal(t, -1, failed)
+	require.Equal(t, time.Millisecond, srv.TTL("t"))
+
+	srv.FastForward(time.Millisecond)
+	require.False(t, srv.Exists("r"))
+	require.False(t, srv.Exists("t"))
+}
+
+func TestGoRedisClient_CompareAndSwap_SubMillisecondTTL_StillExpires(t *testing.T) {
+	srv, client := startMiniredis(t)
+	ctx := context.Background()
+
+	require.NoError(t, client.Set(ctx, "k", "a", 0))
+	swapped, err := client.CompareAndSwap(ctx, "k", "a", "b", 500*time.Microsecond)
+	require.NoError(t, err)
+	require.True(t, swapped)
+	require.Equal(t, time.Millisecond, srv.TTL("k"))
+
+	srv.FastForward(time.Millisecond)
+	require.False(t, srv.Exists("k"))
+}
+
 func TestGoRedisClient_MSet_Empty_IsNoOp(t *testing.T) {
 	_, client := startMiniredis(t)
 
@@ -143,6 +195,114 @@ func TestGoRedisClient_PropagatesErrorsAfterClose(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestGoRedisClient_Scan_MatchesPattern(t *testing.T) {
+	_, client := startMiniredis(t)
+	ctx := context.Background()
+
+	for i := range 30 {
+		require.NoError(t, client.Set(ctx, "p:"+strconv.Itoa(i), "v", 0))
+	}
+	require.NoError(t, client.Set(ctx, "other", "v", 0))
+
+	keys := make([]string, 0, 30)
+	for key, err := range client.Scan(ctx, "p:*", 7) {
+		require.NoError(t, err)
+		keys = append(keys, key)
+	}
+	require.Len(t, keys, 30)
+	require.NotContains(t, keys, "other")
+}
+
+func TestGoRedisClient_Scan_PropagatesError(t *testing.T) {
+	srv, client := startMiniredis(t)
+	srv.Close()
+
+	var err error
+	for _, err = range client.Scan(context.Background(), "*", 10) {
+	}
+	require.Error(t, err)
+}
+
+func TestGoRedisClient_IncrBy_SetsTTLOnCreateOnly(t *testing.T) {
+	srv, client := startMiniredis(t)
+	ctx := context.Background()
+
+	value, err := client.IncrBy(ctx, "c", 5, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), value)
+	require.Equal(t, time.Minute, srv.TTL("c"))
+
+	srv.FastForward(30 * time.Second)
+
+	value, err = client.IncrBy(ctx, "c", -1, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), value)
+	require.Equal(t, 30*time.Second, srv.TTL("c"))
+
+	srv.FastForward(time.Minute)
+
+	_, err = client.Get(ctx, "c")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+}
+
+func TestGoRedisClient_IncrBy_SubMillisecondTTL_StillExpires(t *testing.T) {
+	srv, client := startMiniredis(t)
+	ctx := context.Background()
+
+	_, err := client.IncrBy(ctx, "i", 1, 500*time.Microsecond)
+	require.NoError(t, err)
+	_, err = client.IncrByFloat(ctx, "f", 1.5, 500*time.Microsecond)
+	require.NoError(t, err)
+	require.Equal(t, time.Millisecond, srv.TTL("i"))
+	require.Equal(t, time.Millisecond, srv.TTL("f"))
+
+	srv.FastForward(time.Millisecond)
+	require.False(t, srv.Exists("i"))
+	require.False(t, srv.Exists("f"))
+}
+
+func TestGoRedisClient_IncrByFloat_And_Del(t *testing.T) {
+	srv, client := startMiniredis(t)
+	ctx := context.Background()
+
+	value, err := client.IncrByFloat(ctx, "f", 1.5, 0)
+	require.NoError(t, err)
+	require.InDelta(t, 1.5, value, 1e-9)
+	require.Equal(t, time.Duration(0), srv.TTL("f"))
+
+	value, err = client.IncrByFloat(ctx, "f", 0.25, 0)
+	require.NoError(t, err)
+	require.InDelta(t, 1.75, value, 1e-9)
+
+	require.NoError(t, client.Del(ctx, "f"))
+	require.NoError(t, client.Del(ctx, "f"))
+	require.False(t, srv.Exists("f"))
+}
+
+func TestGoRedisClient_SetNX_CompareAndDelete(t *testing.T) {
+	srv, client := startMiniredis(t)
+	ctx := context.Background()
+
+	ok, err := client.SetNX(ctx, "l", "a", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, time.Minute, srv.TTL("l"))
+
+	ok, err = client.SetNX(ctx, "l", "b", time.Minute)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = client.CompareAndDelete(ctx, "l", "b")
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.True(t, srv.Exists("l"))
+
+	ok, err = client.CompareAndDelete(ctx, "l", "a")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.False(t, srv.Exists("l"))
+}
+
 func TestLowLevelClient_EndToEnd_OnMiniredis(t *testing.T) {
 	// Wire the high-level KVSClient[T] all the way to miniredis to verify the
 	// full stack (generic -> low-level -> GoRedisClient -> miniredis).
@@ -15,15 +15,32 @@ import (
 	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
 
 	"github.com/arielsrv/go-kvs-client/kvs"
+	"github.com/arielsrv/go-kvs-client/kvs/kvstest"
 	kvsredis "github.com/arielsrv/go-kvs-client/kvs/redis"
 )
 
+// redisStackImage ships the RedisJSON module needed by StorageJSON.
+const redisStackImage = "redis/redis-stack-server:7.4.0-v3"
+
 func setupRedisClient(t *testing.T, opts ...kvsredis.BuilderOptions) *kvsredis.LowLevelClient {
 	t.Helper()
 
+	opt := startRedis(t, "redis:7-alpine")
+	underlying := goredis.NewClient(opt)
+	t.Cleanup(func() { _ = underlying.Close() })
+
+	b := kvsredis.NewBuilder(opts...)
+	return b.BuildWithClient(kvsredis.NewGoRedisClient(underlying))
+}
+
+// startRedis runs a Redis container of the given image and returns the options
+// to connect to it.
+func startRedis(t *testing.T, image string) *goredis.Options {
+	t.Helper()
+
 	ctx := context.Background()
 
-	container, err := tcredis.Run(ctx, "redis:7-alpine")
+	container, err := tcredis.Run(ctx, image)
 	require.NoError(t, err)
 	t.Cleanup(func() {
 		_ = testcontainers.TerminateContainer(container)
@@ -34,12 +51,7 @@ func setupRedisClient(t *testing.T, opts ...kvsredis.BuilderOptions) *kvsredis.L
 
 	opt, err := goredis.ParseURL(connStr)
 	require.NoError(t, err)
-
-	underlying := goredis.NewClient(opt)
-	t.Cleanup(func() { _ = underlying.Close() })
-
-	b := kvsredis.NewBuilder(opts...)
-	return b.BuildWithClient(kvsredis.NewGoRedisClient(underlying))
+	return opt
 }
 
 func TestIntegration_Redis_SaveAndGet(t *testing.T) {
@@ -214,3 +226,103 @@ func TestIntegration_Redis_IntegratesWithGenericClient(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, values, 2)
 }
+
+func TestIntegration_Redis_ClientTracking(t *testing.T) {
+	opt := startRedis(t, "redis:7-alpine")
+
+	for name, mode := range map[string]kvsredis.TrackingMode{
+		"default":   kvsredis.TrackingDefault,
+		"broadcast": kvsredis.TrackingBroadcast,
+	} {
+		t.Run(name, func(t *testing.T) {
+			prefix := "__kvs:tracking:" + name
+			// A single connection: the command following a change reads its push.
+			reader := kvsredis.NewBuilder(
+				kvsredis.WithAddresses(opt.Addr),
+				kvsredis.WithKeyPrefix(prefix),
+				kvsredis.WithPoolSize(1),
+				kvsredis.WithClientTracking(mode, 0, time.Hour),
+			).Build()
+			t.Cleanup(func() { _ = reader.Close() })
+			writer := kvsredis.NewBuilder(kvsredis.WithAddresses(opt.Addr), kvsredis.WithKeyPrefix(prefix)).Build()
+			t.Cleanup(func() { _ = writer.Close() })
+
+			require.NoError(t, writer.Save("1", kvs.NewItem("1", "a")))
+			got, err := reader.Get("1")
+			require.NoError(t, err)
+			require.Equal(t, `"a"`, got.Value)
+			require.Equal(t, 1, reader.CacheLen())
+
+			require.NoError(t, writer.Save("1", kvs.NewItem("1", "b")))
+			require.Eventually(t, func() bool {
+				_, _ = reader.Get("other")
+				return reader.CacheLen() == 0
+			}, 5*time.Second, 10*time.Millisecond)
+
+			got, err = reader.Get("1")
+			require.NoError(t, err)
+			require.Equal(t, `"b"`, got.Value)
+		})
+	}
+}
+
+func TestIntegration_Redis_StorageJSON_Conformance(t *testing.T) {
+	opt := startRedis(t, redisStackImage)
+
+	var clients int
+	kvstest.RunConformance(t, func(t *testing.T, ttl time.Duration) kvstest.Backend {
+		clients++
+		client := kvsredis.NewBuilder(
+			kvsredis.WithAddresses(opt.Addr),
+			kvsredis.WithKeyPrefix("__kvs:json:"+strconv.Itoa(clients)),
+			kvsredis.WithTTL(ttl),
+			kvsredis.WithStorageMode(kvsredis.StorageJSON),
+		).Build()
+		t.Cleanup(func() { _ = client.Close() })
+		return kvstest.Backend{Client: client, MaxBulkKeys: kvsredis.MaxBulkKeys}
+	})
+}
+
+func TestIntegration_Redis_StorageJSON_PatchAndGetFields(t *testing.T) {
+	ctx := context.Background()
+	opt := startRedis(t, redisStackImage)
+	underlying := goredis.NewClient(opt)
+	t.Cleanup(func() { _ = underlying.Close() })
+
+	legacy := kvsredis.NewBuilder().BuildWithClient(kvsredis.NewGoRedisClient(underlying))
+	client := kvsredis.NewBuilder(kvsredis.WithStorageMode(kvsredis.StorageJSON)).
+		BuildWithClient(kvsredis.NewGoRedisClient(underlying))
+
+	value := map[string]any{"name": "John", "address": map[string]any{"city": "Paris", "zip": "75001"}}
+	require.NoError(t, client.Save("1", kvs.NewItem("1", value, time.Hour)))
+	require.Equal(t, "ReJSON-RL", underlying.Type(ctx, "1").Val())
+
+	require.NoError(t, client.Patch(ctx, "1", map[string]any{"address.city": "Lyon", "address.zip": nil, "age": 42}))
+	require.Greater(t, underlying.PTTL(ctx, "1").Val(), 59*time.Minute)
+
+	got, err := client.Get("1")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"John","age":42,"address":{"city":"Lyon"}}`, got.Value.(string))
+	require.Equal(t, int64(2), got.Version)
+
+	fields, err := client.GetFields(ctx, "1", "address.city", "age", "missing")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"address":{"city":"Lyon"},"age":42}`, fields.Value.(string))
+	require.Equal(t, got.Version, fields.Version)
+	require.Equal(t, got.TTL, fields.TTL)
+
+	require.ErrorIs(t, client.Patch(ctx, "1", map[string]any{"phone.home": "1"}), kvs.ErrFieldPath)
+	require.NoError(t, client.CompareAndSwap(ctx, "1", got, kvs.NewItem("1", map[string]any{"name": "Jane"})))
+	require.Equal(t, time.Duration(-1), underlying.PTTL(ctx, "1").Val())
+
+	require.NoError(t, legacy.Save("2", kvs.NewItem("2", map[string]any{"a": 1})))
+	require.NoError(t, client.Patch(ctx, "2", map[string]any{"b": 2}))
+	require.Equal(t, "ReJSON-RL", underlying.Type(ctx, "2").Val())
+	fields, err = client.GetFields(ctx, "2", "a", "b")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"a":1,"b":2}`, fields.Value.(string))
+
+	counter, err := client.Increment(ctx, "3", 2, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), counter)
+}
@@ -0,0 +1,359 @@
+// Package redis provides a Redis implementation of the KVS client.
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// ErrNoInvalidationBus is returned by LowLevelClient.SubscribeInvalidations when
+// the client has been built without an invalidation bus.
+const ErrNoInvalidationBus = kvs.KeyValueError("[kvs]: invalidation bus is not configured")
+
+// InvalidationTopicPrefix is prepended to the container name to build the
+// invalidation topic of a LowLevelClient.
+const InvalidationTopicPrefix = "__kvs:invalidation:"
+
+// InvalidationOp is the kind of change carried by an InvalidationEvent.
+type InvalidationOp string
+
+// Invalidation operations.
+const (
+	// InvalidationSet reports that the keys have been written.
+	InvalidationSet InvalidationOp = "set"
+	// InvalidationDelete reports that the keys have been deleted.
+	InvalidationDelete InvalidationOp = "delete"
+	// InvalidationFlush asks subscribers to drop every cached entry of the topic.
+	// Buses emit it when a subscription is re-established after a disconnection,
+	// since events published in the meantime have been lost.
+	InvalidationFlush InvalidationOp = "flush"
+)
+
+// InvalidationEvent describes a change of one or more keys of a container.
+type InvalidationEvent struct {
+	// Topic is the topic the event was received on. It is set by the bus on delivery.
+	Topic string `json:"-"`
+	// Op is the kind of change.
+	Op InvalidationOp `json:"op"`
+	// Origin identifies the LowLevelClient that made the change (see
+	// LowLevelClient.InvalidationOrigin), so that a process can skip its own events.
+	// Empty for events emitted by the bus itself.
+	Origin string `json:"origin,omitempty"`
+	// Keys are the user-facing keys, without the key prefix. Empty for InvalidationFlush.
+	Keys []string `json:"keys,omitempty"`
+}
+
+// InvalidationHandler is called for each event received by a subscription.
+// Events of a subscription are delivered sequentially, in publication order.
+type InvalidationHandler func(event InvalidationEvent)
+
+// Subscription is an active subscription to an invalidation topic.
+type Subscription interface {
+	// Close stops the delivery of events. It waits for the handler to return.
+	Close() error
+}
+
+// InvalidationBus broadcasts key-change events between processes.
+//
+// Implementations MUST be safe for concurrent use.
+type InvalidationBus interface {
+	// Publish broadcasts event to every subscriber of topic.
+	Publish(ctx context.Context, topic string, event InvalidationEvent) error
+
+	// Subscribe registers handler for the events of topic. Events published after
+	// Subscribe returns are guaranteed to be delivered while the connection holds.
+	Subscribe(ctx context.Context, topic string, handler InvalidationHandler) (Subscription, error)
+
+	// Close closes every subscription opened through the bus.
+	Close() error
+}
+
+// InvalidationTopic returns the invalidation topic of a container, as returned by
+// LowLevelClient.ContainerName.
+func InvalidationTopic(containerName string) string {
+	return InvalidationTopicPrefix + containerName
+}
+
+// ---------------------------------------------------------------------------
+// Pub/Sub bus
+// ---------------------------------------------------------------------------
+
+// DefaultHealthCheckInterval is the default interval at which PubSubBus pings idle
+// subscriptions to detect broken connections.
+const DefaultHealthCheckInterval = 3 * time.Second
+
+// PubSubBus is an InvalidationBus backed by Redis Pub/Sub (PUBLISH / SUBSCRIBE).
+//
+// go-redis transparently reconnects broken subscriptions. Since events published
+// while disconnected are lost, every re-established subscription receives an
+// InvalidationFlush event.
+type PubSubBus struct {
+	client              goredis.UniversalClient
+	subscriptions       map[*pubSubSubscription]struct{}
+	healthCheckInterval time.Duration
+	mu                  sync.Mutex
+}
+
+// PubSubBusOptions configures a PubSubBus. Used with the functional-options pattern.
+type PubSubBusOptions func(*PubSubBus)
+
+// WithHealthCheckInterval returns a PubSubBusOptions that sets how often idle
+// subscriptions are pinged; a broken connection is re-established on failure.
+func WithHealthCheckInterval(interval time.Duration) PubSubBusOptions {
+	return func(b *PubSubBus) { b.healthCheckInterval = interval }
+}
+
+// NewPubSubBus creates a new PubSubBus on top of the provided go-redis client.
+func NewPubSubBus(client goredis.UniversalClient, opts ...PubSubBusOptions) *PubSubBus {
+	bus := &PubSubBus{
+		client:              client,
+		subscriptions:       make(map[*pubSubSubscription]struct{}),
+		healthCheckInterval: DefaultHealthCheckInterval,
+	}
+	for _, opt := range opts {
+		opt(bus)
+	}
+	return bus
+}
+
+// Publish implements InvalidationBus using PUBLISH.
+func (r *PubSubBus) Publish(ctx context.Context, topic string, event InvalidationEvent) error {
+	bytes, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("redis Publish: marshal: %w", err)
+	}
+
+	err = r.client.Publish(ctx, topic, bytes).Err()
+	if err != nil {
+		return fmt.Errorf("redis Publish: %w", err)
+	}
+	return nil
+}
+
+// Subscribe implements InvalidationBus using SUBSCRIBE. It returns once the
+// subscription has been confirmed by the server. Malformed messages are ignored.
+func (r *PubSubBus) Subscribe(
+	ctx context.Context,
+	topic string,
+	handler InvalidationHandler,
+) (Subscription, error) {
+	pubsub := r.client.Subscribe(ctx, topic)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, fmt.Errorf("redis Subscribe: %w", err)
+	}
+
+	subscription := &pubSubSubscription{
+		bus:    r,
+		pubsub: pubsub,
+		done:   make(chan struct{}),
+	}
+
+	r.mu.Lock()
+	r.subscriptions[subscription] = struct{}{}
+	r.mu.Unlock()
+
+	messages := pubsub.ChannelWithSubscriptions(goredis.WithChannelHealthCheckInterval(r.healthCheckInterval))
+	go subscription.run(topic, messages, handler)
+
+	return subscription, nil
+}
+
+// Close implements InvalidationBus. The underlying go-redis client is left open.
+func (r *PubSubBus) Close() error {
+	r.mu.Lock()
+	subscriptions := make([]*pubSubSubscription, 0, len(r.subscriptions))
+	for subscription := range r.subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+	r.mu.Unlock()
+
+	for _, subscription := range subscriptions {
+		_ = subscription.Close()
+	}
+	return nil
+}
+
+// pubSubSubscription is the Subscription returned by PubSubBus.
+type pubSubSubscription struct {
+	bus    *PubSubBus
+	pubsub *goredis.PubSub
+	done   chan struct{}
+	once   sync.Once
+}
+
+// run delivers messages to handler until the subscription is closed. The initial
+// subscribe confirmation has been consumed by Subscribe, so any further one means
+// the subscription has been re-established after a disconnection.
+func (r *pubSubSubscription) run(topic string, messages <-chan any, handler InvalidationHandler) {
+	defer close(r.done)
+
+	for message := range messages {
+		switch message := message.(type) {
+		case *goredis.Subscription:
+			if message.Kind == "subscribe" {
+				handler(InvalidationEvent{Topic: topic, Op: InvalidationFlush})
+			}
+		case *goredis.Message:
+			var event InvalidationEvent
+			if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+				continue
+			}
+			event.Topic = message.Channel
+			handler(event)
+		}
+	}
+}
+
+// Close implements Subscription.
+func (r *pubSubSubscription) Close() error {
+	var err error
+	r.once.Do(func() {
+		r.bus.mu.Lock()
+		delete(r.bus.subscriptions, r)
+		r.bus.mu.Unlock()
+
+		err = r.pubsub.Close()
+		<-r.done
+	})
+	return err
+}
+
+// ---------------------------------------------------------------------------
+// Fake bus
+// ---------------------------------------------------------------------------
+
+// FakeBus is an in-memory InvalidationBus for tests. Events are delivered
+// synchronously, before Publish returns. A single FakeBus can be shared by
+// several LowLevelClient instances to simulate multiple processes.
+type FakeBus struct {
+	subscriptions map[string]map[*fakeSubscription]struct{}
+	mu            sync.RWMutex
+	closed        bool
+}
+
+// NewFakeBus creates a new, empty FakeBus.
+func NewFakeBus() *FakeBus {
+	return &FakeBus{
+		subscriptions: make(map[string]map[*fakeSubscription]struct{}),
+	}
+}
+
+// Publish implements InvalidationBus.
+func (r *FakeBus) Publish(ctx context.Context, topic string, event InvalidationEvent) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("redis Publish: %w", err)
+	}
+
+	r.mu.RLock()
+	if r.closed {
+		r.mu.RUnlock()
+		return fmt.Errorf("redis Publish: %w", goredis.ErrClosed)
+	}
+	subscriptions := make([]*fakeSubscription, 0, len(r.subscriptions[topic]))
+	for subscription := range r.subscriptions[topic] {
+		subscriptions = append(subscriptions, subscription)
+	}
+	r.mu.RUnlock()
+
+	event.Topic = topic
+	for _, subscription := range subscriptions {
+		subscription.deliver(event)
+	}
+	return nil
+}
+
+// Subscribe implements InvalidationBus.
+func (r *FakeBus) Subscribe(
+	ctx context.Context,
+	topic string,
+	handler InvalidationHandler,
+) (Subscription, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("redis Subscribe: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil, fmt.Errorf("redis Subscribe: %w", goredis.ErrClosed)
+	}
+
+	subscription := &fakeSubscription{bus: r, topic: topic, handler: handler}
+	if r.subscriptions[topic] == nil {
+		r.subscriptions[topic] = make(map[*fakeSubscription]struct{})
+	}
+	r.subscriptions[topic][subscription] = struct{}{}
+	return subscription, nil
+}
+
+// Reconnect simulates every subscription being re-established after a
+// disconnection: each one receives an InvalidationFlush event.
+func (r *FakeBus) Reconnect() {
+	r.mu.RLock()
+	var subscriptions []*fakeSubscription
+	for _, topicSubscriptions := range r.subscriptions {
+		for subscription := range topicSubscriptions {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	r.mu.RUnlock()
+
+	for _, subscription := range subscriptions {
+		subscription.deliver(InvalidationEvent{Topic: subscription.topic, Op: InvalidationFlush})
+	}
+}
+
+// Subscribers returns the number of active subscriptions to topic.
+func (r *FakeBus) Subscribers(topic string) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.subscriptions[topic])
+}
+
+// Close implements InvalidationBus. Further calls to Publish and Subscribe fail.
+func (r *FakeBus) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	clear(r.subscriptions)
+	return nil
+}
+
+// fakeSubscription is the Subscription returned by FakeBus.
+type fakeSubscription struct {
+	bus     *FakeBus
+	handler InvalidationHandler
+	topic   string
+	mu      sync.Mutex
+	closed  bool
+}
+
+// deliver calls the handler unless the subscription has been closed. The mutex
+// serializes deliveries and lets Close wait for a running handler.
+func (r *fakeSubscription) deliver(event InvalidationEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.closed {
+		r.handler(event)
+	}
+}
+
+// Close implements Subscription.
+func (r *fakeSubscription) Close() error {
+	r.bus.mu.Lock()
+	delete(r.bus.subscriptions[r.topic], r)
+	r.bus.mu.Unlock()
+
+	r.mu.Lock()
+	r.closed = true
+	r.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,192 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	kvsredis "github.com/arielsrv/go-kvs-client/kvs/redis"
+)
+
+// recorder collects the events delivered to a subscription.
+type recorder chan kvsredis.InvalidationEvent
+
+func (r recorder) handle(event kvsredis.InvalidationEvent) { r <- event }
+
+func (r recorder) next(t *testing.T) kvsredis.InvalidationEvent {
+	t.Helper()
+	select {
+	case event := <-r:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("no invalidation event received")
+		return kvsredis.InvalidationEvent{}
+	}
+}
+
+func TestInvalidation_FakeBus_BroadcastsWritesBetweenClients(t *testing.T) {
+	ctx := t.Context()
+	bus := kvsredis.NewFakeBus()
+	writer := newClient(t, kvsredis.WithKeyPrefix("__kvs:users"), kvsredis.WithInvalidationBus(bus))
+	reader := newClient(t, kvsredis.WithKeyPrefix("__kvs:users"), kvsredis.WithInvalidationBus(bus))
+	other := newClient(t, kvsredis.WithKeyPrefix("__kvs:orders"), kvsredis.WithInvalidationBus(bus))
+
+	require.Equal(t, "__kvs:invalidation:__kvs:users", reader.InvalidationTopic())
+	require.NotEqual(t, writer.InvalidationOrigin(), reader.InvalidationOrigin())
+
+	events := make(recorder, 10)
+	subscription, err := reader.SubscribeInvalidations(ctx, events.handle)
+	require.NoError(t, err)
+	require.Equal(t, 1, bus.Subscribers(reader.InvalidationTopic()))
+
+	require.NoError(t, writer.Save("1", kvs.NewItem("1", testUser{ID: 1})))
+	require.Equal(t, kvsredis.InvalidationEvent{
+		Topic:  reader.InvalidationTopic(),
+		Op:     kvsredis.InvalidationSet,
+		Origin: writer.InvalidationOrigin(),
+		Keys:   []string{"1"},
+	}, events.next(t))
+
+	items := new(kvs.Items)
+	items.Add(kvs.NewItem("2", testUser{ID: 2}))
+	items.Add(kvs.NewItem("3", testUser{ID: 3}))
+	require.NoError(t, writer.BulkSave(items))
+	require.Equal(t, []string{"2", "3"}, events.next(t).Keys)
+
+	require.NoError(t, writer.Delete("1"))
+	event := events.next(t)
+	require.Equal(t, kvsredis.InvalidationDelete, event.Op)
+	require.Equal(t, []string{"1"}, event.Keys)
+
+	_, err = writer.Increment(ctx, "hits", 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"hits"}, events.next(t).Keys)
+
+	require.NoError(t, other.Save("1", kvs.NewItem("1", 1)))
+	bus.Reconnect()
+	event = events.next(t)
+	require.Equal(t, kvsredis.InvalidationFlush, event.Op)
+	require.Empty(t, event.Keys)
+
+	require.NoError(t, subscription.Close())
+	require.Equal(t, 0, bus.Subscribers(reader.InvalidationTopic()))
+	require.NoError(t, writer.Save("1", kvs.NewItem("1", testUser{ID: 1})))
+	require.Empty(t, events)
+}
+
+func TestInvalidation_FailedOrSkippedWrites_DoNotPublish(t *testing.T) {
+	ctx := t.Context()
+	client := newClient(t, kvsredis.WithInvalidation())
+
+	events := make(recorder, 10)
+	_, err := client.SubscribeInvalidations(ctx, events.handle)
+	require.NoError(t, err)
+
+	require.NoError(t, client.SaveIfAbsent(ctx, "1", kvs.NewItem("1", 1)))
+	require.Equal(t, kvsredis.InvalidationSet, events.next(t).Op)
+
+	require.ErrorIs(t, client.SaveIfAbsent(ctx, "1", kvs.NewItem("1", 2)), kvs.ErrConditionFailed)
+	require.ErrorIs(t, client.CompareAndDelete(ctx, "1", kvs.NewItem("1", "2")), kvs.ErrConditionFailed)
+
+	expired := kvs.NewItem("2", 1)
+	expired.TTL = time.Now().Add(-time.Minute).Unix()
+	require.NoError(t, client.Save("2", expired))
+	require.Empty(t, events)
+
+	require.NoError(t, client.CompareAndDelete(ctx, "1", kvs.NewItem("1", "1")))
+	require.Equal(t, kvsredis.InvalidationDelete, events.next(t).Op)
+}
+
+func TestInvalidation_WithoutBus(t *testing.T) {
+	client := newClient(t)
+
+	_, err := client.SubscribeInvalidations(t.Context(), func(kvsredis.InvalidationEvent) {})
+	require.ErrorIs(t, err, kvsredis.ErrNoInvalidationBus)
+	require.Empty(t, client.InvalidationOrigin())
+	require.NoError(t, client.Save("1", kvs.NewItem("1", 1)))
+}
+
+func TestInvalidation_PublishError_IsReturned(t *testing.T) {
+	bus := kvsredis.NewFakeBus()
+	client := newClient(t, kvsredis.WithInvalidationBus(bus))
+	require.NoError(t, bus.Close())
+
+	require.ErrorIs(t, client.Save("1", kvs.NewItem("1", 1)), goredis.ErrClosed)
+	_, err := client.SubscribeInvalidations(t.Context(), func(kvsredis.InvalidationEvent) {})
+	require.ErrorIs(t, err, goredis.ErrClosed)
+
+	// The write itself has been applied.
+	_, err = client.Get("1")
+	require.NoError(t, err)
+}
+
+func TestInvalidation_FakeBus_CancelledContext(t *testing.T) {
+	bus := kvsredis.NewFakeBus()
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	require.ErrorIs(t, bus.Publish(ctx, "topic", kvsredis.InvalidationEvent{}), context.Canceled)
+	_, err := bus.Subscribe(ctx, "topic", func(kvsredis.InvalidationEvent) {})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestInvalidation_PubSubBus_OnMiniredis(t *testing.T) {
+	srv := miniredis.RunT(t)
+	ctx := t.Context()
+
+	writer := kvsredis.NewBuilder(
+		kvsredis.WithAddresses(srv.Addr()),
+		kvsredis.WithKeyPrefix("__kvs:users"),
+		kvsredis.WithInvalidation(),
+	).Build()
+	t.Cleanup(func() { _ = writer.Close() })
+
+	universal := goredis.NewUniversalClient(&goredis.UniversalOptions{Addrs: []string{srv.Addr()}})
+	t.Cleanup(func() { _ = universal.Close() })
+	bus := kvsredis.NewPubSubBus(universal, kvsredis.WithHealthCheckInterval(50*time.Millisecond))
+	t.Cleanup(func() { _ = bus.Close() })
+
+	events := make(recorder, 10)
+	_, err := bus.Subscribe(ctx, writer.InvalidationTopic(), events.handle)
+	require.NoError(t, err)
+
+	require.NoError(t, writer.Save("1", kvs.NewItem("1", testUser{ID: 1})))
+	require.Equal(t, kvsredis.InvalidationEvent{
+		Topic:  writer.InvalidationTopic(),
+		Op:     kvsredis.InvalidationSet,
+		Origin: writer.InvalidationOrigin(),
+		Keys:   []string{"1"},
+	}, events.next(t))
+
+	srv.Publish(writer.InvalidationTopic(), "not json")
+	require.NoError(t, writer.Delete("1"))
+	require.Equal(t, kvsredis.InvalidationDelete, events.next(t).Op)
+
+	// A broken connection is re-established and reported as a flush.
+	srv.Close()
+	require.NoError(t, srv.Restart())
+	require.Equal(t, kvsredis.InvalidationFlush, events.next(t).Op)
+
+	require.NoError(t, writer.Save("2", kvs.NewItem("2", testUser{ID: 2})))
+	require.Equal(t, []string{"2"}, events.next(t).Keys)
+
+	require.NoError(t, bus.Close())
+	require.NoError(t, writer.Save("3", kvs.NewItem("3", testUser{ID: 3})))
+	require.Empty(t, events)
+}
+
+func TestInvalidation_PubSubBus_Errors(t *testing.T) {
+	srv := miniredis.RunT(t)
+	universal := goredis.NewUniversalClient(&goredis.UniversalOptions{Addrs: []string{srv.Addr()}})
+	bus := kvsredis.NewPubSubBus(universal)
+	require.NoError(t, universal.Close())
+
+	require.Error(t, bus.Publish(t.Context(), "topic", kvsredis.InvalidationEvent{}))
+	_, err := bus.Subscribe(t.Context(), "topic", func(kvsredis.InvalidationEvent) {})
+	require.Error(t, err)
+}
@@ -0,0 +1,131 @@
+// Package redis provides a Redis implementation of the KVS client.
+package redis
+
+import (
+	"strings"
+)
+
+// ShardFunc maps a user-supplied key to the hash tag of its Redis key, so that
+// the keys sharing a tag are stored in the same Cluster hash slot.
+// Example: func(key string) string { tenant, _, _ := strings.Cut(key, "/"); return tenant }
+type ShardFunc func(key string) string
+
+// KeyLayout selects how LowLevelClient builds Redis keys from the key prefix
+// and the user-supplied keys.
+type KeyLayout int
+
+// Key layouts.
+const (
+	// KeyLayoutPlain stores keys as "prefix:key". Keys are spread across hash
+	// slots, so bulk operations are grouped per slot under Redis Cluster.
+	KeyLayoutPlain KeyLayout = iota
+	// KeyLayoutHashTagPrefix stores keys as "{prefix}:key": every key of the
+	// container lives in the same hash slot (and thus on the same Cluster node),
+	// so bulk operations are single MGET / MSET commands. Ignored without prefix.
+	KeyLayoutHashTagPrefix
+	// KeyLayoutShard stores keys as "prefix:{shard}:key", where shard is
+	// returned by the ShardFunc: related keys co-locate without concentrating
+	// the whole container on one node.
+	KeyLayoutShard
+)
+
+// FullKey returns the Redis key under which a user-supplied key is stored, for
+// use in Lua scripts or transactions run directly against Redis. Under Redis
+// Cluster, keys can only be combined when they hash to the same slot (see
+// HashSlot and KeyLayout).
+func (r *LowLevelClient) FullKey(key string) string {
+	return r.fullKey(key)
+}
+
+// fullKey builds the Redis key of a user-supplied key.
+func (r *LowLevelClient) fullKey(key string) string {
+	return r.keySpace() + r.shardTag(key) + key
+}
+
+// userKey strips the key space and the shard tag from a full Redis key.
+func (r *LowLevelClient) userKey(fullKey string) string {
+	key := strings.TrimPrefix(fullKey, r.keySpace())
+	if r.keyLayout == KeyLayoutShard && strings.HasPrefix(key, "{") {
+		if _, rest, found := strings.Cut(key, "}:"); found {
+			return rest
+		}
+	}
+	return key
+}
+
+// keySpace returns the literal prefix shared by every key of the container:
+// "prefix:", "{prefix}:" or the empty string.
+func (r *LowLevelClient) keySpace() string {
+	switch {
+	case r.keyPrefix == "":
+		return ""
+	case r.keyLayout == KeyLayoutHashTagPrefix:
+		return "{" + r.keyPrefix + "}:"
+	default:
+		return r.keyPrefix + ":"
+	}
+}
+
+// shardTag returns the "{shard}:" segment of a key in the shard layout.
+func (r *LowLevelClient) shardTag(key string) string {
+	if r.keyLayout != KeyLayoutShard || r.shard == nil {
+		return ""
+	}
+	return "{" + r.shard(key) + "}:"
+}
+
+// scanPattern returns the SCAN MATCH pattern of the keys starting with prefix.
+func (r *LowLevelClient) scanPattern(prefix string) string {
+	pattern := escapeGlob(r.keySpace())
+	if r.keyLayout == KeyLayoutShard && r.shard != nil {
+		pattern += "{*}:"
+	}
+	return pattern + escapeGlob(prefix) + "*"
+}
+
+// normalizeKeyPrefix trims spaces and the trailing separator of a key prefix.
+func normalizeKeyPrefix(keyPrefix string) string {
+	return strings.TrimSuffix(strings.TrimSpace(keyPrefix), ":")
+}
+
+// escapeGlob escapes the characters that have a special meaning in Redis
+// glob-style patterns so that s is matched literally.
+func escapeGlob(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for _, c := range s {
+		switch c {
+		case '*', '?', '[', ']', '\\':
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(c)
+	}
+	return sb.String()
+}
+
+// HashSlot returns the Redis Cluster hash slot of a key: the CRC16 of its hash
+// tag (the first non-empty "{...}" section), or of the whole key, modulo 16384.
+func HashSlot(key string) int {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	return int(crc16(key)) % 16384
+}
+
+// crc16 implements CRC-16/XMODEM, the checksum used by Redis Cluster.
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := range len(s) {
+		crc ^= uint16(s[i]) << 8
+		for range 8 {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
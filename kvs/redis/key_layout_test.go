@@ -0,0 +1,240 @@
+package redis_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	kvsredis "github.com/arielsrv/go-kvs-client/kvs/redis"
+)
+
+// tenant is the ShardFunc used by the tests: the part of the key before "/".
+func tenant(key string) string {
+	shard, _, _ := strings.Cut(key, "/")
+	return shard
+}
+
+func TestHashSlot(t *testing.T) {
+	tests := []struct {
+		key  string
+		slot int
+	}{
+		{key: "123456789", slot: 12739},
+		{key: "foo", slot: 12182},
+		{key: "{user1000}.following", slot: kvsredis.HashSlot("user1000")},
+		{key: "foo{}{bar}", slot: kvsredis.HashSlot("foo{}{bar}")},
+		{key: "foo{{bar}}zap", slot: kvsredis.HashSlot("{bar")},
+		{key: "foo{bar}{zap}", slot: kvsredis.HashSlot("bar")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			require.Equal(t, tt.slot, kvsredis.HashSlot(tt.key))
+		})
+	}
+}
+
+func TestKeyLayout_FullKey(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []kvsredis.BuilderOptions
+		want string
+	}{
+		{name: "plain", opts: []kvsredis.BuilderOptions{kvsredis.WithKeyPrefix("__kvs:users")}, want: "__kvs:users:acme/1"},
+		{
+			name: "hash tagged prefix",
+			opts: []kvsredis.BuilderOptions{kvsredis.WithKeyPrefix("__kvs:users"), kvsredis.WithHashTaggedPrefix()},
+			want: "{__kvs:users}:acme/1",
+		},
+		{name: "hash tagged without prefix", opts: []kvsredis.BuilderOptions{kvsredis.WithHashTaggedPrefix()}, want: "acme/1"},
+		{
+			name: "shard",
+			opts: []kvsredis.BuilderOptions{kvsredis.WithKeyPrefix("__kvs:users"), kvsredis.WithShardFunc(tenant)},
+			want: "__kvs:users:{acme}:acme/1",
+		},
+		{name: "shard without prefix", opts: []kvsredis.BuilderOptions{kvsredis.WithShardFunc(tenant)}, want: "{acme}:acme/1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, newClient(t, tt.opts...).FullKey("acme/1"))
+		})
+	}
+}
+
+func TestKeyLayout_FluentSetters(t *testing.T) {
+	tagged := kvsredis.NewBuilder(kvsredis.WithKeyPrefix("users")).WithHashTaggedPrefix().FakeBuild()
+	require.Equal(t, "{users}:1", tagged.FullKey("1"))
+
+	sharded := kvsredis.NewBuilder(kvsredis.WithKeyPrefix("users")).WithShardFunc(tenant).FakeBuild()
+	require.Equal(t, "users:{acme}:acme/1", sharded.FullKey("acme/1"))
+}
+
+func TestKeyLayout_Operations(t *testing.T) {
+	layouts := map[string][]kvsredis.BuilderOptions{
+		"hash tagged prefix":   {kvsredis.WithKeyPrefix("__kvs:users"), kvsredis.WithHashTaggedPrefix()},
+		"shard":                {kvsredis.WithKeyPrefix("__kvs:users"), kvsredis.WithShardFunc(tenant)},
+		"shard without prefix": {kvsredis.WithShardFunc(tenant)},
+	}
+	for name, opts := range layouts {
+		t.Run(name, func(t *testing.T) {
+			srv := miniredis.RunT(t)
+			client := kvsredis.NewBuilder(append(opts, kvsredis.WithAddresses(srv.Addr()))...).Build()
+			t.Cleanup(func() { _ = client.Close() })
+			ctx := t.Context()
+
+			items := new(kvs.Items)
+			items.Add(kvs.NewItem("acme/1", testUser{ID: 1}))
+			items.Add(kvs.NewItem("acme/2", testUser{ID: 2}))
+			items.Add(kvs.NewItem("globex/1", testUser{ID: 3}))
+			require.NoError(t, client.BulkSave(items))
+			require.True(t, srv.Exists(client.FullKey("acme/1")))
+
+			got, err := client.BulkGet([]string{"globex/1", "acme/2", "missing/1"})
+			require.NoError(t, err)
+			require.Equal(t, 2, got.Len())
+
+			var keys []string
+			for item, scanErr := range client.Scan(ctx, kvs.ScanOptions{Prefix: "acme/"}) {
+				require.NoError(t, scanErr)
+				keys = append(keys, item.Key)
+			}
+			require.ElementsMatch(t, []string{"acme/1", "acme/2"}, keys)
+
+			require.NoError(t, client.Delete("acme/1"))
+			_, err = client.Get("acme/1")
+			require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+		})
+	}
+}
+
+// commandCounter is a go-redis hook counting the commands sent, by name.
+type commandCounter struct {
+	counts map[string]int
+	mu     sync.Mutex
+}
+
+func (r *commandCounter) DialHook(next goredis.DialHook) goredis.DialHook { return next }
+
+func (r *commandCounter) ProcessHook(next goredis.ProcessHook) goredis.ProcessHook {
+	return func(ctx context.Context, cmd goredis.Cmder) error {
+		r.add(cmd)
+		return next(ctx, cmd)
+	}
+}
+
+func (r *commandCounter) ProcessPipelineHook(next goredis.ProcessPipelineHook) goredis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []goredis.Cmder) error {
+		for _, cmd := range cmds {
+			r.add(cmd)
+		}
+		return next(ctx, cmds)
+	}
+}
+
+func (r *commandCounter) add(cmd goredis.Cmder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counts[cmd.Name()]++
+}
+
+func (r *commandCounter) take() map[string]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := r.counts
+	r.counts = make(map[string]int)
+	return counts
+}
+
+// newClusterClient returns a GoRedisClient backed by a go-redis Cluster client
+// on miniredis, which serves every slot, and the hook counting its commands.
+func newClusterClient(t *testing.T) (*miniredis.Miniredis, *kvsredis.GoRedisClient, *commandCounter) {
+	t.Helper()
+	srv := miniredis.RunT(t)
+	cluster := goredis.NewClusterClient(&goredis.ClusterOptions{Addrs: []string{srv.Addr()}})
+	t.Cleanup(func() { _ = cluster.Close() })
+
+	require.NoError(t, cluster.Ping(t.Context()).Err())
+	counter := &commandCounter{counts: make(map[string]int)}
+	cluster.AddHook(counter)
+	return srv, kvsredis.NewGoRedisClient(cluster), counter
+}
+
+func TestGoRedisClient_Cluster_GroupsBulkOperationsBySlot(t *testing.T) {
+	srv, client, counter := newClusterClient(t)
+	ctx := t.Context()
+
+	pairs := []kvsredis.Pair{
+		{Key: "{a}:1", Value: "1"},
+		{Key: "{a}:2", Value: "2"},
+		{Key: "{b}:1", Value: "3"},
+	}
+	require.NoError(t, client.MSet(ctx, pairs))
+	require.Equal(t, map[string]int{"mset": 1, "set": 1}, counter.take())
+
+	results, err := client.MGet(ctx, []string{"{b}:1", "{a}:1", "{c}:1", "{a}:2", "{a}:1"})
+	require.NoError(t, err)
+	require.Equal(t, []kvsredis.GetResult{
+		{Key: "{b}:1", Value: "3", Found: true},
+		{Key: "{a}:1", Value: "1", Found: true},
+		{Key: "{c}:1"},
+		{Key: "{a}:2", Value: "2", Found: true},
+		{Key: "{a}:1", Value: "1", Found: true},
+	}, results)
+	require.Equal(t, map[string]int{"mget": 1, "get": 2}, counter.take())
+
+	// Per-pair TTLs are honoured through a script.
+	pairs[0].TTL = time.Minute
+	require.NoError(t, client.MSet(ctx, pairs))
+	require.Equal(t, map[string]int{"eval": 1, "set": 1}, counter.take())
+	require.Equal(t, time.Minute, srv.TTL("{a}:1"))
+	require.Zero(t, srv.TTL("{a}:2"))
+}
+
+func TestGoRedisClient_Standalone_UsesSingleMGetAndMSet(t *testing.T) {
+	srv, client := startMiniredis(t)
+	ctx := t.Context()
+
+	pairs := []kvsredis.Pair{
+		{Key: "a", Value: "1", TTL: time.Minute},
+		{Key: "b", Value: "2"},
+	}
+	require.NoError(t, client.MSet(ctx, pairs))
+	require.Equal(t, time.Minute, srv.TTL("a"))
+	require.Zero(t, srv.TTL("b"))
+
+	results, err := client.MGet(ctx, []string{"b", "missing", "a"})
+	require.NoError(t, err)
+	require.Equal(t, []kvsredis.GetResult{
+		{Key: "b", Value: "2", Found: true},
+		{Key: "missing"},
+		{Key: "a", Value: "1", Found: true},
+	}, results)
+}
+
+func TestGoRedisClient_Cluster_MGet_NonStringKey_IsNotFound(t *testing.T) {
+	srv, client, _ := newClusterClient(t)
+	require.NoError(t, client.Set(t.Context(), "{a}:1", "1", 0))
+	srv.HSet("{b}:1", "field", "value")
+
+	results, err := client.MGet(t.Context(), []string{"{a}:1", "{b}:1"})
+	require.NoError(t, err)
+	require.Equal(t, []kvsredis.GetResult{
+		{Key: "{a}:1", Value: "1", Found: true},
+		{Key: "{b}:1"},
+	}, results)
+}
+
+func TestGoRedisClient_Cluster_MGetError(t *testing.T) {
+	srv, client, _ := newClusterClient(t)
+	srv.SetError("boom")
+
+	_, err := client.MGet(t.Context(), []string{"{a}:1", "{b}:1"})
+	require.Error(t, err)
+	require.Error(t, client.MSet(t.Context(), []kvsredis.Pair{{Key: "{a}:1"}, {Key: "{b}:1"}}))
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"strings"
 	"time"
 
@@ -24,12 +25,30 @@ import (
 //   - Concurrent reads for the same key are de-duplicated via singleflight.
 //   - Bulk operations are capped at MaxBulkKeys keys to preserve a consistent
 //     contract with the DynamoDB implementation (kvs.ErrTooManyKeys).
-//   - Keys are automatically namespaced with the configured key prefix.
+//   - Keys are automatically namespaced with the configured key prefix,
+//     optionally with Cluster hash tags (see KeyLayout).
+//   - When an InvalidationBus is configured, every successful write publishes
+//     an InvalidationEvent on the topic of the container.
+//   - When client tracking is enabled, values read are kept in an in-process
+//     LRU that the server invalidates (see Builder.WithClientTracking).
 type LowLevelClient struct {
 	client    Client
+	bus       InvalidationBus
+	cache     *localCache
 	read      singleflight.Group
+	shard     ShardFunc
 	keyPrefix string
+	origin    string
 	ttl       time.Duration
+	keyLayout KeyLayout
+	storage   StorageMode
+	// now is the clock against which item TTLs are resolved (see Builder.WithClock).
+	now func() time.Time
+	// stop shuts down the embedded server of a client created by MiniBuild.
+	stop    func()
+	ownsBus bool
+	// migrateOnRead rewrites the legacy strings read in the record modes.
+	migrateOnRead bool
 }
 
 // NewLowLevelClient creates a new LowLevelClient backed by the provided
@@ -39,7 +58,8 @@ type LowLevelClient struct {
 func NewLowLevelClient(client Client, keyPrefix string, ttl ...time.Duration) *LowLevelClient {
 	llc := &LowLevelClient{
 		client:    client,
-		keyPrefix: strings.TrimSuffix(strings.TrimSpace(keyPrefix), ":"),
+		keyPrefix: normalizeKeyPrefix(keyPrefix),
+		now:       time.Now,
 	}
 	if len(ttl) > 0 {
 		llc.ttl = ttl[0]
@@ -68,9 +88,63 @@ func (r *LowLevelClient) ContainerName() string {
 	return r.keyPrefix
 }
 
-// Close releases the underlying Redis connection pool.
+// Close releases the underlying Redis connection pool, along with the
+// invalidation bus when it has been created by the Builder and the embedded
+// server started by Builder.MiniBuild.
 func (r *LowLevelClient) Close() error {
-	return r.client.Close()
+	if r.ownsBus {
+		_ = r.bus.Close()
+	}
+	err := r.client.Close()
+	if r.stop != nil {
+		r.stop()
+	}
+	return err
+}
+
+// CacheLen returns the number of values held by the client-side cache; zero when
+// client tracking is disabled.
+func (r *LowLevelClient) CacheLen() int {
+	if r.cache == nil {
+		return 0
+	}
+	return r.cache.len()
+}
+
+// enableTracking attaches a client-side cache of size entries, evicted through
+// tracker. Values older than maxAge are never served.
+func (r *LowLevelClient) enableTracking(tracker Tracker, mode TrackingMode, size int, maxAge time.Duration) {
+	r.cache = newLocalCache(size, maxAge, r.now)
+	var prefixes []string
+	if keySpace := r.keySpace(); keySpace != "" {
+		prefixes = []string{keySpace}
+	}
+	tracker.EnableTracking(mode, prefixes, r.cache.invalidate)
+}
+
+// InvalidationTopic returns the topic on which the client publishes the changes
+// of its container.
+func (r *LowLevelClient) InvalidationTopic() string {
+	return InvalidationTopic(r.ContainerName())
+}
+
+// InvalidationOrigin returns the identifier set as InvalidationEvent.Origin on
+// the events published by this client.
+func (r *LowLevelClient) InvalidationOrigin() string {
+	return r.origin
+}
+
+// SubscribeInvalidations subscribes handler to the changes of the container
+// made by any client sharing the invalidation bus, including this one.
+// Returns ErrNoInvalidationBus if no bus is configured.
+func (r *LowLevelClient) SubscribeInvalidations(
+	ctx context.Context,
+	handler InvalidationHandler,
+) (Subscription, error) {
+	if r.bus == nil {
+		return nil, ErrNoInvalidationBus
+	}
+	return r.bus.Subscribe(ctx, r.InvalidationTopic(), handler)
 }
 
 // Get implements kvs.LowLevelClient.
@@ -88,15 +162,19 @@ func (r *LowLevelClient) GetWithContext(ctx context.Context, key string) (*kvs.I
 		return nil, fmt.Errorf("redis GetWithContext: %w", err)
 	}
 
+	fullKey := r.fullKey(key)
+	if r.cache != nil {
+		if record, found := r.cache.get(fullKey); found {
+			return r.item(key, record), nil
+		}
+	}
+
 	result, err, _ := r.read.Do(key, func() (any, error) {
-		value, gErr := r.client.Get(ctx, r.fullKey(key))
+		record, gErr := r.get(ctx, fullKey)
 		if gErr != nil {
 			return nil, gErr
 		}
-		return &kvs.Item{
-			Key:   key,
-			Value: value,
-		}, nil
+		return r.item(key, record), nil
 	})
 	if err != nil {
 		return nil, err
@@ -115,6 +193,9 @@ func (r *LowLevelClient) Save(key string, item *kvs.Item) error {
 //   - if item.TTL > 0 it is interpreted as a Unix timestamp and converted to
 //     the remaining duration; if it has already elapsed, the call is a no-op.
 //   - otherwise, the builder default (r.ttl) is used (zero means "no expiration").
+//
+// In StorageHash and StorageJSON modes the item is stored as a Record whose
+// version is bumped.
 func (r *LowLevelClient) SaveWithContext(ctx context.Context, key string, item *kvs.Item) error {
 	if strings.TrimSpace(key) == "" {
 		return kvs.ErrEmptyKey
@@ -122,6 +203,9 @@ func (r *LowLevelClient) SaveWithContext(ctx context.Context, key string, item *
 	if item == nil {
 		return kvs.ErrNilItem
 	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("redis SaveWithContext: %w", err)
+	}
 
 	bytes, err := json.Marshal(item.Value)
 	if err != nil {
@@ -133,7 +217,15 @@ func (r *LowLevelClient) SaveWithContext(ctx context.Context, key string, item *
 		return nil
 	}
 
-	return r.client.Set(ctx, r.fullKey(key), string(bytes), ttl)
+	if r.records() {
+		_, err = r.client.SetRecord(ctx, r.fullKey(key), r.newRecord(string(bytes), item.TTL, ttl), ttl, RecordCondition{})
+	} else {
+		err = r.client.Set(ctx, r.fullKey(key), string(bytes), ttl)
+	}
+	if err != nil {
+		return err
+	}
+	return r.invalidate(ctx, InvalidationSet, key)
 }
 
 // BulkGet implements kvs.LowLevelClient.
@@ -143,7 +235,8 @@ func (r *LowLevelClient) BulkGet(keys []string) (*kvs.Items, error) {
 
 // BulkGetWithContext implements kvs.LowLevelClient.
 // At most MaxBulkKeys keys are accepted; otherwise kvs.ErrTooManyKeys is returned.
-// Missing keys are silently skipped (consistent with the DynamoDB backend).
+// Missing and empty keys are silently skipped and duplicate keys are read once
+// (consistent with the DynamoDB backend); items come in the order of keys.
 func (r *LowLevelClient) BulkGetWithContext(ctx context.Context, keys []string) (*kvs.Items, error) {
 	if len(keys) > MaxBulkKeys {
 		return nil, kvs.ErrTooManyKeys
@@ -152,29 +245,84 @@ func (r *LowLevelClient) BulkGetWithContext(ctx context.Context, keys []string)
 		return new(kvs.Items), nil
 	}
 
-	prefixed := make([]string, len(keys))
+	// Items are collected by position so that the order of keys is preserved
+	// when some of them are served from the client-side cache.
+	found := make([]*kvs.Item, len(keys))
+	prefixed := make([]string, 0, len(keys))
+	positions := make([]int, 0, len(keys))
+	seen := make(map[string]bool, len(keys))
 	for i, key := range keys {
-		prefixed[i] = r.fullKey(key)
+		if seen[key] || strings.TrimSpace(key) == "" {
+			continue
+		}
+		seen[key] = true
+
+		fullKey := r.fullKey(key)
+		if r.cache != nil {
+			if record, hit := r.cache.get(fullKey); hit {
+				found[i] = r.item(key, record)
+				continue
+			}
+		}
+		prefixed = append(prefixed, fullKey)
+		positions = append(positions, i)
 	}
 
-	results, err := r.client.MGet(ctx, prefixed)
-	if err != nil {
-		return nil, err
+	if len(prefixed) > 0 {
+		results, err := r.mGet(ctx, prefixed)
+		if err != nil {
+			return nil, err
+		}
+		for i, result := range results {
+			if result.Found {
+				found[positions[i]] = r.item(keys[positions[i]], result.Record)
+			}
+		}
 	}
 
 	items := new(kvs.Items)
-	for i, result := range results {
-		if !result.Found {
-			continue
+	for _, item := range found {
+		if item != nil {
+			items.Add(item)
 		}
-		items.Add(&kvs.Item{
-			Key:   keys[i],
-			Value: result.Value,
-		})
 	}
 	return items, nil
 }
 
+// get fetches a full key, filling the client-side cache.
+func (r *LowLevelClient) get(ctx context.Context, fullKey string) (Record, error) {
+	if r.cache == nil {
+		return r.fetchOne(ctx, fullKey)
+	}
+
+	ticket := r.cache.reserve(fullKey)
+	record, err := r.fetchOne(ctx, fullKey)
+	values := make(map[string]Record, 1)
+	if err == nil {
+		values[fullKey] = record
+	}
+	r.cache.fill(ticket, []string{fullKey}, values)
+	return record, err
+}
+
+// mGet fetches full keys, filling the client-side cache.
+func (r *LowLevelClient) mGet(ctx context.Context, fullKeys []string) ([]RecordResult, error) {
+	if r.cache == nil {
+		return r.fetch(ctx, fullKeys)
+	}
+
+	ticket := r.cache.reserve(fullKeys...)
+	results, err := r.fetch(ctx, fullKeys)
+	values := make(map[string]Record, len(results))
+	for _, result := range results {
+		if result.Found {
+			values[result.Key] = result.Record
+		}
+	}
+	r.cache.fill(ticket, fullKeys, values)
+	return results, err
+}
+
 // BulkSave implements kvs.LowLevelClient.
 func (r *LowLevelClient) BulkSave(items *kvs.Items) error {
 	return r.BulkSaveWithContext(context.Background(), items)
@@ -188,6 +336,8 @@ func (r *LowLevelClient) BulkSaveWithContext(ctx context.Context, kvsItems *kvs.
 	}
 
 	pairs := make([]Pair, 0, kvsItems.Len())
+	records := make([]RecordPair, 0, kvsItems.Len())
+	keys := make([]string, 0, kvsItems.Len())
 	for item := range kvsItems.All() {
 		if item == nil || strings.TrimSpace(item.Key) == "" {
 			continue
@@ -204,29 +354,305 @@ func (r *LowLevelClient) BulkSaveWithContext(ctx context.Context, kvsItems *kvs.
 			continue
 		}
 
-		pairs = append(pairs, Pair{
-			Key:   r.fullKey(item.Key),
-			Value: string(bytes),
-			TTL:   ttl,
-		})
+		if r.records() {
+			records = append(records, RecordPair{
+				Key:    r.fullKey(item.Key),
+				Record: r.newRecord(string(bytes), item.TTL, ttl),
+				TTL:    ttl,
+			})
+		} else {
+			pairs = append(pairs, Pair{
+				Key:   r.fullKey(item.Key),
+				Value: string(bytes),
+				TTL:   ttl,
+			})
+		}
+		keys = append(keys, item.Key)
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	var err error
+	if r.records() {
+		err = r.client.SetRecords(ctx, records)
+	} else {
+		err = r.client.MSet(ctx, pairs)
+	}
+	if err != nil {
+		return err
+	}
+	return r.invalidate(ctx, InvalidationSet, keys...)
+}
+
+// Delete implements kvs.LowLevelClient.
+func (r *LowLevelClient) Delete(key string) error {
+	return r.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext implements kvs.LowLevelClient.
+// Deleting a missing key is not an error.
+func (r *LowLevelClient) DeleteWithContext(ctx context.Context, key string) error {
+	if strings.TrimSpace(key) == "" {
+		return kvs.ErrEmptyKey
+	}
+
+	err := r.client.Del(ctx, r.fullKey(key))
+	if err != nil {
+		return err
 	}
+	return r.invalidate(ctx, InvalidationDelete, key)
+}
 
-	if len(pairs) == 0 {
+// SaveIfAbsent implements kvs.LowLevelClient using SET NX.
+// TTL semantics are those of SaveWithContext.
+func (r *LowLevelClient) SaveIfAbsent(ctx context.Context, key string, item *kvs.Item) error {
+	if strings.TrimSpace(key) == "" {
+		return kvs.ErrEmptyKey
+	}
+	if item == nil {
+		return kvs.ErrNilItem
+	}
+
+	bytes, err := json.Marshal(item.Value)
+	if err != nil {
+		return fmt.Errorf("redis SaveIfAbsent: marshal: %w", err)
+	}
+
+	ttl, skip := r.resolveTTL(item.TTL)
+	if skip {
 		return nil
 	}
 
-	if err := r.client.MSet(ctx, pairs); err != nil {
+	var stored bool
+	if r.records() {
+		stored, err = r.client.SetRecord(
+			ctx, r.fullKey(key), r.newRecord(string(bytes), item.TTL, ttl), ttl, RecordCondition{IfAbsent: true},
+		)
+	} else {
+		stored, err = r.client.SetNX(ctx, r.fullKey(key), string(bytes), ttl)
+	}
+	if err != nil {
+		return fmt.Errorf("redis SaveIfAbsent: %w", err)
+	}
+	if !stored {
+		return kvs.ErrConditionFailed
+	}
+	return r.invalidate(ctx, InvalidationSet, key)
+}
+
+// CompareAndSwap implements kvs.LowLevelClient through a Lua script.
+// TTL semantics are those of SaveWithContext, except that an item whose TTL
+// has already elapsed deletes the stored one.
+func (r *LowLevelClient) CompareAndSwap(ctx context.Context, key string, expected, item *kvs.Item) error {
+	if strings.TrimSpace(key) == "" {
+		return kvs.ErrEmptyKey
+	}
+	if item == nil {
+		return kvs.ErrNilItem
+	}
+
+	expectedValue, err := storedValue(expected)
+	if err != nil {
+		return err
+	}
+
+	bytes, err := json.Marshal(item.Value)
+	if err != nil {
+		return fmt.Errorf("redis CompareAndSwap: marshal: %w", err)
+	}
+
+	var swapped bool
+	op := InvalidationSet
+	ttl, skip := r.resolveTTL(item.TTL)
+	switch {
+	case skip:
+		op = InvalidationDelete
+		swapped, err = r.client.CompareAndDelete(ctx, r.fullKey(key), expectedValue)
+	case r.records():
+		swapped, err = r.client.SetRecord(
+			ctx, r.fullKey(key), r.newRecord(string(bytes), item.TTL, ttl), ttl, RecordCondition{Expected: &expectedValue},
+		)
+	default:
+		swapped, err = r.client.CompareAndSwap(ctx, r.fullKey(key), expectedValue, string(bytes), ttl)
+	}
+	if err != nil {
+		return fmt.Errorf("redis CompareAndSwap: %w", err)
+	}
+	if !swapped {
+		return kvs.ErrConditionFailed
+	}
+	return r.invalidate(ctx, op, key)
+}
+
+// CompareAndDelete implements kvs.LowLevelClient through a Lua script.
+// In StorageHash and StorageJSON modes the value of the record is compared.
+func (r *LowLevelClient) CompareAndDelete(ctx context.Context, key string, expected *kvs.Item) error {
+	if strings.TrimSpace(key) == "" {
+		return kvs.ErrEmptyKey
+	}
+
+	expectedValue, err := storedValue(expected)
+	if err != nil {
 		return err
 	}
+
+	deleted, err := r.client.CompareAndDelete(ctx, r.fullKey(key), expectedValue)
+	if err != nil {
+		return fmt.Errorf("redis CompareAndDelete: %w", err)
+	}
+	if !deleted {
+		return kvs.ErrConditionFailed
+	}
+	return r.invalidate(ctx, InvalidationDelete, key)
+}
+
+// Increment implements kvs.LowLevelClient using INCRBY.
+// Counters are stored as plain integers, which are valid JSON, so they can be
+// read back through Get like any other item (as legacy items in the record
+// modes). A counter stored as a record has its value incremented.
+func (r *LowLevelClient) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	if strings.TrimSpace(key) == "" {
+		return 0, kvs.ErrEmptyKey
+	}
+
+	value, err := r.client.IncrBy(ctx, r.fullKey(key), delta, r.counterTTL(ttl))
+	if err != nil {
+		return 0, fmt.Errorf("redis Increment: %w", err)
+	}
+	return value, r.invalidate(ctx, InvalidationSet, key)
+}
+
+// IncrementFloat implements kvs.LowLevelClient using INCRBYFLOAT.
+func (r *LowLevelClient) IncrementFloat(
+	ctx context.Context,
+	key string,
+	delta float64,
+	ttl time.Duration,
+) (float64, error) {
+	if strings.TrimSpace(key) == "" {
+		return 0, kvs.ErrEmptyKey
+	}
+
+	value, err := r.client.IncrByFloat(ctx, r.fullKey(key), delta, r.counterTTL(ttl))
+	if err != nil {
+		return 0, fmt.Errorf("redis IncrementFloat: %w", err)
+	}
+	return value, r.invalidate(ctx, InvalidationSet, key)
+}
+
+// Scan implements kvs.LowLevelClient.
+// It issues SCAN with a MATCH pattern built from the key prefix and opts.Prefix,
+// then fetches the values of each page of keys through MGet (skipped when
+// opts.KeysOnly is set). Keys that expire between SCAN and MGet are skipped.
+// Yielded items carry the user-facing key, without the configured key prefix.
+func (r *LowLevelClient) Scan(ctx context.Context, opts kvs.ScanOptions) iter.Seq2[*kvs.Item, error] {
+	return func(yield func(*kvs.Item, error) bool) {
+		pageSize := opts.PageSizeOrDefault()
+		match := r.scanPattern(opts.Prefix)
+
+		page := make([]string, 0, pageSize)
+		for key, err := range r.client.Scan(ctx, match, int64(pageSize)) {
+			if err != nil {
+				yield(nil, fmt.Errorf("redis Scan: %w", err))
+				return
+			}
+			page = append(page, key)
+			if len(page) < pageSize {
+				continue
+			}
+			if !r.yieldPage(ctx, page, opts.KeysOnly, yield) {
+				return
+			}
+			page = page[:0]
+		}
+
+		if len(page) > 0 {
+			r.yieldPage(ctx, page, opts.KeysOnly, yield)
+		}
+	}
+}
+
+// yieldPage resolves a page of full (prefixed) keys into items and hands them
+// to yield. It returns false when the iteration must stop.
+func (r *LowLevelClient) yieldPage(
+	ctx context.Context,
+	page []string,
+	keysOnly bool,
+	yield func(*kvs.Item, error) bool,
+) bool {
+	if keysOnly {
+		for _, key := range page {
+			if !yield(&kvs.Item{Key: r.userKey(key)}, nil) {
+				return false
+			}
+		}
+		return true
+	}
+
+	results, err := r.fetch(ctx, page)
+	if err != nil {
+		yield(nil, fmt.Errorf("redis Scan: %w", err))
+		return false
+	}
+	for _, result := range results {
+		if !result.Found {
+			continue
+		}
+		if !yield(r.item(r.userKey(result.Key), result.Record), nil) {
+			return false
+		}
+	}
+	return true
+}
+
+// invalidate drops the locally cached values of keys and publishes their change
+// on the invalidation topic, if a bus is configured. The write has already been
+// applied when it fails.
+func (r *LowLevelClient) invalidate(ctx context.Context, op InvalidationOp, keys ...string) error {
+	if r.cache != nil {
+		fullKeys := make([]string, len(keys))
+		for i, key := range keys {
+			fullKeys[i] = r.fullKey(key)
+		}
+		r.cache.invalidate(fullKeys)
+	}
+	if r.bus == nil {
+		return nil
+	}
+
+	err := r.bus.Publish(ctx, r.InvalidationTopic(), InvalidationEvent{
+		Op:     op,
+		Origin: r.origin,
+		Keys:   keys,
+	})
+	if err != nil {
+		return fmt.Errorf("redis invalidate: %w", err)
+	}
 	return nil
 }
 
-// fullKey joins the configured prefix and the user-supplied key.
-func (r *LowLevelClient) fullKey(key string) string {
-	if r.keyPrefix == "" {
-		return key
+// storedValue returns the raw JSON string held by an item returned by Get.
+func storedValue(item *kvs.Item) (string, error) {
+	if item == nil {
+		return "", kvs.ErrNilItem
+	}
+
+	value, ok := item.Value.(string)
+	if !ok {
+		return "", kvs.ErrConvert
 	}
-	return r.keyPrefix + ":" + key
+	return value, nil
+}
+
+// counterTTL returns the TTL applied when a counter is created: the explicit
+// ttl when positive, the builder default otherwise.
+func (r *LowLevelClient) counterTTL(ttl time.Duration) time.Duration {
+	if ttl > 0 {
+		return ttl
+	}
+	return r.ttl
 }
 
 // resolveTTL converts a kvs.Item TTL (Unix timestamp) into a duration suitable
@@ -234,7 +660,7 @@ func (r *LowLevelClient) fullKey(key string) string {
 // its TTL is already in the past.
 func (r *LowLevelClient) resolveTTL(itemTTL int64) (time.Duration, bool) {
 	if itemTTL > 0 {
-		remaining := time.Until(time.Unix(itemTTL, 0))
+		remaining := time.Unix(itemTTL, 0).Sub(r.now())
 		if remaining <= 0 {
 			return 0, true
 		}
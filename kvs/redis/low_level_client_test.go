@@ -235,3 +235,147 @@ func TestLowLevelClient_IntegratesWithGenericClient(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, values, 2)
 }
+
+func TestLowLevelClient_Scan_FiltersByPrefixAndStripsKeyPrefix(t *testing.T) {
+	client := newClient(t, kvsredis.WithKeyPrefix("__kvs:scan"))
+
+	for _, key := range []string{"user:1", "user:2", "order:1"} {
+		require.NoError(t, client.Save(key, kvs.NewItem(key, testUser{Name: key})))
+	}
+
+	keys := make([]string, 0, 2)
+	for item, err := range client.Scan(t.Context(), kvs.ScanOptions{Prefix: "user:"}) {
+		require.NoError(t, err)
+		out := new(testUser)
+		require.NoError(t, item.TryGetValueAsObjectType(&out))
+		require.Equal(t, item.Key, out.Name)
+		keys = append(keys, item.Key)
+	}
+
+	require.ElementsMatch(t, []string{"user:1", "user:2"}, keys)
+}
+
+func TestLowLevelClient_Scan_KeysOnly(t *testing.T) {
+	client := newClient(t, kvsredis.WithKeyPrefix("__kvs:scan"))
+
+	require.NoError(t, client.Save("1", kvs.NewItem("1", testUser{ID: 1})))
+
+	count := 0
+	for item, err := range client.Scan(t.Context(), kvs.ScanOptions{KeysOnly: true}) {
+		require.NoError(t, err)
+		require.Equal(t, "1", item.Key)
+		require.Nil(t, item.Value)
+		count++
+	}
+	require.Equal(t, 1, count)
+}
+
+func TestLowLevelClient_Scan_PagesAndStopsOnBreak(t *testing.T) {
+	client := newClient(t)
+
+	for i := range 25 {
+		key := strconv.Itoa(i)
+		require.NoError(t, client.Save(key, kvs.NewItem(key, testUser{ID: i})))
+	}
+
+	all := 0
+	for _, err := range client.Scan(t.Context(), kvs.ScanOptions{PageSize: 10}) {
+		require.NoError(t, err)
+		all++
+	}
+	require.Equal(t, 25, all)
+
+	seen := 0
+	for _, err := range client.Scan(t.Context(), kvs.ScanOptions{PageSize: 10}) {
+		require.NoError(t, err)
+		seen++
+		if seen == 3 {
+			break
+		}
+	}
+	require.Equal(t, 3, seen)
+}
+
+func TestLowLevelClient_Scan_EscapesGlobCharactersInPrefix(t *testing.T) {
+	client := newClient(t, kvsredis.WithKeyPrefix("__kvs:scan"))
+
+	require.NoError(t, client.Save("a*1", kvs.NewItem("a*1", "literal")))
+	require.NoError(t, client.Save("ab1", kvs.NewItem("ab1", "other")))
+
+	keys := make([]string, 0, 1)
+	for item, err := range client.Scan(t.Context(), kvs.ScanOptions{Prefix: "a*", KeysOnly: true}) {
+		require.NoError(t, err)
+		keys = append(keys, item.Key)
+	}
+	require.Equal(t, []string{"a*1"}, keys)
+}
+
+func TestLowLevelClient_Increment_AppliesTTLOnCreateOnly(t *testing.T) {
+	fake := kvsredis.NewFakeClient()
+	client := kvsredis.NewLowLevelClient(fake, "__kvs:counter", time.Hour)
+
+	value, err := client.Increment(t.Context(), "hits", 2, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), value)
+
+	value, err = client.Increment(t.Context(), "hits", 3, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), value)
+
+	got, err := client.Get("hits")
+	require.NoError(t, err)
+	var out int64
+	require.NoError(t, got.TryGetValueAsObjectType(&out))
+	require.Equal(t, int64(5), out)
+
+	floatValue, err := client.IncrementFloat(t.Context(), "ratio", 0.5, 0)
+	require.NoError(t, err)
+	require.InDelta(t, 0.5, floatValue, 1e-9)
+
+	require.NoError(t, client.Delete("hits"))
+	_, err = client.Get("hits")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+}
+
+func TestLowLevelClient_Increment_EmptyKey(t *testing.T) {
+	client := newClient(t)
+
+	_, err := client.Increment(t.Context(), "", 1, 0)
+	require.ErrorIs(t, err, kvs.ErrEmptyKey)
+
+	_, err = client.IncrementFloat(t.Context(), " ", 1, 0)
+	require.ErrorIs(t, err, kvs.ErrEmptyKey)
+
+	require.ErrorIs(t, client.Delete(""), kvs.ErrEmptyKey)
+}
+
+func TestLowLevelClient_ConditionalWrites(t *testing.T) {
+	client := newClient(t, kvsredis.WithKeyPrefix("__kvs:test"))
+	ctx := t.Context()
+
+	require.NoError(t, client.SaveIfAbsent(ctx, "1", kvs.NewItem("1", testUser{ID: 1, Name: "a"})))
+	require.ErrorIs(t, client.SaveIfAbsent(ctx, "1", kvs.NewItem("1", testUser{ID: 1, Name: "b"})),
+		kvs.ErrConditionFailed)
+
+	current, err := client.Get("1")
+	require.NoError(t, err)
+
+	require.NoError(t, client.CompareAndSwap(ctx, "1", current, kvs.NewItem("1", testUser{ID: 1, Name: "c"})))
+	require.ErrorIs(t, client.CompareAndSwap(ctx, "1", current, kvs.NewItem("1", testUser{ID: 1, Name: "d"})),
+		kvs.ErrConditionFailed)
+	require.ErrorIs(t, client.CompareAndDelete(ctx, "1", current), kvs.ErrConditionFailed)
+
+	current, err = client.Get("1")
+	require.NoError(t, err)
+	out := new(testUser)
+	require.NoError(t, current.TryGetValueAsObjectType(out))
+	require.Equal(t, "c", out.Name)
+
+	require.NoError(t, client.CompareAndDelete(ctx, "1", current))
+	_, err = client.Get("1")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+	require.ErrorIs(t, client.CompareAndDelete(ctx, "1", current), kvs.ErrConditionFailed)
+
+	require.ErrorIs(t, client.SaveIfAbsent(ctx, " ", kvs.NewItem(" ", 1)), kvs.ErrEmptyKey)
+	require.ErrorIs(t, client.CompareAndSwap(ctx, "1", kvs.NewItem("1", 1), kvs.NewItem("1", 2)), kvs.ErrConvert)
+}
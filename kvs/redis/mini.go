@@ -0,0 +1,46 @@
+// Package redis provides a Redis implementation of the KVS client.
+package redis
+
+import (
+	"github.com/alicebob/miniredis/v2"
+)
+
+// MiniServer is the embedded Redis server started by Builder.MiniBuild. It is
+// a miniredis server, whose methods control it from tests:
+//
+//   - FastForward(d) moves the TTLs of the keys forward, expiring the keys
+//     whose TTL elapses; SetTime sets the clock of the server.
+//   - SetError(msg) makes every command fail with msg until SetError("").
+//   - Keys, Get, HGet, TTL, ... inspect the data set; FlushAll clears it.
+type MiniServer struct {
+	*miniredis.Miniredis
+}
+
+// MiniBuild creates a LowLevelClient backed by GoRedisClient and connected to
+// an embedded miniredis server, so that every Redis feature (hashes, scripts,
+// transactions, Pub/Sub invalidation, ...) can be exercised without a Redis
+// deployment. The configured addresses, master name and TLS settings are
+// ignored; a configured password is required by the server. Closing the client
+// stops the server.
+//
+// miniredis does not implement CLIENT TRACKING: with WithClientTracking, reads
+// fail as they would on a server without tracking.
+func (r *Builder) MiniBuild() (*LowLevelClient, *MiniServer, error) {
+	server, err := miniredis.Run()
+	if err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case r.username != "" && r.password != "":
+		server.RequireUserAuth(r.username, r.password)
+	case r.password != "":
+		server.RequireAuth(r.password)
+	}
+
+	builder := *r
+	builder.addresses = []string{server.Addr()}
+	builder.masterName, builder.tlsConfig = "", nil
+	llc := builder.Build()
+	llc.stop = server.Close
+	return llc, &MiniServer{Miniredis: server}, nil
+}
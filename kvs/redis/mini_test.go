@@ -0,0 +1,87 @@
+package redis_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	kvsredis "github.com/arielsrv/go-kvs-client/kvs/redis"
+)
+
+func newMiniClient(t *testing.T, opts ...kvsredis.BuilderOptions) (*kvsredis.LowLevelClient, *kvsredis.MiniServer) {
+	t.Helper()
+	opts = append(opts, kvsredis.WithKeyPrefix("__kvs:users"))
+	client, server, err := kvsredis.NewBuilder(opts...).MiniBuild()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client, server
+}
+
+func TestMiniBuild_FastForward_ExpiresItems(t *testing.T) {
+	client, server := newMiniClient(t, kvsredis.WithTTL(time.Minute))
+
+	require.NoError(t, client.Save("1", kvs.NewItem("1", testUser{ID: 1})))
+	require.NoError(t, client.Save("2", kvs.NewItem("2", testUser{ID: 2}, time.Hour)))
+	require.True(t, server.Exists("__kvs:users:1"))
+
+	server.FastForward(time.Minute)
+
+	_, err := client.Get("1")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+	item, err := client.Get("2")
+	require.NoError(t, err)
+	require.Equal(t, "2", item.Key)
+}
+
+func TestMiniBuild_SetError_FailsCommands(t *testing.T) {
+	client, server := newMiniClient(t)
+	require.NoError(t, client.Save("1", kvs.NewItem("1", testUser{ID: 1})))
+
+	server.SetError("LOADING Redis is loading the dataset in memory")
+	_, err := client.Get("1")
+	require.ErrorContains(t, err, "LOADING")
+	require.Error(t, client.Save("2", kvs.NewItem("2", testUser{ID: 2})))
+
+	server.SetError("")
+	_, err = client.Get("1")
+	require.NoError(t, err)
+}
+
+func TestMiniBuild_RequiresConfiguredPassword(t *testing.T) {
+	client, _ := newMiniClient(
+		t,
+		kvsredis.WithAddresses("redis.invalid:6379"),
+		kvsredis.WithUsername("kvs"),
+		kvsredis.WithPassword("secret"),
+	)
+
+	require.NoError(t, client.Save("1", kvs.NewItem("1", testUser{ID: 1})))
+	_, err := client.Get("1")
+	require.NoError(t, err)
+}
+
+func TestMiniBuild_StorageHashAndTransactions(t *testing.T) {
+	ctx := t.Context()
+	client, server := newMiniClient(t, kvsredis.WithStorageMode(kvsredis.StorageHash))
+
+	tx := client.NewTx()
+	tx.Put("1", kvs.NewItem("1", testUser{ID: 1}))
+	tx.Put("2", kvs.NewItem("2", testUser{ID: 2}))
+	require.NoError(t, tx.Commit(ctx))
+
+	require.Equal(t, "json", server.HGet("__kvs:users:2", "codec"))
+	items, err := client.BulkGetWithContext(ctx, []string{"1", "2"})
+	require.NoError(t, err)
+	require.Equal(t, 2, items.Len())
+}
+
+func TestMiniBuild_Close_StopsServer(t *testing.T) {
+	client, server, err := kvsredis.NewBuilder().MiniBuild()
+	require.NoError(t, err)
+	addr := server.Addr()
+
+	require.NoError(t, client.Close())
+	require.Error(t, kvsredis.NewBuilder(kvsredis.WithAddresses(addr)).Build().Save("1", kvs.NewItem("1", 1)))
+}
@@ -0,0 +1,150 @@
+// Package redis provides a Redis implementation of the KVS client.
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// maxPatchAttempts bounds the optimistic retries of Patch when the item keeps
+// changing between the read and the conditional write.
+const maxPatchAttempts = 10
+
+// Patch implements kvs.LowLevelClient.
+//
+// In StorageJSON mode, the fields of a document are updated in place with
+// JSON.SET and JSON.DEL (see Client.PatchRecord), without reading the item.
+// Otherwise, and for the keys not yet converted to documents, Patch falls back
+// to an optimistic read-modify-write: the item is read (bypassing the
+// client-side cache), patched with kvs.PatchJSON and written back with a
+// compare-and-swap that keeps its expiration, retrying when it has changed in
+// between. The fallback transfers the whole value twice and returns
+// kvs.ErrConditionFailed when every attempt lost a race against other writers.
+func (r *LowLevelClient) Patch(ctx context.Context, key string, updates map[string]any) error {
+	if strings.TrimSpace(key) == "" {
+		return kvs.ErrEmptyKey
+	}
+
+	fullKey := r.fullKey(key)
+	if r.storage == StorageJSON {
+		fieldUpdates, err := fieldUpdates(updates)
+		if err != nil {
+			return err
+		}
+		patched, err := r.client.PatchRecord(ctx, fullKey, fieldUpdates)
+		if err != nil {
+			return fmt.Errorf("redis Patch: %w", err)
+		}
+		if patched {
+			return r.invalidate(ctx, InvalidationSet, key)
+		}
+	}
+
+	for range maxPatchAttempts {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("redis Patch: %w", err)
+		}
+
+		current, err := r.fetchOne(ctx, fullKey)
+		if err != nil {
+			return err
+		}
+
+		patched, err := kvs.PatchJSON(current.Value, updates)
+		if err != nil {
+			return err
+		}
+
+		var swapped bool
+		if r.records() {
+			swapped, err = r.client.SetRecord(
+				ctx, fullKey, r.newRecord(patched, current.Expires, 0), 0,
+				RecordCondition{Expected: &current.Value, KeepTTL: true},
+			)
+		} else {
+			swapped, err = r.client.CompareAndSwap(ctx, fullKey, current.Value, patched, KeepTTL)
+		}
+		if err != nil {
+			return fmt.Errorf("redis Patch: %w", err)
+		}
+		if swapped {
+			return r.invalidate(ctx, InvalidationSet, key)
+		}
+	}
+	return kvs.ErrConditionFailed
+}
+
+// GetFields implements kvs.LowLevelClient. In StorageJSON mode, only the fields
+// of a document are read, with JSON.GET (see Client.GetRecordFields), unless
+// the item is held by the client-side cache. Otherwise the whole item is read,
+// through the client-side cache when enabled, and projected with
+// kvs.ProjectJSON. The returned item keeps its metadata.
+func (r *LowLevelClient) GetFields(ctx context.Context, key string, fields ...string) (*kvs.Item, error) {
+	if r.storage == StorageJSON && strings.TrimSpace(key) != "" && !r.cached(key) {
+		result, err := r.client.GetRecordFields(ctx, r.fullKey(key), fields)
+		if err != nil {
+			return nil, fmt.Errorf("redis GetFields: %w", err)
+		}
+		if result.Found {
+			return r.item(key, result.Record), nil
+		}
+	}
+
+	item, err := r.GetWithContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := item.Value.(string)
+	if !ok {
+		return nil, kvs.ErrConvert
+	}
+
+	projected, err := kvs.ProjectJSON(value, fields...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := *item
+	result.Value = projected
+	return &result, nil
+}
+
+// cached reports whether the client-side cache holds the item at key.
+func (r *LowLevelClient) cached(key string) bool {
+	if r.cache == nil {
+		return false
+	}
+	_, found := r.cache.get(r.fullKey(key))
+	return found
+}
+
+// fieldUpdates converts the updates of Patch to FieldUpdates, sorted by path.
+// Returns kvs.ErrFieldPath if a path is invalid or kvs.ErrMarshal if an update
+// cannot be marshalled.
+func fieldUpdates(updates map[string]any) ([]FieldUpdate, error) {
+	result := make([]FieldUpdate, 0, len(updates))
+	for path, update := range updates {
+		if _, err := kvs.SplitFieldPath(path); err != nil {
+			return nil, err
+		}
+		if update == nil {
+			result = append(result, FieldUpdate{Path: path, Delete: true})
+			continue
+		}
+		encoded, err := json.Marshal(update)
+		if err != nil {
+			return nil, kvs.ErrMarshal
+		}
+		result = append(result, FieldUpdate{Path: path, Value: string(encoded)})
+	}
+	slices.SortFunc(result, func(a, b FieldUpdate) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return result, nil
+}
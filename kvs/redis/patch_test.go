@@ -0,0 +1,154 @@
+package redis_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	"github.com/arielsrv/go-kvs-client/kvs/model"
+	kvsredis "github.com/arielsrv/go-kvs-client/kvs/redis"
+)
+
+func TestLowLevelClient_PatchAndGetFields(t *testing.T) {
+	for name, newStorageClient := range storageBackends(t) {
+		for mode, storage := range map[string]kvsredis.StorageMode{
+			"string": kvsredis.StorageString,
+			"hash":   kvsredis.StorageHash,
+		} {
+			t.Run(name+"/"+mode, func(t *testing.T) {
+				ctx := t.Context()
+				client := newStorageClient(kvsredis.WithStorageMode(storage))
+				key := "patch-" + mode
+
+				user := model.NewUserDTO("John", "Doe")
+				user.ID = 1
+				require.NoError(t, client.Save(key, kvs.NewItem(key, user)))
+
+				require.NoError(t, client.Patch(ctx, key, map[string]any{"last_name": "Smith", "full_name": nil}))
+
+				got, err := client.Get(key)
+				require.NoError(t, err)
+				var patched model.UserDTO
+				require.NoError(t, got.TryGetValueAsObjectType(&patched))
+				require.Equal(t, model.UserDTO{FirstName: "John", LastName: "Smith", ID: 1}, patched)
+
+				fields, err := client.GetFields(ctx, key, "last_name", "id")
+				require.NoError(t, err)
+				require.JSONEq(t, `{"last_name":"Smith","id":1}`, fields.Value.(string))
+				require.Equal(t, got.Version, fields.Version)
+			})
+		}
+	}
+}
+
+func TestLowLevelClient_Patch_KeepsTTL(t *testing.T) {
+	srv := miniredis.RunT(t)
+	client := kvsredis.NewBuilder(kvsredis.WithAddresses(srv.Addr())).Build()
+	t.Cleanup(func() { _ = client.Close() })
+
+	require.NoError(t, client.Save("1", kvs.NewItem("1", map[string]any{"a": 1}, time.Hour)))
+	require.NoError(t, client.Patch(t.Context(), "1", map[string]any{"b": 2}))
+	require.InDelta(t, time.Hour, srv.TTL("1"), float64(2*time.Second))
+
+	value, err := srv.Get("1")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"a":1,"b":2}`, value)
+}
+
+func TestLowLevelClient_Patch_Errors(t *testing.T) {
+	ctx := t.Context()
+	client := newClient(t)
+	require.NoError(t, client.Save("text", kvs.NewItem("text", "plain")))
+
+	require.ErrorIs(t, client.Patch(ctx, "", nil), kvs.ErrEmptyKey)
+	require.ErrorIs(t, client.Patch(ctx, "missing", map[string]any{"a": 1}), kvs.ErrKeyNotFound)
+	require.ErrorIs(t, client.Patch(ctx, "text", map[string]any{"a": 1}), kvs.ErrConvert)
+	_, err := client.GetFields(ctx, "missing", "a")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+	_, err = client.GetFields(ctx, "text", "a")
+	require.ErrorIs(t, err, kvs.ErrConvert)
+
+	cancelled, cancel := contextWithCancel(t)
+	cancel()
+	require.ErrorIs(t, client.Patch(cancelled, "text", map[string]any{"a": 1}), context.Canceled)
+}
+
+func TestLowLevelClient_Patch_ConcurrentUpdatesAreAllApplied(t *testing.T) {
+	ctx := t.Context()
+	client := newClient(t)
+	require.NoError(t, client.Save("1", kvs.NewItem("1", map[string]any{})))
+
+	var wg sync.WaitGroup
+	for _, field := range []string{"a", "b", "c", "d"} {
+		wg.Go(func() {
+			require.NoError(t, client.Patch(ctx, "1", map[string]any{field: true}))
+		})
+	}
+	wg.Wait()
+
+	got, err := client.Get("1")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"a":true,"b":true,"c":true,"d":true}`, got.Value.(string))
+}
+
+func TestLowLevelClient_PatchAndGetFields_Documents(t *testing.T) {
+	ctx := t.Context()
+	clock := kvs.NewFakeClock(time.Unix(1_700_000_000, 0))
+	client := kvsredis.NewBuilder(
+		kvsredis.WithStorageMode(kvsredis.StorageJSON),
+		kvsredis.WithClock(clock),
+	).FakeBuild()
+
+	value := map[string]any{"name": "John", "address": map[string]any{"city": "Paris", "zip": "75001"}}
+	require.NoError(t, client.Save("1", kvs.NewItem("1", value)))
+
+	require.NoError(t, client.Patch(ctx, "1", map[string]any{"address.city": "Lyon", "address.zip": nil, "age": 42}))
+
+	got, err := client.Get("1")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"John","age":42,"address":{"city":"Lyon"}}`, got.Value.(string))
+	require.Equal(t, int64(2), got.Version)
+	require.Equal(t, clock.Now().Unix(), got.CreatedAt)
+
+	fields, err := client.GetFields(ctx, "1", "address.city", "age", "missing")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"address":{"city":"Lyon"},"age":42}`, fields.Value.(string))
+	require.Equal(t, got.Version, fields.Version)
+	require.Equal(t, got.CreatedAt, fields.CreatedAt)
+
+	require.ErrorIs(t, client.Patch(ctx, "1", map[string]any{"phone.home": "1"}), kvs.ErrFieldPath)
+	require.ErrorIs(t, client.Patch(ctx, "1", map[string]any{"": "1"}), kvs.ErrFieldPath)
+	require.NoError(t, client.Save("text", kvs.NewItem("text", "plain")))
+	require.ErrorIs(t, client.Patch(ctx, "text", map[string]any{"a": 1}), kvs.ErrConvert)
+	_, err = client.GetFields(ctx, "text", "a")
+	require.ErrorIs(t, err, kvs.ErrConvert)
+	require.ErrorIs(t, client.Patch(ctx, "missing", map[string]any{"a": 1}), kvs.ErrKeyNotFound)
+	_, err = client.GetFields(ctx, "missing", "a")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+}
+
+func TestLowLevelClient_Patch_Documents_ConvertsLegacyStrings(t *testing.T) {
+	ctx := t.Context()
+	fake := kvsredis.NewFakeClient()
+	legacy := kvsredis.NewBuilder().BuildWithClient(fake)
+	require.NoError(t, legacy.Save("1", kvs.NewItem("1", map[string]any{"a": 1})))
+
+	client := kvsredis.NewBuilder(kvsredis.WithStorageMode(kvsredis.StorageJSON)).BuildWithClient(fake)
+	require.NoError(t, client.Patch(ctx, "1", map[string]any{"b": 2}))
+
+	records, err := fake.GetRecords(ctx, []string{"1"})
+	require.NoError(t, err)
+	require.True(t, records[0].Record.Document)
+	require.JSONEq(t, `{"a":1,"b":2}`, records[0].Record.Value)
+
+	require.NoError(t, client.Patch(ctx, "1", map[string]any{"a": nil}))
+	fields, err := client.GetFields(ctx, "1", "a", "b")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"b":2}`, fields.Value.(string))
+	require.Equal(t, int64(2), fields.Version)
+}
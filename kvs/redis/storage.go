@@ -0,0 +1,196 @@
+// Package redis provides a Redis implementation of the KVS client.
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// ErrHashStorageDisabled is returned by LowLevelClient.MigrateLegacy when the
+// client uses StorageString.
+const ErrHashStorageDisabled = kvs.KeyValueError("[kvs]: hash storage is not enabled")
+
+// StorageMode selects how LowLevelClient stores items in Redis.
+type StorageMode int
+
+// Storage modes.
+const (
+	// StorageString stores each item as a plain string holding its JSON value.
+	// Items read back carry no metadata.
+	StorageString StorageMode = iota
+	// StorageHash stores each item as a Redis hash (see Record) holding its JSON
+	// value with its version, codec, creation and expiration timestamps, which
+	// are returned in kvs.Item. Keys holding a plain string written by
+	// StorageString are read transparently, as items of version 0.
+	StorageHash
+	// StorageJSON stores each item as a RedisJSON document holding the members
+	// of a Record, so that LowLevelClient.Patch and LowLevelClient.GetFields
+	// update and read single fields with JSON.SET and JSON.GET paths. It needs
+	// the RedisJSON module (Redis Stack, or Redis 8 and later), which the
+	// MiniServer of Builder.MiniBuild lacks. Keys written by the other modes
+	// are read transparently and converted on their next write.
+	StorageJSON
+)
+
+// MigrateLegacy rewrites the plain strings written by StorageString under the
+// key prefix (restricted to opts.Prefix) as records, keeping their value and
+// expiration, and returns the number of keys migrated. Keys changed
+// concurrently are left to the writer. Counters written by Increment are
+// migrated too; Increment keeps working on them.
+func (r *LowLevelClient) MigrateLegacy(ctx context.Context, opts kvs.ScanOptions) (int, error) {
+	if !r.records() {
+		return 0, ErrHashStorageDisabled
+	}
+
+	pageSize := opts.PageSizeOrDefault()
+	page := make([]string, 0, pageSize)
+	migrated := 0
+	flush := func() error {
+		results, err := r.client.GetRecords(ctx, page)
+		if err != nil {
+			return err
+		}
+		count, err := r.migrate(ctx, results)
+		migrated += count
+		page = page[:0]
+		return err
+	}
+
+	for key, err := range r.client.Scan(ctx, r.scanPattern(opts.Prefix), int64(pageSize)) {
+		if err != nil {
+			return migrated, fmt.Errorf("redis MigrateLegacy: %w", err)
+		}
+		page = append(page, key)
+		if len(page) < pageSize {
+			continue
+		}
+		if err = flush(); err != nil {
+			return migrated, fmt.Errorf("redis MigrateLegacy: %w", err)
+		}
+	}
+
+	if len(page) > 0 {
+		if err := flush(); err != nil {
+			return migrated, fmt.Errorf("redis MigrateLegacy: %w", err)
+		}
+	}
+	return migrated, nil
+}
+
+// records reports whether the client stores items as records (StorageHash or
+// StorageJSON).
+func (r *LowLevelClient) records() bool {
+	return r.storage != StorageString
+}
+
+// fetch reads full keys in the storage mode of the client. In the record
+// modes, the legacy strings found are migrated when enabled.
+func (r *LowLevelClient) fetch(ctx context.Context, fullKeys []string) ([]RecordResult, error) {
+	if !r.records() {
+		values, err := r.client.MGet(ctx, fullKeys)
+		if err != nil {
+			return nil, err
+		}
+		results := make([]RecordResult, len(values))
+		for i, value := range values {
+			results[i] = RecordResult{Key: value.Key, Record: Record{Value: value.Value}, Found: value.Found}
+		}
+		return results, nil
+	}
+
+	results, err := r.client.GetRecords(ctx, fullKeys)
+	if err != nil {
+		return nil, err
+	}
+	if r.migrateOnRead {
+		// Best effort: the values have been read whether or not they are migrated.
+		_, _ = r.migrate(ctx, results)
+	}
+	for i := range results {
+		if results[i].Record.Legacy {
+			results[i].Record.Codec = kvs.CodecJSON
+		}
+	}
+	return results, nil
+}
+
+// fetchOne reads a single full key in the storage mode of the client.
+func (r *LowLevelClient) fetchOne(ctx context.Context, fullKey string) (Record, error) {
+	if !r.records() {
+		value, err := r.client.Get(ctx, fullKey)
+		return Record{Value: value}, err
+	}
+
+	results, err := r.fetch(ctx, []string{fullKey})
+	if err != nil {
+		return Record{}, err
+	}
+	if len(results) == 0 || !results[0].Found {
+		return Record{}, kvs.ErrKeyNotFound
+	}
+	return results[0].Record, nil
+}
+
+// migrate rewrites the legacy results as records unless they have changed
+// since they were read; migrated results are updated in place.
+func (r *LowLevelClient) migrate(ctx context.Context, results []RecordResult) (int, error) {
+	migrated := 0
+	var errs []error
+	for i, result := range results {
+		if !result.Found || !result.Record.Legacy {
+			continue
+		}
+
+		value := result.Record.Value
+		record := Record{
+			Value:    value,
+			Codec:    kvs.CodecJSON,
+			Created:  r.now().Unix(),
+			Document: r.storage == StorageJSON,
+		}
+		stored, err := r.client.SetRecord(ctx, result.Key, record, 0, RecordCondition{Expected: &value, KeepTTL: true})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if stored {
+			record.Version = 1
+			results[i].Record = record
+			migrated++
+		}
+	}
+	return migrated, errors.Join(errs...)
+}
+
+// newRecord builds the record of an encoded value. The expiration is the item
+// TTL (Unix timestamp) when set, or derived from ttl otherwise.
+func (r *LowLevelClient) newRecord(value string, itemTTL int64, ttl time.Duration) Record {
+	now := r.now()
+	record := Record{
+		Value:    value,
+		Codec:    kvs.CodecJSON,
+		Created:  now.Unix(),
+		Expires:  itemTTL,
+		Document: r.storage == StorageJSON,
+	}
+	if itemTTL <= 0 && ttl > 0 {
+		record.Expires = now.Add(ttl).Unix()
+	}
+	return record
+}
+
+// item converts a record read at key to a kvs.Item.
+func (r *LowLevelClient) item(key string, record Record) *kvs.Item {
+	return &kvs.Item{
+		Key:       key,
+		Value:     record.Value,
+		Codec:     record.Codec,
+		TTL:       record.Expires,
+		Version:   record.Version,
+		CreatedAt: record.Created,
+	}
+}
@@ -0,0 +1,270 @@
+package redis_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	kvsredis "github.com/arielsrv/go-kvs-client/kvs/redis"
+)
+
+// storageBackends returns, for the fake and miniredis, a constructor of clients
+// sharing the same data.
+func storageBackends(t *testing.T) map[string]func(opts ...kvsredis.BuilderOptions) *kvsredis.LowLevelClient {
+	t.Helper()
+	return map[string]func(opts ...kvsredis.BuilderOptions) *kvsredis.LowLevelClient{
+		"fake": func() func(opts ...kvsredis.BuilderOptions) *kvsredis.LowLevelClient {
+			fake := kvsredis.NewFakeClient()
+			return func(opts ...kvsredis.BuilderOptions) *kvsredis.LowLevelClient {
+				opts = append(opts, kvsredis.WithKeyPrefix("__kvs:users"))
+				return kvsredis.NewBuilder(opts...).BuildWithClient(fake)
+			}
+		}(),
+		"miniredis": func() func(opts ...kvsredis.BuilderOptions) *kvsredis.LowLevelClient {
+			srv := miniredis.RunT(t)
+			return func(opts ...kvsredis.BuilderOptions) *kvsredis.LowLevelClient {
+				opts = append(opts, kvsredis.WithKeyPrefix("__kvs:users"), kvsredis.WithAddresses(srv.Addr()))
+				client := kvsredis.NewBuilder(opts...).Build()
+				t.Cleanup(func() { _ = client.Close() })
+				return client
+			}
+		}(),
+	}
+}
+
+func TestStorageHash_SaveAndGet_ReturnsMetadata(t *testing.T) {
+	for name, newStorageClient := range storageBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			client := newStorageClient(kvsredis.WithStorageMode(kvsredis.StorageHash))
+			before := time.Now().Unix()
+
+			require.NoError(t, client.Save("1", kvs.NewItem("1", testUser{ID: 1}, time.Hour)))
+			got, err := client.Get("1")
+			require.NoError(t, err)
+			require.JSONEq(t, `{"ID":1,"Name":""}`, got.Value.(string))
+			require.Equal(t, kvs.CodecJSON, got.Codec)
+			require.Equal(t, int64(1), got.Version)
+			require.GreaterOrEqual(t, got.CreatedAt, before)
+			require.InDelta(t, time.Now().Add(time.Hour).Unix(), got.TTL, 2)
+
+			created := got.CreatedAt
+			require.NoError(t, client.Save("1", kvs.NewItem("1", testUser{ID: 2})))
+			got, err = client.Get("1")
+			require.NoError(t, err)
+			require.Equal(t, int64(2), got.Version)
+			require.Equal(t, created, got.CreatedAt)
+			require.Zero(t, got.TTL)
+		})
+	}
+}
+
+func TestStorageHash_DefaultTTL_SetsExpires(t *testing.T) {
+	client := newClient(t, kvsredis.WithStorageMode(kvsredis.StorageHash), kvsredis.WithTTL(time.Minute))
+
+	require.NoError(t, client.Save("1", kvs.NewItem("1", 1)))
+	got, err := client.Get("1")
+	require.NoError(t, err)
+	require.InDelta(t, time.Now().Add(time.Minute).Unix(), got.TTL, 2)
+}
+
+func TestStorageHash_BulkOperationsAndScan(t *testing.T) {
+	for name, newStorageClient := range storageBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			client := newStorageClient(kvsredis.WithStorageMode(kvsredis.StorageHash))
+
+			items := new(kvs.Items)
+			items.Add(kvs.NewItem("1", testUser{ID: 1}))
+			items.Add(kvs.NewItem("2", testUser{ID: 2}))
+			require.NoError(t, client.BulkSave(items))
+
+			got, err := client.BulkGet([]string{"2", "missing", "1"})
+			require.NoError(t, err)
+			require.Equal(t, 2, got.Len())
+			for item := range got.All() {
+				require.Equal(t, int64(1), item.Version)
+				require.Equal(t, kvs.CodecJSON, item.Codec)
+			}
+
+			count := 0
+			for item, scanErr := range client.Scan(t.Context(), kvs.ScanOptions{}) {
+				require.NoError(t, scanErr)
+				require.Equal(t, int64(1), item.Version)
+				count++
+			}
+			require.Equal(t, 2, count)
+		})
+	}
+}
+
+func TestStorageHash_ReadsLegacyStrings(t *testing.T) {
+	for name, newStorageClient := range storageBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			legacy := newStorageClient()
+			client := newStorageClient(kvsredis.WithStorageMode(kvsredis.StorageHash))
+			require.NoError(t, legacy.Save("1", kvs.NewItem("1", testUser{ID: 1})))
+
+			got, err := client.Get("1")
+			require.NoError(t, err)
+			require.JSONEq(t, `{"ID":1,"Name":""}`, got.Value.(string))
+			require.Equal(t, kvs.CodecJSON, got.Codec)
+			require.Zero(t, got.Version)
+
+			// Without migration the key is left untouched.
+			_, err = legacy.Get("1")
+			require.NoError(t, err)
+
+			// Writing it converts it; string clients no longer see it.
+			require.NoError(t, client.Save("1", kvs.NewItem("1", testUser{ID: 2})))
+			got, err = client.Get("1")
+			require.NoError(t, err)
+			require.Equal(t, int64(1), got.Version)
+
+			_, err = legacy.Get("1")
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestStorageHash_LegacyMigration(t *testing.T) {
+	for name, newStorageClient := range storageBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			legacy := newStorageClient()
+			client := newStorageClient(kvsredis.WithStorageMode(kvsredis.StorageHash), kvsredis.WithLegacyMigration())
+			require.NoError(t, legacy.Save("1", kvs.NewItem("1", testUser{ID: 1}, time.Hour)))
+
+			got, err := client.Get("1")
+			require.NoError(t, err)
+			require.Equal(t, int64(1), got.Version)
+			require.Equal(t, kvs.CodecJSON, got.Codec)
+
+			got, err = client.Get("1")
+			require.NoError(t, err)
+			require.Equal(t, int64(1), got.Version)
+			require.JSONEq(t, `{"ID":1,"Name":""}`, got.Value.(string))
+		})
+	}
+}
+
+func TestStorageHash_MigrateLegacy(t *testing.T) {
+	for name, newStorageClient := range storageBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			legacy := newStorageClient()
+			client := newStorageClient(kvsredis.WithStorageMode(kvsredis.StorageHash))
+
+			_, err := legacy.MigrateLegacy(ctx, kvs.ScanOptions{})
+			require.ErrorIs(t, err, kvsredis.ErrHashStorageDisabled)
+
+			require.NoError(t, legacy.Save("a/1", kvs.NewItem("a/1", 1)))
+			require.NoError(t, legacy.Save("a/2", kvs.NewItem("a/2", 2)))
+			require.NoError(t, legacy.Save("b/1", kvs.NewItem("b/1", 3)))
+			require.NoError(t, client.Save("a/3", kvs.NewItem("a/3", 4)))
+
+			migrated, err := client.MigrateLegacy(ctx, kvs.ScanOptions{Prefix: "a/", PageSize: 1})
+			require.NoError(t, err)
+			require.Equal(t, 2, migrated)
+
+			migrated, err = client.MigrateLegacy(ctx, kvs.ScanOptions{})
+			require.NoError(t, err)
+			require.Equal(t, 1, migrated)
+
+			got, err := client.Get("a/1")
+			require.NoError(t, err)
+			require.Equal(t, int64(1), got.Version)
+			require.Equal(t, "1", got.Value)
+		})
+	}
+}
+
+func TestStorageHash_ConditionalWritesAndCounters(t *testing.T) {
+	for name, newStorageClient := range storageBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			legacy := newStorageClient()
+			client := newStorageClient(kvsredis.WithStorageMode(kvsredis.StorageHash))
+
+			require.NoError(t, client.SaveIfAbsent(ctx, "1", kvs.NewItem("1", "a")))
+			require.ErrorIs(t, client.SaveIfAbsent(ctx, "1", kvs.NewItem("1", "b")), kvs.ErrConditionFailed)
+
+			current, err := client.Get("1")
+			require.NoError(t, err)
+			require.ErrorIs(t, client.CompareAndSwap(ctx, "1", kvs.NewItem("1", `"x"`), kvs.NewItem("1", "b")),
+				kvs.ErrConditionFailed)
+			require.NoError(t, client.CompareAndSwap(ctx, "1", current, kvs.NewItem("1", "b")))
+
+			current, err = client.Get("1")
+			require.NoError(t, err)
+			require.Equal(t, `"b"`, current.Value)
+			require.Equal(t, int64(2), current.Version)
+			require.NoError(t, client.CompareAndDelete(ctx, "1", current))
+			_, err = client.Get("1")
+			require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+
+			// Legacy values satisfy the conditions like records.
+			require.NoError(t, legacy.Save("2", kvs.NewItem("2", "a")))
+			require.ErrorIs(t, client.SaveIfAbsent(ctx, "2", kvs.NewItem("2", "b")), kvs.ErrConditionFailed)
+			current, err = client.Get("2")
+			require.NoError(t, err)
+			require.NoError(t, client.CompareAndSwap(ctx, "2", current, kvs.NewItem("2", "b")))
+
+			// Counters keep working once stored as records.
+			_, err = client.Increment(ctx, "hits", 2, 0)
+			require.NoError(t, err)
+			migrated, err := client.MigrateLegacy(ctx, kvs.ScanOptions{Prefix: "hits"})
+			require.NoError(t, err)
+			require.Equal(t, 1, migrated)
+			value, err := client.Increment(ctx, "hits", 3, 0)
+			require.NoError(t, err)
+			require.Equal(t, int64(5), value)
+
+			got, err := client.Get("hits")
+			require.NoError(t, err)
+			require.Equal(t, "5", got.Value)
+			require.Equal(t, int64(2), got.Version)
+		})
+	}
+}
+
+func TestStorageHash_ClientTracking_CachesMetadata(t *testing.T) {
+	fake := kvsredis.NewFakeClient()
+	client := kvsredis.NewBuilder(
+		kvsredis.WithStorageMode(kvsredis.StorageHash),
+		kvsredis.WithClientTracking(kvsredis.TrackingDefault, 0, 0),
+	).BuildWithClient(fake)
+
+	require.NoError(t, client.Save("1", kvs.NewItem("1", 1)))
+	_, err := client.Get("1")
+	require.NoError(t, err)
+	require.Equal(t, 1, client.CacheLen())
+
+	got, err := client.Get("1")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), got.Version)
+
+	require.NoError(t, client.Save("1", kvs.NewItem("1", 2)))
+	require.Equal(t, 0, client.CacheLen())
+}
+
+func TestFakeClient_Records_WrongType(t *testing.T) {
+	ctx := t.Context()
+	fake := kvsredis.NewFakeClient()
+
+	stored, err := fake.SetRecord(ctx, "k", kvsredis.Record{Value: "1"}, time.Minute, kvsredis.RecordCondition{})
+	require.NoError(t, err)
+	require.True(t, stored)
+
+	_, err = fake.Get(ctx, "k")
+	require.Error(t, err)
+	results, err := fake.MGet(ctx, []string{"k"})
+	require.NoError(t, err)
+	require.False(t, results[0].Found)
+
+	require.NoError(t, fake.Close())
+	_, err = fake.GetRecords(ctx, []string{"k"})
+	require.ErrorIs(t, err, kvs.ErrInternal)
+	_, err = fake.SetRecord(ctx, "k", kvsredis.Record{}, 0, kvsredis.RecordCondition{})
+	require.ErrorIs(t, err, kvs.ErrInternal)
+}
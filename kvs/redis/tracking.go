@@ -0,0 +1,298 @@
+// Package redis provides a Redis implementation of the KVS client.
+package redis
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/push"
+)
+
+// TrackingMode selects how Redis reports the keys to invalidate from the
+// client-side cache (see CLIENT TRACKING).
+type TrackingMode int
+
+// Client tracking modes.
+const (
+	// TrackingDefault makes the server remember the keys read by each connection
+	// and notify only those keys when they change.
+	TrackingDefault TrackingMode = iota
+	// TrackingBroadcast makes the server notify every change of a key under the
+	// configured key prefix, whether it has been read or not. It costs no server
+	// memory but sends more invalidations.
+	TrackingBroadcast
+)
+
+// Client-side cache defaults.
+const (
+	DefaultTrackingCacheSize = 10_000      // Maximum number of cached values
+	DefaultTrackingMaxAge    = time.Minute // Maximum age of a cached value
+)
+
+// Tracker is implemented by Clients that support server-assisted client-side
+// caching. Builder.BuildWithClient enables it when the client implements it.
+type Tracker interface {
+	// EnableTracking starts tracking the keys read through the client, or every
+	// key under one of prefixes in TrackingBroadcast mode, and calls onInvalidate
+	// with the full keys that changed. onInvalidate receives nil keys when every
+	// cached value must be dropped, e.g. after a reconnection.
+	EnableTracking(mode TrackingMode, prefixes []string, onInvalidate func(keys []string))
+}
+
+// ---------------------------------------------------------------------------
+// go-redis
+// ---------------------------------------------------------------------------
+
+// goRedisTracking enables CLIENT TRACKING on every connection opened by go-redis
+// (RESP3) and forwards "invalidate" push notifications.
+//
+// go-redis reads the pushes received by a pooled connection the next time the
+// connection is used, so a value can be served stale for a while after a change
+// when the pool is idle; the cache max age bounds that window.
+type goRedisTracking struct {
+	onInvalidate func(keys []string)
+	prefixes     []string
+	mode         TrackingMode
+	mu           sync.RWMutex
+}
+
+// onConnect is installed as the OnConnect hook of the go-redis options. A new
+// connection may replace a broken one whose tracked keys are no longer
+// reported, so the cache is flushed.
+func (r *goRedisTracking) onConnect(ctx context.Context, cn *goredis.Conn) error {
+	r.mu.RLock()
+	mode, prefixes := r.mode, r.prefixes
+	r.mu.RUnlock()
+
+	args := []any{"CLIENT", "TRACKING", "ON"}
+	if mode == TrackingBroadcast {
+		args = append(args, "BCAST")
+		for _, prefix := range prefixes {
+			args = append(args, "PREFIX", prefix)
+		}
+	}
+
+	err := cn.Do(ctx, args...).Err()
+	if err != nil {
+		return fmt.Errorf("redis CLIENT TRACKING: %w", err)
+	}
+
+	r.invalidate(nil)
+	return nil
+}
+
+// register installs the push handler on client, or on every node of a cluster.
+func (r *goRedisTracking) register(client goredis.UniversalClient) {
+	switch client := client.(type) {
+	case *goredis.Client:
+		_ = client.RegisterPushNotificationHandler("invalidate", r, true)
+	case *goredis.ClusterClient:
+		client.OnNewNode(func(node *goredis.Client) {
+			_ = node.RegisterPushNotificationHandler("invalidate", r, true)
+		})
+	}
+}
+
+// HandlePushNotification implements push.NotificationHandler for the
+// ["invalidate", keys] pushes; keys is null when the server flushed its data.
+func (r *goRedisTracking) HandlePushNotification(
+	_ context.Context,
+	_ push.NotificationHandlerContext,
+	notification []any,
+) error {
+	if len(notification) < 2 || notification[1] == nil {
+		r.invalidate(nil)
+		return nil
+	}
+
+	values, ok := notification[1].([]any)
+	if !ok {
+		r.invalidate(nil)
+		return nil
+	}
+
+	keys := make([]string, 0, len(values))
+	for _, value := range values {
+		if key, isString := value.(string); isString {
+			keys = append(keys, key)
+		}
+	}
+	r.invalidate(keys)
+	return nil
+}
+
+// EnableTracking implements Tracker. It must be called before the first
+// connection is opened, since the settings are applied on connect.
+func (r *goRedisTracking) EnableTracking(mode TrackingMode, prefixes []string, onInvalidate func(keys []string)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mode, r.prefixes, r.onInvalidate = mode, prefixes, onInvalidate
+}
+
+func (r *goRedisTracking) invalidate(keys []string) {
+	r.mu.RLock()
+	onInvalidate := r.onInvalidate
+	r.mu.RUnlock()
+
+	if onInvalidate != nil {
+		onInvalidate(keys)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Local cache
+// ---------------------------------------------------------------------------
+
+// localCache is the in-process LRU of the values read by a LowLevelClient,
+// keyed by full Redis key.
+//
+// A value read from Redis is only cached if no invalidation of its key (or
+// flush) has been received since the read started, so that a change racing
+// with the read cannot leave a stale value behind.
+type localCache struct {
+	now         func() time.Time
+	entries     map[string]*list.Element
+	order       *list.List // front is most recently used
+	inflight    map[string]int
+	invalidated map[string]uint64
+	maxAge      time.Duration
+	size        int
+	version     uint64
+	flushed     uint64
+	mu          sync.Mutex
+}
+
+type localCacheEntry struct {
+	expiresAt time.Time
+	value     Record
+	key       string
+}
+
+// localCacheTicket is returned by localCache.reserve and consumed by localCache.fill.
+type localCacheTicket struct {
+	version uint64
+}
+
+func newLocalCache(size int, maxAge time.Duration, now func() time.Time) *localCache {
+	if size <= 0 {
+		size = DefaultTrackingCacheSize
+	}
+	if maxAge <= 0 {
+		maxAge = DefaultTrackingMaxAge
+	}
+	return &localCache{
+		now:         now,
+		entries:     make(map[string]*list.Element),
+		order:       list.New(),
+		inflight:    make(map[string]int),
+		invalidated: make(map[string]uint64),
+		maxAge:      maxAge,
+		size:        size,
+	}
+}
+
+// get returns the cached value of key, if present and not older than maxAge.
+func (r *localCache) get(key string) (Record, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	element, found := r.entries[key]
+	if !found {
+		return Record{}, false
+	}
+
+	entry := element.Value.(*localCacheEntry)
+	if !r.now().Before(entry.expiresAt) {
+		r.remove(element)
+		return Record{}, false
+	}
+
+	r.order.MoveToFront(element)
+	return entry.value, true
+}
+
+// reserve must be called before reading keys from Redis; the returned ticket
+// is passed to fill once the values have been read.
+func (r *localCache) reserve(keys ...string) localCacheTicket {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, key := range keys {
+		r.inflight[key]++
+	}
+	return localCacheTicket{version: r.version}
+}
+
+// fill caches the values read for the reserved keys, except those invalidated
+// since the reservation. Keys missing from values are only released.
+func (r *localCache) fill(ticket localCacheTicket, keys []string, values map[string]Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, key := range keys {
+		value, found := values[key]
+		if found && r.flushed <= ticket.version && r.invalidated[key] <= ticket.version {
+			r.put(key, value)
+		}
+
+		r.inflight[key]--
+		if r.inflight[key] <= 0 {
+			delete(r.inflight, key)
+			delete(r.invalidated, key)
+		}
+	}
+}
+
+// invalidate drops the cached values of keys; nil keys drop everything.
+func (r *localCache) invalidate(keys []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.version++
+	if keys == nil {
+		r.flushed = r.version
+		clear(r.entries)
+		r.order.Init()
+		return
+	}
+
+	for _, key := range keys {
+		if element, found := r.entries[key]; found {
+			r.remove(element)
+		}
+		if r.inflight[key] > 0 {
+			r.invalidated[key] = r.version
+		}
+	}
+}
+
+// len returns the number of cached values.
+func (r *localCache) len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.order.Len()
+}
+
+func (r *localCache) put(key string, value Record) {
+	expiresAt := r.now().Add(r.maxAge)
+	if element, found := r.entries[key]; found {
+		entry := element.Value.(*localCacheEntry)
+		entry.value, entry.expiresAt = value, expiresAt
+		r.order.MoveToFront(element)
+		return
+	}
+
+	r.entries[key] = r.order.PushFront(&localCacheEntry{key: key, value: value, expiresAt: expiresAt})
+	for r.order.Len() > r.size {
+		r.remove(r.order.Back())
+	}
+}
+
+func (r *localCache) remove(element *list.Element) {
+	r.order.Remove(element)
+	delete(r.entries, element.Value.(*localCacheEntry).key)
+}
@@ -0,0 +1,239 @@
+package redis_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	kvsredis "github.com/arielsrv/go-kvs-client/kvs/redis"
+)
+
+func newTrackingClient(
+	t *testing.T,
+	fake *kvsredis.FakeClient,
+	mode kvsredis.TrackingMode,
+	size int,
+) *kvsredis.LowLevelClient {
+	t.Helper()
+	return kvsredis.NewBuilder(
+		kvsredis.WithKeyPrefix("__kvs:users"),
+		kvsredis.WithClientTracking(mode, size, 0),
+	).BuildWithClient(fake)
+}
+
+func TestClientTracking_ServesCachedValuesUntilInvalidated(t *testing.T) {
+	for name, mode := range map[string]kvsredis.TrackingMode{
+		"default":   kvsredis.TrackingDefault,
+		"broadcast": kvsredis.TrackingBroadcast,
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			fake := kvsredis.NewFakeClient()
+			client := newTrackingClient(t, fake, mode, 0)
+
+			require.NoError(t, client.Save("1", kvs.NewItem("1", "a")))
+			require.Equal(t, 0, client.CacheLen())
+
+			got, err := client.Get("1")
+			require.NoError(t, err)
+			require.Equal(t, `"a"`, got.Value)
+			require.Equal(t, 1, client.CacheLen())
+
+			// Another process writes the key: the server invalidates it.
+			require.NoError(t, fake.Set(ctx, "__kvs:users:1", `"b"`, 0))
+			require.Equal(t, 0, client.CacheLen())
+
+			got, err = client.Get("1")
+			require.NoError(t, err)
+			require.Equal(t, `"b"`, got.Value)
+
+			require.NoError(t, fake.Del(ctx, "__kvs:users:1"))
+			_, err = client.Get("1")
+			require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+			require.Equal(t, 0, client.CacheLen())
+		})
+	}
+}
+
+func TestClientTracking_BroadcastIsScopedToKeyPrefix(t *testing.T) {
+	ctx := context.Background()
+	fake := kvsredis.NewFakeClient()
+	client := newTrackingClient(t, fake, kvsredis.TrackingBroadcast, 0)
+
+	require.NoError(t, client.Save("1", kvs.NewItem("1", "a")))
+	_, err := client.Get("1")
+	require.NoError(t, err)
+
+	require.NoError(t, fake.Set(ctx, "__kvs:orders:1", `"x"`, 0))
+	require.Equal(t, 1, client.CacheLen())
+
+	require.NoError(t, fake.Set(ctx, "__kvs:users:2", `"x"`, 0))
+	require.Equal(t, 1, client.CacheLen())
+	require.NoError(t, fake.Set(ctx, "__kvs:users:1", `"x"`, 0))
+	require.Equal(t, 0, client.CacheLen())
+}
+
+func TestClientTracking_OwnWritesEvictLocally(t *testing.T) {
+	client := newTrackingClient(t, kvsredis.NewFakeClient(), kvsredis.TrackingDefault, 0)
+	ctx := context.Background()
+
+	require.NoError(t, client.Save("1", kvs.NewItem("1", "a")))
+	_, err := client.Get("1")
+	require.NoError(t, err)
+
+	require.NoError(t, client.Save("1", kvs.NewItem("1", "b")))
+	got, err := client.Get("1")
+	require.NoError(t, err)
+	require.Equal(t, `"b"`, got.Value)
+
+	require.NoError(t, client.CompareAndSwap(ctx, "1", got, kvs.NewItem("1", "c")))
+	got, err = client.Get("1")
+	require.NoError(t, err)
+	require.Equal(t, `"c"`, got.Value)
+
+	require.NoError(t, client.Delete("1"))
+	_, err = client.Get("1")
+	require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+}
+
+func TestClientTracking_ReconnectFlushesCache(t *testing.T) {
+	fake := kvsredis.NewFakeClient()
+	client := newTrackingClient(t, fake, kvsredis.TrackingDefault, 0)
+
+	items := new(kvs.Items)
+	for i := range 3 {
+		items.Add(kvs.NewItem(strconv.Itoa(i), i))
+	}
+	require.NoError(t, client.BulkSave(items))
+
+	got, err := client.BulkGet([]string{"0", "1", "2", "missing"})
+	require.NoError(t, err)
+	require.Equal(t, 3, got.Len())
+	require.Equal(t, 3, client.CacheLen())
+
+	fake.Reconnect()
+	require.Equal(t, 0, client.CacheLen())
+}
+
+func TestClientTracking_BulkGet_MixesHitsAndMissesInKeyOrder(t *testing.T) {
+	ctx := context.Background()
+	fake := kvsredis.NewFakeClient()
+	client := newTrackingClient(t, fake, kvsredis.TrackingDefault, 0)
+
+	for i := range 4 {
+		require.NoError(t, client.Save(strconv.Itoa(i), kvs.NewItem(strconv.Itoa(i), i)))
+	}
+	_, err := client.Get("2")
+	require.NoError(t, err)
+	require.NoError(t, fake.Set(ctx, "__kvs:users:0", "10", 0))
+
+	got, err := client.BulkGet([]string{"3", "2", "1", "0"})
+	require.NoError(t, err)
+
+	var keys, values []string
+	for item := range got.All() {
+		keys = append(keys, item.Key)
+		values = append(values, item.Value.(string))
+	}
+	require.Equal(t, []string{"3", "2", "1", "0"}, keys)
+	require.Equal(t, []string{"3", "2", "1", "10"}, values)
+	require.Equal(t, 4, client.CacheLen())
+}
+
+func TestClientTracking_EvictsLeastRecentlyUsed(t *testing.T) {
+	client := newTrackingClient(t, kvsredis.NewFakeClient(), kvsredis.TrackingDefault, 2)
+
+	for _, key := range []string{"1", "2", "3"} {
+		require.NoError(t, client.Save(key, kvs.NewItem(key, key)))
+	}
+	for _, key := range []string{"1", "2", "1", "3"} {
+		_, err := client.Get(key)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, client.CacheLen())
+}
+
+// silentTracker drops every invalidation, leaving the max age as the only eviction.
+type silentTracker struct {
+	*kvsredis.FakeClient
+}
+
+func (silentTracker) EnableTracking(kvsredis.TrackingMode, []string, func([]string)) {}
+
+func TestClientTracking_MaxAge(t *testing.T) {
+	clock := kvs.NewFakeClock(time.Unix(1_700_000_000, 0))
+	fake := kvsredis.NewFakeClient(kvsredis.WithFakeClock(clock))
+	client := kvsredis.NewBuilder(
+		kvsredis.WithClientTracking(kvsredis.TrackingDefault, 0, time.Minute),
+		kvsredis.WithClock(clock),
+	).BuildWithClient(silentTracker{fake})
+
+	require.NoError(t, fake.Set(context.Background(), "1", "a", 0))
+	_, err := client.Get("1")
+	require.NoError(t, err)
+
+	require.NoError(t, fake.Set(context.Background(), "1", "b", 0))
+	got, err := client.Get("1")
+	require.NoError(t, err)
+	require.Equal(t, "a", got.Value)
+
+	clock.Advance(time.Minute - time.Nanosecond)
+	got, err = client.Get("1")
+	require.NoError(t, err)
+	require.Equal(t, "a", got.Value)
+
+	clock.Advance(time.Nanosecond)
+	got, err = client.Get("1")
+	require.NoError(t, err)
+	require.Equal(t, "b", got.Value)
+}
+
+func TestClientTracking_ConcurrentWritesNeverLeaveStaleValues(t *testing.T) {
+	ctx := context.Background()
+	fake := kvsredis.NewFakeClient()
+	client := newTrackingClient(t, fake, kvsredis.TrackingDefault, 0)
+
+	var wg sync.WaitGroup
+	for i := range 200 {
+		wg.Go(func() {
+			if i%2 == 0 {
+				_ = fake.Set(ctx, "__kvs:users:1", strconv.Itoa(i), 0)
+				return
+			}
+			_, _ = client.Get("1")
+		})
+	}
+	wg.Wait()
+
+	require.NoError(t, fake.Set(ctx, "__kvs:users:1", "final", 0))
+	got, err := client.Get("1")
+	require.NoError(t, err)
+	require.Equal(t, "final", got.Value)
+}
+
+func TestClientTracking_Disabled(t *testing.T) {
+	client := newClient(t)
+
+	require.NoError(t, client.Save("1", kvs.NewItem("1", "a")))
+	_, err := client.Get("1")
+	require.NoError(t, err)
+	require.Equal(t, 0, client.CacheLen())
+}
+
+func TestClientTracking_Build_ServerWithoutTracking_ReturnsError(t *testing.T) {
+	srv := miniredis.RunT(t)
+	client := kvsredis.NewBuilder(
+		kvsredis.WithAddresses(srv.Addr()),
+		kvsredis.WithClientTracking(kvsredis.TrackingBroadcast, 0, 0),
+	).Build()
+	t.Cleanup(func() { _ = client.Close() })
+
+	_, err := client.Get("1")
+	require.ErrorContains(t, err, "TRACKING")
+}
@@ -0,0 +1,95 @@
+// Package redis provides a Redis implementation of the KVS client.
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+// NewTx starts a transaction committed through Client.Transact, with WATCH and
+// MULTI/EXEC on Redis. TTL semantics of Put are those of CompareAndSwap: an item
+// whose TTL has already elapsed deletes the stored one. Under Redis Cluster every
+// key of the transaction must hash to the same slot (see KeyLayout).
+func (r *LowLevelClient) NewTx() *kvs.Tx {
+	return kvs.NewTx(r.commitTx)
+}
+
+// commitTx is the kvs.TxCommitFunc of the transactions of the client.
+func (r *LowLevelClient) commitTx(ctx context.Context, operations []kvs.TxOperation) error {
+	var (
+		checks         []TxCheck
+		writes         []TxWrite
+		checked        []int // index of the operation of each check
+		saved, deleted []string
+	)
+	for i, operation := range operations {
+		fullKey := r.fullKey(operation.Key)
+		switch operation.Type {
+		case kvs.TxConditionCheck:
+			check := TxCheck{Key: fullKey}
+			if operation.Expected != nil {
+				expected, err := storedValue(operation.Expected)
+				if err != nil {
+					return err
+				}
+				check.Expected = &expected
+			}
+			checks = append(checks, check)
+			checked = append(checked, i)
+		case kvs.TxDelete:
+			writes = append(writes, TxWrite{Key: fullKey, Delete: true})
+			deleted = append(deleted, operation.Key)
+		default:
+			write, err := r.txWrite(fullKey, operation.Item)
+			if err != nil {
+				return err
+			}
+			writes = append(writes, write)
+			if write.Delete {
+				deleted = append(deleted, operation.Key)
+			} else {
+				saved = append(saved, operation.Key)
+			}
+		}
+	}
+
+	failed, err := r.client.Transact(ctx, checks, writes)
+	if err != nil {
+		return fmt.Errorf("redis Commit: %w", err)
+	}
+	if failed >= 0 {
+		return &kvs.TxCanceledError{Key: operations[checked[failed]].Key, Index: checked[failed]}
+	}
+
+	var errs []error
+	if len(saved) > 0 {
+		errs = append(errs, r.invalidate(ctx, InvalidationSet, saved...))
+	}
+	if len(deleted) > 0 {
+		errs = append(errs, r.invalidate(ctx, InvalidationDelete, deleted...))
+	}
+	return errors.Join(errs...)
+}
+
+// txWrite returns the TxWrite storing item at fullKey in the storage mode of the client.
+func (r *LowLevelClient) txWrite(fullKey string, item *kvs.Item) (TxWrite, error) {
+	bytes, err := json.Marshal(item.Value)
+	if err != nil {
+		return TxWrite{}, fmt.Errorf("redis Commit: marshal: %w", err)
+	}
+
+	ttl, skip := r.resolveTTL(item.TTL)
+	switch {
+	case skip:
+		return TxWrite{Key: fullKey, Delete: true}, nil
+	case r.records():
+		record := r.newRecord(string(bytes), item.TTL, ttl)
+		return TxWrite{Key: fullKey, Record: &record, TTL: ttl}, nil
+	default:
+		return TxWrite{Key: fullKey, Value: string(bytes), TTL: ttl}, nil
+	}
+}
@@ -0,0 +1,70 @@
+package redis_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	kvsredis "github.com/arielsrv/go-kvs-client/kvs/redis"
+)
+
+func TestLowLevelClient_Tx(t *testing.T) {
+	for name, newStorageClient := range storageBackends(t) {
+		for mode, storage := range map[string]kvsredis.StorageMode{
+			"string": kvsredis.StorageString,
+			"hash":   kvsredis.StorageHash,
+		} {
+			t.Run(name+"/"+mode, func(t *testing.T) {
+				ctx := t.Context()
+				client := newStorageClient(kvsredis.WithStorageMode(storage))
+				inventory, order := "inventory-"+mode, "order-"+mode
+
+				require.NoError(t, client.Save(inventory, kvs.NewItem(inventory, 10)))
+				stock, err := client.Get(inventory)
+				require.NoError(t, err)
+
+				place := func(stock *kvs.Item) *kvs.Tx {
+					return client.NewTx().
+						ConditionCheck(inventory, stock).
+						ConditionCheck(order, nil).
+						Put(order, kvs.NewItem(order, "placed")).
+						Put(inventory, kvs.NewItem(inventory, 9))
+				}
+				require.NoError(t, place(stock).Commit(ctx))
+
+				got, err := client.Get(order)
+				require.NoError(t, err)
+				require.Equal(t, `"placed"`, got.Value)
+				got, err = client.Get(inventory)
+				require.NoError(t, err)
+				require.Equal(t, "9", got.Value)
+				if storage == kvsredis.StorageHash {
+					require.Equal(t, int64(2), got.Version)
+				}
+
+				// The first failed check is reported and nothing is written.
+				require.NoError(t, client.Delete(order))
+				err = place(stock).Commit(ctx)
+				require.ErrorIs(t, err, kvs.ErrConditionFailed)
+				var canceled *kvs.TxCanceledError
+				require.True(t, errors.As(err, &canceled))
+				require.Equal(t, &kvs.TxCanceledError{Key: inventory, Index: 0}, canceled)
+				_, err = client.Get(order)
+				require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+
+				require.NoError(t, client.NewTx().ConditionCheck(inventory, got).Delete(inventory).Commit(ctx))
+				_, err = client.Get(inventory)
+				require.ErrorIs(t, err, kvs.ErrKeyNotFound)
+			})
+		}
+	}
+}
+
+func TestLowLevelClient_Tx_Error(t *testing.T) {
+	client := kvsredis.NewLowLevelClient(&erroringClient{err: kvs.ErrInternal}, "")
+
+	err := client.NewTx().Delete("1").Commit(t.Context())
+	require.ErrorIs(t, err, kvs.ErrInternal)
+}
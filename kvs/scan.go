@@ -0,0 +1,33 @@
+// Package kvs provides a generic key-value store client interface and implementation.
+package kvs
+
+// DefaultScanPageSize is the number of keys fetched per round-trip when
+// ScanOptions.PageSize is not set.
+const DefaultScanPageSize = 100
+
+// ScanOptions configures a LowLevelClient.Scan call.
+// The zero value scans the whole container, fetching keys and values in pages
+// of DefaultScanPageSize.
+type ScanOptions struct {
+	// Prefix restricts the scan to keys starting with the given prefix.
+	// An empty prefix matches every key in the container.
+	Prefix string
+	// PageSize is a hint for the number of keys fetched per round-trip.
+	// Backends may return fewer (or, for Redis SCAN, slightly more) entries per page.
+	PageSize int
+	// Segments splits the scan into parallel segments when the backend supports it
+	// (DynamoDB parallel scan). Values lower than 2 mean a sequential scan.
+	Segments int
+	// KeysOnly skips value retrieval: yielded items carry the Key (and the TTL when
+	// the backend stores it alongside the key) but a nil Value.
+	KeysOnly bool
+}
+
+// PageSizeOrDefault returns the configured page size, falling back to
+// DefaultScanPageSize when it is not positive.
+func (r ScanOptions) PageSizeOrDefault() int {
+	if r.PageSize <= 0 {
+		return DefaultScanPageSize
+	}
+	return r.PageSize
+}
@@ -0,0 +1,40 @@
+package kvs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+func TestScanOptions_PageSizeOrDefault(t *testing.T) {
+	tests := []struct {
+		name     string
+		pageSize int
+		expected int
+	}{
+		{
+			name:     "unset page size",
+			pageSize: 0,
+			expected: kvs.DefaultScanPageSize,
+		},
+		{
+			name:     "negative page size",
+			pageSize: -1,
+			expected: kvs.DefaultScanPageSize,
+		},
+		{
+			name:     "explicit page size",
+			pageSize: 25,
+			expected: 25,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := kvs.ScanOptions{PageSize: tt.pageSize}
+			assert.Equal(t, tt.expected, opts.PageSizeOrDefault())
+		})
+	}
+}
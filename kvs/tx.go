@@ -0,0 +1,176 @@
+// Package kvs provides a generic key-value store client interface and implementation.
+package kvs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// MaxTxOperations is the maximum number of operations of a transaction, the
+// TransactWriteItems limit of DynamoDB.
+const MaxTxOperations = 100
+
+// TxOperationType is the type of a TxOperation.
+type TxOperationType int
+
+// Transaction operation types.
+const (
+	// TxPut stores an item.
+	TxPut TxOperationType = iota
+	// TxDelete removes an item.
+	TxDelete
+	// TxConditionCheck requires the stored item to be in the expected state.
+	TxConditionCheck
+)
+
+// String returns the name of the operation type.
+func (r TxOperationType) String() string {
+	switch r {
+	case TxPut:
+		return "Put"
+	case TxDelete:
+		return "Delete"
+	case TxConditionCheck:
+		return "ConditionCheck"
+	default:
+		return "Unknown"
+	}
+}
+
+// TxOperation is an operation of a transaction.
+type TxOperation struct {
+	Key      string
+	Item     *Item // Item stored by TxPut
+	Expected *Item // Expected item of TxConditionCheck, nil when the key must be absent
+	Type     TxOperationType
+}
+
+// TxCommitFunc applies the operations of a transaction atomically. It is provided by
+// the backends implementing Transactor.
+type TxCommitFunc func(ctx context.Context, operations []TxOperation) error
+
+// Tx is a set of operations on several keys that succeed or fail together.
+// Operations are recorded by Put, Delete and ConditionCheck and applied by Commit.
+// A key can be written at most once, and checked at most once, per transaction.
+// A Tx is not safe for concurrent use.
+type Tx struct {
+	commit     TxCommitFunc
+	operations []TxOperation
+}
+
+// NewTx returns an empty transaction committed by commit.
+func NewTx(commit TxCommitFunc) *Tx {
+	return &Tx{commit: commit}
+}
+
+// Put stores item under key when the transaction commits.
+func (r *Tx) Put(key string, item *Item) *Tx {
+	r.operations = append(r.operations, TxOperation{Type: TxPut, Key: key, Item: item})
+	return r
+}
+
+// Delete removes the item stored under key when the transaction commits.
+func (r *Tx) Delete(key string) *Tx {
+	r.operations = append(r.operations, TxOperation{Type: TxDelete, Key: key})
+	return r
+}
+
+// ConditionCheck makes the transaction conditional on the item stored under key:
+// its value must still be the value of expected, as returned by Get, or no live
+// item must be stored under key when expected is nil.
+func (r *Tx) ConditionCheck(key string, expected *Item) *Tx {
+	r.operations = append(r.operations, TxOperation{Type: TxConditionCheck, Key: key, Expected: expected})
+	return r
+}
+
+// Operations returns the operations recorded so far, in call order.
+func (r *Tx) Operations() []TxOperation {
+	return r.operations
+}
+
+// Commit applies the operations atomically: either every write is applied, or none is.
+// Returns a *TxCanceledError, which matches ErrConditionFailed, if a condition does not
+// hold; ErrInvalidTx if the transaction writes or checks a key twice, or has more than
+// MaxTxOperations operations. Committing an empty transaction is a no-op.
+func (r *Tx) Commit(ctx context.Context) error {
+	if len(r.operations) == 0 {
+		return nil
+	}
+	if err := r.validate(); err != nil {
+		return err
+	}
+	return r.commit(ctx, r.operations)
+}
+
+// validate checks the operations before they are committed.
+func (r *Tx) validate() error {
+	if len(r.operations) > MaxTxOperations {
+		return ErrInvalidTx
+	}
+
+	written, checked := map[string]bool{}, map[string]bool{}
+	for _, operation := range r.operations {
+		if strings.TrimSpace(operation.Key) == "" {
+			return ErrEmptyKey
+		}
+
+		seen := written
+		switch operation.Type {
+		case TxPut:
+			if operation.Item == nil {
+				return ErrNilItem
+			}
+		case TxConditionCheck:
+			seen = checked
+		case TxDelete:
+		default:
+			return ErrInvalidTx
+		}
+		if seen[operation.Key] {
+			return ErrInvalidTx
+		}
+		seen[operation.Key] = true
+	}
+
+	return nil
+}
+
+// TxCanceledError is returned by Tx.Commit when a condition of the transaction does
+// not hold; none of its writes has been applied. It matches ErrConditionFailed.
+type TxCanceledError struct {
+	// Key is the key whose condition failed, empty if the backend did not report it.
+	Key string
+	// Index is the index, in call order, of the ConditionCheck that failed, or -1.
+	Index int
+}
+
+// Error implements the error interface for TxCanceledError.
+func (r *TxCanceledError) Error() string {
+	if r.Index < 0 {
+		return "[kvs]: transaction canceled"
+	}
+	return fmt.Sprintf("[kvs]: transaction canceled: condition of operation %d (key %q) failed", r.Index, r.Key)
+}
+
+// Unwrap returns ErrConditionFailed, so that errors.Is(err, ErrConditionFailed) holds.
+func (r *TxCanceledError) Unwrap() error {
+	return ErrConditionFailed
+}
+
+// NewTxCanceledError returns the TxCanceledError of the ConditionCheck of key among
+// operations, or of an unknown operation when key is not checked.
+func NewTxCanceledError(operations []TxOperation, key string) *TxCanceledError {
+	for i, operation := range operations {
+		if operation.Type == TxConditionCheck && operation.Key == key {
+			return &TxCanceledError{Key: key, Index: i}
+		}
+	}
+	return &TxCanceledError{Key: key, Index: -1}
+}
+
+// Transactor is implemented by the LowLevelClients that support multi-key transactions.
+type Transactor interface {
+	// NewTx starts a transaction on the container.
+	NewTx() *Tx
+}
@@ -0,0 +1,68 @@
+package kvs_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+)
+
+func TestTx_Commit(t *testing.T) {
+	var committed []kvs.TxOperation
+	tx := kvs.NewTx(func(_ context.Context, operations []kvs.TxOperation) error {
+		committed = operations
+		return nil
+	})
+
+	require.NoError(t, tx.Commit(t.Context()))
+	require.Nil(t, committed, "an empty transaction is not committed")
+
+	order := kvs.NewItem("order:1", "{}")
+	tx.ConditionCheck("order:1", nil).Put("order:1", order).Delete("cart:1")
+	require.NoError(t, tx.Commit(t.Context()))
+	require.Equal(t, []kvs.TxOperation{
+		{Type: kvs.TxConditionCheck, Key: "order:1"},
+		{Type: kvs.TxPut, Key: "order:1", Item: order},
+		{Type: kvs.TxDelete, Key: "cart:1"},
+	}, committed)
+	require.Equal(t, "ConditionCheck", committed[0].Type.String())
+}
+
+func TestTx_Commit_Invalid(t *testing.T) {
+	newTx := func() *kvs.Tx {
+		return kvs.NewTx(func(context.Context, []kvs.TxOperation) error {
+			t.Fatal("an invalid transaction must not be committed")
+			return nil
+		})
+	}
+
+	require.ErrorIs(t, newTx().Delete(" ").Commit(t.Context()), kvs.ErrEmptyKey)
+	require.ErrorIs(t, newTx().Put("1", nil).Commit(t.Context()), kvs.ErrNilItem)
+	require.ErrorIs(t, newTx().Delete("1").Put("1", kvs.NewItem("1", 1)).Commit(t.Context()), kvs.ErrInvalidTx)
+	require.ErrorIs(t, newTx().ConditionCheck("1", nil).ConditionCheck("1", nil).Commit(t.Context()), kvs.ErrInvalidTx)
+
+	tx := newTx()
+	for i := range kvs.MaxTxOperations + 1 {
+		tx.ConditionCheck(strconv.Itoa(i), nil)
+	}
+	require.ErrorIs(t, tx.Commit(t.Context()), kvs.ErrInvalidTx)
+}
+
+func TestTxCanceledError(t *testing.T) {
+	operations := kvs.NewTx(nil).Put("1", kvs.NewItem("1", 1)).ConditionCheck("2", nil).Operations()
+
+	var err error = kvs.NewTxCanceledError(operations, "2")
+	require.ErrorIs(t, err, kvs.ErrConditionFailed)
+	require.EqualError(t, err, `[kvs]: transaction canceled: condition of operation 1 (key "2") failed`)
+
+	var canceled *kvs.TxCanceledError
+	require.True(t, errors.As(err, &canceled))
+	require.Equal(t, 1, canceled.Index)
+
+	require.Equal(t, -1, kvs.NewTxCanceledError(operations, "1").Index)
+	require.EqualError(t, kvs.NewTxCanceledError(operations, "1"), "[kvs]: transaction canceled")
+}
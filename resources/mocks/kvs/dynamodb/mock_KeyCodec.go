@@ -0,0 +1,159 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package dynamodb
+
+import (
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockKeyCodec creates a new instance of MockKeyCodec. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockKeyCodec(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockKeyCodec {
+	mock := &MockKeyCodec{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockKeyCodec is an autogenerated mock type for the KeyCodec type
+type MockKeyCodec struct {
+	mock.Mock
+}
+
+type MockKeyCodec_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockKeyCodec) EXPECT() *MockKeyCodec_Expecter {
+	return &MockKeyCodec_Expecter{mock: &_m.Mock}
+}
+
+// Join provides a mock function for the type MockKeyCodec
+func (_mock *MockKeyCodec) Join(partition string, sort string) string {
+	ret := _mock.Called(partition, sort)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Join")
+	}
+
+	var r0 string
+	if returnFunc, ok := ret.Get(0).(func(string, string) string); ok {
+		r0 = returnFunc(partition, sort)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	return r0
+}
+
+// MockKeyCodec_Join_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Join'
+type MockKeyCodec_Join_Call struct {
+	*mock.Call
+}
+
+// Join is a helper method to define mock.On call
+//   - partition string
+//   - sort string
+func (_e *MockKeyCodec_Expecter) Join(partition any, sort any) *MockKeyCodec_Join_Call {
+	return &MockKeyCodec_Join_Call{Call: _e.mock.On("Join", partition, sort)}
+}
+
+func (_c *MockKeyCodec_Join_Call) Run(run func(partition string, sort string)) *MockKeyCodec_Join_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockKeyCodec_Join_Call) Return(s string) *MockKeyCodec_Join_Call {
+	_c.Call.Return(s)
+	return _c
+}
+
+func (_c *MockKeyCodec_Join_Call) RunAndReturn(run func(partition string, sort string) string) *MockKeyCodec_Join_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Split provides a mock function for the type MockKeyCodec
+func (_mock *MockKeyCodec) Split(key string) (string, string, error) {
+	ret := _mock.Called(key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Split")
+	}
+
+	var r0 string
+	var r1 string
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(string) (string, string, error)); ok {
+		return returnFunc(key)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) string); ok {
+		r0 = returnFunc(key)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) string); ok {
+		r1 = returnFunc(key)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+	if returnFunc, ok := ret.Get(2).(func(string) error); ok {
+		r2 = returnFunc(key)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// MockKeyCodec_Split_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Split'
+type MockKeyCodec_Split_Call struct {
+	*mock.Call
+}
+
+// Split is a helper method to define mock.On call
+//   - key string
+func (_e *MockKeyCodec_Expecter) Split(key any) *MockKeyCodec_Split_Call {
+	return &MockKeyCodec_Split_Call{Call: _e.mock.On("Split", key)}
+}
+
+func (_c *MockKeyCodec_Split_Call) Run(run func(key string)) *MockKeyCodec_Split_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockKeyCodec_Split_Call) Return(partition string, sort string, err error) *MockKeyCodec_Split_Call {
+	_c.Call.Return(partition, sort, err)
+	return _c
+}
+
+func (_c *MockKeyCodec_Split_Call) RunAndReturn(run func(key string) (string, string, error)) *MockKeyCodec_Split_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,387 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package dynamodb
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockTableClient creates a new instance of MockTableClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTableClient(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTableClient {
+	mock := &MockTableClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockTableClient is an autogenerated mock type for the TableClient type
+type MockTableClient struct {
+	mock.Mock
+}
+
+type MockTableClient_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockTableClient) EXPECT() *MockTableClient_Expecter {
+	return &MockTableClient_Expecter{mock: &_m.Mock}
+}
+
+// CreateTable provides a mock function for the type MockTableClient
+func (_mock *MockTableClient) CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+	// func(*dynamodb.Options)
+	_va := make([]any, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []any
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTable")
+	}
+
+	var r0 *dynamodb.CreateTableOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dynamodb.CreateTableInput, ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dynamodb.CreateTableInput, ...func(*dynamodb.Options)) *dynamodb.CreateTableOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.CreateTableOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dynamodb.CreateTableInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTableClient_CreateTable_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateTable'
+type MockTableClient_CreateTable_Call struct {
+	*mock.Call
+}
+
+// CreateTable is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.CreateTableInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *MockTableClient_Expecter) CreateTable(ctx any, params any, optFns ...any) *MockTableClient_CreateTable_Call {
+	return &MockTableClient_CreateTable_Call{Call: _e.mock.On("CreateTable",
+		append([]any{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockTableClient_CreateTable_Call) Run(run func(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options))) *MockTableClient_CreateTable_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dynamodb.CreateTableInput
+		if args[1] != nil {
+			arg1 = args[1].(*dynamodb.CreateTableInput)
+		}
+		var arg2 []func(*dynamodb.Options)
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTableClient_CreateTable_Call) Return(createTableOutput *dynamodb.CreateTableOutput, err error) *MockTableClient_CreateTable_Call {
+	_c.Call.Return(createTableOutput, err)
+	return _c
+}
+
+func (_c *MockTableClient_CreateTable_Call) RunAndReturn(run func(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error)) *MockTableClient_CreateTable_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DescribeTable provides a mock function for the type MockTableClient
+func (_mock *MockTableClient) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	// func(*dynamodb.Options)
+	_va := make([]any, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []any
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DescribeTable")
+	}
+
+	var r0 *dynamodb.DescribeTableOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dynamodb.DescribeTableInput, ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dynamodb.DescribeTableInput, ...func(*dynamodb.Options)) *dynamodb.DescribeTableOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.DescribeTableOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dynamodb.DescribeTableInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTableClient_DescribeTable_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeTable'
+type MockTableClient_DescribeTable_Call struct {
+	*mock.Call
+}
+
+// DescribeTable is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.DescribeTableInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *MockTableClient_Expecter) DescribeTable(ctx any, params any, optFns ...any) *MockTableClient_DescribeTable_Call {
+	return &MockTableClient_DescribeTable_Call{Call: _e.mock.On("DescribeTable",
+		append([]any{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockTableClient_DescribeTable_Call) Run(run func(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options))) *MockTableClient_DescribeTable_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dynamodb.DescribeTableInput
+		if args[1] != nil {
+			arg1 = args[1].(*dynamodb.DescribeTableInput)
+		}
+		var arg2 []func(*dynamodb.Options)
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTableClient_DescribeTable_Call) Return(describeTableOutput *dynamodb.DescribeTableOutput, err error) *MockTableClient_DescribeTable_Call {
+	_c.Call.Return(describeTableOutput, err)
+	return _c
+}
+
+func (_c *MockTableClient_DescribeTable_Call) RunAndReturn(run func(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)) *MockTableClient_DescribeTable_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DescribeTimeToLive provides a mock function for the type MockTableClient
+func (_mock *MockTableClient) DescribeTimeToLive(ctx context.Context, params *dynamodb.DescribeTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error) {
+	// func(*dynamodb.Options)
+	_va := make([]any, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []any
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DescribeTimeToLive")
+	}
+
+	var r0 *dynamodb.DescribeTimeToLiveOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dynamodb.DescribeTimeToLiveInput, ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dynamodb.DescribeTimeToLiveInput, ...func(*dynamodb.Options)) *dynamodb.DescribeTimeToLiveOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.DescribeTimeToLiveOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dynamodb.DescribeTimeToLiveInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTableClient_DescribeTimeToLive_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeTimeToLive'
+type MockTableClient_DescribeTimeToLive_Call struct {
+	*mock.Call
+}
+
+// DescribeTimeToLive is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.DescribeTimeToLiveInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *MockTableClient_Expecter) DescribeTimeToLive(ctx any, params any, optFns ...any) *MockTableClient_DescribeTimeToLive_Call {
+	return &MockTableClient_DescribeTimeToLive_Call{Call: _e.mock.On("DescribeTimeToLive",
+		append([]any{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockTableClient_DescribeTimeToLive_Call) Run(run func(ctx context.Context, params *dynamodb.DescribeTimeToLiveInput, optFns ...func(*dynamodb.Options))) *MockTableClient_DescribeTimeToLive_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dynamodb.DescribeTimeToLiveInput
+		if args[1] != nil {
+			arg1 = args[1].(*dynamodb.DescribeTimeToLiveInput)
+		}
+		var arg2 []func(*dynamodb.Options)
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTableClient_DescribeTimeToLive_Call) Return(describeTimeToLiveOutput *dynamodb.DescribeTimeToLiveOutput, err error) *MockTableClient_DescribeTimeToLive_Call {
+	_c.Call.Return(describeTimeToLiveOutput, err)
+	return _c
+}
+
+func (_c *MockTableClient_DescribeTimeToLive_Call) RunAndReturn(run func(ctx context.Context, params *dynamodb.DescribeTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error)) *MockTableClient_DescribeTimeToLive_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateTimeToLive provides a mock function for the type MockTableClient
+func (_mock *MockTableClient) UpdateTimeToLive(ctx context.Context, params *dynamodb.UpdateTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTimeToLiveOutput, error) {
+	// func(*dynamodb.Options)
+	_va := make([]any, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []any
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTimeToLive")
+	}
+
+	var r0 *dynamodb.UpdateTimeToLiveOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dynamodb.UpdateTimeToLiveInput, ...func(*dynamodb.Options)) (*dynamodb.UpdateTimeToLiveOutput, error)); ok {
+		return returnFunc(ctx, params, optFns...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dynamodb.UpdateTimeToLiveInput, ...func(*dynamodb.Options)) *dynamodb.UpdateTimeToLiveOutput); ok {
+		r0 = returnFunc(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.UpdateTimeToLiveOutput)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dynamodb.UpdateTimeToLiveInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = returnFunc(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTableClient_UpdateTimeToLive_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateTimeToLive'
+type MockTableClient_UpdateTimeToLive_Call struct {
+	*mock.Call
+}
+
+// UpdateTimeToLive is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.UpdateTimeToLiveInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *MockTableClient_Expecter) UpdateTimeToLive(ctx any, params any, optFns ...any) *MockTableClient_UpdateTimeToLive_Call {
+	return &MockTableClient_UpdateTimeToLive_Call{Call: _e.mock.On("UpdateTimeToLive",
+		append([]any{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockTableClient_UpdateTimeToLive_Call) Run(run func(ctx context.Context, params *dynamodb.UpdateTimeToLiveInput, optFns ...func(*dynamodb.Options))) *MockTableClient_UpdateTimeToLive_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dynamodb.UpdateTimeToLiveInput
+		if args[1] != nil {
+			arg1 = args[1].(*dynamodb.UpdateTimeToLiveInput)
+		}
+		var arg2 []func(*dynamodb.Options)
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTableClient_UpdateTimeToLive_Call) Return(updateTimeToLiveOutput *dynamodb.UpdateTimeToLiveOutput, err error) *MockTableClient_UpdateTimeToLive_Call {
+	_c.Call.Return(updateTimeToLiveOutput, err)
+	return _c
+}
+
+func (_c *MockTableClient_UpdateTimeToLive_Call) RunAndReturn(run func(ctx context.Context, params *dynamodb.UpdateTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTimeToLiveOutput, error)) *MockTableClient_UpdateTimeToLive_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,249 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package lock
+
+import (
+	"context"
+	"time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockBackend creates a new instance of MockBackend. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockBackend(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockBackend {
+	mock := &MockBackend{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockBackend is an autogenerated mock type for the Backend type
+type MockBackend struct {
+	mock.Mock
+}
+
+type MockBackend_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockBackend) EXPECT() *MockBackend_Expecter {
+	return &MockBackend_Expecter{mock: &_m.Mock}
+}
+
+// Acquire provides a mock function for the type MockBackend
+func (_mock *MockBackend) Acquire(ctx context.Context, name string, owner string, lease time.Duration) (int64, error) {
+	ret := _mock.Called(ctx, name, owner, lease)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Acquire")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) (int64, error)); ok {
+		return returnFunc(ctx, name, owner, lease)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) int64); ok {
+		r0 = returnFunc(ctx, name, owner, lease)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, time.Duration) error); ok {
+		r1 = returnFunc(ctx, name, owner, lease)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockBackend_Acquire_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Acquire'
+type MockBackend_Acquire_Call struct {
+	*mock.Call
+}
+
+// Acquire is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - owner string
+//   - lease time.Duration
+func (_e *MockBackend_Expecter) Acquire(ctx any, name any, owner any, lease any) *MockBackend_Acquire_Call {
+	return &MockBackend_Acquire_Call{Call: _e.mock.On("Acquire", ctx, name, owner, lease)}
+}
+
+func (_c *MockBackend_Acquire_Call) Run(run func(ctx context.Context, name string, owner string, lease time.Duration)) *MockBackend_Acquire_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 time.Duration
+		if args[3] != nil {
+			arg3 = args[3].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockBackend_Acquire_Call) Return(n int64, err error) *MockBackend_Acquire_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockBackend_Acquire_Call) RunAndReturn(run func(ctx context.Context, name string, owner string, lease time.Duration) (int64, error)) *MockBackend_Acquire_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Refresh provides a mock function for the type MockBackend
+func (_mock *MockBackend) Refresh(ctx context.Context, name string, owner string, lease time.Duration) error {
+	ret := _mock.Called(ctx, name, owner, lease)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Refresh")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) error); ok {
+		r0 = returnFunc(ctx, name, owner, lease)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockBackend_Refresh_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Refresh'
+type MockBackend_Refresh_Call struct {
+	*mock.Call
+}
+
+// Refresh is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - owner string
+//   - lease time.Duration
+func (_e *MockBackend_Expecter) Refresh(ctx any, name any, owner any, lease any) *MockBackend_Refresh_Call {
+	return &MockBackend_Refresh_Call{Call: _e.mock.On("Refresh", ctx, name, owner, lease)}
+}
+
+func (_c *MockBackend_Refresh_Call) Run(run func(ctx context.Context, name string, owner string, lease time.Duration)) *MockBackend_Refresh_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 time.Duration
+		if args[3] != nil {
+			arg3 = args[3].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockBackend_Refresh_Call) Return(err error) *MockBackend_Refresh_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockBackend_Refresh_Call) RunAndReturn(run func(ctx context.Context, name string, owner string, lease time.Duration) error) *MockBackend_Refresh_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Release provides a mock function for the type MockBackend
+func (_mock *MockBackend) Release(ctx context.Context, name string, owner string) error {
+	ret := _mock.Called(ctx, name, owner)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, name, owner)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockBackend_Release_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Release'
+type MockBackend_Release_Call struct {
+	*mock.Call
+}
+
+// Release is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - owner string
+func (_e *MockBackend_Expecter) Release(ctx any, name any, owner any) *MockBackend_Release_Call {
+	return &MockBackend_Release_Call{Call: _e.mock.On("Release", ctx, name, owner)}
+}
+
+func (_c *MockBackend_Release_Call) Run(run func(ctx context.Context, name string, owner string)) *MockBackend_Release_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockBackend_Release_Call) Return(err error) *MockBackend_Release_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockBackend_Release_Call) RunAndReturn(run func(ctx context.Context, name string, owner string) error) *MockBackend_Release_Call {
+	_c.Call.Return(run)
+	return _c
+}
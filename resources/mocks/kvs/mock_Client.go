@@ -6,6 +6,7 @@ package kvs
 
 import (
 	"context"
+	"iter"
 	"time"
 
 	"github.com/arielsrv/go-kvs-client/kvs"
@@ -39,6 +40,59 @@ func (_m *MockClient[T]) EXPECT() *MockClient_Expecter[T] {
 	return &MockClient_Expecter[T]{mock: &_m.Mock}
 }
 
+// All provides a mock function for the type MockClient
+func (_mock *MockClient[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for All")
+	}
+
+	var r0 iter.Seq2[T, error]
+	if returnFunc, ok := ret.Get(0).(func(context.Context) iter.Seq2[T, error]); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(iter.Seq2[T, error])
+		}
+	}
+	return r0
+}
+
+// MockClient_All_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'All'
+type MockClient_All_Call[T any] struct {
+	*mock.Call
+}
+
+// All is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockClient_Expecter[T]) All(ctx any) *MockClient_All_Call[T] {
+	return &MockClient_All_Call[T]{Call: _e.mock.On("All", ctx)}
+}
+
+func (_c *MockClient_All_Call[T]) Run(run func(ctx context.Context)) *MockClient_All_Call[T] {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_All_Call[T]) Return(seq2 iter.Seq2[T, error]) *MockClient_All_Call[T] {
+	_c.Call.Return(seq2)
+	return _c
+}
+
+func (_c *MockClient_All_Call[T]) RunAndReturn(run func(ctx context.Context) iter.Seq2[T, error]) *MockClient_All_Call[T] {
+	_c.Call.Return(run)
+	return _c
+}
+
 // BulkGet provides a mock function for the type MockClient
 func (_mock *MockClient[T]) BulkGet(key []string) ([]T, error) {
 	ret := _mock.Called(key)
@@ -169,6 +223,93 @@ func (_c *MockClient_BulkGetWithContext_Call[T]) RunAndReturn(run func(ctx conte
 	return _c
 }
 
+// BulkGetWithOptions provides a mock function for the type MockClient
+func (_mock *MockClient[T]) BulkGetWithOptions(ctx context.Context, keys []string, opts ...kvs.CallOption) ([]T, error) {
+	// kvs.CallOption
+	_va := make([]any, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []any
+	_ca = append(_ca, ctx, keys)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BulkGetWithOptions")
+	}
+
+	var r0 []T
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string, ...kvs.CallOption) ([]T, error)); ok {
+		return returnFunc(ctx, keys, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string, ...kvs.CallOption) []T); ok {
+		r0 = returnFunc(ctx, keys, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]T)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []string, ...kvs.CallOption) error); ok {
+		r1 = returnFunc(ctx, keys, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockClient_BulkGetWithOptions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BulkGetWithOptions'
+type MockClient_BulkGetWithOptions_Call[T any] struct {
+	*mock.Call
+}
+
+// BulkGetWithOptions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keys []string
+//   - opts ...kvs.CallOption
+func (_e *MockClient_Expecter[T]) BulkGetWithOptions(ctx any, keys any, opts ...any) *MockClient_BulkGetWithOptions_Call[T] {
+	return &MockClient_BulkGetWithOptions_Call[T]{Call: _e.mock.On("BulkGetWithOptions",
+		append([]any{ctx, keys}, opts...)...)}
+}
+
+func (_c *MockClient_BulkGetWithOptions_Call[T]) Run(run func(ctx context.Context, keys []string, opts ...kvs.CallOption)) *MockClient_BulkGetWithOptions_Call[T] {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
+		}
+		var arg2 []kvs.CallOption
+		variadicArgs := make([]kvs.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(kvs.CallOption)
+			}
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_BulkGetWithOptions_Call[T]) Return(vs []T, err error) *MockClient_BulkGetWithOptions_Call[T] {
+	_c.Call.Return(vs, err)
+	return _c
+}
+
+func (_c *MockClient_BulkGetWithOptions_Call[T]) RunAndReturn(run func(ctx context.Context, keys []string, opts ...kvs.CallOption) ([]T, error)) *MockClient_BulkGetWithOptions_Call[T] {
+	_c.Call.Return(run)
+	return _c
+}
+
 // BulkSave provides a mock function for the type MockClient
 func (_mock *MockClient[T]) BulkSave(items []T, keyMapper kvs.KeyMapperFunc[T], ttl ...time.Duration) error {
 	// time.Duration
@@ -327,6 +468,88 @@ func (_c *MockClient_BulkSaveWithContext_Call[T]) RunAndReturn(run func(ctx cont
 	return _c
 }
 
+// BulkSaveWithOptions provides a mock function for the type MockClient
+func (_mock *MockClient[T]) BulkSaveWithOptions(ctx context.Context, items []T, keyMapper kvs.KeyMapperFunc[T], opts ...kvs.CallOption) error {
+	// kvs.CallOption
+	_va := make([]any, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []any
+	_ca = append(_ca, ctx, items, keyMapper)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BulkSaveWithOptions")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []T, kvs.KeyMapperFunc[T], ...kvs.CallOption) error); ok {
+		r0 = returnFunc(ctx, items, keyMapper, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockClient_BulkSaveWithOptions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BulkSaveWithOptions'
+type MockClient_BulkSaveWithOptions_Call[T any] struct {
+	*mock.Call
+}
+
+// BulkSaveWithOptions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - items []T
+//   - keyMapper kvs.KeyMapperFunc[T]
+//   - opts ...kvs.CallOption
+func (_e *MockClient_Expecter[T]) BulkSaveWithOptions(ctx any, items any, keyMapper any, opts ...any) *MockClient_BulkSaveWithOptions_Call[T] {
+	return &MockClient_BulkSaveWithOptions_Call[T]{Call: _e.mock.On("BulkSaveWithOptions",
+		append([]any{ctx, items, keyMapper}, opts...)...)}
+}
+
+func (_c *MockClient_BulkSaveWithOptions_Call[T]) Run(run func(ctx context.Context, items []T, keyMapper kvs.KeyMapperFunc[T], opts ...kvs.CallOption)) *MockClient_BulkSaveWithOptions_Call[T] {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []T
+		if args[1] != nil {
+			arg1 = args[1].([]T)
+		}
+		var arg2 kvs.KeyMapperFunc[T]
+		if args[2] != nil {
+			arg2 = args[2].(kvs.KeyMapperFunc[T])
+		}
+		var arg3 []kvs.CallOption
+		variadicArgs := make([]kvs.CallOption, len(args)-3)
+		for i, a := range args[3:] {
+			if a != nil {
+				variadicArgs[i] = a.(kvs.CallOption)
+			}
+		}
+		arg3 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_BulkSaveWithOptions_Call[T]) Return(err error) *MockClient_BulkSaveWithOptions_Call[T] {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockClient_BulkSaveWithOptions_Call[T]) RunAndReturn(run func(ctx context.Context, items []T, keyMapper kvs.KeyMapperFunc[T], opts ...kvs.CallOption) error) *MockClient_BulkSaveWithOptions_Call[T] {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Get provides a mock function for the type MockClient
 func (_mock *MockClient[T]) Get(key string) (*T, error) {
 	ret := _mock.Called(key)
@@ -457,6 +680,93 @@ func (_c *MockClient_GetWithContext_Call[T]) RunAndReturn(run func(ctx context.C
 	return _c
 }
 
+// GetWithOptions provides a mock function for the type MockClient
+func (_mock *MockClient[T]) GetWithOptions(ctx context.Context, key string, opts ...kvs.CallOption) (*T, error) {
+	// kvs.CallOption
+	_va := make([]any, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []any
+	_ca = append(_ca, ctx, key)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWithOptions")
+	}
+
+	var r0 *T
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, ...kvs.CallOption) (*T, error)); ok {
+		return returnFunc(ctx, key, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, ...kvs.CallOption) *T); ok {
+		r0 = returnFunc(ctx, key, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*T)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, ...kvs.CallOption) error); ok {
+		r1 = returnFunc(ctx, key, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockClient_GetWithOptions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWithOptions'
+type MockClient_GetWithOptions_Call[T any] struct {
+	*mock.Call
+}
+
+// GetWithOptions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - opts ...kvs.CallOption
+func (_e *MockClient_Expecter[T]) GetWithOptions(ctx any, key any, opts ...any) *MockClient_GetWithOptions_Call[T] {
+	return &MockClient_GetWithOptions_Call[T]{Call: _e.mock.On("GetWithOptions",
+		append([]any{ctx, key}, opts...)...)}
+}
+
+func (_c *MockClient_GetWithOptions_Call[T]) Run(run func(ctx context.Context, key string, opts ...kvs.CallOption)) *MockClient_GetWithOptions_Call[T] {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 []kvs.CallOption
+		variadicArgs := make([]kvs.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(kvs.CallOption)
+			}
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_GetWithOptions_Call[T]) Return(v *T, err error) *MockClient_GetWithOptions_Call[T] {
+	_c.Call.Return(v, err)
+	return _c
+}
+
+func (_c *MockClient_GetWithOptions_Call[T]) RunAndReturn(run func(ctx context.Context, key string, opts ...kvs.CallOption) (*T, error)) *MockClient_GetWithOptions_Call[T] {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Save provides a mock function for the type MockClient
 func (_mock *MockClient[T]) Save(key string, item *T, ttl ...time.Duration) error {
 	// time.Duration
@@ -614,3 +924,85 @@ func (_c *MockClient_SaveWithContext_Call[T]) RunAndReturn(run func(ctx context.
 	_c.Call.Return(run)
 	return _c
 }
+
+// SaveWithOptions provides a mock function for the type MockClient
+func (_mock *MockClient[T]) SaveWithOptions(ctx context.Context, key string, item *T, opts ...kvs.CallOption) error {
+	// kvs.CallOption
+	_va := make([]any, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []any
+	_ca = append(_ca, ctx, key, item)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveWithOptions")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *T, ...kvs.CallOption) error); ok {
+		r0 = returnFunc(ctx, key, item, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockClient_SaveWithOptions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveWithOptions'
+type MockClient_SaveWithOptions_Call[T any] struct {
+	*mock.Call
+}
+
+// SaveWithOptions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - item *T
+//   - opts ...kvs.CallOption
+func (_e *MockClient_Expecter[T]) SaveWithOptions(ctx any, key any, item any, opts ...any) *MockClient_SaveWithOptions_Call[T] {
+	return &MockClient_SaveWithOptions_Call[T]{Call: _e.mock.On("SaveWithOptions",
+		append([]any{ctx, key, item}, opts...)...)}
+}
+
+func (_c *MockClient_SaveWithOptions_Call[T]) Run(run func(ctx context.Context, key string, item *T, opts ...kvs.CallOption)) *MockClient_SaveWithOptions_Call[T] {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 *T
+		if args[2] != nil {
+			arg2 = args[2].(*T)
+		}
+		var arg3 []kvs.CallOption
+		variadicArgs := make([]kvs.CallOption, len(args)-3)
+		for i, a := range args[3:] {
+			if a != nil {
+				variadicArgs[i] = a.(kvs.CallOption)
+			}
+		}
+		arg3 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_SaveWithOptions_Call[T]) Return(err error) *MockClient_SaveWithOptions_Call[T] {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockClient_SaveWithOptions_Call[T]) RunAndReturn(run func(ctx context.Context, key string, item *T, opts ...kvs.CallOption) error) *MockClient_SaveWithOptions_Call[T] {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,105 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package kvs
+
+import (
+	"context"
+	"iter"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockIndexQuerier creates a new instance of MockIndexQuerier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIndexQuerier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIndexQuerier {
+	mock := &MockIndexQuerier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIndexQuerier is an autogenerated mock type for the IndexQuerier type
+type MockIndexQuerier struct {
+	mock.Mock
+}
+
+type MockIndexQuerier_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIndexQuerier) EXPECT() *MockIndexQuerier_Expecter {
+	return &MockIndexQuerier_Expecter{mock: &_m.Mock}
+}
+
+// QueryIndex provides a mock function for the type MockIndexQuerier
+func (_mock *MockIndexQuerier) QueryIndex(ctx context.Context, index string, value string) iter.Seq2[*kvs.Item, error] {
+	ret := _mock.Called(ctx, index, value)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryIndex")
+	}
+
+	var r0 iter.Seq2[*kvs.Item, error]
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) iter.Seq2[*kvs.Item, error]); ok {
+		r0 = returnFunc(ctx, index, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(iter.Seq2[*kvs.Item, error])
+		}
+	}
+	return r0
+}
+
+// MockIndexQuerier_QueryIndex_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryIndex'
+type MockIndexQuerier_QueryIndex_Call struct {
+	*mock.Call
+}
+
+// QueryIndex is a helper method to define mock.On call
+//   - ctx context.Context
+//   - index string
+//   - value string
+func (_e *MockIndexQuerier_Expecter) QueryIndex(ctx any, index any, value any) *MockIndexQuerier_QueryIndex_Call {
+	return &MockIndexQuerier_QueryIndex_Call{Call: _e.mock.On("QueryIndex", ctx, index, value)}
+}
+
+func (_c *MockIndexQuerier_QueryIndex_Call) Run(run func(ctx context.Context, index string, value string)) *MockIndexQuerier_QueryIndex_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIndexQuerier_QueryIndex_Call) Return(seq2 iter.Seq2[*kvs.Item, error]) *MockIndexQuerier_QueryIndex_Call {
+	_c.Call.Return(seq2)
+	return _c
+}
+
+func (_c *MockIndexQuerier_QueryIndex_Call) RunAndReturn(run func(ctx context.Context, index string, value string) iter.Seq2[*kvs.Item, error]) *MockIndexQuerier_QueryIndex_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -6,6 +6,8 @@ package kvs
 
 import (
 	"context"
+	"iter"
+	"time"
 
 	"github.com/arielsrv/go-kvs-client/kvs"
 	mock "github.com/stretchr/testify/mock"
@@ -276,6 +278,138 @@ func (_c *MockLowLevelClient_BulkSaveWithContext_Call) RunAndReturn(run func(ctx
 	return _c
 }
 
+// CompareAndDelete provides a mock function for the type MockLowLevelClient
+func (_mock *MockLowLevelClient) CompareAndDelete(ctx context.Context, key string, expected *kvs.Item) error {
+	ret := _mock.Called(ctx, key, expected)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompareAndDelete")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *kvs.Item) error); ok {
+		r0 = returnFunc(ctx, key, expected)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockLowLevelClient_CompareAndDelete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompareAndDelete'
+type MockLowLevelClient_CompareAndDelete_Call struct {
+	*mock.Call
+}
+
+// CompareAndDelete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - expected *kvs.Item
+func (_e *MockLowLevelClient_Expecter) CompareAndDelete(ctx any, key any, expected any) *MockLowLevelClient_CompareAndDelete_Call {
+	return &MockLowLevelClient_CompareAndDelete_Call{Call: _e.mock.On("CompareAndDelete", ctx, key, expected)}
+}
+
+func (_c *MockLowLevelClient_CompareAndDelete_Call) Run(run func(ctx context.Context, key string, expected *kvs.Item)) *MockLowLevelClient_CompareAndDelete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 *kvs.Item
+		if args[2] != nil {
+			arg2 = args[2].(*kvs.Item)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLowLevelClient_CompareAndDelete_Call) Return(err error) *MockLowLevelClient_CompareAndDelete_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockLowLevelClient_CompareAndDelete_Call) RunAndReturn(run func(ctx context.Context, key string, expected *kvs.Item) error) *MockLowLevelClient_CompareAndDelete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CompareAndSwap provides a mock function for the type MockLowLevelClient
+func (_mock *MockLowLevelClient) CompareAndSwap(ctx context.Context, key string, expected *kvs.Item, item *kvs.Item) error {
+	ret := _mock.Called(ctx, key, expected, item)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompareAndSwap")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *kvs.Item, *kvs.Item) error); ok {
+		r0 = returnFunc(ctx, key, expected, item)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockLowLevelClient_CompareAndSwap_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompareAndSwap'
+type MockLowLevelClient_CompareAndSwap_Call struct {
+	*mock.Call
+}
+
+// CompareAndSwap is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - expected *kvs.Item
+//   - item *kvs.Item
+func (_e *MockLowLevelClient_Expecter) CompareAndSwap(ctx any, key any, expected any, item any) *MockLowLevelClient_CompareAndSwap_Call {
+	return &MockLowLevelClient_CompareAndSwap_Call{Call: _e.mock.On("CompareAndSwap", ctx, key, expected, item)}
+}
+
+func (_c *MockLowLevelClient_CompareAndSwap_Call) Run(run func(ctx context.Context, key string, expected *kvs.Item, item *kvs.Item)) *MockLowLevelClient_CompareAndSwap_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 *kvs.Item
+		if args[2] != nil {
+			arg2 = args[2].(*kvs.Item)
+		}
+		var arg3 *kvs.Item
+		if args[3] != nil {
+			arg3 = args[3].(*kvs.Item)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLowLevelClient_CompareAndSwap_Call) Return(err error) *MockLowLevelClient_CompareAndSwap_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockLowLevelClient_CompareAndSwap_Call) RunAndReturn(run func(ctx context.Context, key string, expected *kvs.Item, item *kvs.Item) error) *MockLowLevelClient_CompareAndSwap_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ContainerName provides a mock function for the type MockLowLevelClient
 func (_mock *MockLowLevelClient) ContainerName() string {
 	ret := _mock.Called()
@@ -320,6 +454,114 @@ func (_c *MockLowLevelClient_ContainerName_Call) RunAndReturn(run func() string)
 	return _c
 }
 
+// Delete provides a mock function for the type MockLowLevelClient
+func (_mock *MockLowLevelClient) Delete(key string) error {
+	ret := _mock.Called(key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string) error); ok {
+		r0 = returnFunc(key)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockLowLevelClient_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockLowLevelClient_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - key string
+func (_e *MockLowLevelClient_Expecter) Delete(key any) *MockLowLevelClient_Delete_Call {
+	return &MockLowLevelClient_Delete_Call{Call: _e.mock.On("Delete", key)}
+}
+
+func (_c *MockLowLevelClient_Delete_Call) Run(run func(key string)) *MockLowLevelClient_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLowLevelClient_Delete_Call) Return(err error) *MockLowLevelClient_Delete_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockLowLevelClient_Delete_Call) RunAndReturn(run func(key string) error) *MockLowLevelClient_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteWithContext provides a mock function for the type MockLowLevelClient
+func (_mock *MockLowLevelClient) DeleteWithContext(ctx context.Context, key string) error {
+	ret := _mock.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWithContext")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockLowLevelClient_DeleteWithContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteWithContext'
+type MockLowLevelClient_DeleteWithContext_Call struct {
+	*mock.Call
+}
+
+// DeleteWithContext is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *MockLowLevelClient_Expecter) DeleteWithContext(ctx any, key any) *MockLowLevelClient_DeleteWithContext_Call {
+	return &MockLowLevelClient_DeleteWithContext_Call{Call: _e.mock.On("DeleteWithContext", ctx, key)}
+}
+
+func (_c *MockLowLevelClient_DeleteWithContext_Call) Run(run func(ctx context.Context, key string)) *MockLowLevelClient_DeleteWithContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLowLevelClient_DeleteWithContext_Call) Return(err error) *MockLowLevelClient_DeleteWithContext_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockLowLevelClient_DeleteWithContext_Call) RunAndReturn(run func(ctx context.Context, key string) error) *MockLowLevelClient_DeleteWithContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Get provides a mock function for the type MockLowLevelClient
 func (_mock *MockLowLevelClient) Get(key string) (*kvs.Item, error) {
 	ret := _mock.Called(key)
@@ -382,6 +624,93 @@ func (_c *MockLowLevelClient_Get_Call) RunAndReturn(run func(key string) (*kvs.I
 	return _c
 }
 
+// GetFields provides a mock function for the type MockLowLevelClient
+func (_mock *MockLowLevelClient) GetFields(ctx context.Context, key string, fields ...string) (*kvs.Item, error) {
+	// string
+	_va := make([]any, len(fields))
+	for _i := range fields {
+		_va[_i] = fields[_i]
+	}
+	var _ca []any
+	_ca = append(_ca, ctx, key)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFields")
+	}
+
+	var r0 *kvs.Item
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, ...string) (*kvs.Item, error)); ok {
+		return returnFunc(ctx, key, fields...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, ...string) *kvs.Item); ok {
+		r0 = returnFunc(ctx, key, fields...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*kvs.Item)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, ...string) error); ok {
+		r1 = returnFunc(ctx, key, fields...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLowLevelClient_GetFields_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFields'
+type MockLowLevelClient_GetFields_Call struct {
+	*mock.Call
+}
+
+// GetFields is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - fields ...string
+func (_e *MockLowLevelClient_Expecter) GetFields(ctx any, key any, fields ...any) *MockLowLevelClient_GetFields_Call {
+	return &MockLowLevelClient_GetFields_Call{Call: _e.mock.On("GetFields",
+		append([]any{ctx, key}, fields...)...)}
+}
+
+func (_c *MockLowLevelClient_GetFields_Call) Run(run func(ctx context.Context, key string, fields ...string)) *MockLowLevelClient_GetFields_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 []string
+		variadicArgs := make([]string, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(string)
+			}
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLowLevelClient_GetFields_Call) Return(item *kvs.Item, err error) *MockLowLevelClient_GetFields_Call {
+	_c.Call.Return(item, err)
+	return _c
+}
+
+func (_c *MockLowLevelClient_GetFields_Call) RunAndReturn(run func(ctx context.Context, key string, fields ...string) (*kvs.Item, error)) *MockLowLevelClient_GetFields_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetWithContext provides a mock function for the type MockLowLevelClient
 func (_mock *MockLowLevelClient) GetWithContext(ctx context.Context, key string) (*kvs.Item, error) {
 	ret := _mock.Called(ctx, key)
@@ -450,6 +779,225 @@ func (_c *MockLowLevelClient_GetWithContext_Call) RunAndReturn(run func(ctx cont
 	return _c
 }
 
+// Increment provides a mock function for the type MockLowLevelClient
+func (_mock *MockLowLevelClient) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	ret := _mock.Called(ctx, key, delta, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Increment")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64, time.Duration) (int64, error)); ok {
+		return returnFunc(ctx, key, delta, ttl)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64, time.Duration) int64); ok {
+		r0 = returnFunc(ctx, key, delta, ttl)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int64, time.Duration) error); ok {
+		r1 = returnFunc(ctx, key, delta, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLowLevelClient_Increment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Increment'
+type MockLowLevelClient_Increment_Call struct {
+	*mock.Call
+}
+
+// Increment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - delta int64
+//   - ttl time.Duration
+func (_e *MockLowLevelClient_Expecter) Increment(ctx any, key any, delta any, ttl any) *MockLowLevelClient_Increment_Call {
+	return &MockLowLevelClient_Increment_Call{Call: _e.mock.On("Increment", ctx, key, delta, ttl)}
+}
+
+func (_c *MockLowLevelClient_Increment_Call) Run(run func(ctx context.Context, key string, delta int64, ttl time.Duration)) *MockLowLevelClient_Increment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		var arg3 time.Duration
+		if args[3] != nil {
+			arg3 = args[3].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLowLevelClient_Increment_Call) Return(n int64, err error) *MockLowLevelClient_Increment_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockLowLevelClient_Increment_Call) RunAndReturn(run func(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)) *MockLowLevelClient_Increment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IncrementFloat provides a mock function for the type MockLowLevelClient
+func (_mock *MockLowLevelClient) IncrementFloat(ctx context.Context, key string, delta float64, ttl time.Duration) (float64, error) {
+	ret := _mock.Called(ctx, key, delta, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IncrementFloat")
+	}
+
+	var r0 float64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, float64, time.Duration) (float64, error)); ok {
+		return returnFunc(ctx, key, delta, ttl)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, float64, time.Duration) float64); ok {
+		r0 = returnFunc(ctx, key, delta, ttl)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, float64, time.Duration) error); ok {
+		r1 = returnFunc(ctx, key, delta, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLowLevelClient_IncrementFloat_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncrementFloat'
+type MockLowLevelClient_IncrementFloat_Call struct {
+	*mock.Call
+}
+
+// IncrementFloat is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - delta float64
+//   - ttl time.Duration
+func (_e *MockLowLevelClient_Expecter) IncrementFloat(ctx any, key any, delta any, ttl any) *MockLowLevelClient_IncrementFloat_Call {
+	return &MockLowLevelClient_IncrementFloat_Call{Call: _e.mock.On("IncrementFloat", ctx, key, delta, ttl)}
+}
+
+func (_c *MockLowLevelClient_IncrementFloat_Call) Run(run func(ctx context.Context, key string, delta float64, ttl time.Duration)) *MockLowLevelClient_IncrementFloat_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 float64
+		if args[2] != nil {
+			arg2 = args[2].(float64)
+		}
+		var arg3 time.Duration
+		if args[3] != nil {
+			arg3 = args[3].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLowLevelClient_IncrementFloat_Call) Return(f float64, err error) *MockLowLevelClient_IncrementFloat_Call {
+	_c.Call.Return(f, err)
+	return _c
+}
+
+func (_c *MockLowLevelClient_IncrementFloat_Call) RunAndReturn(run func(ctx context.Context, key string, delta float64, ttl time.Duration) (float64, error)) *MockLowLevelClient_IncrementFloat_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Patch provides a mock function for the type MockLowLevelClient
+func (_mock *MockLowLevelClient) Patch(ctx context.Context, key string, updates map[string]any) error {
+	ret := _mock.Called(ctx, key, updates)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Patch")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, map[string]any) error); ok {
+		r0 = returnFunc(ctx, key, updates)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockLowLevelClient_Patch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Patch'
+type MockLowLevelClient_Patch_Call struct {
+	*mock.Call
+}
+
+// Patch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - updates map[string]any
+func (_e *MockLowLevelClient_Expecter) Patch(ctx any, key any, updates any) *MockLowLevelClient_Patch_Call {
+	return &MockLowLevelClient_Patch_Call{Call: _e.mock.On("Patch", ctx, key, updates)}
+}
+
+func (_c *MockLowLevelClient_Patch_Call) Run(run func(ctx context.Context, key string, updates map[string]any)) *MockLowLevelClient_Patch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 map[string]any
+		if args[2] != nil {
+			arg2 = args[2].(map[string]any)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLowLevelClient_Patch_Call) Return(err error) *MockLowLevelClient_Patch_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockLowLevelClient_Patch_Call) RunAndReturn(run func(ctx context.Context, key string, updates map[string]any) error) *MockLowLevelClient_Patch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Save provides a mock function for the type MockLowLevelClient
 func (_mock *MockLowLevelClient) Save(key string, item *kvs.Item) error {
 	ret := _mock.Called(key, item)
@@ -507,6 +1055,69 @@ func (_c *MockLowLevelClient_Save_Call) RunAndReturn(run func(key string, item *
 	return _c
 }
 
+// SaveIfAbsent provides a mock function for the type MockLowLevelClient
+func (_mock *MockLowLevelClient) SaveIfAbsent(ctx context.Context, key string, item *kvs.Item) error {
+	ret := _mock.Called(ctx, key, item)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveIfAbsent")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *kvs.Item) error); ok {
+		r0 = returnFunc(ctx, key, item)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockLowLevelClient_SaveIfAbsent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveIfAbsent'
+type MockLowLevelClient_SaveIfAbsent_Call struct {
+	*mock.Call
+}
+
+// SaveIfAbsent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - item *kvs.Item
+func (_e *MockLowLevelClient_Expecter) SaveIfAbsent(ctx any, key any, item any) *MockLowLevelClient_SaveIfAbsent_Call {
+	return &MockLowLevelClient_SaveIfAbsent_Call{Call: _e.mock.On("SaveIfAbsent", ctx, key, item)}
+}
+
+func (_c *MockLowLevelClient_SaveIfAbsent_Call) Run(run func(ctx context.Context, key string, item *kvs.Item)) *MockLowLevelClient_SaveIfAbsent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 *kvs.Item
+		if args[2] != nil {
+			arg2 = args[2].(*kvs.Item)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLowLevelClient_SaveIfAbsent_Call) Return(err error) *MockLowLevelClient_SaveIfAbsent_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockLowLevelClient_SaveIfAbsent_Call) RunAndReturn(run func(ctx context.Context, key string, item *kvs.Item) error) *MockLowLevelClient_SaveIfAbsent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SaveWithContext provides a mock function for the type MockLowLevelClient
 func (_mock *MockLowLevelClient) SaveWithContext(ctx context.Context, key string, item *kvs.Item) error {
 	ret := _mock.Called(ctx, key, item)
@@ -569,3 +1180,62 @@ func (_c *MockLowLevelClient_SaveWithContext_Call) RunAndReturn(run func(ctx con
 	_c.Call.Return(run)
 	return _c
 }
+
+// Scan provides a mock function for the type MockLowLevelClient
+func (_mock *MockLowLevelClient) Scan(ctx context.Context, opts kvs.ScanOptions) iter.Seq2[*kvs.Item, error] {
+	ret := _mock.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Scan")
+	}
+
+	var r0 iter.Seq2[*kvs.Item, error]
+	if returnFunc, ok := ret.Get(0).(func(context.Context, kvs.ScanOptions) iter.Seq2[*kvs.Item, error]); ok {
+		r0 = returnFunc(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(iter.Seq2[*kvs.Item, error])
+		}
+	}
+	return r0
+}
+
+// MockLowLevelClient_Scan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Scan'
+type MockLowLevelClient_Scan_Call struct {
+	*mock.Call
+}
+
+// Scan is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opts kvs.ScanOptions
+func (_e *MockLowLevelClient_Expecter) Scan(ctx any, opts any) *MockLowLevelClient_Scan_Call {
+	return &MockLowLevelClient_Scan_Call{Call: _e.mock.On("Scan", ctx, opts)}
+}
+
+func (_c *MockLowLevelClient_Scan_Call) Run(run func(ctx context.Context, opts kvs.ScanOptions)) *MockLowLevelClient_Scan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 kvs.ScanOptions
+		if args[1] != nil {
+			arg1 = args[1].(kvs.ScanOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLowLevelClient_Scan_Call) Return(seq2 iter.Seq2[*kvs.Item, error]) *MockLowLevelClient_Scan_Call {
+	_c.Call.Return(seq2)
+	return _c
+}
+
+func (_c *MockLowLevelClient_Scan_Call) RunAndReturn(run func(ctx context.Context, opts kvs.ScanOptions) iter.Seq2[*kvs.Item, error]) *MockLowLevelClient_Scan_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,197 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package kvs
+
+import (
+	"context"
+	"iter"
+
+	"github.com/arielsrv/go-kvs-client/kvs"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockQuerier creates a new instance of MockQuerier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockQuerier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockQuerier {
+	mock := &MockQuerier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockQuerier is an autogenerated mock type for the Querier type
+type MockQuerier struct {
+	mock.Mock
+}
+
+type MockQuerier_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockQuerier) EXPECT() *MockQuerier_Expecter {
+	return &MockQuerier_Expecter{mock: &_m.Mock}
+}
+
+// Query provides a mock function for the type MockQuerier
+func (_mock *MockQuerier) Query(ctx context.Context, partition string, condition kvs.SortCondition, opts kvs.QueryOptions) iter.Seq2[*kvs.Item, error] {
+	ret := _mock.Called(ctx, partition, condition, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Query")
+	}
+
+	var r0 iter.Seq2[*kvs.Item, error]
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, kvs.SortCondition, kvs.QueryOptions) iter.Seq2[*kvs.Item, error]); ok {
+		r0 = returnFunc(ctx, partition, condition, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(iter.Seq2[*kvs.Item, error])
+		}
+	}
+	return r0
+}
+
+// MockQuerier_Query_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Query'
+type MockQuerier_Query_Call struct {
+	*mock.Call
+}
+
+// Query is a helper method to define mock.On call
+//   - ctx context.Context
+//   - partition string
+//   - condition kvs.SortCondition
+//   - opts kvs.QueryOptions
+func (_e *MockQuerier_Expecter) Query(ctx any, partition any, condition any, opts any) *MockQuerier_Query_Call {
+	return &MockQuerier_Query_Call{Call: _e.mock.On("Query", ctx, partition, condition, opts)}
+}
+
+func (_c *MockQuerier_Query_Call) Run(run func(ctx context.Context, partition string, condition kvs.SortCondition, opts kvs.QueryOptions)) *MockQuerier_Query_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 kvs.SortCondition
+		if args[2] != nil {
+			arg2 = args[2].(kvs.SortCondition)
+		}
+		var arg3 kvs.QueryOptions
+		if args[3] != nil {
+			arg3 = args[3].(kvs.QueryOptions)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockQuerier_Query_Call) Return(seq2 iter.Seq2[*kvs.Item, error]) *MockQuerier_Query_Call {
+	_c.Call.Return(seq2)
+	return _c
+}
+
+func (_c *MockQuerier_Query_Call) RunAndReturn(run func(ctx context.Context, partition string, condition kvs.SortCondition, opts kvs.QueryOptions) iter.Seq2[*kvs.Item, error]) *MockQuerier_Query_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryPage provides a mock function for the type MockQuerier
+func (_mock *MockQuerier) QueryPage(ctx context.Context, partition string, condition kvs.SortCondition, opts kvs.QueryOptions) (*kvs.Items, string, error) {
+	ret := _mock.Called(ctx, partition, condition, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryPage")
+	}
+
+	var r0 *kvs.Items
+	var r1 string
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, kvs.SortCondition, kvs.QueryOptions) (*kvs.Items, string, error)); ok {
+		return returnFunc(ctx, partition, condition, opts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, kvs.SortCondition, kvs.QueryOptions) *kvs.Items); ok {
+		r0 = returnFunc(ctx, partition, condition, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*kvs.Items)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, kvs.SortCondition, kvs.QueryOptions) string); ok {
+		r1 = returnFunc(ctx, partition, condition, opts)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, string, kvs.SortCondition, kvs.QueryOptions) error); ok {
+		r2 = returnFunc(ctx, partition, condition, opts)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// MockQuerier_QueryPage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryPage'
+type MockQuerier_QueryPage_Call struct {
+	*mock.Call
+}
+
+// QueryPage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - partition string
+//   - condition kvs.SortCondition
+//   - opts kvs.QueryOptions
+func (_e *MockQuerier_Expecter) QueryPage(ctx any, partition any, condition any, opts any) *MockQuerier_QueryPage_Call {
+	return &MockQuerier_QueryPage_Call{Call: _e.mock.On("QueryPage", ctx, partition, condition, opts)}
+}
+
+func (_c *MockQuerier_QueryPage_Call) Run(run func(ctx context.Context, partition string, condition kvs.SortCondition, opts kvs.QueryOptions)) *MockQuerier_QueryPage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 kvs.SortCondition
+		if args[2] != nil {
+			arg2 = args[2].(kvs.SortCondition)
+		}
+		var arg3 kvs.QueryOptions
+		if args[3] != nil {
+			arg3 = args[3].(kvs.QueryOptions)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockQuerier_QueryPage_Call) Return(items *kvs.Items, s string, err error) *MockQuerier_QueryPage_Call {
+	_c.Call.Return(items, s, err)
+	return _c
+}
+
+func (_c *MockQuerier_QueryPage_Call) RunAndReturn(run func(ctx context.Context, partition string, condition kvs.SortCondition, opts kvs.QueryOptions) (*kvs.Items, string, error)) *MockQuerier_QueryPage_Call {
+	_c.Call.Return(run)
+	return _c
+}
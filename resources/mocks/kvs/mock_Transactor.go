@@ -0,0 +1,83 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package kvs
+
+import (
+	"github.com/arielsrv/go-kvs-client/kvs"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockTransactor creates a new instance of MockTransactor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTransactor(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTransactor {
+	mock := &MockTransactor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockTransactor is an autogenerated mock type for the Transactor type
+type MockTransactor struct {
+	mock.Mock
+}
+
+type MockTransactor_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockTransactor) EXPECT() *MockTransactor_Expecter {
+	return &MockTransactor_Expecter{mock: &_m.Mock}
+}
+
+// NewTx provides a mock function for the type MockTransactor
+func (_mock *MockTransactor) NewTx() *kvs.Tx {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for NewTx")
+	}
+
+	var r0 *kvs.Tx
+	if returnFunc, ok := ret.Get(0).(func() *kvs.Tx); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*kvs.Tx)
+		}
+	}
+	return r0
+}
+
+// MockTransactor_NewTx_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NewTx'
+type MockTransactor_NewTx_Call struct {
+	*mock.Call
+}
+
+// NewTx is a helper method to define mock.On call
+func (_e *MockTransactor_Expecter) NewTx() *MockTransactor_NewTx_Call {
+	return &MockTransactor_NewTx_Call{Call: _e.mock.On("NewTx")}
+}
+
+func (_c *MockTransactor_NewTx_Call) Run(run func()) *MockTransactor_NewTx_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockTransactor_NewTx_Call) Return(tx *kvs.Tx) *MockTransactor_NewTx_Call {
+	_c.Call.Return(tx)
+	return _c
+}
+
+func (_c *MockTransactor_NewTx_Call) RunAndReturn(run func() *kvs.Tx) *MockTransactor_NewTx_Call {
+	_c.Call.Return(run)
+	return _c
+}
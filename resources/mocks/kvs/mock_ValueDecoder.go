@@ -0,0 +1,87 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package kvs
+
+import (
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockValueDecoder creates a new instance of MockValueDecoder. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockValueDecoder(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockValueDecoder {
+	mock := &MockValueDecoder{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockValueDecoder is an autogenerated mock type for the ValueDecoder type
+type MockValueDecoder struct {
+	mock.Mock
+}
+
+type MockValueDecoder_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockValueDecoder) EXPECT() *MockValueDecoder_Expecter {
+	return &MockValueDecoder_Expecter{mock: &_m.Mock}
+}
+
+// DecodeValue provides a mock function for the type MockValueDecoder
+func (_mock *MockValueDecoder) DecodeValue(out any) error {
+	ret := _mock.Called(out)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DecodeValue")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(any) error); ok {
+		r0 = returnFunc(out)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockValueDecoder_DecodeValue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DecodeValue'
+type MockValueDecoder_DecodeValue_Call struct {
+	*mock.Call
+}
+
+// DecodeValue is a helper method to define mock.On call
+//   - out any
+func (_e *MockValueDecoder_Expecter) DecodeValue(out any) *MockValueDecoder_DecodeValue_Call {
+	return &MockValueDecoder_DecodeValue_Call{Call: _e.mock.On("DecodeValue", out)}
+}
+
+func (_c *MockValueDecoder_DecodeValue_Call) Run(run func(out any)) *MockValueDecoder_DecodeValue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 any
+		if args[0] != nil {
+			arg0 = args[0].(any)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockValueDecoder_DecodeValue_Call) Return(err error) *MockValueDecoder_DecodeValue_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockValueDecoder_DecodeValue_Call) RunAndReturn(run func(out any) error) *MockValueDecoder_DecodeValue_Call {
+	_c.Call.Return(run)
+	return _c
+}
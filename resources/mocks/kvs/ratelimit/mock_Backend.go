@@ -0,0 +1,292 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/arielsrv/go-kvs-client/kvs/ratelimit"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockBackend creates a new instance of MockBackend. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockBackend(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockBackend {
+	mock := &MockBackend{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockBackend is an autogenerated mock type for the Backend type
+type MockBackend struct {
+	mock.Mock
+}
+
+type MockBackend_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockBackend) EXPECT() *MockBackend_Expecter {
+	return &MockBackend_Expecter{mock: &_m.Mock}
+}
+
+// FixedWindow provides a mock function for the type MockBackend
+func (_mock *MockBackend) FixedWindow(ctx context.Context, key string, n int64, limit int64, window time.Duration) (ratelimit.Result, error) {
+	ret := _mock.Called(ctx, key, n, limit, window)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FixedWindow")
+	}
+
+	var r0 ratelimit.Result
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64, int64, time.Duration) (ratelimit.Result, error)); ok {
+		return returnFunc(ctx, key, n, limit, window)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64, int64, time.Duration) ratelimit.Result); ok {
+		r0 = returnFunc(ctx, key, n, limit, window)
+	} else {
+		r0 = ret.Get(0).(ratelimit.Result)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int64, int64, time.Duration) error); ok {
+		r1 = returnFunc(ctx, key, n, limit, window)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockBackend_FixedWindow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FixedWindow'
+type MockBackend_FixedWindow_Call struct {
+	*mock.Call
+}
+
+// FixedWindow is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - n int64
+//   - limit int64
+//   - window time.Duration
+func (_e *MockBackend_Expecter) FixedWindow(ctx any, key any, n any, limit any, window any) *MockBackend_FixedWindow_Call {
+	return &MockBackend_FixedWindow_Call{Call: _e.mock.On("FixedWindow", ctx, key, n, limit, window)}
+}
+
+func (_c *MockBackend_FixedWindow_Call) Run(run func(ctx context.Context, key string, n int64, limit int64, window time.Duration)) *MockBackend_FixedWindow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
+		}
+		var arg4 time.Duration
+		if args[4] != nil {
+			arg4 = args[4].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *MockBackend_FixedWindow_Call) Return(result ratelimit.Result, err error) *MockBackend_FixedWindow_Call {
+	_c.Call.Return(result, err)
+	return _c
+}
+
+func (_c *MockBackend_FixedWindow_Call) RunAndReturn(run func(ctx context.Context, key string, n int64, limit int64, window time.Duration) (ratelimit.Result, error)) *MockBackend_FixedWindow_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SlidingWindowLog provides a mock function for the type MockBackend
+func (_mock *MockBackend) SlidingWindowLog(ctx context.Context, key string, n int64, limit int64, window time.Duration) (ratelimit.Result, error) {
+	ret := _mock.Called(ctx, key, n, limit, window)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SlidingWindowLog")
+	}
+
+	var r0 ratelimit.Result
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64, int64, time.Duration) (ratelimit.Result, error)); ok {
+		return returnFunc(ctx, key, n, limit, window)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64, int64, time.Duration) ratelimit.Result); ok {
+		r0 = returnFunc(ctx, key, n, limit, window)
+	} else {
+		r0 = ret.Get(0).(ratelimit.Result)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int64, int64, time.Duration) error); ok {
+		r1 = returnFunc(ctx, key, n, limit, window)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockBackend_SlidingWindowLog_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SlidingWindowLog'
+type MockBackend_SlidingWindowLog_Call struct {
+	*mock.Call
+}
+
+// SlidingWindowLog is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - n int64
+//   - limit int64
+//   - window time.Duration
+func (_e *MockBackend_Expecter) SlidingWindowLog(ctx any, key any, n any, limit any, window any) *MockBackend_SlidingWindowLog_Call {
+	return &MockBackend_SlidingWindowLog_Call{Call: _e.mock.On("SlidingWindowLog", ctx, key, n, limit, window)}
+}
+
+func (_c *MockBackend_SlidingWindowLog_Call) Run(run func(ctx context.Context, key string, n int64, limit int64, window time.Duration)) *MockBackend_SlidingWindowLog_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
+		}
+		var arg4 time.Duration
+		if args[4] != nil {
+			arg4 = args[4].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *MockBackend_SlidingWindowLog_Call) Return(result ratelimit.Result, err error) *MockBackend_SlidingWindowLog_Call {
+	_c.Call.Return(result, err)
+	return _c
+}
+
+func (_c *MockBackend_SlidingWindowLog_Call) RunAndReturn(run func(ctx context.Context, key string, n int64, limit int64, window time.Duration) (ratelimit.Result, error)) *MockBackend_SlidingWindowLog_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TokenBucket provides a mock function for the type MockBackend
+func (_mock *MockBackend) TokenBucket(ctx context.Context, key string, n int64, capacity int64, interval time.Duration) (ratelimit.Result, error) {
+	ret := _mock.Called(ctx, key, n, capacity, interval)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TokenBucket")
+	}
+
+	var r0 ratelimit.Result
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64, int64, time.Duration) (ratelimit.Result, error)); ok {
+		return returnFunc(ctx, key, n, capacity, interval)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64, int64, time.Duration) ratelimit.Result); ok {
+		r0 = returnFunc(ctx, key, n, capacity, interval)
+	} else {
+		r0 = ret.Get(0).(ratelimit.Result)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int64, int64, time.Duration) error); ok {
+		r1 = returnFunc(ctx, key, n, capacity, interval)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockBackend_TokenBucket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TokenBucket'
+type MockBackend_TokenBucket_Call struct {
+	*mock.Call
+}
+
+// TokenBucket is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - n int64
+//   - capacity int64
+//   - interval time.Duration
+func (_e *MockBackend_Expecter) TokenBucket(ctx any, key any, n any, capacity any, interval any) *MockBackend_TokenBucket_Call {
+	return &MockBackend_TokenBucket_Call{Call: _e.mock.On("TokenBucket", ctx, key, n, capacity, interval)}
+}
+
+func (_c *MockBackend_TokenBucket_Call) Run(run func(ctx context.Context, key string, n int64, capacity int64, interval time.Duration)) *MockBackend_TokenBucket_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
+		}
+		var arg4 time.Duration
+		if args[4] != nil {
+			arg4 = args[4].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *MockBackend_TokenBucket_Call) Return(result ratelimit.Result, err error) *MockBackend_TokenBucket_Call {
+	_c.Call.Return(result, err)
+	return _c
+}
+
+func (_c *MockBackend_TokenBucket_Call) RunAndReturn(run func(ctx context.Context, key string, n int64, capacity int64, interval time.Duration) (ratelimit.Result, error)) *MockBackend_TokenBucket_Call {
+	_c.Call.Return(run)
+	return _c
+}
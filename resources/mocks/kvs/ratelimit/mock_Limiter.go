@@ -0,0 +1,111 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package ratelimit
+
+import (
+	"context"
+
+	"github.com/arielsrv/go-kvs-client/kvs/ratelimit"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockLimiter creates a new instance of MockLimiter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockLimiter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockLimiter {
+	mock := &MockLimiter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockLimiter is an autogenerated mock type for the Limiter type
+type MockLimiter struct {
+	mock.Mock
+}
+
+type MockLimiter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockLimiter) EXPECT() *MockLimiter_Expecter {
+	return &MockLimiter_Expecter{mock: &_m.Mock}
+}
+
+// Allow provides a mock function for the type MockLimiter
+func (_mock *MockLimiter) Allow(ctx context.Context, key string, n int64) (ratelimit.Result, error) {
+	ret := _mock.Called(ctx, key, n)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Allow")
+	}
+
+	var r0 ratelimit.Result
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) (ratelimit.Result, error)); ok {
+		return returnFunc(ctx, key, n)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) ratelimit.Result); ok {
+		r0 = returnFunc(ctx, key, n)
+	} else {
+		r0 = ret.Get(0).(ratelimit.Result)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = returnFunc(ctx, key, n)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLimiter_Allow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Allow'
+type MockLimiter_Allow_Call struct {
+	*mock.Call
+}
+
+// Allow is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - n int64
+func (_e *MockLimiter_Expecter) Allow(ctx any, key any, n any) *MockLimiter_Allow_Call {
+	return &MockLimiter_Allow_Call{Call: _e.mock.On("Allow", ctx, key, n)}
+}
+
+func (_c *MockLimiter_Allow_Call) Run(run func(ctx context.Context, key string, n int64)) *MockLimiter_Allow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLimiter_Allow_Call) Return(result ratelimit.Result, err error) *MockLimiter_Allow_Call {
+	_c.Call.Return(result, err)
+	return _c
+}
+
+func (_c *MockLimiter_Allow_Call) RunAndReturn(run func(ctx context.Context, key string, n int64) (ratelimit.Result, error)) *MockLimiter_Allow_Call {
+	_c.Call.Return(run)
+	return _c
+}
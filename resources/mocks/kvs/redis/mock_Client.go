@@ -6,6 +6,7 @@ package redis
 
 import (
 	"context"
+	"iter"
 	"time"
 
 	"github.com/arielsrv/go-kvs-client/kvs/redis"
@@ -83,6 +84,219 @@ func (_c *MockClient_Close_Call) RunAndReturn(run func() error) *MockClient_Clos
 	return _c
 }
 
+// CompareAndDelete provides a mock function for the type MockClient
+func (_mock *MockClient) CompareAndDelete(ctx context.Context, key string, expected string) (bool, error) {
+	ret := _mock.Called(ctx, key, expected)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompareAndDelete")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (bool, error)); ok {
+		return returnFunc(ctx, key, expected)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = returnFunc(ctx, key, expected)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, key, expected)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockClient_CompareAndDelete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompareAndDelete'
+type MockClient_CompareAndDelete_Call struct {
+	*mock.Call
+}
+
+// CompareAndDelete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - expected string
+func (_e *MockClient_Expecter) CompareAndDelete(ctx any, key any, expected any) *MockClient_CompareAndDelete_Call {
+	return &MockClient_CompareAndDelete_Call{Call: _e.mock.On("CompareAndDelete", ctx, key, expected)}
+}
+
+func (_c *MockClient_CompareAndDelete_Call) Run(run func(ctx context.Context, key string, expected string)) *MockClient_CompareAndDelete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_CompareAndDelete_Call) Return(b bool, err error) *MockClient_CompareAndDelete_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockClient_CompareAndDelete_Call) RunAndReturn(run func(ctx context.Context, key string, expected string) (bool, error)) *MockClient_CompareAndDelete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CompareAndSwap provides a mock function for the type MockClient
+func (_mock *MockClient) CompareAndSwap(ctx context.Context, key string, expected string, value string, ttl time.Duration) (bool, error) {
+	ret := _mock.Called(ctx, key, expected, value, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompareAndSwap")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string, time.Duration) (bool, error)); ok {
+		return returnFunc(ctx, key, expected, value, ttl)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string, time.Duration) bool); ok {
+		r0 = returnFunc(ctx, key, expected, value, ttl)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, string, time.Duration) error); ok {
+		r1 = returnFunc(ctx, key, expected, value, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockClient_CompareAndSwap_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompareAndSwap'
+type MockClient_CompareAndSwap_Call struct {
+	*mock.Call
+}
+
+// CompareAndSwap is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - expected string
+//   - value string
+//   - ttl time.Duration
+func (_e *MockClient_Expecter) CompareAndSwap(ctx any, key any, expected any, value any, ttl any) *MockClient_CompareAndSwap_Call {
+	return &MockClient_CompareAndSwap_Call{Call: _e.mock.On("CompareAndSwap", ctx, key, expected, value, ttl)}
+}
+
+func (_c *MockClient_CompareAndSwap_Call) Run(run func(ctx context.Context, key string, expected string, value string, ttl time.Duration)) *MockClient_CompareAndSwap_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		var arg4 time.Duration
+		if args[4] != nil {
+			arg4 = args[4].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_CompareAndSwap_Call) Return(b bool, err error) *MockClient_CompareAndSwap_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockClient_CompareAndSwap_Call) RunAndReturn(run func(ctx context.Context, key string, expected string, value string, ttl time.Duration) (bool, error)) *MockClient_CompareAndSwap_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Del provides a mock function for the type MockClient
+func (_mock *MockClient) Del(ctx context.Context, key string) error {
+	ret := _mock.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Del")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockClient_Del_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Del'
+type MockClient_Del_Call struct {
+	*mock.Call
+}
+
+// Del is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *MockClient_Expecter) Del(ctx any, key any) *MockClient_Del_Call {
+	return &MockClient_Del_Call{Call: _e.mock.On("Del", ctx, key)}
+}
+
+func (_c *MockClient_Del_Call) Run(run func(ctx context.Context, key string)) *MockClient_Del_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_Del_Call) Return(err error) *MockClient_Del_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockClient_Del_Call) RunAndReturn(run func(ctx context.Context, key string) error) *MockClient_Del_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Get provides a mock function for the type MockClient
 func (_mock *MockClient) Get(ctx context.Context, key string) (string, error) {
 	ret := _mock.Called(ctx, key)
@@ -149,112 +363,127 @@ func (_c *MockClient_Get_Call) RunAndReturn(run func(ctx context.Context, key st
 	return _c
 }
 
-// MGet provides a mock function for the type MockClient
-func (_mock *MockClient) MGet(ctx context.Context, keys []string) ([]redis.GetResult, error) {
-	ret := _mock.Called(ctx, keys)
+// GetRecordFields provides a mock function for the type MockClient
+func (_mock *MockClient) GetRecordFields(ctx context.Context, key string, paths []string) (redis.RecordResult, error) {
+	ret := _mock.Called(ctx, key, paths)
 
 	if len(ret) == 0 {
-		panic("no return value specified for MGet")
+		panic("no return value specified for GetRecordFields")
 	}
 
-	var r0 []redis.GetResult
+	var r0 redis.RecordResult
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) ([]redis.GetResult, error)); ok {
-		return returnFunc(ctx, keys)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []string) (redis.RecordResult, error)); ok {
+		return returnFunc(ctx, key, paths)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) []redis.GetResult); ok {
-		r0 = returnFunc(ctx, keys)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []string) redis.RecordResult); ok {
+		r0 = returnFunc(ctx, key, paths)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]redis.GetResult)
-		}
+		r0 = ret.Get(0).(redis.RecordResult)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, []string) error); ok {
-		r1 = returnFunc(ctx, keys)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = returnFunc(ctx, key, paths)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockClient_MGet_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MGet'
-type MockClient_MGet_Call struct {
+// MockClient_GetRecordFields_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRecordFields'
+type MockClient_GetRecordFields_Call struct {
 	*mock.Call
 }
 
-// MGet is a helper method to define mock.On call
+// GetRecordFields is a helper method to define mock.On call
 //   - ctx context.Context
-//   - keys []string
-func (_e *MockClient_Expecter) MGet(ctx any, keys any) *MockClient_MGet_Call {
-	return &MockClient_MGet_Call{Call: _e.mock.On("MGet", ctx, keys)}
+//   - key string
+//   - paths []string
+func (_e *MockClient_Expecter) GetRecordFields(ctx any, key any, paths any) *MockClient_GetRecordFields_Call {
+	return &MockClient_GetRecordFields_Call{Call: _e.mock.On("GetRecordFields", ctx, key, paths)}
 }
 
-func (_c *MockClient_MGet_Call) Run(run func(ctx context.Context, keys []string)) *MockClient_MGet_Call {
+func (_c *MockClient_GetRecordFields_Call) Run(run func(ctx context.Context, key string, paths []string)) *MockClient_GetRecordFields_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 []string
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].([]string)
+			arg1 = args[1].(string)
+		}
+		var arg2 []string
+		if args[2] != nil {
+			arg2 = args[2].([]string)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockClient_MGet_Call) Return(getResults []redis.GetResult, err error) *MockClient_MGet_Call {
-	_c.Call.Return(getResults, err)
+func (_c *MockClient_GetRecordFields_Call) Return(recordResult redis.RecordResult, err error) *MockClient_GetRecordFields_Call {
+	_c.Call.Return(recordResult, err)
 	return _c
 }
 
-func (_c *MockClient_MGet_Call) RunAndReturn(run func(ctx context.Context, keys []string) ([]redis.GetResult, error)) *MockClient_MGet_Call {
+func (_c *MockClient_GetRecordFields_Call) RunAndReturn(run func(ctx context.Context, key string, paths []string) (redis.RecordResult, error)) *MockClient_GetRecordFields_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// MSet provides a mock function for the type MockClient
-func (_mock *MockClient) MSet(ctx context.Context, pairs []redis.Pair) error {
-	ret := _mock.Called(ctx, pairs)
+// GetRecords provides a mock function for the type MockClient
+func (_mock *MockClient) GetRecords(ctx context.Context, keys []string) ([]redis.RecordResult, error) {
+	ret := _mock.Called(ctx, keys)
 
 	if len(ret) == 0 {
-		panic("no return value specified for MSet")
+		panic("no return value specified for GetRecords")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []redis.Pair) error); ok {
-		r0 = returnFunc(ctx, pairs)
+	var r0 []redis.RecordResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) ([]redis.RecordResult, error)); ok {
+		return returnFunc(ctx, keys)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) []redis.RecordResult); ok {
+		r0 = returnFunc(ctx, keys)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]redis.RecordResult)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = returnFunc(ctx, keys)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockClient_MSet_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MSet'
-type MockClient_MSet_Call struct {
+// MockClient_GetRecords_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRecords'
+type MockClient_GetRecords_Call struct {
 	*mock.Call
 }
 
-// MSet is a helper method to define mock.On call
+// GetRecords is a helper method to define mock.On call
 //   - ctx context.Context
-//   - pairs []redis.Pair
-func (_e *MockClient_Expecter) MSet(ctx any, pairs any) *MockClient_MSet_Call {
-	return &MockClient_MSet_Call{Call: _e.mock.On("MSet", ctx, pairs)}
+//   - keys []string
+func (_e *MockClient_Expecter) GetRecords(ctx any, keys any) *MockClient_GetRecords_Call {
+	return &MockClient_GetRecords_Call{Call: _e.mock.On("GetRecords", ctx, keys)}
 }
 
-func (_c *MockClient_MSet_Call) Run(run func(ctx context.Context, pairs []redis.Pair)) *MockClient_MSet_Call {
+func (_c *MockClient_GetRecords_Call) Run(run func(ctx context.Context, keys []string)) *MockClient_GetRecords_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 []redis.Pair
+		var arg1 []string
 		if args[1] != nil {
-			arg1 = args[1].([]redis.Pair)
+			arg1 = args[1].([]string)
 		}
 		run(
 			arg0,
@@ -264,48 +493,57 @@ func (_c *MockClient_MSet_Call) Run(run func(ctx context.Context, pairs []redis.
 	return _c
 }
 
-func (_c *MockClient_MSet_Call) Return(err error) *MockClient_MSet_Call {
-	_c.Call.Return(err)
+func (_c *MockClient_GetRecords_Call) Return(recordResults []redis.RecordResult, err error) *MockClient_GetRecords_Call {
+	_c.Call.Return(recordResults, err)
 	return _c
 }
 
-func (_c *MockClient_MSet_Call) RunAndReturn(run func(ctx context.Context, pairs []redis.Pair) error) *MockClient_MSet_Call {
+func (_c *MockClient_GetRecords_Call) RunAndReturn(run func(ctx context.Context, keys []string) ([]redis.RecordResult, error)) *MockClient_GetRecords_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Set provides a mock function for the type MockClient
-func (_mock *MockClient) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
-	ret := _mock.Called(ctx, key, value, ttl)
+// IncrBy provides a mock function for the type MockClient
+func (_mock *MockClient) IncrBy(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	ret := _mock.Called(ctx, key, delta, ttl)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Set")
+		panic("no return value specified for IncrBy")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) error); ok {
-		r0 = returnFunc(ctx, key, value, ttl)
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64, time.Duration) (int64, error)); ok {
+		return returnFunc(ctx, key, delta, ttl)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64, time.Duration) int64); ok {
+		r0 = returnFunc(ctx, key, delta, ttl)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(int64)
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int64, time.Duration) error); ok {
+		r1 = returnFunc(ctx, key, delta, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockClient_Set_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Set'
-type MockClient_Set_Call struct {
+// MockClient_IncrBy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncrBy'
+type MockClient_IncrBy_Call struct {
 	*mock.Call
 }
 
-// Set is a helper method to define mock.On call
+// IncrBy is a helper method to define mock.On call
 //   - ctx context.Context
 //   - key string
-//   - value string
+//   - delta int64
 //   - ttl time.Duration
-func (_e *MockClient_Expecter) Set(ctx any, key any, value any, ttl any) *MockClient_Set_Call {
-	return &MockClient_Set_Call{Call: _e.mock.On("Set", ctx, key, value, ttl)}
+func (_e *MockClient_Expecter) IncrBy(ctx any, key any, delta any, ttl any) *MockClient_IncrBy_Call {
+	return &MockClient_IncrBy_Call{Call: _e.mock.On("IncrBy", ctx, key, delta, ttl)}
 }
 
-func (_c *MockClient_Set_Call) Run(run func(ctx context.Context, key string, value string, ttl time.Duration)) *MockClient_Set_Call {
+func (_c *MockClient_IncrBy_Call) Run(run func(ctx context.Context, key string, delta int64, ttl time.Duration)) *MockClient_IncrBy_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -315,9 +553,9 @@ func (_c *MockClient_Set_Call) Run(run func(ctx context.Context, key string, val
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
-		var arg2 string
+		var arg2 int64
 		if args[2] != nil {
-			arg2 = args[2].(string)
+			arg2 = args[2].(int64)
 		}
 		var arg3 time.Duration
 		if args[3] != nil {
@@ -333,12 +571,712 @@ func (_c *MockClient_Set_Call) Run(run func(ctx context.Context, key string, val
 	return _c
 }
 
-func (_c *MockClient_Set_Call) Return(err error) *MockClient_Set_Call {
-	_c.Call.Return(err)
+func (_c *MockClient_IncrBy_Call) Return(n int64, err error) *MockClient_IncrBy_Call {
+	_c.Call.Return(n, err)
 	return _c
 }
 
-func (_c *MockClient_Set_Call) RunAndReturn(run func(ctx context.Context, key string, value string, ttl time.Duration) error) *MockClient_Set_Call {
+func (_c *MockClient_IncrBy_Call) RunAndReturn(run func(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)) *MockClient_IncrBy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IncrByFloat provides a mock function for the type MockClient
+func (_mock *MockClient) IncrByFloat(ctx context.Context, key string, delta float64, ttl time.Duration) (float64, error) {
+	ret := _mock.Called(ctx, key, delta, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IncrByFloat")
+	}
+
+	var r0 float64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, float64, time.Duration) (float64, error)); ok {
+		return returnFunc(ctx, key, delta, ttl)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, float64, time.Duration) float64); ok {
+		r0 = returnFunc(ctx, key, delta, ttl)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, float64, time.Duration) error); ok {
+		r1 = returnFunc(ctx, key, delta, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockClient_IncrByFloat_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncrByFloat'
+type MockClient_IncrByFloat_Call struct {
+	*mock.Call
+}
+
+// IncrByFloat is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - delta float64
+//   - ttl time.Duration
+func (_e *MockClient_Expecter) IncrByFloat(ctx any, key any, delta any, ttl any) *MockClient_IncrByFloat_Call {
+	return &MockClient_IncrByFloat_Call{Call: _e.mock.On("IncrByFloat", ctx, key, delta, ttl)}
+}
+
+func (_c *MockClient_IncrByFloat_Call) Run(run func(ctx context.Context, key string, delta float64, ttl time.Duration)) *MockClient_IncrByFloat_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 float64
+		if args[2] != nil {
+			arg2 = args[2].(float64)
+		}
+		var arg3 time.Duration
+		if args[3] != nil {
+			arg3 = args[3].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_IncrByFloat_Call) Return(f float64, err error) *MockClient_IncrByFloat_Call {
+	_c.Call.Return(f, err)
+	return _c
+}
+
+func (_c *MockClient_IncrByFloat_Call) RunAndReturn(run func(ctx context.Context, key string, delta float64, ttl time.Duration) (float64, error)) *MockClient_IncrByFloat_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MGet provides a mock function for the type MockClient
+func (_mock *MockClient) MGet(ctx context.Context, keys []string) ([]redis.GetResult, error) {
+	ret := _mock.Called(ctx, keys)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MGet")
+	}
+
+	var r0 []redis.GetResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) ([]redis.GetResult, error)); ok {
+		return returnFunc(ctx, keys)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) []redis.GetResult); ok {
+		r0 = returnFunc(ctx, keys)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]redis.GetResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = returnFunc(ctx, keys)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockClient_MGet_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MGet'
+type MockClient_MGet_Call struct {
+	*mock.Call
+}
+
+// MGet is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keys []string
+func (_e *MockClient_Expecter) MGet(ctx any, keys any) *MockClient_MGet_Call {
+	return &MockClient_MGet_Call{Call: _e.mock.On("MGet", ctx, keys)}
+}
+
+func (_c *MockClient_MGet_Call) Run(run func(ctx context.Context, keys []string)) *MockClient_MGet_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_MGet_Call) Return(getResults []redis.GetResult, err error) *MockClient_MGet_Call {
+	_c.Call.Return(getResults, err)
+	return _c
+}
+
+func (_c *MockClient_MGet_Call) RunAndReturn(run func(ctx context.Context, keys []string) ([]redis.GetResult, error)) *MockClient_MGet_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MSet provides a mock function for the type MockClient
+func (_mock *MockClient) MSet(ctx context.Context, pairs []redis.Pair) error {
+	ret := _mock.Called(ctx, pairs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MSet")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []redis.Pair) error); ok {
+		r0 = returnFunc(ctx, pairs)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockClient_MSet_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MSet'
+type MockClient_MSet_Call struct {
+	*mock.Call
+}
+
+// MSet is a helper method to define mock.On call
+//   - ctx context.Context
+//   - pairs []redis.Pair
+func (_e *MockClient_Expecter) MSet(ctx any, pairs any) *MockClient_MSet_Call {
+	return &MockClient_MSet_Call{Call: _e.mock.On("MSet", ctx, pairs)}
+}
+
+func (_c *MockClient_MSet_Call) Run(run func(ctx context.Context, pairs []redis.Pair)) *MockClient_MSet_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []redis.Pair
+		if args[1] != nil {
+			arg1 = args[1].([]redis.Pair)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_MSet_Call) Return(err error) *MockClient_MSet_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockClient_MSet_Call) RunAndReturn(run func(ctx context.Context, pairs []redis.Pair) error) *MockClient_MSet_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PatchRecord provides a mock function for the type MockClient
+func (_mock *MockClient) PatchRecord(ctx context.Context, key string, updates []redis.FieldUpdate) (bool, error) {
+	ret := _mock.Called(ctx, key, updates)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchRecord")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []redis.FieldUpdate) (bool, error)); ok {
+		return returnFunc(ctx, key, updates)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []redis.FieldUpdate) bool); ok {
+		r0 = returnFunc(ctx, key, updates)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, []redis.FieldUpdate) error); ok {
+		r1 = returnFunc(ctx, key, updates)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockClient_PatchRecord_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PatchRecord'
+type MockClient_PatchRecord_Call struct {
+	*mock.Call
+}
+
+// PatchRecord is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - updates []redis.FieldUpdate
+func (_e *MockClient_Expecter) PatchRecord(ctx any, key any, updates any) *MockClient_PatchRecord_Call {
+	return &MockClient_PatchRecord_Call{Call: _e.mock.On("PatchRecord", ctx, key, updates)}
+}
+
+func (_c *MockClient_PatchRecord_Call) Run(run func(ctx context.Context, key string, updates []redis.FieldUpdate)) *MockClient_PatchRecord_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 []redis.FieldUpdate
+		if args[2] != nil {
+			arg2 = args[2].([]redis.FieldUpdate)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_PatchRecord_Call) Return(b bool, err error) *MockClient_PatchRecord_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockClient_PatchRecord_Call) RunAndReturn(run func(ctx context.Context, key string, updates []redis.FieldUpdate) (bool, error)) *MockClient_PatchRecord_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Scan provides a mock function for the type MockClient
+func (_mock *MockClient) Scan(ctx context.Context, match string, count int64) iter.Seq2[string, error] {
+	ret := _mock.Called(ctx, match, count)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Scan")
+	}
+
+	var r0 iter.Seq2[string, error]
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) iter.Seq2[string, error]); ok {
+		r0 = returnFunc(ctx, match, count)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(iter.Seq2[string, error])
+		}
+	}
+	return r0
+}
+
+// MockClient_Scan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Scan'
+type MockClient_Scan_Call struct {
+	*mock.Call
+}
+
+// Scan is a helper method to define mock.On call
+//   - ctx context.Context
+//   - match string
+//   - count int64
+func (_e *MockClient_Expecter) Scan(ctx any, match any, count any) *MockClient_Scan_Call {
+	return &MockClient_Scan_Call{Call: _e.mock.On("Scan", ctx, match, count)}
+}
+
+func (_c *MockClient_Scan_Call) Run(run func(ctx context.Context, match string, count int64)) *MockClient_Scan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_Scan_Call) Return(seq2 iter.Seq2[string, error]) *MockClient_Scan_Call {
+	_c.Call.Return(seq2)
+	return _c
+}
+
+func (_c *MockClient_Scan_Call) RunAndReturn(run func(ctx context.Context, match string, count int64) iter.Seq2[string, error]) *MockClient_Scan_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Set provides a mock function for the type MockClient
+func (_mock *MockClient) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	ret := _mock.Called(ctx, key, value, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Set")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) error); ok {
+		r0 = returnFunc(ctx, key, value, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockClient_Set_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Set'
+type MockClient_Set_Call struct {
+	*mock.Call
+}
+
+// Set is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - value string
+//   - ttl time.Duration
+func (_e *MockClient_Expecter) Set(ctx any, key any, value any, ttl any) *MockClient_Set_Call {
+	return &MockClient_Set_Call{Call: _e.mock.On("Set", ctx, key, value, ttl)}
+}
+
+func (_c *MockClient_Set_Call) Run(run func(ctx context.Context, key string, value string, ttl time.Duration)) *MockClient_Set_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 time.Duration
+		if args[3] != nil {
+			arg3 = args[3].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_Set_Call) Return(err error) *MockClient_Set_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockClient_Set_Call) RunAndReturn(run func(ctx context.Context, key string, value string, ttl time.Duration) error) *MockClient_Set_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetNX provides a mock function for the type MockClient
+func (_mock *MockClient) SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error) {
+	ret := _mock.Called(ctx, key, value, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetNX")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) (bool, error)); ok {
+		return returnFunc(ctx, key, value, ttl)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) bool); ok {
+		r0 = returnFunc(ctx, key, value, ttl)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, time.Duration) error); ok {
+		r1 = returnFunc(ctx, key, value, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockClient_SetNX_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetNX'
+type MockClient_SetNX_Call struct {
+	*mock.Call
+}
+
+// SetNX is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - value string
+//   - ttl time.Duration
+func (_e *MockClient_Expecter) SetNX(ctx any, key any, value any, ttl any) *MockClient_SetNX_Call {
+	return &MockClient_SetNX_Call{Call: _e.mock.On("SetNX", ctx, key, value, ttl)}
+}
+
+func (_c *MockClient_SetNX_Call) Run(run func(ctx context.Context, key string, value string, ttl time.Duration)) *MockClient_SetNX_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 time.Duration
+		if args[3] != nil {
+			arg3 = args[3].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_SetNX_Call) Return(b bool, err error) *MockClient_SetNX_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockClient_SetNX_Call) RunAndReturn(run func(ctx context.Context, key string, value string, ttl time.Duration) (bool, error)) *MockClient_SetNX_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetRecord provides a mock function for the type MockClient
+func (_mock *MockClient) SetRecord(ctx context.Context, key string, record redis.Record, ttl time.Duration, condition redis.RecordCondition) (bool, error) {
+	ret := _mock.Called(ctx, key, record, ttl, condition)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetRecord")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, redis.Record, time.Duration, redis.RecordCondition) (bool, error)); ok {
+		return returnFunc(ctx, key, record, ttl, condition)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, redis.Record, time.Duration, redis.RecordCondition) bool); ok {
+		r0 = returnFunc(ctx, key, record, ttl, condition)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, redis.Record, time.Duration, redis.RecordCondition) error); ok {
+		r1 = returnFunc(ctx, key, record, ttl, condition)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockClient_SetRecord_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetRecord'
+type MockClient_SetRecord_Call struct {
+	*mock.Call
+}
+
+// SetRecord is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - record redis.Record
+//   - ttl time.Duration
+//   - condition redis.RecordCondition
+func (_e *MockClient_Expecter) SetRecord(ctx any, key any, record any, ttl any, condition any) *MockClient_SetRecord_Call {
+	return &MockClient_SetRecord_Call{Call: _e.mock.On("SetRecord", ctx, key, record, ttl, condition)}
+}
+
+func (_c *MockClient_SetRecord_Call) Run(run func(ctx context.Context, key string, record redis.Record, ttl time.Duration, condition redis.RecordCondition)) *MockClient_SetRecord_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 redis.Record
+		if args[2] != nil {
+			arg2 = args[2].(redis.Record)
+		}
+		var arg3 time.Duration
+		if args[3] != nil {
+			arg3 = args[3].(time.Duration)
+		}
+		var arg4 redis.RecordCondition
+		if args[4] != nil {
+			arg4 = args[4].(redis.RecordCondition)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_SetRecord_Call) Return(b bool, err error) *MockClient_SetRecord_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockClient_SetRecord_Call) RunAndReturn(run func(ctx context.Context, key string, record redis.Record, ttl time.Duration, condition redis.RecordCondition) (bool, error)) *MockClient_SetRecord_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetRecords provides a mock function for the type MockClient
+func (_mock *MockClient) SetRecords(ctx context.Context, pairs []redis.RecordPair) error {
+	ret := _mock.Called(ctx, pairs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetRecords")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []redis.RecordPair) error); ok {
+		r0 = returnFunc(ctx, pairs)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockClient_SetRecords_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetRecords'
+type MockClient_SetRecords_Call struct {
+	*mock.Call
+}
+
+// SetRecords is a helper method to define mock.On call
+//   - ctx context.Context
+//   - pairs []redis.RecordPair
+func (_e *MockClient_Expecter) SetRecords(ctx any, pairs any) *MockClient_SetRecords_Call {
+	return &MockClient_SetRecords_Call{Call: _e.mock.On("SetRecords", ctx, pairs)}
+}
+
+func (_c *MockClient_SetRecords_Call) Run(run func(ctx context.Context, pairs []redis.RecordPair)) *MockClient_SetRecords_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []redis.RecordPair
+		if args[1] != nil {
+			arg1 = args[1].([]redis.RecordPair)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_SetRecords_Call) Return(err error) *MockClient_SetRecords_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockClient_SetRecords_Call) RunAndReturn(run func(ctx context.Context, pairs []redis.RecordPair) error) *MockClient_SetRecords_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Transact provides a mock function for the type MockClient
+func (_mock *MockClient) Transact(ctx context.Context, checks []redis.TxCheck, writes []redis.TxWrite) (int, error) {
+	ret := _mock.Called(ctx, checks, writes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Transact")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []redis.TxCheck, []redis.TxWrite) (int, error)); ok {
+		return returnFunc(ctx, checks, writes)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []redis.TxCheck, []redis.TxWrite) int); ok {
+		r0 = returnFunc(ctx, checks, writes)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []redis.TxCheck, []redis.TxWrite) error); ok {
+		r1 = returnFunc(ctx, checks, writes)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockClient_Transact_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Transact'
+type MockClient_Transact_Call struct {
+	*mock.Call
+}
+
+// Transact is a helper method to define mock.On call
+//   - ctx context.Context
+//   - checks []redis.TxCheck
+//   - writes []redis.TxWrite
+func (_e *MockClient_Expecter) Transact(ctx any, checks any, writes any) *MockClient_Transact_Call {
+	return &MockClient_Transact_Call{Call: _e.mock.On("Transact", ctx, checks, writes)}
+}
+
+func (_c *MockClient_Transact_Call) Run(run func(ctx context.Context, checks []redis.TxCheck, writes []redis.TxWrite)) *MockClient_Transact_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []redis.TxCheck
+		if args[1] != nil {
+			arg1 = args[1].([]redis.TxCheck)
+		}
+		var arg2 []redis.TxWrite
+		if args[2] != nil {
+			arg2 = args[2].([]redis.TxWrite)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_Transact_Call) Return(n int, err error) *MockClient_Transact_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockClient_Transact_Call) RunAndReturn(run func(ctx context.Context, checks []redis.TxCheck, writes []redis.TxWrite) (int, error)) *MockClient_Transact_Call {
 	_c.Call.Return(run)
 	return _c
 }
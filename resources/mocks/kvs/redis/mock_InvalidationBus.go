@@ -0,0 +1,220 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package redis
+
+import (
+	"context"
+
+	"github.com/arielsrv/go-kvs-client/kvs/redis"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockInvalidationBus creates a new instance of MockInvalidationBus. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockInvalidationBus(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockInvalidationBus {
+	mock := &MockInvalidationBus{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockInvalidationBus is an autogenerated mock type for the InvalidationBus type
+type MockInvalidationBus struct {
+	mock.Mock
+}
+
+type MockInvalidationBus_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockInvalidationBus) EXPECT() *MockInvalidationBus_Expecter {
+	return &MockInvalidationBus_Expecter{mock: &_m.Mock}
+}
+
+// Close provides a mock function for the type MockInvalidationBus
+func (_mock *MockInvalidationBus) Close() error {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Close")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func() error); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockInvalidationBus_Close_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Close'
+type MockInvalidationBus_Close_Call struct {
+	*mock.Call
+}
+
+// Close is a helper method to define mock.On call
+func (_e *MockInvalidationBus_Expecter) Close() *MockInvalidationBus_Close_Call {
+	return &MockInvalidationBus_Close_Call{Call: _e.mock.On("Close")}
+}
+
+func (_c *MockInvalidationBus_Close_Call) Run(run func()) *MockInvalidationBus_Close_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockInvalidationBus_Close_Call) Return(err error) *MockInvalidationBus_Close_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockInvalidationBus_Close_Call) RunAndReturn(run func() error) *MockInvalidationBus_Close_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Publish provides a mock function for the type MockInvalidationBus
+func (_mock *MockInvalidationBus) Publish(ctx context.Context, topic string, event redis.InvalidationEvent) error {
+	ret := _mock.Called(ctx, topic, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, redis.InvalidationEvent) error); ok {
+		r0 = returnFunc(ctx, topic, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockInvalidationBus_Publish_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Publish'
+type MockInvalidationBus_Publish_Call struct {
+	*mock.Call
+}
+
+// Publish is a helper method to define mock.On call
+//   - ctx context.Context
+//   - topic string
+//   - event redis.InvalidationEvent
+func (_e *MockInvalidationBus_Expecter) Publish(ctx any, topic any, event any) *MockInvalidationBus_Publish_Call {
+	return &MockInvalidationBus_Publish_Call{Call: _e.mock.On("Publish", ctx, topic, event)}
+}
+
+func (_c *MockInvalidationBus_Publish_Call) Run(run func(ctx context.Context, topic string, event redis.InvalidationEvent)) *MockInvalidationBus_Publish_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 redis.InvalidationEvent
+		if args[2] != nil {
+			arg2 = args[2].(redis.InvalidationEvent)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockInvalidationBus_Publish_Call) Return(err error) *MockInvalidationBus_Publish_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockInvalidationBus_Publish_Call) RunAndReturn(run func(ctx context.Context, topic string, event redis.InvalidationEvent) error) *MockInvalidationBus_Publish_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Subscribe provides a mock function for the type MockInvalidationBus
+func (_mock *MockInvalidationBus) Subscribe(ctx context.Context, topic string, handler redis.InvalidationHandler) (redis.Subscription, error) {
+	ret := _mock.Called(ctx, topic, handler)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Subscribe")
+	}
+
+	var r0 redis.Subscription
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, redis.InvalidationHandler) (redis.Subscription, error)); ok {
+		return returnFunc(ctx, topic, handler)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, redis.InvalidationHandler) redis.Subscription); ok {
+		r0 = returnFunc(ctx, topic, handler)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(redis.Subscription)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, redis.InvalidationHandler) error); ok {
+		r1 = returnFunc(ctx, topic, handler)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockInvalidationBus_Subscribe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Subscribe'
+type MockInvalidationBus_Subscribe_Call struct {
+	*mock.Call
+}
+
+// Subscribe is a helper method to define mock.On call
+//   - ctx context.Context
+//   - topic string
+//   - handler redis.InvalidationHandler
+func (_e *MockInvalidationBus_Expecter) Subscribe(ctx any, topic any, handler any) *MockInvalidationBus_Subscribe_Call {
+	return &MockInvalidationBus_Subscribe_Call{Call: _e.mock.On("Subscribe", ctx, topic, handler)}
+}
+
+func (_c *MockInvalidationBus_Subscribe_Call) Run(run func(ctx context.Context, topic string, handler redis.InvalidationHandler)) *MockInvalidationBus_Subscribe_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 redis.InvalidationHandler
+		if args[2] != nil {
+			arg2 = args[2].(redis.InvalidationHandler)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockInvalidationBus_Subscribe_Call) Return(subscription redis.Subscription, err error) *MockInvalidationBus_Subscribe_Call {
+	_c.Call.Return(subscription, err)
+	return _c
+}
+
+func (_c *MockInvalidationBus_Subscribe_Call) RunAndReturn(run func(ctx context.Context, topic string, handler redis.InvalidationHandler) (redis.Subscription, error)) *MockInvalidationBus_Subscribe_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,89 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package redis
+
+import (
+	"github.com/arielsrv/go-kvs-client/kvs/redis"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockTracker creates a new instance of MockTracker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTracker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTracker {
+	mock := &MockTracker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockTracker is an autogenerated mock type for the Tracker type
+type MockTracker struct {
+	mock.Mock
+}
+
+type MockTracker_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockTracker) EXPECT() *MockTracker_Expecter {
+	return &MockTracker_Expecter{mock: &_m.Mock}
+}
+
+// EnableTracking provides a mock function for the type MockTracker
+func (_mock *MockTracker) EnableTracking(mode redis.TrackingMode, prefixes []string, onInvalidate func(keys []string)) {
+	_mock.Called(mode, prefixes, onInvalidate)
+	return
+}
+
+// MockTracker_EnableTracking_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnableTracking'
+type MockTracker_EnableTracking_Call struct {
+	*mock.Call
+}
+
+// EnableTracking is a helper method to define mock.On call
+//   - mode redis.TrackingMode
+//   - prefixes []string
+//   - onInvalidate func(keys []string)
+func (_e *MockTracker_Expecter) EnableTracking(mode any, prefixes any, onInvalidate any) *MockTracker_EnableTracking_Call {
+	return &MockTracker_EnableTracking_Call{Call: _e.mock.On("EnableTracking", mode, prefixes, onInvalidate)}
+}
+
+func (_c *MockTracker_EnableTracking_Call) Run(run func(mode redis.TrackingMode, prefixes []string, onInvalidate func(keys []string))) *MockTracker_EnableTracking_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 redis.TrackingMode
+		if args[0] != nil {
+			arg0 = args[0].(redis.TrackingMode)
+		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
+		}
+		var arg2 func(keys []string)
+		if args[2] != nil {
+			arg2 = args[2].(func(keys []string))
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTracker_EnableTracking_Call) Return() *MockTracker_EnableTracking_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockTracker_EnableTracking_Call) RunAndReturn(run func(mode redis.TrackingMode, prefixes []string, onInvalidate func(keys []string))) *MockTracker_EnableTracking_Call {
+	_c.Run(run)
+	return _c
+}